@@ -33,6 +33,7 @@ func Mux(
 	ctx context.Context,
 	service eventable.Service,
 	feedManager *readeef.FeedManager,
+	publisher *readeef.Publisher,
 	searchProvider search.Provider,
 	extractor extract.Generator,
 	fs http.FileSystem,
@@ -64,24 +65,37 @@ func Mux(
 		return nil, errors.Wrap(err, "initializing token storage")
 	}
 
+	subroutes := []routes{
+		featureRoutes(features, gzip, access),
+		feedsRoutes(service, feedManager, log, gzip, access),
+		tagRoutes(service, feedManager, log, gzip, access),
+		articlesRoutes(service, extractor, searchProvider, processors, config, log, gzip, access),
+		opmlRoutes(service, feedManager, log, gzip, access),
+		eventsRoutes(ctx, service, storage, feedManager, log),
+		userRoutes(service, []byte(config.Auth.Secret), log, gzip, access),
+		subscriptionRoutes(service, log, gzip, access),
+	}
+
+	if publisher != nil {
+		subroutes = append(subroutes, publishedFeedRoutes(publisher, log, gzip, access))
+	}
+
 	routes := []routes{tokenRoutes(service.UserRepo(), storage, []byte(config.Auth.Secret), log, gzip, access)}
 
 	if config.Hubbub.CallbackURL != "" {
 		routes = append(routes, hubbubRoutes(service, log, gzip, access))
 	}
 
+	if publisher != nil {
+		routes = append(routes, publishRoutes(publisher, log, gzip, access))
+	}
+
 	emulatorRoutes := emulatorRoutes(ctx, service, searchProvider, feedManager, processors, config, log, gzip, access)
 	routes = append(routes, emulatorRoutes...)
 
 	routes = append(routes, mainRoutes(
 		userMiddleware(service.UserRepo(), storage, []byte(config.Auth.Secret), log),
-		featureRoutes(features, gzip, access),
-		feedsRoutes(service, feedManager, log, gzip, access),
-		tagRoutes(service.TagRepo(), log, gzip, access),
-		articlesRoutes(service, extractor, searchProvider, processors, config, log, gzip, access),
-		opmlRoutes(service, feedManager, log, gzip, access),
-		eventsRoutes(ctx, service, storage, feedManager, log),
-		userRoutes(service, []byte(config.Auth.Secret), log, gzip, access),
+		subroutes...,
 	))
 
 	r := chi.NewRouter()
@@ -148,6 +162,33 @@ func hubbubRoutes(service repo.Service, log log.Log, gzip, access mw) routes {
 	}}
 }
 
+func subscriptionRoutes(service repo.Service, log log.Log, gzip, access mw) routes {
+	return routes{path: "/subscription", route: func(r chi.Router) {
+		r.Use(timeout(5*time.Second), gzip, access, adminValidator)
+		r.Get("/", listSubscriptions(service, log))
+	}}
+}
+
+func publishRoutes(publisher *readeef.Publisher, log log.Log, gzip, access mw) routes {
+	return routes{path: "/publish", route: func(r chi.Router) {
+		r.Use(timeout(10*time.Second), gzip, access)
+		r.Post("/hub", publisherHub(publisher, log))
+
+		r.Route("/{login}", func(r chi.Router) {
+			r.Get("/favorite", publishedFeed(publisher, readeef.PublishedFavorites, log))
+			r.Get("/tag/{id:[0-9]+}", publishedFeed(publisher, readeef.PublishedTag, log))
+			r.Get("/filter/{id:[0-9]+}", publishedFeed(publisher, readeef.PublishedFilter, log))
+		})
+	}}
+}
+
+func publishedFeedRoutes(publisher *readeef.Publisher, log log.Log, gzip, access mw) routes {
+	return routes{path: "/published", route: func(r chi.Router) {
+		r.Use(timeout(5*time.Second), gzip, access)
+		r.Get("/", listPublishedFeeds(publisher, log))
+	}}
+}
+
 func emulatorRoutes(
 	ctx context.Context,
 	service repo.Service,
@@ -242,34 +283,49 @@ func feedsRoutes(service repo.Service, feedManager *readeef.FeedManager, log log
 	return routes{path: "/feed", route: func(r chi.Router) {
 		feedRepo := service.FeedRepo()
 		r.Use(gzip, access)
-		r.With(timeout(5*time.Second)).Get("/", listFeeds(feedRepo, log))
+		r.With(timeout(5*time.Second)).Get("/", listFeeds(feedRepo, feedManager, log))
 		r.With(timeout(15*time.Second)).Post("/", addFeed(feedRepo, feedManager))
+		r.With(timeout(15*time.Second)).Post("/scrape", addScrapedFeed(feedRepo, feedManager))
 
 		r.With(timeout(30*time.Second)).Get("/discover", discoverFeeds(feedRepo, feedManager, log))
 
 		r.Route("/{feedID:[0-9]+}", func(r chi.Router) {
 			r.Use(feedContext(service.FeedRepo(), log))
-			r.Use(timeout(5 * time.Second))
 
-			r.Delete("/", deleteFeed(feedRepo, feedManager, log))
+			r.With(timeout(5*time.Second)).Delete("/", deleteFeed(feedRepo, feedManager, log))
+
+			r.With(timeout(5*time.Second)).Get("/icon", getFeedIcon(service.FeedImageRepo(), log))
 
-			r.Get("/tags", getFeedTags(service.TagRepo(), log))
-			r.Put("/tags", setFeedTags(feedRepo, log))
+			r.With(timeout(5*time.Second)).Get("/tags", getFeedTags(service.TagRepo(), log))
+			r.With(timeout(5*time.Second)).Put("/tags", setFeedTags(feedRepo, log))
+
+			r.With(timeout(time.Minute)).Post("/refresh", refreshFeed(feedManager, log))
+			r.With(timeout(time.Minute)).Get("/diagnostics", feedDiagnostics(feedManager))
+			r.With(timeout(5*time.Second)).Get("/updates", getFeedUpdates(service.FeedUpdateRepo(), log))
 
 		})
 	}}
 }
 
-func tagRoutes(repo repo.Tag, log log.Log, gzip, access mw) routes {
+func tagRoutes(service repo.Service, feedManager *readeef.FeedManager, log log.Log, gzip, access mw) routes {
 	return routes{path: "/tag", route: func(r chi.Router) {
-		r.Use(timeout(5*time.Second), gzip, access)
-		r.Get("/", listTags(repo, log))
-		r.Get("/feedIDs", getTagsFeedIDs(repo, log))
+		repo := service.TagRepo()
+
+		r.Use(gzip, access)
+		r.With(timeout(5*time.Second)).Get("/", listTags(repo, log))
+		r.With(timeout(5*time.Second)).Get("/feedIDs", getTagsFeedIDs(repo, log))
+		r.With(timeout(5*time.Second)).Put("/order", setTagOrder(repo, log))
 
 		r.Route("/{tagID:[0-9]+}", func(r chi.Router) {
 			r.Use(tagContext(repo, log))
 
-			r.Get("/feedIDs", getTagFeedIDs(repo, log))
+			r.With(timeout(5*time.Second)).Patch("/", renameTag(repo, log))
+			r.With(timeout(5*time.Second)).Delete("/", deleteTag(repo, log))
+			r.With(timeout(5*time.Second)).Get("/feedIDs", getTagFeedIDs(repo, log))
+			r.With(timeout(5*time.Second)).Post("/merge", mergeTags(repo, log))
+			r.With(timeout(5*time.Second)).Put("/parent", setTagParent(repo, log))
+
+			r.With(timeout(2*time.Minute)).Post("/refresh", refreshTag(service.FeedRepo(), feedManager, log))
 		})
 	}}
 }
@@ -312,12 +368,15 @@ func articlesRoutes(
 
 			r.Get("/", getArticle)
 			if extractor != nil {
-				r.Get("/format", formatArticle(service.ExtractRepo(), extractor, processors, log))
+				r.Get("/format", formatArticle(service.ExtractRepo(), service.FeedRepo(), extractor, processors, []byte(config.Auth.Secret), log))
 			}
 			r.Post("/read", articleStateChange(articleRepo, read, log))
 			r.Delete("/read", articleStateChange(articleRepo, read, log))
 			r.Post("/favorite", articleStateChange(articleRepo, favorite, log))
 			r.Delete("/favorite", articleStateChange(articleRepo, favorite, log))
+
+			r.Get("/revisions", getArticleRevisions(service.ArticleRevisionRepo(), processors, log))
+			r.Get("/revisions/{revisionID:[0-9]+}/diff", getArticleRevisionDiff(service.ArticleRevisionRepo(), processors, log))
 		})
 
 		r.Route("/favorite", func(r chi.Router) {
@@ -378,7 +437,7 @@ func opmlRoutes(service repo.Service, feedManager *readeef.FeedManager, log log.
 	return routes{path: "/opml", route: func(r chi.Router) {
 		r.Use(gzip, access)
 		r.With(timeout(10*time.Second)).Get("/", exportOPML(service, log))
-		r.With(timeout(30*time.Second)).Post("/", importOPML(service.FeedRepo(), feedManager, log))
+		r.With(timeout(30*time.Second)).Post("/", importOPML(service.FeedRepo(), service.TagRepo(), feedManager, log))
 	}}
 }
 
@@ -313,12 +313,14 @@ func getIDs(
 
 func formatArticle(
 	repo repo.Extract,
+	feedRepo repo.Feed,
 	extractor extract.Generator,
 	processors []processor.Article,
+	secret []byte,
 	log log.Log,
 ) http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
-		_, stop := userFromRequest(w, r)
+		user, stop := userFromRequest(w, r)
 		if stop {
 			return
 		}
@@ -328,7 +330,21 @@ func formatArticle(
 			return
 		}
 
-		extract, err := extract.Get(article, repo, extractor, processors)
+		feed, err := feedRepo.Get(article.FeedID, user)
+		if err != nil {
+			fatal(w, log, "Error getting article feed: %+v", err)
+			return
+		}
+
+		auth, err := feed.Auth(secret)
+		if err != nil {
+			fatal(w, log, "Error getting feed auth: %+v", err)
+			return
+		}
+
+		auth = auth.ForLink(feed.Link, article.Link)
+
+		extract, err := extract.Get(article, auth, repo, extractor, processors)
 		if err != nil {
 			fatal(w, log, "Error getting article extract: %+v", err)
 			return
@@ -561,6 +577,14 @@ func articleQueryOptions(w http.ResponseWriter, r *http.Request, articlesLimit i
 		o = append(o, content.IDs(ids))
 	}
 
+	if authors, ok := query["author"]; ok {
+		o = append(o, content.Authors(authors))
+	}
+
+	if categories, ok := query["category"]; ok {
+		o = append(o, content.Categories(categories))
+	}
+
 	if _, ok := query["unreadOnly"]; ok {
 		o = append(o, content.UnreadOnly)
 	}
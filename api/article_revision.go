@@ -0,0 +1,89 @@
+package api
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/go-chi/chi"
+	"github.com/urandom/readeef/content"
+	"github.com/urandom/readeef/content/processor"
+	"github.com/urandom/readeef/content/repo"
+	"github.com/urandom/readeef/log"
+)
+
+func getArticleRevisions(repo repo.ArticleRevision, processors []processor.Article, log log.Log) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		article, stop := articleFromRequest(w, r)
+		if stop {
+			return
+		}
+
+		revisions, err := repo.ForArticle(article)
+		if err != nil {
+			fatal(w, log, "Error getting article revisions: %+v", err)
+			return
+		}
+
+		args{"revisions": processRevisions(article, revisions, processors)}.WriteJSON(w)
+	}
+}
+
+// getArticleRevisionDiff returns the changes made to the article after the
+// revision, up to the next revision or to its current content.
+func getArticleRevisionDiff(repo repo.ArticleRevision, processors []processor.Article, log log.Log) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		article, stop := articleFromRequest(w, r)
+		if stop {
+			return
+		}
+
+		id, err := strconv.ParseInt(chi.URLParam(r, "revisionID"), 10, 64)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+
+		revisions, err := repo.ForArticle(article)
+		if err != nil {
+			fatal(w, log, "Error getting article revisions: %+v", err)
+			return
+		}
+
+		revisions = append(processRevisions(article, revisions, processors), article.Revision())
+
+		for i := 0; i < len(revisions)-1; i++ {
+			if revisions[i].ID == content.ArticleRevisionID(id) {
+				args{"revision": revisions[i], "diff": revisions[i].Diff(revisions[i+1])}.WriteJSON(w)
+				return
+			}
+		}
+
+		http.Error(w, "Not found", http.StatusNotFound)
+	}
+}
+
+// processRevisions runs the article processors over the revisions, so that
+// they can be compared with the processed article.
+func processRevisions(article content.Article, revisions []content.ArticleRevision, processors []processor.Article) []content.ArticleRevision {
+	if len(processors) == 0 {
+		return revisions
+	}
+
+	articles := make([]content.Article, len(revisions))
+	for i, r := range revisions {
+		articles[i] = content.Article{
+			ID: article.ID, FeedID: article.FeedID, Link: article.Link,
+			Title: r.Title, Description: r.Description, Summary: r.Summary,
+		}
+	}
+
+	articles = processor.Articles(processors).Process(articles)
+
+	processed := make([]content.ArticleRevision, len(revisions))
+	for i, r := range revisions {
+		r.Title, r.Description, r.Summary = articles[i].Title, articles[i].Description, articles[i].Summary
+		processed[i] = r
+	}
+
+	return processed
+}
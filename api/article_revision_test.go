@@ -0,0 +1,161 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/pkg/errors"
+	"github.com/urandom/readeef/content"
+	"github.com/urandom/readeef/content/processor"
+	"github.com/urandom/readeef/content/repo/mock_repo"
+)
+
+type upperDescriptions struct{}
+
+func (p upperDescriptions) ProcessArticles(articles []content.Article) []content.Article {
+	for i := range articles {
+		articles[i].Description = strings.ToUpper(articles[i].Description)
+	}
+
+	return articles
+}
+
+var (
+	revisionDate = time.Date(2018, time.March, 1, 12, 0, 0, 0, time.UTC)
+	revisions    = []content.ArticleRevision{
+		{ID: 1, ArticleID: 1, Date: revisionDate, Title: "First", Description: "first text"},
+		{ID: 2, ArticleID: 1, Date: revisionDate.Add(time.Hour), Title: "Second", Description: "second text"},
+	}
+	revisedArticle = content.Article{ID: 1, FeedID: 1, Link: "http://sugr.org/a/1", Title: "Third", Description: "THIRD TEXT",
+		Updated: true, UpdateDate: &revisionDate}
+)
+
+func Test_getArticleRevisions(t *testing.T) {
+	tests := []struct {
+		name       string
+		noArticle  bool
+		revisions  []content.ArticleRevision
+		err        error
+		processors []processor.Article
+		want       []content.ArticleRevision
+		code       int
+	}{
+		{name: "no article", noArticle: true, code: http.StatusBadRequest},
+		{name: "error", err: errors.New("err"), code: http.StatusInternalServerError},
+		{name: "no revisions", code: http.StatusOK},
+		{name: "revisions", revisions: revisions, want: revisions, code: http.StatusOK},
+		{name: "processed", revisions: revisions, processors: []processor.Article{upperDescriptions{}}, want: []content.ArticleRevision{
+			{ID: 1, ArticleID: 1, Date: revisionDate, Title: "First", Description: "FIRST TEXT"},
+			{ID: 2, ArticleID: 1, Date: revisionDate.Add(time.Hour), Title: "Second", Description: "SECOND TEXT"},
+		}, code: http.StatusOK},
+	}
+
+	type data struct {
+		Revisions []content.ArticleRevision `json:"revisions"`
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+			repo := mock_repo.NewMockArticleRevision(ctrl)
+
+			r := httptest.NewRequest("GET", "/", nil)
+			w := httptest.NewRecorder()
+
+			if !tt.noArticle {
+				r = r.WithContext(context.WithValue(r.Context(), articleKey, revisedArticle))
+				repo.EXPECT().ForArticle(revisedArticle).Return(append([]content.ArticleRevision{}, tt.revisions...), tt.err)
+			}
+
+			getArticleRevisions(repo, tt.processors, logger).ServeHTTP(w, r)
+
+			if tt.code != w.Code {
+				t.Errorf("getArticleRevisions() code = %v, want %v", w.Code, tt.code)
+				return
+			}
+
+			if tt.code != http.StatusOK {
+				return
+			}
+
+			var got data
+			if err := json.Unmarshal(w.Body.Bytes(), &got); err != nil {
+				t.Errorf("getArticleRevisions() body = %s, error = %+v", w.Body, err)
+				return
+			}
+
+			if len(got.Revisions) != 0 || len(tt.want) != 0 {
+				if !reflect.DeepEqual(got.Revisions, tt.want) {
+					t.Errorf("getArticleRevisions() got = %v, want = %v", got.Revisions, tt.want)
+				}
+			}
+		})
+	}
+}
+
+func Test_getArticleRevisionDiff(t *testing.T) {
+	tests := []struct {
+		name       string
+		revisionID string
+		err        error
+		processors []processor.Article
+		want       content.ArticleRevision
+		wantTo     content.ArticleRevision
+		code       int
+	}{
+		{name: "error", revisionID: "1", err: errors.New("err"), code: http.StatusInternalServerError},
+		{name: "not found", revisionID: "3", code: http.StatusNotFound},
+		{name: "next revision", revisionID: "1", want: revisions[0], wantTo: revisions[1], code: http.StatusOK},
+		{name: "current article", revisionID: "2", processors: []processor.Article{upperDescriptions{}},
+			want:   content.ArticleRevision{ID: 2, ArticleID: 1, Date: revisionDate.Add(time.Hour), Title: "Second", Description: "SECOND TEXT"},
+			wantTo: revisedArticle.Revision(), code: http.StatusOK},
+	}
+
+	type data struct {
+		Revision content.ArticleRevision `json:"revision"`
+		Diff     content.ArticleDiff     `json:"diff"`
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+			repo := mock_repo.NewMockArticleRevision(ctrl)
+
+			r := httptest.NewRequest("GET", "/", nil)
+			r = r.WithContext(context.WithValue(r.Context(), articleKey, revisedArticle))
+			r = addChiParam(r, "revisionID", tt.revisionID)
+			w := httptest.NewRecorder()
+
+			repo.EXPECT().ForArticle(revisedArticle).Return(append([]content.ArticleRevision{}, revisions...), tt.err)
+
+			getArticleRevisionDiff(repo, tt.processors, logger).ServeHTTP(w, r)
+
+			if tt.code != w.Code {
+				t.Errorf("getArticleRevisionDiff() code = %v, want %v", w.Code, tt.code)
+				return
+			}
+
+			if tt.code != http.StatusOK {
+				return
+			}
+
+			var got data
+			if err := json.Unmarshal(w.Body.Bytes(), &got); err != nil {
+				t.Errorf("getArticleRevisionDiff() body = %s, error = %+v", w.Body, err)
+				return
+			}
+
+			want := data{Revision: tt.want, Diff: tt.want.Diff(tt.wantTo)}
+			if !reflect.DeepEqual(got, want) {
+				t.Errorf("getArticleRevisionDiff() got = %v, want = %v", got, want)
+			}
+		})
+	}
+}
@@ -483,12 +483,14 @@ func Test_formatArticle(t *testing.T) {
 		name       string
 		noUser     bool
 		noArticle  bool
+		feedErr    error
 		extract    content.Extract
 		extractErr error
 		code       int
 	}{
 		{name: "no user", noUser: true, code: 400},
 		{name: "no article", noArticle: true, code: 400},
+		{name: "feed error", feedErr: errors.New("err"), code: 500},
 		{name: "extract error", extractErr: errors.New("err"), code: 500},
 		{name: "success", extract: content.Extract{Title: "New package updates", Content: "There have been a total of 40 package updates to date. Some of them are crucial. Others not so."}, code: 200},
 	}
@@ -502,6 +504,7 @@ func Test_formatArticle(t *testing.T) {
 			ctrl := gomock.NewController(t)
 			defer ctrl.Finish()
 			extractRepo := mock_repo.NewMockExtract(ctrl)
+			feedRepo := mock_repo.NewMockFeed(ctrl)
 			generator := NewMockGenerator(ctrl)
 			proc := NewMockArticleProcessor(ctrl)
 
@@ -524,9 +527,15 @@ func Test_formatArticle(t *testing.T) {
 					break
 				}
 
-				article = content.Article{ID: 4, Link: "http://example.com"}
+				article = content.Article{ID: 4, FeedID: 2, Link: "http://example.com"}
 				r = r.WithContext(context.WithValue(r.Context(), articleKey, article))
 
+				feedRepo.EXPECT().Get(article.FeedID, userMatcher{user}).Return(content.Feed{ID: 2}, tt.feedErr)
+
+				if tt.feedErr != nil {
+					break
+				}
+
 				extractRepo.EXPECT().Get(article).Return(tt.extract, tt.extractErr)
 
 				if tt.extractErr != nil {
@@ -537,7 +546,7 @@ func Test_formatArticle(t *testing.T) {
 				proc.EXPECT().ProcessArticles(modified).Return(modified)
 			}
 
-			formatArticle(extractRepo, generator, []processor.Article{proc}, logger).ServeHTTP(w, r)
+			formatArticle(extractRepo, feedRepo, generator, []processor.Article{proc}, []byte("secret"), logger).ServeHTTP(w, r)
 
 			if tt.code != w.Code {
 				t.Errorf("formatArticle() code = %v, want %v", w.Code, tt.code)
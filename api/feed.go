@@ -7,11 +7,13 @@ import (
 	"net/url"
 	"strconv"
 	"strings"
+	"time"
 
 	"github.com/go-chi/chi"
 	"github.com/pkg/errors"
 	"github.com/urandom/readeef/content"
 	"github.com/urandom/readeef/content/repo"
+	"github.com/urandom/readeef/feed"
 	"github.com/urandom/readeef/log"
 )
 
@@ -57,7 +59,7 @@ func feedFromRequest(w http.ResponseWriter, r *http.Request) (feed content.Feed,
 	return content.Feed{}, true
 }
 
-func listFeeds(repo repo.Feed, log log.Log) http.HandlerFunc {
+func listFeeds(repo repo.Feed, feedManager feedManager, log log.Log) http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
 		user, stop := userFromRequest(w, r)
 		if stop {
@@ -70,6 +72,12 @@ func listFeeds(repo repo.Feed, log log.Log) http.HandlerFunc {
 			return
 		}
 
+		for i := range feeds {
+			if next, ok := feedManager.NextUpdate(feeds[i]); ok {
+				feeds[i].NextUpdate = next
+			}
+		}
+
 		args{"feeds": feeds}.WriteJSON(w)
 	}
 }
@@ -85,9 +93,105 @@ func (e addFeedError) Error() string {
 }
 
 type feedManager interface {
-	AddFeedByLink(link string) (content.Feed, error)
+	AddFeedByLink(link string, auth content.FeedAuth) (content.Feed, error)
+	AddScrapedFeed(link string, rules content.ScrapeRules, auth content.FeedAuth) (content.Feed, error)
 	RemoveFeed(feed content.Feed)
-	DiscoverFeeds(link string) ([]content.Feed, error)
+	DiscoverFeeds(link string, auth content.FeedAuth) ([]content.Feed, error)
+	NextUpdate(feed content.Feed) (time.Time, bool)
+	RefreshFeed(ctx context.Context, f content.Feed) (feed.RefreshResult, error)
+	CheckFeed(f content.Feed) feed.Report
+}
+
+// feedRefresh is the outcome of a manual feed refresh.
+type feedRefresh struct {
+	FeedID      content.FeedID `json:"feedID"`
+	Updated     bool           `json:"updated"`
+	NewArticles int            `json:"newArticles"`
+	Error       string         `json:"error,omitempty"`
+}
+
+func newFeedRefresh(id content.FeedID, result feed.RefreshResult, err error) feedRefresh {
+	refresh := feedRefresh{FeedID: id, Updated: result.Updated, NewArticles: result.NewArticles}
+
+	if err == nil {
+		err = result.Err
+	}
+
+	if err != nil {
+		refresh.Error = err.Error()
+	}
+
+	return refresh
+}
+
+func refreshFeed(feedManager feedManager, log log.Log) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		f, stop := feedFromRequest(w, r)
+		if stop {
+			return
+		}
+
+		result, err := feedManager.RefreshFeed(r.Context(), f)
+		if err != nil {
+			switch err := errors.Cause(err).(type) {
+			case feed.RefreshLimitError:
+				w.Header().Set("Retry-After", strconv.Itoa(int(err.RetryAfter.Seconds())+1))
+				http.Error(w, err.Error(), http.StatusTooManyRequests)
+			default:
+				if err == feed.ErrNotScheduled {
+					http.Error(w, err.Error(), http.StatusConflict)
+				} else {
+					fatal(w, log, "Error refreshing feed: %+v", err)
+				}
+			}
+			return
+		}
+
+		refresh := newFeedRefresh(f.ID, result, nil)
+		args{"success": refresh.Error == "", "refresh": refresh}.WriteJSON(w)
+	}
+}
+
+func feedDiagnostics(feedManager feedManager) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		f, stop := feedFromRequest(w, r)
+		if stop {
+			return
+		}
+
+		report := feedManager.CheckFeed(f)
+		args{"success": report.Error == "", "diagnostics": report}.WriteJSON(w)
+	}
+}
+
+// defaultUpdatesLimit is the number of most recent feed updates returned
+// when the request has no limit.
+const defaultUpdatesLimit = 100
+
+func getFeedUpdates(repo repo.FeedUpdate, log log.Log) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		feed, stop := feedFromRequest(w, r)
+		if stop {
+			return
+		}
+
+		limit := defaultUpdatesLimit
+		if value := r.Form.Get("limit"); value != "" {
+			var err error
+			if limit, err = strconv.Atoi(value); err != nil || limit <= 0 {
+				http.Error(w, "Invalid limit", http.StatusBadRequest)
+				return
+			}
+		}
+
+		updates, err := repo.ForFeed(feed, limit)
+		if err != nil {
+			fatal(w, log, "Error getting feed updates: %+v", err)
+			return
+		}
+
+		args{"updates": updates}.WriteJSON(w)
+	}
 }
 
 func addFeed(repo repo.Feed, feedManager feedManager) http.HandlerFunc {
@@ -97,12 +201,18 @@ func addFeed(repo repo.Feed, feedManager feedManager) http.HandlerFunc {
 			return
 		}
 
+		auth, err := feedAuthFromRequest(r)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+
 		links := r.Form["link"]
 
 		errs := make([]error, 0, len(links))
 		feeds := map[string]content.Feed{}
 		for _, link := range links {
-			feed, err := addFeedByURL(link, user, repo, feedManager)
+			feed, err := addFeedByURL(link, auth, user, repo, feedManager)
 			if err == nil {
 				feeds[link] = feed
 			} else {
@@ -114,8 +224,84 @@ func addFeed(repo repo.Feed, feedManager feedManager) http.HandlerFunc {
 	}
 }
 
+// addScrapedFeed adds a feed whose articles are scraped from the html page
+// at the link, using the css selectors given in the request form.
+func addScrapedFeed(repo repo.Feed, feedManager feedManager) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		user, stop := userFromRequest(w, r)
+		if stop {
+			return
+		}
+
+		auth, err := feedAuthFromRequest(r)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+
+		rules := content.ScrapeRules{
+			Item:  r.Form.Get("item"),
+			Title: r.Form.Get("title"),
+			Link:  r.Form.Get("itemLink"),
+			Date:  r.Form.Get("date"),
+			Body:  r.Form.Get("body"),
+		}
+
+		if err := rules.Validate(); err != nil {
+			http.Error(w, "Invalid scrape rules: "+err.Error(), http.StatusBadRequest)
+			return
+		}
+
+		link := r.Form.Get("link")
+		if u, err := url.Parse(link); err != nil || !u.IsAbs() {
+			http.Error(w, "Link is not absolute", http.StatusBadRequest)
+			return
+		}
+
+		errs := []error{}
+		feeds := map[string]content.Feed{}
+		if f, err := feedManager.AddScrapedFeed(link, rules, auth); err != nil {
+			errs = append(errs, addFeedError{Link: link, Message: "adding feed to the database: " + err.Error()})
+		} else if err = repo.AttachTo(f, user); err != nil {
+			errs = append(errs, addFeedError{Link: link, Title: f.Title, Message: fmt.Sprintf("adding feed to user %s: %s", user, err.Error())})
+		} else {
+			feeds[link] = f
+		}
+
+		args{"errors": errs, "feeds": feeds, "success": len(errs) == 0}.WriteJSON(w)
+	}
+}
+
+// feedAuthFromRequest reads the credentials and headers for private feeds
+// from the request form. Headers are given in "Name: value" form.
+func feedAuthFromRequest(r *http.Request) (content.FeedAuth, error) {
+	auth := content.FeedAuth{
+		Username: r.Form.Get("username"),
+		Password: r.Form.Get("password"),
+	}
+
+	for _, header := range r.Form["header"] {
+		parts := strings.SplitN(header, ":", 2)
+		if len(parts) != 2 {
+			return auth, errors.Errorf("Invalid header '%s'", header)
+		}
+
+		if auth.Headers == nil {
+			auth.Headers = map[string]string{}
+		}
+		auth.Headers[strings.TrimSpace(parts[0])] = strings.TrimSpace(parts[1])
+	}
+
+	if err := auth.Validate(); err != nil {
+		return auth, errors.WithMessage(err, "Invalid feed auth")
+	}
+
+	return auth, nil
+}
+
 func addFeedByURL(
 	link string,
+	auth content.FeedAuth,
 	user content.User,
 	repo repo.Feed,
 	feedManager feedManager,
@@ -129,7 +315,7 @@ func addFeedByURL(
 		return content.Feed{}, addFeedError{Link: link, Message: "Link is not absolute"}
 	}
 
-	if f, err := feedManager.AddFeedByLink(link); err == nil {
+	if f, err := feedManager.AddFeedByLink(link, auth); err == nil {
 		err = repo.AttachTo(f, user)
 		if err != nil {
 			return content.Feed{}, addFeedError{Link: link, Title: f.Title, Message: fmt.Sprintf("adding feed to user %s: %s", user, err.Error())}
@@ -218,7 +404,7 @@ func discoverFeedsByQuery(query string, user content.User, repo repo.Feed, disco
 		}
 	}
 
-	feeds, err := discoverer.DiscoverFeeds(query)
+	feeds, err := discoverer.DiscoverFeeds(query, content.FeedAuth{})
 	if err != nil {
 		return nil, err
 	}
@@ -233,3 +419,41 @@ func discoverFeedsByQuery(query string, user content.User, repo repo.Feed, disco
 
 	return respFeeds, nil
 }
+
+// getFeedIcon serves the cached favicon of the feed's site, falling back to
+// a redirect to the logo advertised by the feed.
+func getFeedIcon(repo repo.FeedImage, log log.Log) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		feed, stop := feedFromRequest(w, r)
+		if stop {
+			return
+		}
+
+		favicon, err := repo.Favicon(feed)
+		if err != nil && !content.IsNoContent(err) {
+			fatal(w, log, "Error getting feed favicon: %+v", err)
+			return
+		}
+
+		if mimeType, b, err := favicon.Decode(); err == nil && content.IsRasterImage(mimeType) {
+			w.Header().Set("Content-Type", mimeType)
+			w.Header().Set("Cache-Control", "max-age=86400")
+			w.Header().Set("X-Content-Type-Options", "nosniff")
+			w.Header().Set("Content-Security-Policy", "sandbox")
+			w.Write(b)
+			return
+		}
+
+		image, err := repo.Get(feed)
+		if err != nil {
+			if content.IsNoContent(err) {
+				http.Error(w, "Not found", http.StatusNotFound)
+			} else {
+				fatal(w, log, "Error getting feed image: %+v", err)
+			}
+			return
+		}
+
+		http.Redirect(w, r, image.URL, http.StatusFound)
+	}
+}
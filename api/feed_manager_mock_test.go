@@ -5,10 +5,13 @@
 package api
 
 import (
+	context "context"
 	reflect "reflect"
+	time "time"
 
 	gomock "github.com/golang/mock/gomock"
 	content "github.com/urandom/readeef/content"
+	feed "github.com/urandom/readeef/feed"
 )
 
 // MockfeedManager is a mock of feedManager interface
@@ -35,16 +38,29 @@ func (m *MockfeedManager) EXPECT() *MockfeedManagerMockRecorder {
 }
 
 // AddFeedByLink mocks base method
-func (m *MockfeedManager) AddFeedByLink(link string) (content.Feed, error) {
-	ret := m.ctrl.Call(m, "AddFeedByLink", link)
+func (m *MockfeedManager) AddFeedByLink(link string, auth content.FeedAuth) (content.Feed, error) {
+	ret := m.ctrl.Call(m, "AddFeedByLink", link, auth)
 	ret0, _ := ret[0].(content.Feed)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // AddFeedByLink indicates an expected call of AddFeedByLink
-func (mr *MockfeedManagerMockRecorder) AddFeedByLink(link interface{}) *gomock.Call {
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AddFeedByLink", reflect.TypeOf((*MockfeedManager)(nil).AddFeedByLink), link)
+func (mr *MockfeedManagerMockRecorder) AddFeedByLink(link, auth interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AddFeedByLink", reflect.TypeOf((*MockfeedManager)(nil).AddFeedByLink), link, auth)
+}
+
+// AddScrapedFeed mocks base method
+func (m *MockfeedManager) AddScrapedFeed(link string, rules content.ScrapeRules, auth content.FeedAuth) (content.Feed, error) {
+	ret := m.ctrl.Call(m, "AddScrapedFeed", link, rules, auth)
+	ret0, _ := ret[0].(content.Feed)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// AddScrapedFeed indicates an expected call of AddScrapedFeed
+func (mr *MockfeedManagerMockRecorder) AddScrapedFeed(link, rules, auth interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AddScrapedFeed", reflect.TypeOf((*MockfeedManager)(nil).AddScrapedFeed), link, rules, auth)
 }
 
 // RemoveFeed mocks base method
@@ -58,14 +74,52 @@ func (mr *MockfeedManagerMockRecorder) RemoveFeed(feed interface{}) *gomock.Call
 }
 
 // DiscoverFeeds mocks base method
-func (m *MockfeedManager) DiscoverFeeds(link string) ([]content.Feed, error) {
-	ret := m.ctrl.Call(m, "DiscoverFeeds", link)
+func (m *MockfeedManager) DiscoverFeeds(link string, auth content.FeedAuth) ([]content.Feed, error) {
+	ret := m.ctrl.Call(m, "DiscoverFeeds", link, auth)
 	ret0, _ := ret[0].([]content.Feed)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // DiscoverFeeds indicates an expected call of DiscoverFeeds
-func (mr *MockfeedManagerMockRecorder) DiscoverFeeds(link interface{}) *gomock.Call {
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DiscoverFeeds", reflect.TypeOf((*MockfeedManager)(nil).DiscoverFeeds), link)
+func (mr *MockfeedManagerMockRecorder) DiscoverFeeds(link, auth interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DiscoverFeeds", reflect.TypeOf((*MockfeedManager)(nil).DiscoverFeeds), link, auth)
+}
+
+// NextUpdate mocks base method
+func (m *MockfeedManager) NextUpdate(feed content.Feed) (time.Time, bool) {
+	ret := m.ctrl.Call(m, "NextUpdate", feed)
+	ret0, _ := ret[0].(time.Time)
+	ret1, _ := ret[1].(bool)
+	return ret0, ret1
+}
+
+// NextUpdate indicates an expected call of NextUpdate
+func (mr *MockfeedManagerMockRecorder) NextUpdate(feed interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "NextUpdate", reflect.TypeOf((*MockfeedManager)(nil).NextUpdate), feed)
+}
+
+// RefreshFeed mocks base method
+func (m *MockfeedManager) RefreshFeed(ctx context.Context, f content.Feed) (feed.RefreshResult, error) {
+	ret := m.ctrl.Call(m, "RefreshFeed", ctx, f)
+	ret0, _ := ret[0].(feed.RefreshResult)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// RefreshFeed indicates an expected call of RefreshFeed
+func (mr *MockfeedManagerMockRecorder) RefreshFeed(ctx, f interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RefreshFeed", reflect.TypeOf((*MockfeedManager)(nil).RefreshFeed), ctx, f)
+}
+
+// CheckFeed mocks base method
+func (m *MockfeedManager) CheckFeed(f content.Feed) feed.Report {
+	ret := m.ctrl.Call(m, "CheckFeed", f)
+	ret0, _ := ret[0].(feed.Report)
+	return ret0
+}
+
+// CheckFeed indicates an expected call of CheckFeed
+func (mr *MockfeedManagerMockRecorder) CheckFeed(f interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CheckFeed", reflect.TypeOf((*MockfeedManager)(nil).CheckFeed), f)
 }
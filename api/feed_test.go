@@ -10,11 +10,13 @@ import (
 	"strconv"
 	"strings"
 	"testing"
+	"time"
 
 	"github.com/golang/mock/gomock"
 	"github.com/pkg/errors"
 	"github.com/urandom/readeef/content"
 	"github.com/urandom/readeef/content/repo/mock_repo"
+	"github.com/urandom/readeef/feed"
 )
 
 func Test_feedContext(t *testing.T) {
@@ -93,15 +95,19 @@ func Test_feedContext(t *testing.T) {
 }
 
 func Test_listFeeds(t *testing.T) {
+	next := time.Date(2018, time.March, 1, 12, 0, 0, 0, time.UTC)
+
 	tests := []struct {
 		name     string
 		hasUser  bool
 		feeds    []content.Feed
 		feedsErr error
+		want     []content.Feed
 	}{
 		{name: "no user"},
 		{name: "feed list err", hasUser: true, feedsErr: errors.New("err")},
-		{name: "feed list", hasUser: true, feeds: []content.Feed{{ID: 1}, {ID: 2, Link: "http://example.com"}}},
+		{name: "feed list", hasUser: true, feeds: []content.Feed{{ID: 1}, {ID: 2, Link: "http://example.com"}},
+			want: []content.Feed{{ID: 1, NextUpdate: next}, {ID: 2, Link: "http://example.com"}}},
 	}
 
 	type data struct {
@@ -113,6 +119,7 @@ func Test_listFeeds(t *testing.T) {
 			ctrl := gomock.NewController(t)
 			defer ctrl.Finish()
 			feedRepo := mock_repo.NewMockFeed(ctrl)
+			feedManager := NewMockfeedManager(ctrl)
 
 			r := httptest.NewRequest("GET", "/", nil)
 			w := httptest.NewRecorder()
@@ -132,10 +139,19 @@ func Test_listFeeds(t *testing.T) {
 				feedRepo.EXPECT().ForUser(userMatcher{user}).Return(tt.feeds, tt.feedsErr)
 				if tt.feedsErr != nil {
 					code = http.StatusInternalServerError
+					break
+				}
+
+				for _, f := range tt.feeds {
+					if f.ID == 1 {
+						feedManager.EXPECT().NextUpdate(f).Return(next, true)
+					} else {
+						feedManager.EXPECT().NextUpdate(f).Return(time.Time{}, false)
+					}
 				}
 			}
 
-			listFeeds(feedRepo, logger).ServeHTTP(w, r)
+			listFeeds(feedRepo, feedManager, logger).ServeHTTP(w, r)
 
 			if code != w.Code {
 				t.Errorf("listFeeds() code = %v, want %v", code, w.Code)
@@ -148,8 +164,8 @@ func Test_listFeeds(t *testing.T) {
 				return
 			}
 
-			if !reflect.DeepEqual(got.Feeds, tt.feeds) {
-				t.Errorf("listFeeds() got = %v, want %v", got.Feeds, tt.feeds)
+			if !reflect.DeepEqual(got.Feeds, tt.want) {
+				t.Errorf("listFeeds() got = %v, want %v", got.Feeds, tt.want)
 				return
 			}
 		})
@@ -161,6 +177,8 @@ func Test_addFeed(t *testing.T) {
 		name         string
 		form         url.Values
 		noUser       bool
+		invalidAuth  bool
+		auth         content.FeedAuth
 		addedFeed    []content.Feed
 		addedFeedErr []error
 		attachErr    []error
@@ -181,6 +199,29 @@ func Test_addFeed(t *testing.T) {
 			addedFeedErr: []error{nil, errors.New("err")},
 			attachErr:    []error{nil},
 		},
+		{
+			name: "with auth",
+			form: url.Values{
+				"link": []string{"http://example.com"}, "username": []string{"user"}, "password": []string{"pass"},
+				"header": []string{"X-Token: abc", "Cookie: session=1; theme=dark"},
+			},
+			auth: content.FeedAuth{Username: "user", Password: "pass", Headers: map[string]string{
+				"X-Token": "abc", "Cookie": "session=1; theme=dark",
+			}},
+			addedFeed:    []content.Feed{{ID: 1, Link: "http://example.com"}},
+			addedFeedErr: []error{nil},
+			attachErr:    []error{nil},
+		},
+		{
+			name:        "invalid header",
+			form:        url.Values{"link": []string{"http://example.com"}, "header": []string{"X-Token"}},
+			invalidAuth: true,
+		},
+		{
+			name:        "invalid header name",
+			form:        url.Values{"link": []string{"http://example.com"}, "header": []string{"X Token: abc"}},
+			invalidAuth: true,
+		},
 	}
 
 	type addErr struct {
@@ -218,10 +259,15 @@ func Test_addFeed(t *testing.T) {
 					r = r.WithContext(context.WithValue(r.Context(), userKey, user))
 				}
 
+				if tt.invalidAuth {
+					code = http.StatusBadRequest
+					break
+				}
+
 				want.Errors = []addErr{}
 				want.Feeds = map[string]content.Feed{}
 				for i, link := range tt.form["link"] {
-					feedManager.EXPECT().AddFeedByLink(link).Return(tt.addedFeed[i], tt.addedFeedErr[i])
+					feedManager.EXPECT().AddFeedByLink(link, tt.auth).Return(tt.addedFeed[i], tt.addedFeedErr[i])
 
 					if tt.addedFeedErr[i] != nil {
 						want.Errors = append(want.Errors, addErr{Link: link, Error: "adding feed to the database: " + tt.addedFeedErr[i].Error()})
@@ -260,6 +306,102 @@ func Test_addFeed(t *testing.T) {
 	}
 }
 
+func Test_addScrapedFeed(t *testing.T) {
+	rules := content.ScrapeRules{Item: "article", Title: "h2", Link: "h2 a", Date: "time", Body: ".content"}
+	form := url.Values{
+		"link": []string{"http://example.com/blog"}, "item": []string{"article"}, "title": []string{"h2"},
+		"itemLink": []string{"h2 a"}, "date": []string{"time"}, "body": []string{".content"},
+	}
+
+	tests := []struct {
+		name      string
+		form      url.Values
+		noUser    bool
+		invalid   bool
+		feed      content.Feed
+		addErr    error
+		attachErr error
+	}{
+		{name: "no user", form: form, noUser: true},
+		{name: "no item", form: url.Values{"link": []string{"http://example.com/blog"}}, invalid: true},
+		{name: "invalid selector", form: url.Values{"link": []string{"http://example.com/blog"}, "item": []string{"article["}}, invalid: true},
+		{name: "relative link", form: url.Values{"link": []string{"/blog"}, "item": []string{"article"}}, invalid: true},
+		{name: "add error", form: form, addErr: errors.New("err")},
+		{name: "attach error", form: form, feed: content.Feed{ID: 1, Link: "http://example.com/blog"}, attachErr: errors.New("err")},
+		{name: "success", form: form, feed: content.Feed{ID: 1, Link: "http://example.com/blog"}},
+	}
+
+	type addErr struct {
+		Link  string `json:"link"`
+		Title string `json:"title"`
+		Error string `json:"error"`
+	}
+	type data struct {
+		Errors  []addErr                `json:"errors"`
+		Feeds   map[string]content.Feed `json:"feeds"`
+		Success bool                    `json:"success"`
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+			feedRepo := mock_repo.NewMockFeed(ctrl)
+			feedManager := NewMockfeedManager(ctrl)
+
+			r := httptest.NewRequest("POST", "/", strings.NewReader(tt.form.Encode()))
+			r.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+			r.ParseForm()
+			w := httptest.NewRecorder()
+
+			user := content.User{Login: "test"}
+			if !tt.noUser {
+				r = r.WithContext(context.WithValue(r.Context(), userKey, user))
+			}
+
+			code := http.StatusOK
+			want := data{Errors: []addErr{}, Feeds: map[string]content.Feed{}}
+			link := tt.form.Get("link")
+			switch {
+			case tt.noUser, tt.invalid:
+				code = http.StatusBadRequest
+			case tt.addErr != nil:
+				feedManager.EXPECT().AddScrapedFeed(link, rules, content.FeedAuth{}).Return(content.Feed{}, tt.addErr)
+				want.Errors = append(want.Errors, addErr{Link: link, Error: "adding feed to the database: " + tt.addErr.Error()})
+			default:
+				feedManager.EXPECT().AddScrapedFeed(link, rules, content.FeedAuth{}).Return(tt.feed, nil)
+				feedRepo.EXPECT().AttachTo(tt.feed, userMatcher{user}).Return(tt.attachErr)
+				if tt.attachErr != nil {
+					want.Errors = append(want.Errors, addErr{Link: link, Error: "adding feed to user test: " + tt.attachErr.Error()})
+				} else {
+					want.Feeds[link] = tt.feed
+					want.Success = true
+				}
+			}
+
+			addScrapedFeed(feedRepo, feedManager).ServeHTTP(w, r)
+
+			if code != w.Code {
+				t.Errorf("addScrapedFeed() code = %v, want %v", w.Code, code)
+				return
+			}
+
+			if code != http.StatusOK {
+				return
+			}
+
+			var got data
+			if err := json.Unmarshal(w.Body.Bytes(), &got); err != nil {
+				t.Errorf("addScrapedFeed() body = %s, error = %+v", w.Body, err)
+				return
+			}
+
+			if !reflect.DeepEqual(got, want) {
+				t.Errorf("addScrapedFeed() got = %v, want = %v", got, want)
+			}
+		})
+	}
+}
+
 func Test_deleteFeed(t *testing.T) {
 	tests := []struct {
 		name      string
@@ -340,6 +482,133 @@ func Test_deleteFeed(t *testing.T) {
 	}
 }
 
+func Test_refreshFeed(t *testing.T) {
+	tests := []struct {
+		name       string
+		noFeed     bool
+		result     feed.RefreshResult
+		err        error
+		code       int
+		retryAfter string
+		want       feedRefresh
+	}{
+		{name: "no feed", noFeed: true, code: http.StatusBadRequest},
+		{name: "not scheduled", err: feed.ErrNotScheduled, code: http.StatusConflict},
+		{name: "rate limited", err: feed.RefreshLimitError{RetryAfter: 30 * time.Second}, code: http.StatusTooManyRequests, retryAfter: "31"},
+		{name: "refresh err", err: errors.New("err"), code: http.StatusInternalServerError},
+		{name: "download err", result: feed.RefreshResult{Err: errors.New("HTTP Status: 500")}, code: http.StatusOK,
+			want: feedRefresh{FeedID: 1, Error: "HTTP Status: 500"}},
+		{name: "success", result: feed.RefreshResult{Updated: true, NewArticles: 3}, code: http.StatusOK,
+			want: feedRefresh{FeedID: 1, Updated: true, NewArticles: 3}},
+	}
+
+	type data struct {
+		Success bool        `json:"success"`
+		Refresh feedRefresh `json:"refresh"`
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+			feedManager := NewMockfeedManager(ctrl)
+
+			r := httptest.NewRequest("POST", "/", nil)
+			w := httptest.NewRecorder()
+
+			if !tt.noFeed {
+				f := content.Feed{ID: 1, Link: "http://example.com"}
+				r = r.WithContext(context.WithValue(r.Context(), feedKey, f))
+
+				feedManager.EXPECT().RefreshFeed(gomock.Any(), f).Return(tt.result, tt.err)
+			}
+
+			refreshFeed(feedManager, logger).ServeHTTP(w, r)
+
+			if tt.code != w.Code {
+				t.Errorf("refreshFeed() code = %v, want %v", w.Code, tt.code)
+				return
+			}
+
+			if got := w.Header().Get("Retry-After"); got != tt.retryAfter {
+				t.Errorf("refreshFeed() Retry-After = %q, want %q", got, tt.retryAfter)
+			}
+
+			if tt.code != http.StatusOK {
+				return
+			}
+
+			var got data
+			if err := json.Unmarshal(w.Body.Bytes(), &got); err != nil {
+				t.Errorf("refreshFeed() body = %s, error = %+v", w.Body, err)
+				return
+			}
+
+			want := data{Success: tt.want.Error == "", Refresh: tt.want}
+			if !reflect.DeepEqual(got, want) {
+				t.Errorf("refreshFeed() got = %v, want = %v", got, want)
+			}
+		})
+	}
+}
+
+func Test_feedDiagnostics(t *testing.T) {
+	tests := []struct {
+		name   string
+		noFeed bool
+		report feed.Report
+		code   int
+	}{
+		{name: "no feed", noFeed: true, code: http.StatusBadRequest},
+		{name: "error", report: feed.Report{Link: "http://example.com", Status: http.StatusNotFound, Error: "HTTP Status: 404"}, code: http.StatusOK},
+		{name: "success", report: feed.Report{Link: "http://example.com", Status: http.StatusOK, Format: "rss 2.0", Articles: 2,
+			DuplicateGuids: []string{"guid"}}, code: http.StatusOK},
+	}
+
+	type data struct {
+		Success     bool        `json:"success"`
+		Diagnostics feed.Report `json:"diagnostics"`
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+			feedManager := NewMockfeedManager(ctrl)
+
+			r := httptest.NewRequest("GET", "/", nil)
+			w := httptest.NewRecorder()
+
+			if !tt.noFeed {
+				f := content.Feed{ID: 1, Link: "http://example.com"}
+				r = r.WithContext(context.WithValue(r.Context(), feedKey, f))
+
+				feedManager.EXPECT().CheckFeed(f).Return(tt.report)
+			}
+
+			feedDiagnostics(feedManager).ServeHTTP(w, r)
+
+			if tt.code != w.Code {
+				t.Errorf("feedDiagnostics() code = %v, want %v", w.Code, tt.code)
+				return
+			}
+
+			if tt.code != http.StatusOK {
+				return
+			}
+
+			var got data
+			if err := json.Unmarshal(w.Body.Bytes(), &got); err != nil {
+				t.Errorf("feedDiagnostics() body = %s, error = %+v", w.Body, err)
+				return
+			}
+
+			want := data{Success: tt.report.Error == "", Diagnostics: tt.report}
+			if !reflect.DeepEqual(got, want) {
+				t.Errorf("feedDiagnostics() got = %v, want = %v", got, want)
+			}
+		})
+	}
+}
+
 func Test_discoverFeeds(t *testing.T) {
 	tests := []struct {
 		name             string
@@ -402,7 +671,7 @@ func Test_discoverFeeds(t *testing.T) {
 					break
 				}
 
-				feedManager.EXPECT().DiscoverFeeds(query).Return(tt.discoverFeeds, tt.discoverFeedsErr)
+				feedManager.EXPECT().DiscoverFeeds(query, content.FeedAuth{}).Return(tt.discoverFeeds, tt.discoverFeedsErr)
 
 				if tt.discoverFeedsErr != nil {
 					code = http.StatusInternalServerError
@@ -431,3 +700,137 @@ func Test_discoverFeeds(t *testing.T) {
 		})
 	}
 }
+
+func Test_getFeedIcon(t *testing.T) {
+	tests := []struct {
+		name       string
+		favicon    content.Favicon
+		faviconErr error
+		image      content.FeedImage
+		imageErr   error
+		code       int
+		body       string
+		location   string
+	}{
+		{name: "favicon err", faviconErr: errors.New("err"), code: http.StatusInternalServerError},
+		{name: "favicon", favicon: content.Favicon{FeedID: 1, Data: "data:image/png;base64,AQID"}, code: http.StatusOK, body: "\x01\x02\x03"},
+		{name: "svg favicon", favicon: content.Favicon{FeedID: 1, Data: "data:image/svg+xml;base64,AQID"}, image: content.FeedImage{FeedID: 1, URL: "http://example.com/logo.png"}, code: http.StatusFound, location: "http://example.com/logo.png"},
+		{name: "logo", faviconErr: content.ErrNoContent, image: content.FeedImage{FeedID: 1, URL: "http://example.com/logo.png"}, code: http.StatusFound, location: "http://example.com/logo.png"},
+		{name: "icon not found", favicon: content.Favicon{FeedID: 1}, imageErr: content.ErrNoContent, code: http.StatusNotFound},
+		{name: "image err", faviconErr: content.ErrNoContent, imageErr: errors.New("err"), code: http.StatusInternalServerError},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+			repo := mock_repo.NewMockFeedImage(ctrl)
+
+			feed := content.Feed{ID: 1}
+			r := httptest.NewRequest("GET", "/", nil)
+			r = r.WithContext(context.WithValue(r.Context(), feedKey, feed))
+			w := httptest.NewRecorder()
+
+			repo.EXPECT().Favicon(feed).Return(tt.favicon, tt.faviconErr)
+			if tt.faviconErr == nil || content.IsNoContent(tt.faviconErr) {
+				if tt.favicon.Data == "" || tt.code == http.StatusFound {
+					repo.EXPECT().Get(feed).Return(tt.image, tt.imageErr)
+				}
+			}
+
+			getFeedIcon(repo, logger).ServeHTTP(w, r)
+
+			if tt.code != w.Code {
+				t.Errorf("getFeedIcon() code = %v, want %v", w.Code, tt.code)
+				return
+			}
+
+			if tt.body != "" && w.Body.String() != tt.body {
+				t.Errorf("getFeedIcon() body = %q, want %q", w.Body.String(), tt.body)
+			}
+
+			if tt.body != "" {
+				if got := w.Header().Get("X-Content-Type-Options"); got != "nosniff" {
+					t.Errorf("getFeedIcon() X-Content-Type-Options = %q, want nosniff", got)
+				}
+
+				if got := w.Header().Get("Content-Security-Policy"); got != "sandbox" {
+					t.Errorf("getFeedIcon() Content-Security-Policy = %q, want sandbox", got)
+				}
+			}
+
+			if tt.location != "" && w.Header().Get("Location") != tt.location {
+				t.Errorf("getFeedIcon() location = %v, want %v", w.Header().Get("Location"), tt.location)
+			}
+		})
+	}
+}
+
+func Test_getFeedUpdates(t *testing.T) {
+	date := time.Date(2018, 3, 4, 5, 6, 7, 0, time.UTC)
+	updates := []content.FeedUpdate{
+		{FeedID: 1, Date: date, Duration: time.Second, Status: http.StatusOK, Bytes: 1024, NewArticles: 2, UpdatedArticles: 1},
+		{FeedID: 1, Date: date.Add(-time.Hour), Duration: time.Minute, Error: "HTTP Status: 500", Status: http.StatusInternalServerError},
+	}
+
+	tests := []struct {
+		name    string
+		noFeed  bool
+		limit   string
+		want    int
+		updates []content.FeedUpdate
+		err     error
+		code    int
+	}{
+		{name: "no feed", noFeed: true, code: http.StatusBadRequest},
+		{name: "default limit", want: defaultUpdatesLimit, updates: updates, code: http.StatusOK},
+		{name: "limit", limit: "1", want: 1, updates: updates[:1], code: http.StatusOK},
+		{name: "invalid limit", limit: "all", code: http.StatusBadRequest},
+		{name: "negative limit", limit: "-1", code: http.StatusBadRequest},
+		{name: "error", want: defaultUpdatesLimit, err: errors.New("err"), code: http.StatusInternalServerError},
+	}
+
+	type data struct {
+		Updates []content.FeedUpdate `json:"updates"`
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+			repo := mock_repo.NewMockFeedUpdate(ctrl)
+
+			r := httptest.NewRequest("GET", "/?limit="+tt.limit, nil)
+			r.ParseForm()
+			w := httptest.NewRecorder()
+
+			if !tt.noFeed {
+				feed := content.Feed{ID: 1}
+				r = r.WithContext(context.WithValue(r.Context(), feedKey, feed))
+
+				if tt.want > 0 {
+					repo.EXPECT().ForFeed(feed, tt.want).Return(tt.updates, tt.err)
+				}
+			}
+
+			getFeedUpdates(repo, logger).ServeHTTP(w, r)
+
+			if tt.code != w.Code {
+				t.Errorf("getFeedUpdates() code = %v, want %v", w.Code, tt.code)
+				return
+			}
+
+			if tt.code != http.StatusOK {
+				return
+			}
+
+			var got data
+			if err := json.Unmarshal(w.Body.Bytes(), &got); err != nil {
+				t.Errorf("getFeedUpdates() body = %s, error = %+v", w.Body, err)
+				return
+			}
+
+			if !reflect.DeepEqual(got.Updates, tt.updates) {
+				t.Errorf("getFeedUpdates() got = %v, want = %v", got.Updates, tt.updates)
+			}
+		})
+	}
+}
@@ -0,0 +1,46 @@
+package fever
+
+import (
+	"net/http"
+	"strings"
+
+	"github.com/pkg/errors"
+	"github.com/urandom/readeef/content"
+	"github.com/urandom/readeef/content/repo"
+	"github.com/urandom/readeef/log"
+)
+
+type favicon struct {
+	Id   content.FeedID `json:"id"`
+	Data string         `json:"data"`
+}
+
+// favicons returns the cached site icons, using the feed ids as favicon ids.
+func favicons(
+	r *http.Request,
+	resp resp,
+	user content.User,
+	service repo.Service,
+	log log.Log,
+) error {
+	log.Infoln("Fetching fever favicons")
+
+	cached, err := service.FeedImageRepo().Favicons(user)
+	if err != nil {
+		return errors.WithMessage(err, "getting user feed favicons")
+	}
+
+	feverFavicons := make([]favicon, 0, len(cached))
+	for _, f := range cached {
+		// Fever expects the data uri without the 'data:' scheme.
+		feverFavicons = append(feverFavicons, favicon{Id: f.FeedID, Data: strings.TrimPrefix(f.Data, "data:")})
+	}
+
+	resp["favicons"] = feverFavicons
+
+	return nil
+}
+
+func init() {
+	actions["favicons"] = favicons
+}
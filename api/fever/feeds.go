@@ -12,6 +12,7 @@ import (
 
 type feed struct {
 	Id         content.FeedID `json:"id"`
+	FaviconId  content.FeedID `json:"favicon_id"`
 	Title      string         `json:"title"`
 	Url        string         `json:"url"`
 	SiteUrl    string         `json:"site_url"`
@@ -35,12 +36,26 @@ func feeds(
 		return errors.WithMessage(err, "getting user feeds")
 	}
 
+	favicons, err := service.FeedImageRepo().Favicons(user)
+	if err != nil {
+		return errors.WithMessage(err, "getting user feed favicons")
+	}
+
+	hasFavicon := make(map[content.FeedID]bool, len(favicons))
+	for _, f := range favicons {
+		hasFavicon[f.FeedID] = true
+	}
+
 	now := time.Now().Unix()
 	for _, f := range feeds {
 		feed := feed{
 			Id: f.ID, Title: f.Title, Url: f.Link, SiteUrl: f.SiteLink, UpdateTime: now,
 		}
 
+		if hasFavicon[f.ID] {
+			feed.FaviconId = f.ID
+		}
+
 		feverFeeds = append(feverFeeds, feed)
 	}
 
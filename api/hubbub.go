@@ -29,8 +29,17 @@ func hubbubRegistration(
 			return
 		}
 
+		mode := r.Form.Get("hub.mode")
+
 		f, err := feedRepo.Get(content.FeedID(feedID), content.User{})
 		if err != nil {
+			if content.IsNoContent(err) && mode == "unsubscribe" {
+				// Feeds that were removed, or merged into others, are
+				// not wanted anymore.
+				w.Write([]byte(r.Form.Get("hub.challenge")))
+				return
+			}
+
 			fatal(w, log, fmt.Sprintf("Error getting feed %d", feedID)+": %+v", err)
 			return
 		}
@@ -41,22 +50,44 @@ func hubbubRegistration(
 			return
 		}
 
-		log.Infoln("Receiving hubbub event " + r.Form.Get("hub.mode") + " for " + f.String())
+		log.Infoln("Receiving hubbub event " + mode + " for " + f.String())
+
+		switch mode {
+		case "subscribe", "unsubscribe", "denied":
+			topic := s.Topic
+			if topic == "" {
+				topic = f.Link
+			}
+
+			// Only the requests sent for the subscription's topic are
+			// verified, and only while they are pending.
+			if r.Form.Get("hub.topic") != topic || (mode != "denied" && s.Pending != mode) {
+				log.Printf("Rejecting hubbub event %s for subscription %s from %s", mode, s, r.RemoteAddr)
+				http.NotFound(w, r)
+				return
+			}
+		}
 
-		switch r.Form.Get("hub.mode") {
+		switch mode {
 		case "subscribe":
 			if lease, err := strconv.Atoi(r.Form.Get("hub.lease_seconds")); err == nil {
 				s.LeaseDuration = int64(lease) * int64(time.Second)
 			}
 			s.VerificationTime = time.Now()
 			s.SubscriptionFailure = false
+			s.Pending = ""
 
 			w.Write([]byte(r.Form.Get("hub.challenge")))
 		case "unsubscribe":
 			s.SubscriptionFailure = true
+			s.Pending = ""
 
 			w.Write([]byte(r.Form.Get("hub.challenge")))
 		case "denied":
+			// The feed is polled until the subscription succeeds.
+			s.SubscriptionFailure = true
+			s.Pending = ""
+
 			w.Write([]byte{})
 			log.Printf("Unable to subscribe to '%s': %s\n", r.Form.Get("hub.topic"), r.Form.Get("hub.reason"))
 		default:
@@ -70,7 +101,15 @@ func hubbubRegistration(
 				return
 			}
 
-			if pf, err := parser.ParseFeed(buf.Bytes(), parser.ParseRss2, parser.ParseAtom, parser.ParseRss1); err == nil {
+			// The push is acknowledged even when its signature is invalid,
+			// as WebSub allows, but its content is ignored.
+			if err := s.VerifySignature(r.Header, buf.Bytes()); err != nil {
+				log.Printf("Rejecting content for subscription %s from %s: %+v", s, r.RemoteAddr, err)
+				return
+			}
+
+			if pf, err := parser.ParseContent(buf.Bytes(), r.Header.Get("Content-Type"), parser.ParseJSON, parser.ParseRss2, parser.ParseAtom, parser.ParseRss1); err == nil {
+				pf.InferDates(time.Now())
 				f.Refresh(pf)
 
 				if _, err = feedRepo.Update(&f); err != nil {
@@ -91,3 +130,58 @@ func hubbubRegistration(
 		}
 	}
 }
+
+// subscriptionStatus describes the state of a hubbub subscription to the
+// administrators.
+type subscriptionStatus struct {
+	FeedID           content.FeedID             `json:"feedID"`
+	Title            string                     `json:"title"`
+	Hub              string                     `json:"hub"`
+	Topic            string                     `json:"topic"`
+	Status           content.SubscriptionStatus `json:"status"`
+	VerificationTime time.Time                  `json:"verificationTime"`
+	LeaseExpiry      time.Time                  `json:"leaseExpiry"`
+	Error            string                     `json:"error,omitempty"`
+}
+
+func listSubscriptions(service repo.Service, log log.Log) http.HandlerFunc {
+	feedRepo := service.FeedRepo()
+	subRepo := service.SubscriptionRepo()
+
+	return func(w http.ResponseWriter, r *http.Request) {
+		subscriptions, err := subRepo.All()
+		if err != nil {
+			fatal(w, log, "Error getting subscriptions: %+v", err)
+			return
+		}
+
+		now := time.Now()
+		statuses := make([]subscriptionStatus, 0, len(subscriptions))
+		for _, s := range subscriptions {
+			status := subscriptionStatus{
+				FeedID:           s.FeedID,
+				Hub:              s.Link,
+				Topic:            s.Topic,
+				Status:           s.Status(now),
+				VerificationTime: s.VerificationTime,
+				LeaseExpiry:      s.LeaseExpiry(),
+			}
+
+			f, err := feedRepo.Get(s.FeedID, content.User{})
+			if err != nil {
+				fatal(w, log, fmt.Sprintf("Error getting feed %d", s.FeedID)+": %+v", err)
+				return
+			}
+
+			status.Title = f.Title
+			status.Error = f.SubscribeError
+			if status.Topic == "" {
+				status.Topic = f.Link
+			}
+
+			statuses = append(statuses, status)
+		}
+
+		args{"subscriptions": statuses}.WriteJSON(w)
+	}
+}
@@ -1,6 +1,10 @@
 package api
 
 import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
 	"net/http"
 	"net/http/httptest"
 	"reflect"
@@ -15,6 +19,8 @@ import (
 )
 
 func Test_hubbubRegistration(t *testing.T) {
+	feed := content.Feed{ID: 12, Link: "http://sugr.org/feed"}
+
 	tests := []struct {
 		name          string
 		url           string
@@ -29,20 +35,32 @@ func Test_hubbubRegistration(t *testing.T) {
 		updateSubErr  error
 		hasFeedXML    bool
 		hasFeedXMLErr bool
+		signSecret    string
+		rejected      bool
+		notFound      bool
 		updateFeedErr error
 		response      []byte
 	}{
 		{name: "invalid url", url: "/whatever", hasURLErr: true},
 		{name: "get feed err", url: "/feed/12", feedID: 12, feedErr: errors.New("get feed err")},
 		{name: "get sub err", url: "/feed/12", feedID: 12, feed: content.Feed{ID: 12}, subErr: errors.New("get sub err")},
-		{name: "subscription", url: "/feed/12", form: "hub.mode=subscribe&hub.challenge=test", feedID: 12, feed: content.Feed{ID: 12}, sub: content.Subscription{FeedID: 12, SubscriptionFailure: true}, updateSub: content.Subscription{FeedID: 12, VerificationTime: time.Now()}, response: []byte("test")},
-		{name: "subscription with lease", url: "/feed/12", form: "hub.mode=subscribe&hub.lease_seconds=300&hub.challenge=test", feedID: 12, feed: content.Feed{ID: 12}, sub: content.Subscription{FeedID: 12, SubscriptionFailure: true}, updateSub: content.Subscription{FeedID: 12, VerificationTime: time.Now(), LeaseDuration: int64(300 * time.Second)}, response: []byte("test")},
-		{name: "unsubscribe", url: "/feed/12", form: "hub.mode=unsubscribe&hub.challenge=test", feedID: 12, feed: content.Feed{ID: 12}, sub: content.Subscription{FeedID: 12}, updateSub: content.Subscription{FeedID: 12, SubscriptionFailure: true}, response: []byte("test")},
-		{name: "denied", url: "/feed/12", form: "hub.mode=denied", feedID: 12, feed: content.Feed{ID: 12}, sub: content.Subscription{FeedID: 12}, updateSub: content.Subscription{FeedID: 12}},
-		{name: "update error", url: "/feed/12", form: "hub.mode=denied", feedID: 12, feed: content.Feed{ID: 12}, sub: content.Subscription{FeedID: 12}, updateSub: content.Subscription{FeedID: 12}, updateSubErr: errors.New("err")},
-		{name: "feed update", url: "/feed/12", form: singleAtomXML, feedID: 12, feed: content.Feed{ID: 12}, sub: content.Subscription{FeedID: 12}, hasFeedXML: true},
-		{name: "unknown feed format", url: "/feed/12", form: "not-a-feed-xml-format", feedID: 12, feed: content.Feed{ID: 12}, sub: content.Subscription{FeedID: 12}, hasFeedXML: true, hasFeedXMLErr: true},
-		{name: "feed update err", url: "/feed/12", form: singleAtomXML, feedID: 12, feed: content.Feed{ID: 12}, sub: content.Subscription{FeedID: 12}, hasFeedXML: true, updateFeedErr: errors.New("update feed err")},
+		{name: "subscription", url: "/feed/12", form: "hub.mode=subscribe&hub.topic=http://sugr.org/feed&hub.challenge=test", feedID: 12, feed: feed, sub: content.Subscription{FeedID: 12, SubscriptionFailure: true, Pending: "subscribe"}, updateSub: content.Subscription{FeedID: 12, VerificationTime: time.Now()}, response: []byte("test")},
+		{name: "subscription with lease", url: "/feed/12", form: "hub.mode=subscribe&hub.topic=http://sugr.org/feed&hub.lease_seconds=300&hub.challenge=test", feedID: 12, feed: feed, sub: content.Subscription{FeedID: 12, SubscriptionFailure: true, Pending: "subscribe"}, updateSub: content.Subscription{FeedID: 12, VerificationTime: time.Now(), LeaseDuration: int64(300 * time.Second)}, response: []byte("test")},
+		{name: "subscription with self topic", url: "/feed/12", form: "hub.mode=subscribe&hub.topic=http://sugr.org/self&hub.challenge=test", feedID: 12, feed: feed, sub: content.Subscription{FeedID: 12, Topic: "http://sugr.org/self", SubscriptionFailure: true, Pending: "subscribe"}, updateSub: content.Subscription{FeedID: 12, Topic: "http://sugr.org/self", VerificationTime: time.Now()}, response: []byte("test")},
+		{name: "subscription for other topic", url: "/feed/12", form: "hub.mode=subscribe&hub.topic=http://example.com/feed&hub.challenge=test", feedID: 12, feed: feed, sub: content.Subscription{FeedID: 12, SubscriptionFailure: true, Pending: "subscribe"}, notFound: true},
+		{name: "subscription not pending", url: "/feed/12", form: "hub.mode=subscribe&hub.topic=http://sugr.org/feed&hub.challenge=test", feedID: 12, feed: feed, sub: content.Subscription{FeedID: 12, SubscriptionFailure: true}, notFound: true},
+		{name: "unsubscribe", url: "/feed/12", form: "hub.mode=unsubscribe&hub.topic=http://sugr.org/feed&hub.challenge=test", feedID: 12, feed: feed, sub: content.Subscription{FeedID: 12, Pending: "unsubscribe"}, updateSub: content.Subscription{FeedID: 12, SubscriptionFailure: true}, response: []byte("test")},
+		{name: "unsubscribe not pending", url: "/feed/12", form: "hub.mode=unsubscribe&hub.topic=http://sugr.org/feed&hub.challenge=test", feedID: 12, feed: feed, sub: content.Subscription{FeedID: 12, Pending: "subscribe"}, notFound: true},
+		{name: "unsubscribe removed feed", url: "/feed/12", form: "hub.mode=unsubscribe&hub.topic=http://sugr.org/feed&hub.challenge=test", feedID: 12, feedErr: content.ErrNoContent, response: []byte("test")},
+		{name: "denied", url: "/feed/12", form: "hub.mode=denied&hub.topic=http://sugr.org/feed", feedID: 12, feed: feed, sub: content.Subscription{FeedID: 12}, updateSub: content.Subscription{FeedID: 12, SubscriptionFailure: true}},
+		{name: "denied for other topic", url: "/feed/12", form: "hub.mode=denied&hub.topic=http://example.com/feed", feedID: 12, feed: feed, sub: content.Subscription{FeedID: 12}, notFound: true},
+		{name: "update error", url: "/feed/12", form: "hub.mode=denied&hub.topic=http://sugr.org/feed", feedID: 12, feed: feed, sub: content.Subscription{FeedID: 12}, updateSub: content.Subscription{FeedID: 12, SubscriptionFailure: true}, updateSubErr: errors.New("err")},
+		{name: "feed update", url: "/feed/12", form: singleAtomXML, feedID: 12, feed: content.Feed{ID: 12}, sub: content.Subscription{FeedID: 12, Secret: "secret"}, hasFeedXML: true, signSecret: "secret"},
+		{name: "unknown feed format", url: "/feed/12", form: "not-a-feed-xml-format", feedID: 12, feed: content.Feed{ID: 12}, sub: content.Subscription{FeedID: 12, Secret: "secret"}, hasFeedXML: true, signSecret: "secret", hasFeedXMLErr: true},
+		{name: "feed update err", url: "/feed/12", form: singleAtomXML, feedID: 12, feed: content.Feed{ID: 12}, sub: content.Subscription{FeedID: 12, Secret: "secret"}, hasFeedXML: true, signSecret: "secret", updateFeedErr: errors.New("update feed err")},
+		{name: "unsigned feed update", url: "/feed/12", form: singleAtomXML, feedID: 12, feed: content.Feed{ID: 12}, sub: content.Subscription{FeedID: 12, Secret: "secret"}, hasFeedXML: true, rejected: true},
+		{name: "mis-signed feed update", url: "/feed/12", form: singleAtomXML, feedID: 12, feed: content.Feed{ID: 12}, sub: content.Subscription{FeedID: 12, Secret: "secret"}, hasFeedXML: true, signSecret: "other", rejected: true},
+		{name: "feed update without secret", url: "/feed/12", form: singleAtomXML, feedID: 12, feed: content.Feed{ID: 12}, sub: content.Subscription{FeedID: 12}, hasFeedXML: true, signSecret: "secret", rejected: true},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
@@ -59,6 +77,11 @@ func Test_hubbubRegistration(t *testing.T) {
 			if !tt.hasFeedXML {
 				r.Header.Set("Content-Type", "application/x-www-form-urlencoded")
 			}
+			if tt.signSecret != "" {
+				mac := hmac.New(sha256.New, []byte(tt.signSecret))
+				mac.Write([]byte(tt.form))
+				r.Header.Set("X-Hub-Signature-256", "sha256="+hex.EncodeToString(mac.Sum(nil)))
+			}
 			r.ParseForm()
 			w := httptest.NewRecorder()
 
@@ -72,7 +95,9 @@ func Test_hubbubRegistration(t *testing.T) {
 				}
 
 				feedRepo.EXPECT().Get(tt.feedID, userMatcher{content.User{}}).Return(tt.feed, tt.feedErr)
-				if tt.feedErr != nil {
+				if tt.feedErr == content.ErrNoContent {
+					break
+				} else if tt.feedErr != nil {
 					code = http.StatusInternalServerError
 					break
 				}
@@ -84,8 +109,13 @@ func Test_hubbubRegistration(t *testing.T) {
 					break
 				}
 
+				if tt.notFound {
+					code = http.StatusNotFound
+					break
+				}
+
 				if tt.hasFeedXML {
-					if !tt.hasFeedXMLErr {
+					if !tt.hasFeedXMLErr && !tt.rejected {
 						feedRepo.EXPECT().Update(gomock.Any()).Return(nil, tt.updateFeedErr)
 					}
 					break
@@ -120,6 +150,8 @@ func (u subscriptionMatcher) Matches(x interface{}) bool {
 			u.subscription.Link == subscription.Link &&
 			u.subscription.LeaseDuration == subscription.LeaseDuration &&
 			u.subscription.SubscriptionFailure == subscription.SubscriptionFailure &&
+			u.subscription.Topic == subscription.Topic &&
+			u.subscription.Pending == subscription.Pending &&
 			u.subscription.VerificationTime.Sub(subscription.VerificationTime) < time.Second
 	}
 	return false
@@ -151,3 +183,87 @@ const (
 </feed>
 `
 )
+
+func Test_listSubscriptions(t *testing.T) {
+	now := time.Now()
+	lease := int64(time.Hour)
+
+	tests := []struct {
+		name    string
+		subs    []content.Subscription
+		subsErr error
+		feeds   []content.Feed
+		feedErr error
+		want    []subscriptionStatus
+	}{
+		{name: "no subscriptions", want: []subscriptionStatus{}},
+		{name: "subscriptions error", subsErr: errors.New("err")},
+		{name: "feed error", subs: []content.Subscription{{FeedID: 1, Link: "http://hub.sugr.org"}}, feeds: []content.Feed{{ID: 1}}, feedErr: errors.New("err")},
+		{name: "subscriptions", subs: []content.Subscription{
+			{FeedID: 1, Link: "http://hub.sugr.org", Topic: "http://sugr.org/self", VerificationTime: now, LeaseDuration: lease},
+			{FeedID: 2, Link: "http://hub.sugr.org", SubscriptionFailure: true},
+		}, feeds: []content.Feed{
+			{ID: 1, Title: "Active", Link: "http://sugr.org/feed1"},
+			{ID: 2, Title: "Denied", Link: "http://sugr.org/feed2", SubscribeError: "denied"},
+		}, want: []subscriptionStatus{
+			{FeedID: 1, Title: "Active", Hub: "http://hub.sugr.org", Topic: "http://sugr.org/self", Status: content.SubscriptionActive, VerificationTime: now, LeaseExpiry: now.Add(time.Hour)},
+			{FeedID: 2, Title: "Denied", Hub: "http://hub.sugr.org", Topic: "http://sugr.org/feed2", Status: content.SubscriptionInactive, Error: "denied"},
+		}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+			service := mock_repo.NewMockService(ctrl)
+			feedRepo := mock_repo.NewMockFeed(ctrl)
+			subRepo := mock_repo.NewMockSubscription(ctrl)
+
+			service.EXPECT().FeedRepo().Return(feedRepo)
+			service.EXPECT().SubscriptionRepo().Return(subRepo)
+
+			subRepo.EXPECT().All().Return(tt.subs, tt.subsErr)
+			for _, f := range tt.feeds {
+				feedRepo.EXPECT().Get(f.ID, userMatcher{content.User{}}).Return(f, tt.feedErr)
+			}
+
+			r := httptest.NewRequest("GET", "/", nil)
+			w := httptest.NewRecorder()
+
+			listSubscriptions(service, logger).ServeHTTP(w, r)
+
+			code := http.StatusOK
+			if tt.subsErr != nil || tt.feedErr != nil {
+				code = http.StatusInternalServerError
+			}
+
+			if w.Code != code {
+				t.Errorf("listSubscriptions() code = %v, want %v", w.Code, code)
+				return
+			}
+
+			if code != http.StatusOK {
+				return
+			}
+
+			var got struct {
+				Subscriptions []subscriptionStatus `json:"subscriptions"`
+			}
+			if err := json.Unmarshal(w.Body.Bytes(), &got); err != nil {
+				t.Fatalf("listSubscriptions() body = '%s', error = %v", w.Body, err)
+			}
+
+			if len(got.Subscriptions) != len(tt.want) {
+				t.Fatalf("listSubscriptions() = %v, want %v", got.Subscriptions, tt.want)
+			}
+
+			for i := range tt.want {
+				g, want := got.Subscriptions[i], tt.want[i]
+				if g.FeedID != want.FeedID || g.Title != want.Title || g.Hub != want.Hub ||
+					g.Topic != want.Topic || g.Status != want.Status || g.Error != want.Error ||
+					!g.VerificationTime.Equal(want.VerificationTime) || !g.LeaseExpiry.Equal(want.LeaseExpiry) {
+					t.Errorf("listSubscriptions() [%d] = %v, want %v", i, g, want)
+				}
+			}
+		})
+	}
+}
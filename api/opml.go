@@ -13,6 +13,7 @@ import (
 
 func importOPML(
 	repo repo.Feed,
+	tagRepo repo.Tag,
 	feedManager feedManager,
 	log log.Log,
 ) http.HandlerFunc {
@@ -25,6 +26,12 @@ func importOPML(
 		opmlData := r.Form.Get("opml")
 		_, dryRun := r.Form["dryRun"]
 
+		formAuth, err := feedAuthFromRequest(r)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+
 		opml, err := parser.ParseOpml([]byte(opmlData))
 		if err != nil {
 			http.Error(w, "Error parsing OPML: "+err.Error(), http.StatusBadRequest)
@@ -45,12 +52,16 @@ func importOPML(
 
 		var skipped []string
 		for _, opmlFeed := range opml.Feeds {
-			if _, err := repo.FindByLink(opmlFeed.URL); err == nil {
+			// Credentials embedded in the outline url are specific to the
+			// feed, and take precedence over the ones of the form.
+			link, auth := content.SplitLinkAuth(opmlFeed.URL, formAuth)
+
+			if _, err := repo.FindByLink(link); err == nil {
 				skipped = append(skipped, opmlFeed.URL)
 				continue
 			}
 
-			discovered, err := feedManager.DiscoverFeeds(opmlFeed.URL)
+			discovered, err := feedManager.DiscoverFeeds(link, auth)
 			if err != nil {
 				skipped = append(skipped, opmlFeed.URL)
 				continue
@@ -65,7 +76,7 @@ func importOPML(
 					if dryRun {
 						feeds = append(feeds, f)
 					} else {
-						if feed, err := addFeedByURL(f.Link, user, repo, feedManager); err == nil {
+						if feed, err := addFeedByURL(f.Link, auth, user, repo, feedManager); err == nil {
 							feeds = append(feeds, feed)
 						} else {
 							fatal(w, log, "Error adding feed: %+v", err)
@@ -76,6 +87,22 @@ func importOPML(
 			}
 		}
 
+		if !dryRun {
+			// The folders are listed innermost first, so that the nested
+			// tags, which might not have any feeds of their own, are kept.
+			for _, folder := range opml.Folders {
+				err := tagRepo.SetParent(
+					content.Tag{Value: content.TagValue(folder.Tag)},
+					content.Tag{Value: content.TagValue(folder.Parent)},
+					user,
+				)
+				if err != nil && !content.IsValidationError(err) {
+					fatal(w, log, "Error nesting tags: %+v", err)
+					return
+				}
+			}
+		}
+
 		args{"feeds": feeds, "skipped": skipped}.WriteJSON(w)
 	}
 }
@@ -101,8 +128,16 @@ func exportOPML(
 			return
 		}
 
-		body := parser.OpmlBody{}
 		tagRepo := service.TagRepo()
+		userTags, err := tagRepo.ForUser(user)
+		if err != nil {
+			fatal(w, log, "Error getting user tags: %+v", err)
+			return
+		}
+
+		tree := newOpmlTagTree(userTags)
+
+		var untagged []parser.OpmlOutline
 		for _, f := range feeds {
 			tags, err := tagRepo.ForFeed(f, user)
 			if err != nil {
@@ -114,17 +149,26 @@ func exportOPML(
 			for i, t := range tags {
 				category[i] = string(t.Value)
 			}
-			body.Outline = append(body.Outline, parser.OpmlOutline{
+			outline := parser.OpmlOutline{
 				Text:     f.Title,
 				Title:    f.Title,
 				XmlUrl:   f.Link,
 				HtmlUrl:  f.SiteLink,
 				Category: strings.Join(category, ","),
 				Type:     "rss",
-			})
+			}
+
+			if len(tags) == 0 {
+				untagged = append(untagged, outline)
+			}
+
+			// Feeds are listed in the folder of each of their tags.
+			for _, t := range tags {
+				tree.addFeed(t, outline)
+			}
 		}
 
-		o.Body = body
+		o.Body = parser.OpmlBody{Outline: append(tree.outlines(), untagged...)}
 
 		if b, err := xml.MarshalIndent(o, "", "    "); err == nil {
 			args{"opml": xml.Header + string(b)}.WriteJSON(w)
@@ -133,3 +177,68 @@ func exportOPML(
 		}
 	}
 }
+
+// opmlTagTree arranges the feed outlines in folders, following the nesting
+// of the user's tags.
+type opmlTagTree struct {
+	roots    []content.Tag
+	known    map[content.TagID]bool
+	children map[content.TagID][]content.Tag
+	feeds    map[content.TagID][]parser.OpmlOutline
+}
+
+func newOpmlTagTree(tags []content.Tag) opmlTagTree {
+	tree := opmlTagTree{
+		known:    map[content.TagID]bool{},
+		children: map[content.TagID][]content.Tag{},
+		feeds:    map[content.TagID][]parser.OpmlOutline{},
+	}
+
+	for _, t := range tags {
+		tree.known[t.ID] = true
+	}
+
+	for _, t := range tags {
+		if t.ParentID != 0 && tree.known[t.ParentID] {
+			tree.children[t.ParentID] = append(tree.children[t.ParentID], t)
+		} else {
+			tree.roots = append(tree.roots, t)
+		}
+	}
+
+	return tree
+}
+
+func (tree *opmlTagTree) addFeed(tag content.Tag, outline parser.OpmlOutline) {
+	if !tree.known[tag.ID] {
+		tree.known[tag.ID] = true
+		tree.roots = append(tree.roots, tag)
+	}
+
+	tree.feeds[tag.ID] = append(tree.feeds[tag.ID], outline)
+}
+
+func (tree opmlTagTree) outlines() []parser.OpmlOutline {
+	var outlines []parser.OpmlOutline
+	for _, t := range tree.roots {
+		if folder := tree.folder(t); len(folder.Outline) > 0 {
+			outlines = append(outlines, folder)
+		}
+	}
+
+	return outlines
+}
+
+func (tree opmlTagTree) folder(tag content.Tag) parser.OpmlOutline {
+	folder := parser.OpmlOutline{Text: string(tag.Value), Title: string(tag.Value)}
+
+	for _, t := range tree.children[tag.ID] {
+		if child := tree.folder(t); len(child.Outline) > 0 {
+			folder.Outline = append(folder.Outline, child)
+		}
+	}
+
+	folder.Outline = append(folder.Outline, tree.feeds[tag.ID]...)
+
+	return folder
+}
@@ -7,6 +7,7 @@ import (
 	"net/http"
 	"net/http/httptest"
 	"net/url"
+	"reflect"
 	"strings"
 	"testing"
 
@@ -34,11 +35,12 @@ func Test_importOPML(t *testing.T) {
 		addedErrs       [][]error
 		attachErrs      [][]error
 		setUserTagsErrs [][]error
+		auth            content.FeedAuth
 	}{
-		{"no user", false, nil, 0, false, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil},
-		{"parse error", true, url.Values{"opml": []string{"not-xml"}}, 0, true, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil},
-		{"feeds err", true, url.Values{"opml": []string{singleOmplXML}}, 1, false, nil, errors.New("feeds err"), nil, nil, nil, nil, nil, nil, nil, nil},
-		{"exists", true, url.Values{"opml": []string{singleOmplXML}}, 1, false, nil, nil, []error{nil}, nil, nil, nil, nil, nil, nil, nil},
+		{"no user", false, nil, 0, false, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, content.FeedAuth{}},
+		{"parse error", true, url.Values{"opml": []string{"not-xml"}}, 0, true, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, content.FeedAuth{}},
+		{"feeds err", true, url.Values{"opml": []string{singleOmplXML}}, 1, false, nil, errors.New("feeds err"), nil, nil, nil, nil, nil, nil, nil, nil, content.FeedAuth{}},
+		{"exists", true, url.Values{"opml": []string{singleOmplXML}}, 1, false, nil, nil, []error{nil}, nil, nil, nil, nil, nil, nil, nil, content.FeedAuth{}},
 		{
 			name:            "discovered errs",
 			hasUser:         true,
@@ -192,12 +194,38 @@ func Test_importOPML(t *testing.T) {
 			attachErrs:      [][]error{{}},
 			setUserTagsErrs: nil,
 		},
+		{
+			name:            "with auth",
+			hasUser:         true,
+			form:            url.Values{"opml": []string{singleOmplXML}, "username": []string{"user"}, "password": []string{"pass"}},
+			numInput:        1,
+			hasParseErr:     false,
+			feeds:           []content.Feed{{ID: 1}},
+			feedsErr:        nil,
+			findErrs:        []error{content.ErrNoContent},
+			discovered:      [][]content.Feed{{{Link: "https://example.com"}}},
+			discoveredErrs:  []error{nil},
+			addedCalls:      [][]bool{{true}},
+			added:           [][]content.Feed{{{Link: "https://example.com"}}},
+			addedErrs:       [][]error{{nil}},
+			attachErrs:      [][]error{{nil}},
+			setUserTagsErrs: nil,
+			auth:            content.FeedAuth{Username: "user", Password: "pass"},
+		},
+		{
+			name:        "invalid auth",
+			hasUser:     true,
+			form:        url.Values{"opml": []string{singleOmplXML}, "header": []string{"no-value"}},
+			numInput:    1,
+			hasParseErr: true,
+		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
 			ctrl := gomock.NewController(t)
 			defer ctrl.Finish()
 			feedRepo := mock_repo.NewMockFeed(ctrl)
+			tagRepo := mock_repo.NewMockTag(ctrl)
 			feedManager := NewMockfeedManager(ctrl)
 
 			r := httptest.NewRequest("POST", "/", strings.NewReader(tt.form.Encode()))
@@ -234,7 +262,7 @@ func Test_importOPML(t *testing.T) {
 									total--
 								}
 
-								feedManager.EXPECT().DiscoverFeeds(link).Return(tt.discovered[i], err)
+								feedManager.EXPECT().DiscoverFeeds(link, tt.auth).Return(tt.discovered[i], err)
 							}
 						}
 
@@ -254,7 +282,7 @@ func Test_importOPML(t *testing.T) {
 											code = http.StatusInternalServerError
 										}
 									}
-									feedManager.EXPECT().AddFeedByLink(link).Return(tt.added[i][j], tt.addedErrs[i][j])
+									feedManager.EXPECT().AddFeedByLink(link, tt.auth).Return(tt.added[i][j], tt.addedErrs[i][j])
 								}
 
 								if tt.addedErrs[i][j] == nil {
@@ -276,7 +304,7 @@ func Test_importOPML(t *testing.T) {
 				}
 			}
 
-			importOPML(feedRepo, feedManager, logger).ServeHTTP(w, r)
+			importOPML(feedRepo, tagRepo, feedManager, logger).ServeHTTP(w, r)
 
 			if w.Code != code {
 				t.Errorf("importOPML() code = %v, want %v", w.Code, code)
@@ -286,6 +314,54 @@ func Test_importOPML(t *testing.T) {
 	}
 }
 
+func Test_importOPML_folders(t *testing.T) {
+	tests := []struct {
+		name      string
+		form      url.Values
+		parentErr []error
+		code      int
+	}{
+		{"dry run", url.Values{"opml": []string{nestedOpmlXML}, "dryRun": []string{""}}, nil, http.StatusOK},
+		{"nested", url.Values{"opml": []string{nestedOpmlXML}}, []error{nil, nil}, http.StatusOK},
+		{"cycle", url.Values{"opml": []string{nestedOpmlXML}}, []error{content.NewValidationError(errors.New("cycle")), nil}, http.StatusOK},
+		{"parent err", url.Values{"opml": []string{nestedOpmlXML}}, []error{errors.New("parent err")}, http.StatusInternalServerError},
+	}
+
+	folders := [][2]content.TagValue{{"Gadgets", "Tech"}, {"Tech", "News"}}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+			feedRepo := mock_repo.NewMockFeed(ctrl)
+			tagRepo := mock_repo.NewMockTag(ctrl)
+			feedManager := NewMockfeedManager(ctrl)
+
+			r := httptest.NewRequest("POST", "/", strings.NewReader(tt.form.Encode()))
+			r.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+			r.ParseForm()
+			w := httptest.NewRecorder()
+
+			user := content.User{Login: "test"}
+			r = r.WithContext(context.WithValue(r.Context(), userKey, user))
+
+			feedRepo.EXPECT().ForUser(userMatcher{user}).Return(nil, nil)
+			feedRepo.EXPECT().FindByLink("http://www.item1.com/rss").Return(content.Feed{}, nil)
+
+			for i, err := range tt.parentErr {
+				tagRepo.EXPECT().SetParent(
+					content.Tag{Value: folders[i][0]}, content.Tag{Value: folders[i][1]}, userMatcher{user},
+				).Return(err)
+			}
+
+			importOPML(feedRepo, tagRepo, feedManager, logger).ServeHTTP(w, r)
+
+			if w.Code != tt.code {
+				t.Errorf("importOPML() code = %v, want %v", w.Code, tt.code)
+			}
+		})
+	}
+}
+
 const (
 	singleOmplXML = `
 <?xml version="1.0" encoding="UTF-8"?>
@@ -313,6 +389,25 @@ const (
         <outline type="rss" text="text2" title="Item 2 title" xmlUrl="http://www.item2.com/rss" htmlUrl="http://www.item2.com" category="cat1"></outline>
     </body>
 </opml>
+`
+	nestedOpmlXML = `
+<?xml version="1.0" encoding="UTF-8"?>
+<opml version="1.1">
+    <head>
+        <title>
+			OPML title
+		</title>
+    </head>
+    <body>
+        <outline text="News">
+            <outline text="Tech">
+                <outline text="Gadgets">
+                    <outline type="rss" text="text1" title="Item 1 title" xmlUrl="http://www.item1.com/rss"></outline>
+                </outline>
+            </outline>
+        </outline>
+    </body>
+</opml>
 `
 )
 
@@ -322,11 +417,15 @@ func Test_exportOPML(t *testing.T) {
 		hasUser      bool
 		userFeeds    []content.Feed
 		userFeedsErr error
+		userTags     []content.Tag
+		userTagsErr  error
 		tags         [][]content.Tag
 		tagsErr      []error
+		folders      []parser.OpmlFolder
 	}{
 		{name: "no user", hasUser: false},
 		{name: "user feeds err", hasUser: true, userFeedsErr: errors.New("user feeds err")},
+		{name: "user tags err", hasUser: true, userTagsErr: errors.New("user tags err")},
 		{
 			name:      "feeds without tags",
 			hasUser:   true,
@@ -341,6 +440,22 @@ func Test_exportOPML(t *testing.T) {
 			tags:      [][]content.Tag{{{Value: "tag1"}, {Value: "tag2"}}, {}},
 			tagsErr:   []error{nil, errors.New("tag err")},
 		},
+		{
+			name:      "nested tags",
+			hasUser:   true,
+			userFeeds: []content.Feed{{Link: "http://example.com"}, {Link: "http://example2.com"}, {Link: "http://example3.com"}},
+			userTags: []content.Tag{
+				{ID: 1, Value: "News"}, {ID: 2, Value: "Tech", ParentID: 1},
+				{ID: 3, Value: "Gadgets", ParentID: 2}, {ID: 4, Value: "Empty", ParentID: 1},
+			},
+			tags: [][]content.Tag{
+				{{ID: 3, Value: "Gadgets"}},
+				{{ID: 2, Value: "Tech"}, {ID: 1, Value: "News"}},
+				{},
+			},
+			tagsErr: []error{nil, nil, nil},
+			folders: []parser.OpmlFolder{{Tag: "Gadgets", Parent: "Tech"}, {Tag: "Tech", Parent: "News"}},
+		},
 	}
 
 	type data struct {
@@ -367,6 +482,12 @@ func Test_exportOPML(t *testing.T) {
 
 				if tt.userFeedsErr == nil {
 					service.EXPECT().TagRepo().Return(tagRepo)
+					tagRepo.EXPECT().ForUser(userMatcher{user}).Return(tt.userTags, tt.userTagsErr)
+				}
+
+				if tt.userTagsErr != nil {
+					code = http.StatusInternalServerError
+				} else if tt.userFeedsErr == nil {
 					for i, f := range tt.userFeeds {
 						tagRepo.EXPECT().ForFeed(f, userMatcher{user}).Return(tt.tags[i], tt.tagsErr[i])
 						if tt.tagsErr[i] != nil {
@@ -412,6 +533,14 @@ func Test_exportOPML(t *testing.T) {
 						t.Errorf("exportOPML() opml.Feed = %v, want = %v", opml.Feeds[i], tt.userFeeds[i])
 						return
 					}
+
+					if len(opml.Feeds[i].Tags) != len(tt.tags[i]) {
+						t.Errorf("exportOPML() opml.Feed tags = %v, want = %v", opml.Feeds[i].Tags, tt.tags[i])
+					}
+				}
+
+				if !reflect.DeepEqual(opml.Folders, tt.folders) {
+					t.Errorf("exportOPML() opml.Folders = %v, want = %v", opml.Folders, tt.folders)
 				}
 			}
 
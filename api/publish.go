@@ -0,0 +1,116 @@
+package api
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/go-chi/chi"
+	"github.com/pkg/errors"
+	"github.com/urandom/readeef"
+	"github.com/urandom/readeef/content"
+	"github.com/urandom/readeef/log"
+)
+
+// publishedFeed serves the Atom document of a feed published by readeef.
+// The key of the feed url is checked, as the feed is available without
+// authentication.
+func publishedFeed(publisher *readeef.Publisher, kind readeef.PublishedFeedKind, log log.Log) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		pf := readeef.PublishedFeed{User: content.Login(chi.URLParam(r, "login")), Kind: kind}
+
+		if kind != readeef.PublishedFavorites {
+			var err error
+			if pf.ID, err = strconv.ParseInt(chi.URLParam(r, "id"), 10, 64); err != nil {
+				http.Error(w, err.Error(), http.StatusBadRequest)
+				return
+			}
+		}
+
+		if !publisher.ValidKey(pf, r.URL.Query().Get("key")) {
+			http.Error(w, http.StatusText(http.StatusNotFound), http.StatusNotFound)
+			return
+		}
+
+		b, err := publisher.Feed(pf)
+		if err != nil {
+			if content.IsNoContent(err) {
+				http.Error(w, http.StatusText(http.StatusNotFound), http.StatusNotFound)
+			} else {
+				fatal(w, log, "Error generating published feed: %+v", err)
+			}
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/atom+xml; charset=utf-8")
+		w.Header().Set("Link", fmt.Sprintf(`<%s>; rel="hub", <%s>; rel="self"`, publisher.HubURL(), publisher.TopicURL(pf)))
+		w.Write(b)
+	}
+}
+
+// publisherHub accepts the WebSub subscription requests for the published
+// feeds.
+func publisherHub(publisher *readeef.Publisher, log log.Log) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if err := r.ParseForm(); err != nil {
+			http.Error(w, "Error parsing form data", http.StatusBadRequest)
+			return
+		}
+
+		var lease time.Duration
+		if seconds, err := strconv.Atoi(r.Form.Get("hub.lease_seconds")); err == nil {
+			lease = time.Duration(seconds) * time.Second
+		}
+
+		log.Infoln("Receiving hub request " + r.Form.Get("hub.mode") + " for " + r.Form.Get("hub.topic"))
+
+		if err := publisher.HubRequest(
+			r.Form.Get("hub.mode"),
+			r.Form.Get("hub.topic"),
+			r.Form.Get("hub.callback"),
+			r.Form.Get("hub.secret"),
+			lease,
+		); err != nil {
+			switch errors.Cause(err) {
+			case readeef.ErrInvalidHubMode, readeef.ErrInvalidTopic, readeef.ErrInvalidCallback, readeef.ErrInvalidSecret:
+				http.Error(w, err.Error(), http.StatusBadRequest)
+			default:
+				fatal(w, log, "Error handling hub request: %+v", err)
+			}
+			return
+		}
+
+		w.WriteHeader(http.StatusAccepted)
+	}
+}
+
+type publishedFeedLink struct {
+	readeef.PublishedFeed
+	Title string `json:"title"`
+	Link  string `json:"link"`
+}
+
+// listPublishedFeeds returns the links of the feeds published for the
+// current user.
+func listPublishedFeeds(publisher *readeef.Publisher, log log.Log) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		user, stop := userFromRequest(w, r)
+		if stop {
+			return
+		}
+
+		feeds, titles, err := publisher.UserFeeds(user)
+		if err != nil {
+			fatal(w, log, "Error getting published feeds: %+v", err)
+			return
+		}
+
+		links := make([]publishedFeedLink, len(feeds))
+		for i := range feeds {
+			links[i] = publishedFeedLink{feeds[i], titles[i], publisher.TopicURL(feeds[i])}
+		}
+
+		args{"feeds": links, "hub": publisher.HubURL()}.WriteJSON(w)
+	}
+}
@@ -0,0 +1,325 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/pkg/errors"
+	"github.com/urandom/readeef"
+	"github.com/urandom/readeef/config"
+	"github.com/urandom/readeef/content"
+	"github.com/urandom/readeef/content/repo/mock_repo"
+)
+
+func newTestPublisher(service *mock_repo.MockService) *readeef.Publisher {
+	c := config.Config{}
+	c.Hubbub.CallbackURL = "http://readeef.example.com"
+	c.Auth.Secret = "secret"
+
+	return readeef.NewPublisher(service, c, http.DefaultClient, logger, "/api/v2/publish")
+}
+
+func Test_publishedFeed(t *testing.T) {
+	user := content.User{Login: "user1", ProfileData: content.ProfileData{
+		"filters": []content.Filter{{TitleTerm: "golang"}},
+	}}
+	articles := []content.Article{
+		{ID: 1, FeedID: 2, Title: "Article 1", Link: "http://sugr.org/1", Date: time.Now()},
+		{ID: 2, FeedID: 3, Title: "Article 2", Link: "http://sugr.org/2", Date: time.Now()},
+	}
+
+	tests := []struct {
+		name     string
+		kind     readeef.PublishedFeedKind
+		id       string
+		key      string
+		userErr  error
+		tag      content.Tag
+		feedIDs  []content.FeedID
+		articles []content.Article
+		code     int
+		title    string
+	}{
+		{name: "favorites", kind: readeef.PublishedFavorites, articles: articles, code: http.StatusOK, title: "Favorites of user1"},
+		{name: "tag", kind: readeef.PublishedTag, id: "4", tag: content.Tag{ID: 4, Value: "tag1"}, feedIDs: []content.FeedID{2, 3}, articles: articles, code: http.StatusOK, title: "tag1"},
+		{name: "empty tag", kind: readeef.PublishedTag, id: "4", tag: content.Tag{ID: 4, Value: "tag1"}, code: http.StatusOK, title: "tag1"},
+		{name: "filter", kind: readeef.PublishedFilter, id: strconv.FormatInt(content.Filter{TitleTerm: "golang"}.ID(), 10), articles: articles, code: http.StatusOK, title: "Filter: golang"},
+		{name: "unknown filter", kind: readeef.PublishedFilter, id: "0", code: http.StatusNotFound},
+		{name: "invalid key", kind: readeef.PublishedFavorites, key: "invalid", code: http.StatusNotFound},
+		{name: "unknown user", kind: readeef.PublishedFavorites, userErr: content.ErrNoContent, code: http.StatusNotFound},
+		{name: "user error", kind: readeef.PublishedFavorites, userErr: errors.New("err"), code: http.StatusInternalServerError},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+			service := mock_repo.NewMockService(ctrl)
+			userRepo := mock_repo.NewMockUser(ctrl)
+			tagRepo := mock_repo.NewMockTag(ctrl)
+			articleRepo := mock_repo.NewMockArticle(ctrl)
+
+			publisher := newTestPublisher(service)
+
+			pf := readeef.PublishedFeed{User: user.Login, Kind: tt.kind}
+			pf.ID, _ = strconv.ParseInt(tt.id, 10, 64)
+
+			topic, _ := url.Parse(publisher.TopicURL(pf))
+			key := topic.Query().Get("key")
+			if tt.key != "" {
+				key = tt.key
+			} else {
+				service.EXPECT().UserRepo().Return(userRepo)
+				userRepo.EXPECT().Get(user.Login).Return(user, tt.userErr)
+			}
+
+			if tt.tag.ID != 0 {
+				service.EXPECT().TagRepo().Return(tagRepo)
+				tagRepo.EXPECT().Get(tt.tag.ID, userMatcher{user}).Return(tt.tag, nil)
+				tagRepo.EXPECT().FeedIDs(tt.tag, userMatcher{user}).Return(tt.feedIDs, nil)
+			}
+
+			if tt.articles != nil {
+				service.EXPECT().ArticleRepo().Return(articleRepo)
+				articleRepo.EXPECT().ForUser(userMatcher{user}, gomock.Any()).Return(tt.articles, nil)
+			}
+
+			r := httptest.NewRequest("GET", "/?key="+key, nil)
+			r = addChiParam(r, "login", string(user.Login), "id", tt.id)
+			w := httptest.NewRecorder()
+
+			publishedFeed(publisher, tt.kind, logger).ServeHTTP(w, r)
+
+			if w.Code != tt.code {
+				t.Errorf("publishedFeed() code = %v, want %v", w.Code, tt.code)
+				return
+			}
+
+			if tt.code != http.StatusOK {
+				return
+			}
+
+			if link := w.Header().Get("Link"); !strings.Contains(link, `<http://readeef.example.com/api/v2/publish/hub>; rel="hub"`) {
+				t.Errorf("publishedFeed() Link = %v, want a hub link", link)
+			}
+
+			body := w.Body.String()
+			if !strings.Contains(body, "<title>"+tt.title+"</title>") {
+				t.Errorf("publishedFeed() body = %v, want title %v", body, tt.title)
+			}
+
+			for _, a := range tt.articles {
+				if !strings.Contains(body, "<title>"+a.Title+"</title>") {
+					t.Errorf("publishedFeed() body = %v, want article %v", body, a.Title)
+				}
+			}
+		})
+	}
+}
+
+func Test_publisherHub(t *testing.T) {
+	pf := readeef.PublishedFeed{User: "user1", Kind: readeef.PublishedTag, ID: 4}
+
+	tests := []struct {
+		name     string
+		mode     string
+		topic    string
+		callback string
+		secret   string
+		confirm  bool
+		code     int
+	}{
+		{name: "subscribe", mode: "subscribe", secret: "secret", confirm: true, code: http.StatusAccepted},
+		{name: "unsubscribe", mode: "unsubscribe", confirm: true, code: http.StatusAccepted},
+		{name: "unconfirmed", mode: "subscribe", code: http.StatusAccepted},
+		{name: "invalid mode", mode: "publish", code: http.StatusBadRequest},
+		{name: "invalid topic", mode: "subscribe", topic: "http://readeef.example.com/api/v2/publish/user1/tag/4?key=invalid", code: http.StatusBadRequest},
+		{name: "foreign topic", mode: "subscribe", topic: "http://sugr.org/feed", code: http.StatusBadRequest},
+		{name: "invalid callback", mode: "subscribe", callback: "ftp://example.com", code: http.StatusBadRequest},
+		{name: "long secret", mode: "subscribe", secret: strings.Repeat("s", 200), code: http.StatusBadRequest},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+			service := mock_repo.NewMockService(ctrl)
+			subRepo := mock_repo.NewMockSubscription(ctrl)
+
+			publisher := newTestPublisher(service)
+
+			verified := make(chan url.Values, 1)
+			callback := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				if tt.confirm {
+					w.Write([]byte(r.URL.Query().Get("hub.challenge")))
+				} else {
+					http.Error(w, "Not Found", http.StatusNotFound)
+				}
+				verified <- r.URL.Query()
+			}))
+			defer callback.Close()
+
+			topic := tt.topic
+			if topic == "" {
+				topic = publisher.TopicURL(pf)
+			}
+
+			cb := tt.callback
+			if cb == "" {
+				cb = callback.URL + "/callback?id=1"
+			}
+
+			done := make(chan content.Subscriber, 1)
+			if tt.confirm {
+				service.EXPECT().SubscriptionRepo().Return(subRepo)
+
+				store := func(s content.Subscriber) error {
+					done <- s
+					return nil
+				}
+				if tt.mode == "subscribe" {
+					subRepo.EXPECT().UpdateSubscriber(gomock.Any()).DoAndReturn(store)
+				} else {
+					subRepo.EXPECT().DeleteSubscriber(gomock.Any()).DoAndReturn(store)
+				}
+			}
+
+			form := url.Values{}
+			form.Set("hub.mode", tt.mode)
+			form.Set("hub.topic", topic)
+			form.Set("hub.callback", cb)
+			form.Set("hub.secret", tt.secret)
+			form.Set("hub.lease_seconds", "3600")
+
+			r := httptest.NewRequest("POST", "/hub", strings.NewReader(form.Encode()))
+			r.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+			w := httptest.NewRecorder()
+
+			publisherHub(publisher, logger).ServeHTTP(w, r)
+
+			if w.Code != tt.code {
+				t.Errorf("publisherHub() code = %v, want %v", w.Code, tt.code)
+				return
+			}
+
+			if tt.code != http.StatusAccepted {
+				return
+			}
+
+			select {
+			case query := <-verified:
+				if query.Get("hub.mode") != tt.mode || query.Get("hub.topic") != topic || query.Get("id") != "1" {
+					t.Errorf("publisherHub() verification query = %v", query)
+				}
+			case <-time.After(5 * time.Second):
+				t.Fatalf("publisherHub() intent not verified")
+			}
+
+			if !tt.confirm {
+				return
+			}
+
+			select {
+			case s := <-done:
+				if s.Topic != publisher.TopicURL(pf) || s.Callback != cb || s.Secret != tt.secret {
+					t.Errorf("publisherHub() subscriber = %#v", s)
+				}
+
+				if tt.mode == "subscribe" && (s.VerificationTime.IsZero() || s.LeaseDuration != int64(time.Hour)) {
+					t.Errorf("publisherHub() subscriber lease = %#v", s)
+				}
+			case <-time.After(5 * time.Second):
+				t.Fatalf("publisherHub() subscriber not stored")
+			}
+		})
+	}
+}
+
+func Test_listPublishedFeeds(t *testing.T) {
+	user := content.User{Login: "user1", ProfileData: content.ProfileData{
+		"filters": []content.Filter{{URLTerm: "sugr.org"}},
+	}}
+
+	tests := []struct {
+		name    string
+		hasUser bool
+		tags    []content.Tag
+		tagsErr error
+		want    []string
+	}{
+		{name: "no user"},
+		{name: "tags error", hasUser: true, tagsErr: errors.New("err")},
+		{name: "feeds", hasUser: true, tags: []content.Tag{{ID: 4, Value: "tag1"}}, want: []string{
+			"Favorites of user1", "tag1", "Filter: sugr.org",
+		}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+			service := mock_repo.NewMockService(ctrl)
+			tagRepo := mock_repo.NewMockTag(ctrl)
+
+			publisher := newTestPublisher(service)
+
+			r := httptest.NewRequest("GET", "/", nil)
+			w := httptest.NewRecorder()
+
+			code := http.StatusBadRequest
+			if tt.hasUser {
+				r = r.WithContext(context.WithValue(r.Context(), userKey, user))
+
+				service.EXPECT().TagRepo().Return(tagRepo)
+				tagRepo.EXPECT().ForUser(userMatcher{user}).Return(tt.tags, tt.tagsErr)
+
+				code = http.StatusOK
+				if tt.tagsErr != nil {
+					code = http.StatusInternalServerError
+				}
+			}
+
+			listPublishedFeeds(publisher, logger).ServeHTTP(w, r)
+
+			if w.Code != code {
+				t.Errorf("listPublishedFeeds() code = %v, want %v", w.Code, code)
+				return
+			}
+
+			if code != http.StatusOK {
+				return
+			}
+
+			var got struct {
+				Feeds []publishedFeedLink `json:"feeds"`
+				Hub   string              `json:"hub"`
+			}
+			if err := json.Unmarshal(w.Body.Bytes(), &got); err != nil {
+				t.Fatalf("listPublishedFeeds() body = '%s', error = %v", w.Body, err)
+			}
+
+			if got.Hub != publisher.HubURL() {
+				t.Errorf("listPublishedFeeds() hub = %v, want %v", got.Hub, publisher.HubURL())
+			}
+
+			if len(got.Feeds) != len(tt.want) {
+				t.Fatalf("listPublishedFeeds() = %v, want %v", got.Feeds, tt.want)
+			}
+
+			for i := range got.Feeds {
+				if got.Feeds[i].Title != tt.want[i] {
+					t.Errorf("listPublishedFeeds() title = %v, want %v", got.Feeds[i].Title, tt.want[i])
+				}
+
+				if _, err := publisher.ParseTopic(got.Feeds[i].Link); err != nil {
+					t.Errorf("listPublishedFeeds() link %v is invalid: %v", got.Feeds[i].Link, err)
+				}
+			}
+		})
+	}
+}
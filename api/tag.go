@@ -4,6 +4,8 @@ import (
 	"context"
 	"net/http"
 	"strconv"
+	"strings"
+	"sync"
 
 	"github.com/go-chi/chi"
 	"github.com/urandom/readeef/content"
@@ -86,6 +88,237 @@ func getTagFeedIDs(repo repo.Tag, log log.Log) http.HandlerFunc {
 	}
 }
 
+func refreshTag(repo repo.Feed, feedManager feedManager, log log.Log) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		user, stop := userFromRequest(w, r)
+		if stop {
+			return
+		}
+
+		tag, stop := tagFromRequest(w, r)
+		if stop {
+			return
+		}
+
+		feeds, err := repo.ForTag(tag, user)
+		if err != nil {
+			fatal(w, log, "Error getting tag feeds: %+v", err)
+			return
+		}
+
+		var wg sync.WaitGroup
+		refreshes := make([]feedRefresh, len(feeds))
+
+		for i := range feeds {
+			wg.Add(1)
+
+			go func(i int) {
+				defer wg.Done()
+
+				result, err := feedManager.RefreshFeed(r.Context(), feeds[i])
+				refreshes[i] = newFeedRefresh(feeds[i].ID, result, err)
+			}(i)
+		}
+
+		wg.Wait()
+
+		success := false
+		for _, refresh := range refreshes {
+			if refresh.Error == "" {
+				success = true
+				break
+			}
+		}
+
+		args{"success": success, "refreshes": refreshes}.WriteJSON(w)
+	}
+}
+
+func renameTag(repo repo.Tag, log log.Log) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		user, stop := userFromRequest(w, r)
+		if stop {
+			return
+		}
+
+		tag, stop := tagFromRequest(w, r)
+		if stop {
+			return
+		}
+
+		value := content.TagValue(strings.TrimSpace(r.Form.Get("value")))
+		if value == "" {
+			http.Error(w, "No tag value", http.StatusBadRequest)
+			return
+		}
+
+		renamed, err := repo.Rename(tag, value, user)
+		if err != nil {
+			if content.IsValidationError(err) {
+				http.Error(w, err.Error(), http.StatusBadRequest)
+			} else {
+				fatal(w, log, "Error renaming tag: %+v", err)
+			}
+			return
+		}
+
+		args{"tag": renamed}.WriteJSON(w)
+	}
+}
+
+func deleteTag(repo repo.Tag, log log.Log) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		user, stop := userFromRequest(w, r)
+		if stop {
+			return
+		}
+
+		tag, stop := tagFromRequest(w, r)
+		if stop {
+			return
+		}
+
+		if err := repo.Delete(tag, user); err != nil {
+			fatal(w, log, "Error deleting tag: %+v", err)
+			return
+		}
+
+		args{"success": true}.WriteJSON(w)
+	}
+}
+
+func mergeTags(repo repo.Tag, log log.Log) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		user, stop := userFromRequest(w, r)
+		if stop {
+			return
+		}
+
+		tag, stop := tagFromRequest(w, r)
+		if stop {
+			return
+		}
+
+		ids, err := tagIDs(r.Form["from"])
+		if err != nil || len(ids) == 0 {
+			http.Error(w, "Invalid source tags", http.StatusBadRequest)
+			return
+		}
+
+		from := make([]content.Tag, 0, len(ids))
+		for _, id := range ids {
+			if id == tag.ID {
+				http.Error(w, "Cannot merge a tag with itself", http.StatusBadRequest)
+				return
+			}
+
+			t, err := repo.Get(id, user)
+			if err != nil {
+				if content.IsNoContent(err) {
+					http.Error(w, http.StatusText(http.StatusNotFound), http.StatusNotFound)
+				} else {
+					fatal(w, log, "Error getting tag: %+v", err)
+				}
+				return
+			}
+
+			from = append(from, t)
+		}
+
+		if err := repo.Merge(from, tag, user); err != nil {
+			if content.IsValidationError(err) {
+				http.Error(w, err.Error(), http.StatusBadRequest)
+			} else {
+				fatal(w, log, "Error merging tags: %+v", err)
+			}
+			return
+		}
+
+		args{"tag": tag}.WriteJSON(w)
+	}
+}
+
+func setTagParent(repo repo.Tag, log log.Log) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		user, stop := userFromRequest(w, r)
+		if stop {
+			return
+		}
+
+		tag, stop := tagFromRequest(w, r)
+		if stop {
+			return
+		}
+
+		var parent content.Tag
+		if p := r.Form.Get("parent"); p != "" && p != "0" {
+			id, err := strconv.ParseInt(p, 10, 64)
+			if err != nil {
+				http.Error(w, err.Error(), http.StatusBadRequest)
+				return
+			}
+
+			if parent, err = repo.Get(content.TagID(id), user); err != nil {
+				if content.IsNoContent(err) {
+					http.Error(w, http.StatusText(http.StatusNotFound), http.StatusNotFound)
+				} else {
+					fatal(w, log, "Error getting tag: %+v", err)
+				}
+				return
+			}
+		}
+
+		if err := repo.SetParent(tag, parent, user); err != nil {
+			if content.IsValidationError(err) {
+				http.Error(w, err.Error(), http.StatusBadRequest)
+			} else {
+				fatal(w, log, "Error setting tag parent: %+v", err)
+			}
+			return
+		}
+
+		tag.ParentID = parent.ID
+
+		args{"tag": tag}.WriteJSON(w)
+	}
+}
+
+func setTagOrder(repo repo.Tag, log log.Log) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		user, stop := userFromRequest(w, r)
+		if stop {
+			return
+		}
+
+		ids, err := tagIDs(r.Form["id"])
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+
+		if err := repo.SetOrder(ids, user); err != nil {
+			fatal(w, log, "Error setting tag order: %+v", err)
+			return
+		}
+
+		args{"success": true}.WriteJSON(w)
+	}
+}
+
+func tagIDs(values []string) ([]content.TagID, error) {
+	ids := make([]content.TagID, 0, len(values))
+	for _, v := range values {
+		id, err := strconv.ParseInt(v, 10, 64)
+		if err != nil {
+			return nil, err
+		}
+
+		ids = append(ids, content.TagID(id))
+	}
+
+	return ids, nil
+}
+
 func getFeedTags(repo repo.Tag, log log.Log) http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
 		user, stop := userFromRequest(w, r)
@@ -235,6 +235,366 @@ func Test_getTagFeedIDs(t *testing.T) {
 	}
 }
 
+func Test_renameTag(t *testing.T) {
+	tests := []struct {
+		name      string
+		hasUser   bool
+		hasTag    bool
+		form      string
+		value     content.TagValue
+		renameErr error
+	}{
+		{"no user", false, false, "", "", nil},
+		{"no tag", true, false, "", "", nil},
+		{"no value", true, true, "value=+", "", nil},
+		{"rename", true, true, "value=+bar", "bar", nil},
+		{"rename err", true, true, "value=bar", "bar", errors.New("rename err")},
+	}
+
+	type data struct {
+		Tag content.Tag `json:"tag"`
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+			tagRepo := mock_repo.NewMockTag(ctrl)
+
+			r := httptest.NewRequest("PATCH", "/", strings.NewReader(tt.form))
+			r.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+			r.ParseForm()
+			w := httptest.NewRecorder()
+
+			code := http.StatusBadRequest
+			var want data
+			if tt.hasUser {
+				user := content.User{Login: "test"}
+				r = r.WithContext(context.WithValue(r.Context(), userKey, user))
+
+				if tt.hasTag {
+					tag := content.Tag{ID: 1, Value: "foo"}
+					r = r.WithContext(context.WithValue(r.Context(), tagKey, tag))
+
+					if tt.value != "" {
+						renamed := content.Tag{ID: 2, Value: tt.value}
+						if tt.renameErr == nil {
+							code = http.StatusOK
+							want.Tag = renamed
+						} else {
+							code = http.StatusInternalServerError
+						}
+
+						tagRepo.EXPECT().Rename(tag, tt.value, userMatcher{user}).Return(renamed, tt.renameErr)
+					}
+				}
+			}
+
+			renameTag(tagRepo, logger).ServeHTTP(w, r)
+
+			if w.Code != code {
+				t.Errorf("renameTag() code = %v, want %v", w.Code, code)
+				return
+			}
+
+			got := data{}
+			if err := json.Unmarshal(w.Body.Bytes(), &got); (err != nil) && (w.Code == http.StatusOK) {
+				t.Errorf("renameTag() body = '%s', error = %v", w.Body, err)
+				return
+			}
+
+			if !reflect.DeepEqual(got, want) {
+				t.Errorf("renameTag() got = %v, want = %v", got, want)
+			}
+		})
+	}
+}
+
+func Test_deleteTag(t *testing.T) {
+	tests := []struct {
+		name      string
+		hasUser   bool
+		hasTag    bool
+		deleteErr error
+	}{
+		{"no user", false, false, nil},
+		{"no tag", true, false, nil},
+		{"delete", true, true, nil},
+		{"delete err", true, true, errors.New("delete err")},
+	}
+
+	type data struct {
+		Success bool `json:"success"`
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+			tagRepo := mock_repo.NewMockTag(ctrl)
+
+			r := httptest.NewRequest("DELETE", "/", nil)
+			w := httptest.NewRecorder()
+
+			code := http.StatusBadRequest
+			if tt.hasUser {
+				user := content.User{Login: "test"}
+				r = r.WithContext(context.WithValue(r.Context(), userKey, user))
+
+				if tt.hasTag {
+					tag := content.Tag{ID: 1, Value: "foo"}
+					r = r.WithContext(context.WithValue(r.Context(), tagKey, tag))
+
+					if tt.deleteErr == nil {
+						code = http.StatusOK
+					} else {
+						code = http.StatusInternalServerError
+					}
+
+					tagRepo.EXPECT().Delete(tag, userMatcher{user}).Return(tt.deleteErr)
+				}
+			}
+
+			deleteTag(tagRepo, logger).ServeHTTP(w, r)
+
+			if w.Code != code {
+				t.Errorf("deleteTag() code = %v, want %v", w.Code, code)
+				return
+			}
+
+			got := data{}
+			if err := json.Unmarshal(w.Body.Bytes(), &got); (err != nil) && (w.Code == http.StatusOK) {
+				t.Errorf("deleteTag() body = '%s', error = %v", w.Body, err)
+				return
+			}
+
+			want := data{Success: code == http.StatusOK}
+
+			if !reflect.DeepEqual(got, want) {
+				t.Errorf("deleteTag() got = %v, want = %v", got, want)
+			}
+		})
+	}
+}
+
+func Test_mergeTags(t *testing.T) {
+	tests := []struct {
+		name     string
+		hasUser  bool
+		hasTag   bool
+		form     string
+		from     []content.Tag
+		getErr   error
+		mergeErr error
+		code     int
+	}{
+		{"no user", false, false, "", nil, nil, nil, http.StatusBadRequest},
+		{"no tag", true, false, "", nil, nil, nil, http.StatusBadRequest},
+		{"no from", true, true, "", nil, nil, nil, http.StatusBadRequest},
+		{"invalid from", true, true, "from=foo", nil, nil, nil, http.StatusBadRequest},
+		{"self", true, true, "from=1", nil, nil, nil, http.StatusBadRequest},
+		{"unknown from", true, true, "from=2", []content.Tag{{ID: 2}}, content.ErrNoContent, nil, http.StatusNotFound},
+		{"get err", true, true, "from=2", []content.Tag{{ID: 2}}, errors.New("get err"), nil, http.StatusInternalServerError},
+		{"merge", true, true, "from=2&from=3", []content.Tag{{ID: 2, Value: "bar"}, {ID: 3, Value: "baz"}}, nil, nil, http.StatusOK},
+		{"merge err", true, true, "from=2", []content.Tag{{ID: 2, Value: "bar"}}, nil, errors.New("merge err"), http.StatusInternalServerError},
+	}
+
+	type data struct {
+		Tag content.Tag `json:"tag"`
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+			tagRepo := mock_repo.NewMockTag(ctrl)
+
+			r := httptest.NewRequest("POST", "/", strings.NewReader(tt.form))
+			r.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+			r.ParseForm()
+			w := httptest.NewRecorder()
+
+			tag := content.Tag{ID: 1, Value: "foo"}
+			if tt.hasUser {
+				user := content.User{Login: "test"}
+				r = r.WithContext(context.WithValue(r.Context(), userKey, user))
+
+				if tt.hasTag {
+					r = r.WithContext(context.WithValue(r.Context(), tagKey, tag))
+
+					for _, from := range tt.from {
+						tagRepo.EXPECT().Get(from.ID, userMatcher{user}).Return(from, tt.getErr)
+					}
+
+					if tt.getErr == nil && len(tt.from) > 0 {
+						tagRepo.EXPECT().Merge(tt.from, tag, userMatcher{user}).Return(tt.mergeErr)
+					}
+				}
+			}
+
+			mergeTags(tagRepo, logger).ServeHTTP(w, r)
+
+			if w.Code != tt.code {
+				t.Errorf("mergeTags() code = %v, want %v", w.Code, tt.code)
+				return
+			}
+
+			got := data{}
+			if err := json.Unmarshal(w.Body.Bytes(), &got); (err != nil) && (w.Code == http.StatusOK) {
+				t.Errorf("mergeTags() body = '%s', error = %v", w.Body, err)
+				return
+			}
+
+			var want data
+			if tt.code == http.StatusOK {
+				want.Tag = tag
+			}
+
+			if !reflect.DeepEqual(got, want) {
+				t.Errorf("mergeTags() got = %v, want = %v", got, want)
+			}
+		})
+	}
+}
+
+func Test_setTagParent(t *testing.T) {
+	tests := []struct {
+		name      string
+		hasUser   bool
+		hasTag    bool
+		form      string
+		parent    content.Tag
+		getErr    error
+		parentErr error
+		code      int
+	}{
+		{"no user", false, false, "", content.Tag{}, nil, nil, http.StatusBadRequest},
+		{"no tag", true, false, "", content.Tag{}, nil, nil, http.StatusBadRequest},
+		{"invalid parent", true, true, "parent=foo", content.Tag{}, nil, nil, http.StatusBadRequest},
+		{"unknown parent", true, true, "parent=2", content.Tag{ID: 2}, content.ErrNoContent, nil, http.StatusNotFound},
+		{"get err", true, true, "parent=2", content.Tag{ID: 2}, errors.New("get err"), nil, http.StatusInternalServerError},
+		{"top level", true, true, "parent=0", content.Tag{}, nil, nil, http.StatusOK},
+		{"nest", true, true, "parent=2", content.Tag{ID: 2, Value: "bar"}, nil, nil, http.StatusOK},
+		{"cycle", true, true, "parent=2", content.Tag{ID: 2, Value: "bar"}, nil, content.NewValidationError(errors.New("cycle")), http.StatusBadRequest},
+		{"parent err", true, true, "parent=2", content.Tag{ID: 2, Value: "bar"}, nil, errors.New("parent err"), http.StatusInternalServerError},
+	}
+
+	type data struct {
+		Tag content.Tag `json:"tag"`
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+			tagRepo := mock_repo.NewMockTag(ctrl)
+
+			r := httptest.NewRequest("PUT", "/", strings.NewReader(tt.form))
+			r.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+			r.ParseForm()
+			w := httptest.NewRecorder()
+
+			tag := content.Tag{ID: 1, Value: "foo"}
+			if tt.hasUser {
+				user := content.User{Login: "test"}
+				r = r.WithContext(context.WithValue(r.Context(), userKey, user))
+
+				if tt.hasTag {
+					r = r.WithContext(context.WithValue(r.Context(), tagKey, tag))
+
+					if tt.parent.ID != 0 {
+						tagRepo.EXPECT().Get(tt.parent.ID, userMatcher{user}).Return(tt.parent, tt.getErr)
+					}
+
+					if tt.getErr == nil && tt.form != "parent=foo" {
+						tagRepo.EXPECT().SetParent(tag, tt.parent, userMatcher{user}).Return(tt.parentErr)
+					}
+				}
+			}
+
+			setTagParent(tagRepo, logger).ServeHTTP(w, r)
+
+			if w.Code != tt.code {
+				t.Errorf("setTagParent() code = %v, want %v", w.Code, tt.code)
+				return
+			}
+
+			got := data{}
+			if err := json.Unmarshal(w.Body.Bytes(), &got); (err != nil) && (w.Code == http.StatusOK) {
+				t.Errorf("setTagParent() body = '%s', error = %v", w.Body, err)
+				return
+			}
+
+			var want data
+			if tt.code == http.StatusOK {
+				want.Tag = tag
+				want.Tag.ParentID = tt.parent.ID
+			}
+
+			if !reflect.DeepEqual(got, want) {
+				t.Errorf("setTagParent() got = %v, want = %v", got, want)
+			}
+		})
+	}
+}
+
+func Test_setTagOrder(t *testing.T) {
+	tests := []struct {
+		name     string
+		hasUser  bool
+		form     string
+		ids      []content.TagID
+		orderErr error
+		code     int
+	}{
+		{"no user", false, "", nil, nil, http.StatusBadRequest},
+		{"invalid id", true, "id=2&id=foo", nil, nil, http.StatusBadRequest},
+		{"reset", true, "", []content.TagID{}, nil, http.StatusOK},
+		{"order", true, "id=3&id=1&id=2", []content.TagID{3, 1, 2}, nil, http.StatusOK},
+		{"order err", true, "id=1", []content.TagID{1}, errors.New("order err"), http.StatusInternalServerError},
+	}
+
+	type data struct {
+		Success bool `json:"success"`
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+			tagRepo := mock_repo.NewMockTag(ctrl)
+
+			r := httptest.NewRequest("PUT", "/", strings.NewReader(tt.form))
+			r.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+			r.ParseForm()
+			w := httptest.NewRecorder()
+
+			if tt.hasUser {
+				user := content.User{Login: "test"}
+				r = r.WithContext(context.WithValue(r.Context(), userKey, user))
+
+				if tt.ids != nil {
+					tagRepo.EXPECT().SetOrder(tt.ids, userMatcher{user}).Return(tt.orderErr)
+				}
+			}
+
+			setTagOrder(tagRepo, logger).ServeHTTP(w, r)
+
+			if w.Code != tt.code {
+				t.Errorf("setTagOrder() code = %v, want %v", w.Code, tt.code)
+				return
+			}
+
+			got := data{}
+			if err := json.Unmarshal(w.Body.Bytes(), &got); (err != nil) && (w.Code == http.StatusOK) {
+				t.Errorf("setTagOrder() body = '%s', error = %v", w.Body, err)
+				return
+			}
+
+			want := data{Success: tt.code == http.StatusOK}
+
+			if !reflect.DeepEqual(got, want) {
+				t.Errorf("setTagOrder() got = %v, want = %v", got, want)
+			}
+		})
+	}
+}
+
 func Test_getFeedTags(t *testing.T) {
 	tests := []struct {
 		name    string
@@ -28,8 +28,9 @@ type headline struct {
 	Content   string            `json:"content,omitempty"`
 	FeedTitle string            `json:"feed_title"`
 
-	Tags   []string `json:"tags,omitempty"`
-	Labels []string `json:"labels,omitempty"`
+	Tags        []string     `json:"tags,omitempty"`
+	Labels      []string     `json:"labels,omitempty"`
+	Attachments []attachment `json:"attachments,omitempty"`
 }
 
 type headlinesHeader struct {
@@ -52,7 +53,17 @@ type article struct {
 	FeedId    string `json:"feed_id"`
 	FeedTitle string `json:"feed_title"`
 
-	Labels []string `json:"labels,omitempty"`
+	Labels      []string     `json:"labels,omitempty"`
+	Attachments []attachment `json:"attachments"`
+}
+
+type attachment struct {
+	Id          string `json:"id"`
+	ContentUrl  string `json:"content_url"`
+	ContentType string `json:"content_type"`
+	PostId      string `json:"post_id"`
+	Title       string `json:"title"`
+	Duration    string `json:"duration"`
 }
 
 func registerArticleActions(searchProvider search.Provider, processors []processor.Article) {
@@ -195,7 +206,7 @@ func getHeadlines(
 			firstID = articles[0].ID
 		}
 
-		headlines := headlinesFromArticles(articles, feedTitle, req.ShowContent, req.ShowExcerpt)
+		headlines := headlinesFromArticles(articles, feedTitle, req.ShowContent, req.ShowExcerpt, req.IncludeAttachments)
 		if req.IncludeHeader {
 			header := headlinesHeader{Id: req.FeedId, FirstId: firstID, IsCat: req.IsCat}
 			hContent := headlinesHeaderContent{}
@@ -354,7 +365,10 @@ func getArticle(
 			Link:      a.Link,
 			FeedId:    strconv.FormatInt(int64(a.FeedID), 10),
 			FeedTitle: title,
+			Author:    a.Author,
 			Content:   a.Description,
+
+			Attachments: attachmentsFromArticle(a),
 		}
 
 		cContent = append(cContent, h)
@@ -363,7 +377,7 @@ func getArticle(
 	return cContent, nil
 }
 
-func headlinesFromArticles(articles []content.Article, feedTitle string, content, excerpt bool) headlinesContent {
+func headlinesFromArticles(articles []content.Article, feedTitle string, content, excerpt, attachments bool) headlinesContent {
 	c := headlinesContent{}
 	for _, a := range articles {
 		title := feedTitle
@@ -377,6 +391,7 @@ func headlinesFromArticles(articles []content.Article, feedTitle string, content
 			Link:      a.Link,
 			FeedId:    strconv.FormatInt(int64(a.FeedID), 10),
 			FeedTitle: title,
+			Author:    a.Author,
 		}
 
 		if content {
@@ -384,7 +399,14 @@ func headlinesFromArticles(articles []content.Article, feedTitle string, content
 		}
 
 		if excerpt {
-			excerpt := search.StripTags(a.Description)
+			// The publisher's summary makes for a better excerpt than
+			// the start of the full body, when present.
+			text := a.Summary
+			if text == "" {
+				text = a.Description
+			}
+
+			excerpt := search.StripTags(text)
 			if len(excerpt) > 100 {
 				excerpt = excerpt[:100]
 			}
@@ -392,8 +414,29 @@ func headlinesFromArticles(articles []content.Article, feedTitle string, content
 			h.Excerpt = excerpt
 		}
 
+		if attachments {
+			h.Attachments = attachmentsFromArticle(a)
+		}
+
 		c = append(c, h)
 	}
 
 	return c
 }
+
+func attachmentsFromArticle(a content.Article) []attachment {
+	attachments := make([]attachment, len(a.Enclosures))
+	postID := strconv.FormatInt(int64(a.ID), 10)
+
+	for i, e := range a.Enclosures {
+		attachments[i] = attachment{
+			Id:          postID + "-" + strconv.Itoa(i),
+			ContentUrl:  e.URL,
+			ContentType: e.MIMEType,
+			PostId:      postID,
+			Duration:    strconv.FormatInt(e.Duration, 10),
+		}
+	}
+
+	return attachments
+}
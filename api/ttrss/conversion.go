@@ -50,6 +50,8 @@ func convertRequest(in map[string]interface{}) request {
 			req.HasSandbox = parseBool(v)
 		case "include_header":
 			req.IncludeHeader = parseBool(v)
+		case "include_attachments":
+			req.IncludeAttachments = parseBool(v)
 		case "seq":
 			req.Seq = parseInt(v)
 		case "limit":
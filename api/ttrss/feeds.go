@@ -1,11 +1,13 @@
 package ttrss
 
 import (
+	"context"
 	"fmt"
 	"strconv"
 	"time"
 
 	"github.com/pkg/errors"
+	"github.com/urandom/readeef"
 	"github.com/urandom/readeef/content"
 	"github.com/urandom/readeef/content/repo"
 )
@@ -171,7 +173,29 @@ func getFeeds(req request, user content.User, service repo.Service) (interface{}
 	return fContent, nil
 }
 
-func updateFeed(req request, user content.User, service repo.Service) (interface{}, error) {
+// updateFeedTimeout limits how long the updateFeed operation waits for the
+// feed to be refreshed.
+const updateFeedTimeout = time.Minute
+
+// updateFeed refreshes the feed synchronously. Like in tt-rss, failures to
+// refresh the feed are not reported, as they end up in the feed's update
+// errors.
+func updateFeed(
+	req request,
+	user content.User,
+	feedManager *readeef.FeedManager,
+	service repo.Service,
+) (interface{}, error) {
+	f, err := service.FeedRepo().Get(req.FeedId, user)
+	if err != nil {
+		return nil, errors.WithMessage(err, "getting user feed")
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), updateFeedTimeout)
+	defer cancel()
+
+	feedManager.RefreshFeed(ctx, f)
+
 	return genericContent{Status: "OK"}, nil
 }
 
@@ -240,8 +264,27 @@ func getFeedTree(req request, user content.User, service repo.Service) (interfac
 		return nil, errors.WithMessage(err, "getting user feeds")
 	}
 
+	tags, err := service.TagRepo().ForUser(user)
+	if err != nil {
+		return nil, errors.WithMessage(err, "getting user tags")
+	}
+
 	uncat := category{Id: "CAT:0", Items: []category{}, BareId: 0, Name: "Uncategorized", Type: "category"}
-	tagCategories := map[content.Tag]category{}
+	tagCategories := map[content.TagID]*category{}
+	children := map[content.TagID][]content.TagID{}
+	var roots []content.TagID
+
+	for _, t := range tags {
+		tagCategories[t.ID] = newTagCategory(t)
+	}
+
+	for _, t := range tags {
+		if _, ok := tagCategories[t.ParentID]; ok && t.ParentID != 0 {
+			children[t.ParentID] = append(children[t.ParentID], t.ID)
+		} else {
+			roots = append(roots, t.ID)
+		}
+	}
 
 	for _, f := range feeds {
 		tags, err := service.TagRepo().ForFeed(f, user)
@@ -256,39 +299,25 @@ func getFeedTree(req request, user content.User, service repo.Service) (interfac
 
 		if len(tags) > 0 {
 			for _, t := range tags {
-				var c category
-				if cached, ok := tagCategories[t]; ok {
-					c = cached
-				} else {
-					c = category{
-						Id:     "CAT:" + strconv.FormatInt(int64(t.ID), 10),
-						BareId: content.FeedID(t.ID),
-						Name:   string(t.Value),
-						Type:   "category",
-						Items:  []category{},
-					}
+				c, ok := tagCategories[t.ID]
+				if !ok {
+					c = newTagCategory(t)
+					tagCategories[t.ID] = c
+					roots = append(roots, t.ID)
 				}
 
 				c.Items = append(c.Items, item)
-				tagCategories[t] = c
 			}
 		} else {
 			uncat.Items = append(uncat.Items, item)
 		}
 	}
 
-	categories := []category{uncat}
-	for _, c := range tagCategories {
-		categories = append(categories, c)
-	}
+	uncat.Param = feedCountParam(len(uncat.Items))
+	items = append(items, uncat)
 
-	for _, c := range categories {
-		if len(c.Items) == 1 {
-			c.Param = "(1 feed)"
-		} else {
-			c.Param = fmt.Sprintf("(%d feed)", len(c.Items))
-		}
-		items = append(items, c)
+	for _, id := range roots {
+		items = append(items, nestTagCategory(id, tagCategories, children))
 	}
 
 	fl := category{Identifier: "id", Label: "name"}
@@ -297,6 +326,40 @@ func getFeedTree(req request, user content.User, service repo.Service) (interfac
 	return feedTreeContent{Categories: fl}, nil
 }
 
+func newTagCategory(t content.Tag) *category {
+	return &category{
+		Id:     "CAT:" + strconv.FormatInt(int64(t.ID), 10),
+		BareId: content.FeedID(t.ID),
+		Name:   string(t.Value),
+		Type:   "category",
+		Items:  []category{},
+	}
+}
+
+// nestTagCategory returns the category of the tag, with the categories of its
+// nested tags listed before its feeds.
+func nestTagCategory(id content.TagID, categories map[content.TagID]*category, children map[content.TagID][]content.TagID) category {
+	c := *categories[id]
+	feedCount := len(c.Items)
+
+	nested := make([]category, 0, len(children[id])+feedCount)
+	for _, child := range children[id] {
+		nested = append(nested, nestTagCategory(child, categories, children))
+	}
+	c.Items = append(nested, c.Items...)
+	c.Param = feedCountParam(feedCount)
+
+	return c
+}
+
+func feedCountParam(count int) string {
+	if count == 1 {
+		return "(1 feed)"
+	}
+
+	return fmt.Sprintf("(%d feed)", count)
+}
+
 func specialTitle(id content.FeedID) (t string) {
 	switch id {
 	case FAVORITE_ID:
@@ -382,7 +445,7 @@ func createSpecialCategory(repo repo.Article, user content.User) (category, erro
 
 func init() {
 	actions["getFeeds"] = getFeeds
-	actions["updateFeed"] = updateFeed
+	feedActions["updateFeed"] = updateFeed
 	actions["catchupFeed"] = catchupFeed
 	actions["getFeedTree"] = getFeedTree
 }
@@ -50,6 +50,10 @@ type request struct {
 	ArticleId     []content.ArticleID `json:"article_id"`
 	PrefName      string              `json:"pref_name"`
 	FeedUrl       string              `json:"feed_url"`
+	Login         string              `json:"login"`
+
+	IncludeAttachments bool `json:"include_attachments"`
+	EnableNested       bool `json:"enable_nested"`
 }
 
 type response struct {
@@ -60,6 +64,9 @@ type response struct {
 
 type action func(request, content.User, repo.Service) (interface{}, error)
 
+// feedAction is an action that needs the feed manager to do its work.
+type feedAction func(request, content.User, *readeef.FeedManager, repo.Service) (interface{}, error)
+
 type errorContent struct {
 	Error string `json:"error"`
 }
@@ -87,7 +94,8 @@ const (
 )
 
 var (
-	actions = make(map[string]action)
+	actions     = make(map[string]action)
+	feedActions = make(map[string]feedAction)
 )
 
 func Handler(
@@ -146,12 +154,16 @@ func Handler(
 		if err == nil {
 			log.Debugf("TT-RSS OP: %s\n", req.Op)
 
-			a, ok := actions[req.Op]
-			if !ok {
-				a = unknown
-			}
+			if fa, ok := feedActions[req.Op]; ok {
+				con, err = fa(req, user, feedManager, service)
+			} else {
+				a, ok := actions[req.Op]
+				if !ok {
+					a = unknown
+				}
 
-			con, err = a(req, user, service)
+				con, err = a(req, user, service)
+			}
 		}
 
 		if err == nil {
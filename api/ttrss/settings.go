@@ -63,7 +63,8 @@ func subscribeToFeed(
 	repo := service.FeedRepo()
 	feed, err := repo.FindByLink(req.FeedUrl)
 	if content.IsNoContent(err) {
-		feed, err = feedManager.AddFeedByLink(req.FeedUrl)
+		auth := content.FeedAuth{Username: req.Login, Password: req.Password}
+		feed, err = feedManager.AddFeedByLink(req.FeedUrl, auth)
 		if err != nil {
 			return nil, errors.WithStack(newErr(err.Error(), "INCORRECT_USAGE"))
 		}
@@ -28,6 +28,11 @@ func getCategories(req request, user content.User, service repo.Service) (interf
 		return nil, errors.WithMessage(err, "getting user tags")
 	}
 	for _, tag := range tags {
+		// The nested categories are included in the counts of their parents.
+		if req.EnableNested && tag.ParentID != 0 {
+			continue
+		}
+
 		ids, err := tagRepo.FeedIDs(tag, user)
 		if err != nil {
 			return nil, errors.WithMessage(err, "getting tag feed ids")
@@ -0,0 +1,162 @@
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/pkg/errors"
+	"github.com/urandom/readeef"
+	"github.com/urandom/readeef/config"
+	"github.com/urandom/readeef/content"
+	"github.com/urandom/readeef/feed"
+	"github.com/urandom/readeef/httpclient"
+)
+
+var (
+	feedCheckVerbose  bool
+	feedCheckJSON     bool
+	feedCheckUsername string
+	feedCheckPassword string
+)
+
+func runFeedCheck(config config.Config, args []string) error {
+	if len(args) != 1 {
+		return errors.New("invalid number of arguments")
+	}
+
+	if feedCheckVerbose {
+		config.Log.Level = "debug"
+	}
+
+	log := initLog(config.Log)
+
+	client, err := httpclient.New(config.HTTPClient)
+	if err != nil {
+		return errors.WithMessage(err, "creating http client")
+	}
+
+	feedManager := readeef.NewFeedManager(nil, config, client, log)
+
+	processors, err := initFeedProcessors(config.FeedParser.Processors, config.FeedParser.ProxyHTTPURLTemplate, log)
+	if err != nil {
+		return errors.WithMessage(err, "initializing parser processors")
+	}
+
+	for _, p := range processors {
+		feedManager.AddFeedProcessor(p)
+	}
+
+	link, auth := content.SplitLinkAuth(args[0], content.FeedAuth{Username: feedCheckUsername, Password: feedCheckPassword})
+
+	f := content.Feed{Link: link}
+	if err := f.SetAuth(auth, []byte(config.Auth.Secret)); err != nil {
+		return errors.WithMessage(err, "setting feed auth")
+	}
+
+	report := feedManager.CheckFeed(f)
+
+	if feedCheckJSON {
+		b, err := json.MarshalIndent(report, "", "  ")
+		if err != nil {
+			return errors.Wrap(err, "marshaling report")
+		}
+
+		fmt.Println(string(b))
+	} else {
+		printFeedReport(report)
+	}
+
+	if report.Error != "" {
+		os.Exit(1)
+	}
+
+	return nil
+}
+
+func printFeedReport(report feed.Report) {
+	fmt.Printf("Link: %s\n", report.Link)
+
+	if report.Status != 0 {
+		fmt.Printf("Status: %d\n", report.Status)
+	}
+
+	if len(report.Header) > 0 {
+		names := make([]string, 0, len(report.Header))
+		for name := range report.Header {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+
+		fmt.Printf("Headers:\n")
+		for _, name := range names {
+			fmt.Printf("\t%s: %s\n", name, strings.Join(report.Header[name], ", "))
+		}
+	}
+
+	if report.Error != "" {
+		fmt.Printf("Error: %s\n", report.Error)
+	}
+
+	if len(report.Discovered) > 0 {
+		fmt.Printf("Discovered feeds:\n")
+		for _, link := range report.Discovered {
+			fmt.Printf("\t%s\n", link)
+		}
+	}
+
+	if report.Format == "" {
+		return
+	}
+
+	fmt.Printf("Format: %s\n", report.Format)
+	if report.Diagnostic != "" {
+		fmt.Printf("Diagnostic: %s\n", report.Diagnostic)
+	}
+	fmt.Printf("Articles: %d\n", report.Articles)
+
+	if len(report.DateFailures) > 0 {
+		fmt.Printf("Date parsing failures:\n")
+		for _, a := range report.DateFailures {
+			fmt.Printf("\t%s\n", a)
+		}
+	}
+
+	if len(report.DuplicateGuids) > 0 {
+		fmt.Printf("Duplicate guids:\n")
+		for _, guid := range report.DuplicateGuids {
+			fmt.Printf("\t%s\n", guid)
+		}
+	}
+
+	for _, p := range report.Processors {
+		fmt.Printf("Processor %s: %d changed articles\n", p.Name, len(p.Changed))
+		for _, a := range p.Changed {
+			fmt.Printf("\t%s\n", a)
+		}
+	}
+}
+
+func init() {
+	flags := flag.NewFlagSet("feed-check", flag.ExitOnError)
+	flags.BoolVar(&feedCheckVerbose, "verbose", false, "verbose output")
+	flags.BoolVar(&feedCheckJSON, "json", false, "print the report as json")
+	flags.StringVar(&feedCheckUsername, "username", "", "username for feeds that require authentication")
+	flags.StringVar(&feedCheckPassword, "password", "", "password for feeds that require authentication")
+
+	commands = append(commands, Command{
+		Name:  "feed-check",
+		Desc:  "download, parse and process a feed, reporting any problems",
+		Flags: flags,
+		Run:   runFeedCheck,
+	})
+
+	flags.Usage = func() {
+		fmt.Fprintf(os.Stderr, "Usage of feed-check:\n\n")
+		fmt.Fprintf(os.Stderr, "\tfeed-check [arguments] URL\n\n")
+		flags.PrintDefaults()
+	}
+}
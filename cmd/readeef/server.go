@@ -26,6 +26,7 @@ import (
 	"github.com/urandom/readeef/config"
 	"github.com/urandom/readeef/content"
 	"github.com/urandom/readeef/content/extract"
+	"github.com/urandom/readeef/content/favicon"
 	"github.com/urandom/readeef/content/monitor"
 	"github.com/urandom/readeef/content/processor"
 	"github.com/urandom/readeef/content/repo"
@@ -34,6 +35,7 @@ import (
 	"github.com/urandom/readeef/content/repo/sql"
 	"github.com/urandom/readeef/content/search"
 	"github.com/urandom/readeef/content/thumbnail"
+	"github.com/urandom/readeef/httpclient"
 	"github.com/urandom/readeef/log"
 	"github.com/urandom/readeef/popularity"
 	"github.com/urandom/readeef/web"
@@ -66,7 +68,12 @@ func runServer(cfg config.Config, args []string) error {
 
 	logger := initLog(cfg.Log)
 
-	handler, err := web.Mux(fs, sessionManager, cfg, logger)
+	client, err := httpclient.New(cfg.HTTPClient)
+	if err != nil {
+		return errors.WithMessage(err, "creating http client")
+	}
+
+	handler, err := web.Mux(fs, sessionManager, cfg, client, logger)
 	if err != nil {
 		return errors.WithMessage(err, "creating web mux")
 	}
@@ -110,7 +117,8 @@ func runServer(cfg config.Config, args []string) error {
 		return errors.WithMessage(err, "initializing admin user")
 	}
 
-	feedManager := readeef.NewFeedManager(service.FeedRepo(), cfg, logger)
+	feedManager := readeef.NewFeedManager(service.FeedRepo(), cfg, client, logger)
+	feedManager.SetUpdateRepo(service.FeedUpdateRepo())
 
 	if processors, err := initFeedProcessors(cfg.FeedParser.Processors, cfg.FeedParser.ProxyHTTPURLTemplate, logger); err == nil {
 		for _, p := range processors {
@@ -122,7 +130,7 @@ func runServer(cfg config.Config, args []string) error {
 
 	searchProvider := initSearchProvider(cfg.Content, service, logger)
 
-	extractor, err := initArticleExtractor(cfg.Content, fs)
+	extractor, err := initArticleExtractor(cfg.Content, fs, client)
 	if err != nil {
 		return errors.WithMessage(err, "initializing content extract generator")
 	}
@@ -132,16 +140,20 @@ func runServer(cfg config.Config, args []string) error {
 		return errors.WithMessage(err, "initializing article processors")
 	}
 
-	thumbnailer, err := initThumbnailGenerator(service, cfg.Content, extractor, articleProcessors, logger)
+	thumbnailer, err := initThumbnailGenerator(service, cfg.Content, extractor, articleProcessors, client, []byte(cfg.Auth.Secret), logger)
 	if err != nil {
 		return errors.Wrap(err, "initializing thumbnail generator")
 	}
 
-	initPopularityScore(ctx, service, cfg.Popularity, logger)
+	initPopularityScore(ctx, service, cfg.Popularity, client, logger)
+
+	favicons := favicon.NewFetcher(service.FeedImageRepo(), client, cfg.Content.Favicon.MaxSize, logger)
+
+	initFeedMonitors(ctx, cfg.FeedManager, service, searchProvider, thumbnailer, favicons, feedManager, logger)
 
-	initFeedMonitors(ctx, cfg.FeedManager, service, searchProvider, thumbnailer, logger)
+	go readeef.NewPurger(service, searchProvider, cfg.Content.Retention, logger).Start(ctx)
 
-	hubbub, err := initHubbub(cfg, service, feedManager, logger)
+	hubbub, err := initHubbub(cfg, service, feedManager, client, logger)
 	if err != nil {
 		return errors.WithMessage(err, "initializing hubbub")
 	}
@@ -150,7 +162,9 @@ func runServer(cfg config.Config, args []string) error {
 		feedManager.SetHubbub(hubbub)
 	}
 
-	handler, err = api.Mux(ctx, service, feedManager, searchProvider, extractor, fs, articleProcessors, cfg, logger, accessMiddleware)
+	publisher := initPublisher(ctx, cfg, service, client, logger)
+
+	handler, err = api.Mux(ctx, service, feedManager, publisher, searchProvider, extractor, fs, articleProcessors, cfg, logger, accessMiddleware)
 	if err != nil {
 		return errors.WithMessage(err, "creating api mux")
 	}
@@ -357,10 +371,10 @@ func initSearchProvider(config config.Content, service repo.Service, log log.Log
 	return searchProvider
 }
 
-func initArticleExtractor(config config.Content, fs http.FileSystem) (extract.Generator, error) {
+func initArticleExtractor(config config.Content, fs http.FileSystem, client *http.Client) (extract.Generator, error) {
 	switch config.Extract.Generator {
 	case "readability":
-		if ce, err := extract.WithReadability(config.Extract.ReadabilityKey); err == nil {
+		if ce, err := extract.WithReadability(config.Extract.ReadabilityKey, client); err == nil {
 			return ce, nil
 		} else {
 			return nil, errors.WithMessage(err, "initializing Readability extract generator")
@@ -368,7 +382,7 @@ func initArticleExtractor(config config.Content, fs http.FileSystem) (extract.Ge
 	case "goose":
 		fallthrough
 	default:
-		if ce, err := extract.WithGoose("templates", fs); err == nil {
+		if ce, err := extract.WithGoose("templates", fs, client); err == nil {
 			return ce, nil
 		} else {
 			return nil, errors.WithMessage(err, "initializing Goose extract generator")
@@ -381,12 +395,14 @@ func initThumbnailGenerator(
 	config config.Content,
 	extract extract.Generator,
 	processors []processor.Article,
+	client *http.Client,
+	secret []byte,
 	log log.Log,
 ) (thumbnail.Generator, error) {
 
 	switch config.ThumbnailGenerator {
 	case "extract":
-		if t, err := thumbnail.FromExtract(service.ThumbnailRepo(), service.ExtractRepo(), extract, processors, log); err == nil {
+		if t, err := thumbnail.FromExtract(service.ThumbnailRepo(), service.ExtractRepo(), service.FeedRepo(), extract, processors, client, secret, log); err == nil {
 			return t, nil
 		} else {
 			return nil, errors.WithMessage(err, "initializing Extract thumbnail generator")
@@ -394,12 +410,12 @@ func initThumbnailGenerator(
 	case "description":
 		fallthrough
 	default:
-		return thumbnail.FromDescription(service.ThumbnailRepo(), log), nil
+		return thumbnail.FromDescription(service.ThumbnailRepo(), client, log), nil
 	}
 }
 
-func initPopularityScore(ctx context.Context, service repo.Service, config config.Popularity, log log.Log) {
-	popularity.New(config, log).ScoreContent(ctx, service)
+func initPopularityScore(ctx context.Context, service repo.Service, config config.Popularity, client *http.Client, log log.Log) {
+	popularity.New(config, client, log).ScoreContent(ctx, service)
 }
 
 func initFeedMonitors(
@@ -408,9 +424,11 @@ func initFeedMonitors(
 	service eventable.Service,
 	searchProvider search.Provider,
 	thumbnailer thumbnail.Generator,
+	favicons favicon.Fetcher,
+	limiter monitor.Limiter,
 	log log.Log,
 ) {
-	go monitor.Unread(ctx, service, log)
+	go monitor.Unread(ctx, service, config.MarkUpdatedUnread, log)
 	go monitor.UserFilters(service, log)
 
 	for _, m := range config.Monitors {
@@ -423,6 +441,8 @@ func initFeedMonitors(
 			if thumbnailer != nil {
 				go monitor.Thumbnailer(service, thumbnailer, log)
 			}
+		case "favicons":
+			go monitor.Favicons(ctx, service, favicons, limiter, log)
 		}
 	}
 }
@@ -431,10 +451,11 @@ func initHubbub(
 	config config.Config,
 	service repo.Service,
 	feedManager *readeef.FeedManager,
+	client *http.Client,
 	log log.Log,
 ) (*readeef.Hubbub, error) {
 	if config.Hubbub.CallbackURL != "" {
-		hubbub := readeef.NewHubbub(service, config, log, "/api/v2/hubbub", feedManager)
+		hubbub := readeef.NewHubbub(service, config, client, log, "/api/v2/hubbub", feedManager)
 
 		if err := hubbub.InitSubscriptions(); err != nil {
 			return nil, errors.WithMessage(err, "initializing hubbub subscriptions")
@@ -446,6 +467,24 @@ func initHubbub(
 	return nil, nil
 }
 
+func initPublisher(
+	ctx context.Context,
+	config config.Config,
+	service eventable.Service,
+	client *http.Client,
+	log log.Log,
+) *readeef.Publisher {
+	if config.Hubbub.Publish && config.Hubbub.CallbackURL != "" {
+		publisher := readeef.NewPublisher(service, config, client, log, "/api/v2/publish")
+
+		go publisher.Start(ctx, service.Listener())
+
+		return publisher
+	}
+
+	return nil
+}
+
 func makeHTTPServer(mux http.Handler) *http.Server {
 	return &http.Server{
 		ReadTimeout: 5 * time.Second,
@@ -14,6 +14,7 @@ type Config struct {
 	Log         Log         `toml:"log"`
 	API         API         `toml:"api"`
 	Timeout     Timeout     `toml:"timeout"`
+	HTTPClient  HTTPClient  `toml:"http-client"`
 	DB          DB          `toml:"db"`
 	Auth        Auth        `toml:"auth"`
 	Hubbub      Hubbub      `toml:"hubbub"`
@@ -37,7 +38,7 @@ func Read(path string) (Config, error) {
 		return Config{}, err
 	}
 
-	for _, c := range []converter{&c.API, &c.Log, &c.Timeout, &c.FeedManager, &c.Popularity} {
+	for _, c := range []converter{&c.API, &c.Log, &c.Timeout, &c.HTTPClient, &c.FeedManager, &c.Popularity, &c.Content.Retention} {
 		c.Convert()
 	}
 
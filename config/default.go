@@ -41,12 +41,23 @@ var DefaultCfg = `
 	token-storage-path = "./storage/token.db"
 [feed-manager]
 	update-interval = "30m"
-	monitors = ["index", "thumbnailer"]
-[timeout]
-	connect = "1s"
-	read-write = "2s"
+	min-update-interval = "5m"
+	max-update-interval = "12h"
+	fetch-concurrency = 10
+	host-concurrency = 2
+	update-history-retention = "720h"  # 0 keeps the history forever
+	mark-updated-unread = false
+	monitors = ["index", "thumbnailer", "favicons"]
+[http-client]
+	proxy = ""                     # http://, https:// or socks5:// url, defaults to the environment
+	user-agent = "readeef"
+	ca-bundle = ""                 # additional trusted certificates, in PEM format
+	max-response-size = 10485760   # bytes
+	timeout = "30s"
+	connect-timeout = "10s"
 [hubbub]
 	from = "readeef"
+	publish = false
 [popularity]
 	delay = "5s"
 	providers = ["Facebook", "Reddit"]
@@ -65,6 +76,15 @@ var DefaultCfg = `
 	bleve-path = "./storage/search.bleve"
 	elastic-url = "http://localhost:9200"
 	proxy-http-url-template = "/proxy?url={{ . }}"
+[content.favicon]
+	max-size = 65536 # bytes
+[content.retention]
+	max-age = "0"      # 0 keeps the articles forever
+	max-articles = 0   # per feed, 0 keeps all of them
+#[[content.retention.feed]]
+#	link = "http://example.com/feed.xml"
+#	max-age = "2160h"
+#	max-articles = 500
 [ui]
 	path = "./rf-ng/ui"
 `
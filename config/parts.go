@@ -43,6 +43,8 @@ type API struct {
 	} `toml:"limits"`
 }
 
+// Timeout is deprecated, the timeouts of outbound requests are set in the
+// HTTPClient section.
 type Timeout struct {
 	Connect   string `toml:"connect"`
 	ReadWrite string `toml:"read-write"`
@@ -53,6 +55,21 @@ type Timeout struct {
 	}
 }
 
+// HTTPClient configures the client used for all outbound requests.
+type HTTPClient struct {
+	Proxy           string `toml:"proxy"` // http://, https:// or socks5:// url
+	UserAgent       string `toml:"user-agent"`
+	CABundle        string `toml:"ca-bundle"`
+	MaxResponseSize int64  `toml:"max-response-size"`
+	Timeout         string `toml:"timeout"`
+	ConnectTimeout  string `toml:"connect-timeout"`
+
+	Converted struct {
+		Timeout        time.Duration
+		ConnectTimeout time.Duration
+	}
+}
+
 type DB struct {
 	Driver  string `toml:"driver"`
 	Connect string `toml:"connect"`
@@ -67,6 +84,10 @@ type Auth struct {
 type Hubbub struct {
 	CallbackURL string `toml:"callback-url"` // http://www.example.com
 	From        string `toml:"from"`
+	// Publish makes readeef a hub for the feeds it generates from the
+	// favorites, tags and filters of its users. It requires the callback
+	// url, which is used as the base of the feed links.
+	Publish bool `toml:"publish"`
 }
 
 type Popularity struct {
@@ -92,12 +113,28 @@ type FeedParser struct {
 }
 
 type FeedManager struct {
-	UpdateInterval string `toml:"update-interval"`
+	UpdateInterval    string `toml:"update-interval"`
+	MinUpdateInterval string `toml:"min-update-interval"`
+	MaxUpdateInterval string `toml:"max-update-interval"`
+
+	FetchConcurrency int `toml:"fetch-concurrency"`
+	HostConcurrency  int `toml:"host-concurrency"`
+
+	// UpdateHistoryRetention is how long the record of each feed download
+	// is kept. A zero duration keeps it forever.
+	UpdateHistoryRetention string `toml:"update-history-retention"`
+
+	// MarkUpdatedUnread marks the articles edited by their publishers as
+	// unread again, for the users who have already read them.
+	MarkUpdatedUnread bool `toml:"mark-updated-unread"`
 
 	Monitors []string `toml:"monitors"`
 
 	Converted struct {
-		UpdateInterval time.Duration
+		UpdateInterval         time.Duration
+		MinUpdateInterval      time.Duration
+		MaxUpdateInterval      time.Duration
+		UpdateHistoryRetention time.Duration
 	}
 }
 
@@ -120,6 +157,41 @@ type Content struct {
 		Processors           []string `toml:"processors"`
 		ProxyHTTPURLTemplate string   `toml:"proxy-http-url-template"`
 	} `toml:"article"`
+
+	Favicon struct {
+		MaxSize int64 `toml:"max-size"`
+	} `toml:"favicon"`
+
+	Retention Retention `toml:"retention"`
+}
+
+// Retention is the policy for purging old articles. Favorite articles are
+// never purged.
+type Retention struct {
+	// MaxAge is the age after which articles are purged. A zero duration
+	// keeps them forever.
+	MaxAge string `toml:"max-age"`
+	// MaxArticles is the number of the newest articles kept for each
+	// feed. Zero keeps all of them.
+	MaxArticles int `toml:"max-articles"`
+
+	// Feeds override the policy for the feeds with the given links. An
+	// override replaces the whole policy of its feed.
+	Feeds []FeedRetention `toml:"feed"`
+
+	Converted struct {
+		MaxAge time.Duration
+	}
+}
+
+type FeedRetention struct {
+	Link        string `toml:"link"`
+	MaxAge      string `toml:"max-age"`
+	MaxArticles int    `toml:"max-articles"`
+
+	Converted struct {
+		MaxAge time.Duration
+	}
 }
 
 type UI struct {
@@ -161,6 +233,65 @@ func (c *Timeout) Convert() {
 	}
 }
 
+func (c *HTTPClient) Convert() {
+	if d, err := time.ParseDuration(c.Timeout); err == nil {
+		c.Converted.Timeout = d
+	} else {
+		c.Converted.Timeout = 30 * time.Second
+	}
+
+	if d, err := time.ParseDuration(c.ConnectTimeout); err == nil {
+		c.Converted.ConnectTimeout = d
+	} else {
+		c.Converted.ConnectTimeout = 10 * time.Second
+	}
+}
+
+func (c *Retention) Convert() {
+	c.Converted.MaxAge = parseRetentionAge(c.MaxAge)
+
+	for i := range c.Feeds {
+		c.Feeds[i].Converted.MaxAge = parseRetentionAge(c.Feeds[i].MaxAge)
+	}
+}
+
+// ForFeed returns the max age and article count of the feed with the given
+// link.
+func (c Retention) ForFeed(link string) (time.Duration, int) {
+	for _, f := range c.Feeds {
+		if f.Link == link {
+			return f.Converted.MaxAge, f.MaxArticles
+		}
+	}
+
+	return c.Converted.MaxAge, c.MaxArticles
+}
+
+// Enabled returns true if any articles are subject to purging.
+func (c Retention) Enabled() bool {
+	if c.Converted.MaxAge > 0 || c.MaxArticles > 0 {
+		return true
+	}
+
+	for _, f := range c.Feeds {
+		if f.Converted.MaxAge > 0 || f.MaxArticles > 0 {
+			return true
+		}
+	}
+
+	return false
+}
+
+// An invalid or negative age keeps the articles forever, rather than purging
+// them by mistake.
+func parseRetentionAge(age string) time.Duration {
+	if d, err := time.ParseDuration(age); err == nil && d > 0 {
+		return d
+	}
+
+	return 0
+}
+
 func (c *Popularity) Convert() {
 	if d, err := time.ParseDuration(c.Delay); err == nil {
 		c.Converted.Delay = d
@@ -175,4 +306,26 @@ func (c *FeedManager) Convert() {
 	} else {
 		c.Converted.UpdateInterval = 30 * time.Minute
 	}
+
+	if d, err := time.ParseDuration(c.MinUpdateInterval); err == nil {
+		c.Converted.MinUpdateInterval = d
+	} else {
+		c.Converted.MinUpdateInterval = 5 * time.Minute
+	}
+
+	if d, err := time.ParseDuration(c.MaxUpdateInterval); err == nil {
+		c.Converted.MaxUpdateInterval = d
+	} else {
+		c.Converted.MaxUpdateInterval = 12 * time.Hour
+	}
+
+	if c.Converted.MaxUpdateInterval < c.Converted.MinUpdateInterval {
+		c.Converted.MaxUpdateInterval = c.Converted.MinUpdateInterval
+	}
+
+	if d, err := time.ParseDuration(c.UpdateHistoryRetention); err == nil && d >= 0 {
+		c.Converted.UpdateHistoryRetention = d
+	} else {
+		c.Converted.UpdateHistoryRetention = 30 * 24 * time.Hour
+	}
 }
@@ -1,11 +1,18 @@
 package content
 
 import (
+	"crypto/md5"
 	"database/sql"
 	"database/sql/driver"
+	"encoding/hex"
+	"encoding/json"
 	"errors"
 	"fmt"
+	"hash/fnv"
+	"io"
+	"math"
 	"net/url"
+	"sort"
 	"time"
 )
 
@@ -18,8 +25,28 @@ type Article struct {
 
 	Title       string    `json:"title"`
 	Description string    `json:"description"`
+	Summary     string    `json:"summary,omitempty"`
 	Link        string    `json:"link"`
 	Date        time.Time `json:"date"`
+	Author      string    `json:"author,omitempty"`
+	Categories  []string  `db:"-" json:"categories,omitempty"`
+
+	// DateInferred is true if the feed did not provide a valid date for
+	// the article, and the date it was first seen at is used instead.
+	DateInferred bool `db:"date_inferred" json:"dateInferred,omitempty"`
+
+	// ContentHash identifies the title, description and summary of the
+	// article, in order to detect when the publisher edits them.
+	ContentHash string `db:"content_hash" json:"-"`
+
+	// Updated is true if the article was edited after it was first stored,
+	// and UpdateDate is the time the last edit was seen at.
+	Updated    bool       `json:"updated,omitempty"`
+	UpdateDate *time.Time `db:"update_date" json:"updateDate,omitempty"`
+
+	// DuplicateOf is the id of the canonical article, if the article is a
+	// copy of a story that was first stored from another feed.
+	DuplicateOf ArticleID `db:"duplicate_of" json:"duplicateOf,omitempty"`
 
 	Read          bool   `json:"read"`
 	Favorite      bool   `json:"favorite"`
@@ -27,6 +54,8 @@ type Article struct {
 	Thumbnail     string `json:"thumbnail,omitempty"`
 	ThumbnailLink string `db:"thumbnail_link" json:"thumbnailLink,omitempty"`
 
+	Enclosures []Enclosure `db:"-" json:"enclosures,omitempty"`
+
 	IsNew bool `json:"-"`
 
 	Hit struct {
@@ -77,8 +106,12 @@ type QueryOptions struct {
 	AfterDate       time.Time
 	IDs             []ArticleID
 	FeedIDs         []FeedID
+	Authors         []string
+	Categories      []string
 	Filters         []Filter
 
+	ExcludeReadDuplicates bool
+
 	SortField sortingField
 	SortOrder sortingOrder
 }
@@ -97,6 +130,26 @@ func (f Filter) Valid() bool {
 	return (f.URLTerm != "" || f.TitleTerm != "") && (f.TagID == 0 || len(f.FeedIDs) > 0)
 }
 
+// ID returns a non-negative identifier derived from the filter terms, which
+// does not change when other filters are added, removed or reordered. The
+// feeds of a tag filter are left out, as they follow the feeds of the tag.
+func (f Filter) ID() int64 {
+	var ids []FeedID
+	if f.TagID == 0 {
+		ids = make([]FeedID, len(f.FeedIDs))
+		copy(ids, f.FeedIDs)
+		sort.Slice(ids, func(i, j int) bool { return ids[i] < ids[j] })
+	}
+	f.FeedIDs = ids
+
+	b, _ := json.Marshal(f)
+
+	h := fnv.New64a()
+	h.Write(b)
+
+	return int64(h.Sum64() & math.MaxInt64)
+}
+
 // Paging sets the article query paging optons.
 func Paging(limit, offset int) QueryOpt {
 	return QueryOpt{func(o *QueryOptions) {
@@ -127,6 +180,22 @@ func FeedIDs(ids []FeedID) QueryOpt {
 	}}
 }
 
+// Authors limits the query to articles written by any of the specified
+// authors. The comparison is case-insensitive.
+func Authors(authors []string) QueryOpt {
+	return QueryOpt{func(o *QueryOptions) {
+		o.Authors = authors
+	}}
+}
+
+// Categories limits the query to articles belonging to any of the specified
+// categories. The comparison is case-insensitive.
+func Categories(categories []string) QueryOpt {
+	return QueryOpt{func(o *QueryOptions) {
+		o.Categories = categories
+	}}
+}
+
 // TimeRange sets the minimum and maximum times of returned articles.
 func TimeRange(after, before time.Time) QueryOpt {
 	return QueryOpt{func(o *QueryOptions) {
@@ -185,6 +254,12 @@ var (
 	HighScoredFirst = QueryOpt{func(o *QueryOptions) {
 		o.HighScoredFirst = true
 	}}
+
+	// ExcludeReadDuplicates sets the query to skip articles whose
+	// duplicates from other feeds have already been read.
+	ExcludeReadDuplicates = QueryOpt{func(o *QueryOptions) {
+		o.ExcludeReadDuplicates = true
+	}}
 )
 
 // Apply applies the settings from the passed opts to the QueryOptions
@@ -198,6 +273,18 @@ func (a Article) String() string {
 	return fmt.Sprintf("%d: %s", a.ID, a.Title)
 }
 
+// Hash returns the content hash of the article's title, description and
+// summary.
+func (a Article) Hash() string {
+	h := md5.New()
+	for _, s := range []string{a.Title, a.Description, a.Summary} {
+		io.WriteString(h, s)
+		h.Write([]byte{0})
+	}
+
+	return hex.EncodeToString(h.Sum(nil))
+}
+
 func (a Article) Validate() error {
 	if a.ID == 0 {
 		return NewValidationError(errors.New("no ID"))
@@ -0,0 +1,65 @@
+package content
+
+import (
+	"errors"
+	"fmt"
+	"time"
+)
+
+type ArticleRevisionID int64
+
+// ArticleRevision holds the content of an article as it was before the
+// publisher edited it. Date is the time the edit was seen at.
+type ArticleRevision struct {
+	ID        ArticleRevisionID `json:"id"`
+	ArticleID ArticleID         `db:"article_id" json:"articleID"`
+	Date      time.Time         `db:"revision_date" json:"date"`
+
+	Title       string `json:"title"`
+	Description string `json:"description"`
+	Summary     string `json:"summary,omitempty"`
+}
+
+// ArticleDiff holds the changes made to each part of an article between two
+// of its revisions.
+type ArticleDiff struct {
+	Title       []DiffOp `json:"title"`
+	Description []DiffOp `json:"description"`
+	Summary     []DiffOp `json:"summary,omitempty"`
+}
+
+// Revision returns the current content of the article as a revision.
+func (a Article) Revision() ArticleRevision {
+	r := ArticleRevision{ArticleID: a.ID, Title: a.Title, Description: a.Description, Summary: a.Summary}
+	if a.UpdateDate != nil {
+		r.Date = *a.UpdateDate
+	}
+
+	return r
+}
+
+// Diff returns the changes needed to turn the revision into the given one.
+func (r ArticleRevision) Diff(to ArticleRevision) ArticleDiff {
+	d := ArticleDiff{
+		Title:       Diff(r.Title, to.Title),
+		Description: Diff(r.Description, to.Description),
+	}
+
+	if r.Summary != "" || to.Summary != "" {
+		d.Summary = Diff(r.Summary, to.Summary)
+	}
+
+	return d
+}
+
+func (r ArticleRevision) Validate() error {
+	if r.ArticleID == 0 {
+		return NewValidationError(errors.New("Article revision has no article id"))
+	}
+
+	return nil
+}
+
+func (r ArticleRevision) String() string {
+	return fmt.Sprintf("%d: %d %s", r.ArticleID, r.ID, r.Date.Format(time.RFC3339))
+}
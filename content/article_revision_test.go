@@ -0,0 +1,52 @@
+package content_test
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/urandom/readeef/content"
+)
+
+func TestArticleRevision_Validate(t *testing.T) {
+	tests := []struct {
+		name      string
+		ArticleID content.ArticleID
+		wantErr   bool
+	}{
+		{"valid", 1, false},
+		{"invalid", 0, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := content.ArticleRevision{
+				ArticleID: tt.ArticleID,
+			}
+			if err := r.Validate(); (err != nil) != tt.wantErr {
+				t.Errorf("ArticleRevision.Validate() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestArticleRevision_Diff(t *testing.T) {
+	date := time.Date(2018, time.March, 1, 12, 0, 0, 0, time.UTC)
+	article := content.Article{ID: 1, Title: "New title", Description: "Same text", UpdateDate: &date}
+
+	current := article.Revision()
+	if want := (content.ArticleRevision{ArticleID: 1, Date: date, Title: "New title", Description: "Same text"}); current != want {
+		t.Errorf("Article.Revision() = %v, want %v", current, want)
+	}
+
+	got := content.ArticleRevision{ArticleID: 1, Title: "Old title", Description: "Same text"}.Diff(current)
+	want := content.ArticleDiff{
+		Title: []content.DiffOp{
+			{Type: content.DiffDelete, Text: "Old"}, {Type: content.DiffInsert, Text: "New"}, {Type: content.DiffEqual, Text: " title"},
+		},
+		Description: []content.DiffOp{{Type: content.DiffEqual, Text: "Same text"}},
+	}
+
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("ArticleRevision.Diff() = %v, want %v", got, want)
+	}
+}
@@ -37,3 +37,54 @@ func TestArticle_Validate(t *testing.T) {
 		})
 	}
 }
+
+func TestFilter_ID(t *testing.T) {
+	f := content.Filter{TitleTerm: "golang", FeedIDs: []content.FeedID{1, 2}}
+
+	tests := []struct {
+		name  string
+		other content.Filter
+		same  bool
+	}{
+		{"same", content.Filter{TitleTerm: "golang", FeedIDs: []content.FeedID{1, 2}}, true},
+		{"feed order", content.Filter{TitleTerm: "golang", FeedIDs: []content.FeedID{2, 1}}, true},
+		{"other term", content.Filter{TitleTerm: "rust", FeedIDs: []content.FeedID{1, 2}}, false},
+		{"inverse", content.Filter{TitleTerm: "golang", FeedIDs: []content.FeedID{1, 2}, InverseTitle: true}, false},
+	}
+
+	tag := content.Filter{TitleTerm: "golang", TagID: 3, FeedIDs: []content.FeedID{1}}
+	if got, want := tag.ID(), (content.Filter{TitleTerm: "golang", TagID: 3, FeedIDs: []content.FeedID{1, 4}}).ID(); got != want {
+		t.Errorf("Filter.ID() = %v for a tag filter with other feeds, want %v", got, want)
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.other.ID(); got < 0 || (got == f.ID()) != tt.same {
+				t.Errorf("Filter.ID() = %v, filter id %v, want same %v", got, f.ID(), tt.same)
+			}
+		})
+	}
+}
+
+func TestArticle_Hash(t *testing.T) {
+	base := content.Article{ID: 1, Title: "Title", Description: "Description", Summary: "Summary", Link: "http://sugr.org"}
+
+	tests := []struct {
+		name    string
+		article content.Article
+		same    bool
+	}{
+		{"same content", content.Article{ID: 2, Title: "Title", Description: "Description", Summary: "Summary", Link: "http://sugr.org/other"}, true},
+		{"title", content.Article{Title: "Title 2", Description: "Description", Summary: "Summary"}, false},
+		{"description", content.Article{Title: "Title", Description: "Edited description", Summary: "Summary"}, false},
+		{"summary", content.Article{Title: "Title", Description: "Description"}, false},
+		{"moved text", content.Article{Title: "TitleDescription", Summary: "Summary"}, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.article.Hash() == base.Hash(); got != tt.same {
+				t.Errorf("Article.Hash() same = %v, want %v", got, tt.same)
+			}
+		})
+	}
+}
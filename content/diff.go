@@ -0,0 +1,105 @@
+package content
+
+import "regexp"
+
+type DiffType string
+
+const (
+	DiffEqual  DiffType = "equal"
+	DiffInsert DiffType = "insert"
+	DiffDelete DiffType = "delete"
+)
+
+// DiffOp is a part of the changes between two texts, which is either kept,
+// inserted or deleted.
+type DiffOp struct {
+	Type DiffType `json:"type"`
+	Text string   `json:"text"`
+}
+
+// maxDiffCells limits the size of the table used to find the common parts
+// of two texts. Larger changes are reported as a deletion of the old text
+// followed by an insertion of the new one.
+const maxDiffCells = 1 << 20
+
+// diffToken splits text into words, whitespace runs and single symbols, so
+// that html markup and punctuation are compared separately from the words
+// next to them.
+var diffToken = regexp.MustCompile(`[\p{L}\p{N}_]+|\s+|[^\p{L}\p{N}_\s]`)
+
+// Diff returns the word level changes needed to turn one text into another.
+func Diff(from, to string) []DiffOp {
+	a := diffToken.FindAllString(from, -1)
+	b := diffToken.FindAllString(to, -1)
+
+	var prefix, suffix int
+	for prefix < len(a) && prefix < len(b) && a[prefix] == b[prefix] {
+		prefix++
+	}
+
+	for suffix < len(a)-prefix && suffix < len(b)-prefix && a[len(a)-1-suffix] == b[len(b)-1-suffix] {
+		suffix++
+	}
+
+	ops := []DiffOp{}
+	add := func(t DiffType, tokens ...string) {
+		for _, token := range tokens {
+			if l := len(ops); l > 0 && ops[l-1].Type == t {
+				ops[l-1].Text += token
+			} else {
+				ops = append(ops, DiffOp{Type: t, Text: token})
+			}
+		}
+	}
+
+	add(DiffEqual, a[:prefix]...)
+
+	tail := a[len(a)-suffix:]
+	a, b = a[prefix:len(a)-suffix], b[prefix:len(b)-suffix]
+	if len(a)*len(b) > maxDiffCells {
+		add(DiffDelete, a...)
+		add(DiffInsert, b...)
+	} else {
+		// lcs[i][j] is the length of the longest common subsequence of
+		// a[i:] and b[j:].
+		lcs := make([][]int32, len(a)+1)
+		for i := range lcs {
+			lcs[i] = make([]int32, len(b)+1)
+		}
+
+		for i := len(a) - 1; i >= 0; i-- {
+			for j := len(b) - 1; j >= 0; j-- {
+				if a[i] == b[j] {
+					lcs[i][j] = lcs[i+1][j+1] + 1
+				} else if lcs[i+1][j] >= lcs[i][j+1] {
+					lcs[i][j] = lcs[i+1][j]
+				} else {
+					lcs[i][j] = lcs[i][j+1]
+				}
+			}
+		}
+
+		i, j := 0, 0
+		for i < len(a) && j < len(b) {
+			switch {
+			case a[i] == b[j]:
+				add(DiffEqual, a[i])
+				i++
+				j++
+			case lcs[i+1][j] >= lcs[i][j+1]:
+				add(DiffDelete, a[i])
+				i++
+			default:
+				add(DiffInsert, b[j])
+				j++
+			}
+		}
+
+		add(DiffDelete, a[i:]...)
+		add(DiffInsert, b[j:]...)
+	}
+
+	add(DiffEqual, tail...)
+
+	return ops
+}
@@ -0,0 +1,47 @@
+package content_test
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/urandom/readeef/content"
+)
+
+func TestDiff(t *testing.T) {
+	long := strings.Repeat("word ", 2000)
+	op := func(t content.DiffType, text string) content.DiffOp {
+		return content.DiffOp{Type: t, Text: text}
+	}
+
+	tests := []struct {
+		name string
+		from string
+		to   string
+		want []content.DiffOp
+	}{
+		{"empty", "", "", []content.DiffOp{}},
+		{"same", "Some text", "Some text", []content.DiffOp{op(content.DiffEqual, "Some text")}},
+		{"added", "", "Some text", []content.DiffOp{op(content.DiffInsert, "Some text")}},
+		{"removed", "Some text", "", []content.DiffOp{op(content.DiffDelete, "Some text")}},
+		{"replaced word", "The quick brown fox", "The slow brown fox", []content.DiffOp{
+			op(content.DiffEqual, "The "), op(content.DiffDelete, "quick"), op(content.DiffInsert, "slow"), op(content.DiffEqual, " brown fox"),
+		}},
+		{"inserted words", "Edited post", "Edited and corrected post", []content.DiffOp{
+			op(content.DiffEqual, "Edited "), op(content.DiffInsert, "and corrected "), op(content.DiffEqual, "post"),
+		}},
+		{"markup", "<p>Text</p>", "<p><b>Text</b></p>", []content.DiffOp{
+			op(content.DiffEqual, "<p>"), op(content.DiffInsert, "<b>"), op(content.DiffEqual, "Text"), op(content.DiffInsert, "</b>"), op(content.DiffEqual, "</p>"),
+		}},
+		{"too large", long + "a", "b" + long, []content.DiffOp{
+			op(content.DiffDelete, long+"a"), op(content.DiffInsert, "b"+long),
+		}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := content.Diff(tt.from, tt.to); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Diff() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,129 @@
+package content
+
+import (
+	"hash/fnv"
+	"html"
+	"math/bits"
+	"net/url"
+	"regexp"
+	"strings"
+)
+
+// MaxFingerprintDistance is the largest number of differing bits between
+// the fingerprints of two articles that are considered duplicates.
+const MaxFingerprintDistance = 3
+
+const fingerprintMinTokens = 20
+
+var (
+	trackingParams = map[string]bool{
+		"fbclid": true, "gclid": true, "dclid": true, "msclkid": true,
+		"yclid": true, "igshid": true, "mc_cid": true, "mc_eid": true,
+		"_ga": true, "_hsenc": true, "_hsmi": true, "ref_src": true,
+		"ref_url": true,
+	}
+	trackingPrefixes = []string{"utm_", "pk_", "__twitter"}
+
+	fingerprintTags   = regexp.MustCompile(`<[^>]*>`)
+	fingerprintTokens = regexp.MustCompile(`[\pL\pN]+`)
+)
+
+// CanonicalLink normalizes the article link, so that the same story linked
+// from different feeds can be matched. The scheme, a leading 'www.' and the
+// default port are ignored, and the fragment, as well as any tracking
+// parameters, are dropped. The remaining query parameters are sorted.
+func CanonicalLink(link string) string {
+	u, err := url.Parse(strings.TrimSpace(link))
+	if err != nil || !u.IsAbs() || u.Host == "" {
+		return link
+	}
+
+	host := strings.TrimPrefix(strings.ToLower(u.Hostname()), "www.")
+	if port := u.Port(); port != "" && port != "80" && port != "443" {
+		host += ":" + port
+	}
+
+	query := u.Query()
+	for param := range query {
+		if isTrackingParam(strings.ToLower(param)) {
+			query.Del(param)
+		}
+	}
+
+	path := u.EscapedPath()
+	if path != "/" {
+		path = strings.TrimSuffix(path, "/")
+	}
+
+	canonical := "https://" + host + path
+	// Encode sorts the parameters by key.
+	if q := query.Encode(); q != "" {
+		canonical += "?" + q
+	}
+
+	return canonical
+}
+
+func isTrackingParam(param string) bool {
+	if trackingParams[param] {
+		return true
+	}
+
+	for _, prefix := range trackingPrefixes {
+		if strings.HasPrefix(param, prefix) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// Fingerprint returns a simhash of the distinct words in the article's
+// description, such that the fingerprints of nearly identical articles
+// differ only in a few bits. The title is left out, since aggregators tend
+// to decorate it. Zero is returned for articles with too little text to be
+// compared reliably.
+func (a Article) Fingerprint() int64 {
+	text := html.UnescapeString(fingerprintTags.ReplaceAllString(a.Description, " "))
+	tokens := fingerprintTokens.FindAllString(strings.ToLower(text), -1)
+
+	if len(tokens) < fingerprintMinTokens {
+		return 0
+	}
+
+	var weights [64]int
+	seen := map[string]bool{}
+	for _, token := range tokens {
+		if seen[token] {
+			continue
+		}
+		seen[token] = true
+
+		h := fnv.New64a()
+		h.Write([]byte(token))
+		sum := h.Sum64()
+
+		for b := uint(0); b < 64; b++ {
+			if sum&(1<<b) == 0 {
+				weights[b]--
+			} else {
+				weights[b]++
+			}
+		}
+	}
+
+	var fingerprint uint64
+	for b := uint(0); b < 64; b++ {
+		if weights[b] > 0 {
+			fingerprint |= 1 << b
+		}
+	}
+
+	return int64(fingerprint)
+}
+
+// FingerprintDistance returns the number of bits in which two article
+// fingerprints differ.
+func FingerprintDistance(a, b int64) int {
+	return bits.OnesCount64(uint64(a ^ b))
+}
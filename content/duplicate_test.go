@@ -0,0 +1,76 @@
+package content_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/urandom/readeef/content"
+)
+
+const duplicateText = `The city council approved the new budget on Tuesday evening after a long
+debate about the funding of public transport, the renovation of several schools and the
+maintenance of the old bridge across the river, which has been closed since spring.`
+
+func TestCanonicalLink(t *testing.T) {
+	tests := []struct {
+		name string
+		link string
+		want string
+	}{
+		{"plain", "https://example.com/story", "https://example.com/story"},
+		{"scheme and host", "http://WWW.Example.com/story", "https://example.com/story"},
+		{"default port", "http://example.com:80/story", "https://example.com/story"},
+		{"custom port", "http://example.com:8080/story", "https://example.com:8080/story"},
+		{"trailing slash", "https://example.com/story/", "https://example.com/story"},
+		{"root", "https://example.com/", "https://example.com/"},
+		{"fragment", "https://example.com/story#comments", "https://example.com/story"},
+		{"tracking", "https://example.com/story?utm_source=rss&utm_medium=feed&fbclid=abc", "https://example.com/story"},
+		{"sorted query", "https://example.com/story?p=2&id=5&UTM_Campaign=x", "https://example.com/story?id=5&p=2"},
+		{"relative", "/story", "/story"},
+		{"invalid", "%zz", "%zz"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := content.CanonicalLink(tt.link); got != tt.want {
+				t.Errorf("CanonicalLink() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestArticle_Fingerprint(t *testing.T) {
+	base := content.Article{Title: "Budget approved", Description: duplicateText}
+
+	tests := []struct {
+		name      string
+		article   content.Article
+		duplicate bool
+	}{
+		{"same", base, true},
+		{"markup", content.Article{Title: "Budget <b>approved</b>", Description: "<p>" + duplicateText + "</p>"}, true},
+		{"case and punctuation", content.Article{Title: "Budget approved", Description: strings.ToUpper(duplicateText) + "!"}, true},
+		{"title", content.Article{Title: "Budget approved | City News", Description: duplicateText}, true},
+		{"appended", content.Article{Title: "Budget approved", Description: duplicateText + " Read more"}, true},
+		{"footer", content.Article{Title: "Budget approved", Description: duplicateText + " The post Budget approved appeared first on City News."}, true},
+		{"different", content.Article{Title: "Match report", Description: `The home team won the match on Sunday
+afternoon with two late goals, ending a run of four defeats and moving three places up the
+table, while the coach praised the young players who came in after the break.`}, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := tt.article.Fingerprint()
+			if got == 0 {
+				t.Fatalf("Article.Fingerprint() = 0")
+			}
+
+			distance := content.FingerprintDistance(base.Fingerprint(), got)
+			if (distance <= content.MaxFingerprintDistance) != tt.duplicate {
+				t.Errorf("Article.Fingerprint() distance = %d, want duplicate %v", distance, tt.duplicate)
+			}
+		})
+	}
+
+	if got := (content.Article{Title: "Short", Description: "Too short to compare"}).Fingerprint(); got != 0 {
+		t.Errorf("Article.Fingerprint() = %d, want 0", got)
+	}
+}
@@ -0,0 +1,35 @@
+package content
+
+import (
+	"errors"
+	"fmt"
+	"net/url"
+)
+
+// Enclosure is a media object, such as a podcast episode, attached to an
+// article.
+type Enclosure struct {
+	ArticleID ArticleID `db:"article_id" json:"-"`
+	URL       string    `db:"url" json:"url"`
+	MIMEType  string    `db:"mime_type" json:"mimeType,omitempty"`
+	// Length is the size of the media object in bytes.
+	Length int64 `db:"length" json:"length,omitempty"`
+	// Duration is the playing time of the media object in seconds.
+	Duration int64 `db:"duration" json:"duration,omitempty"`
+}
+
+func (e Enclosure) Validate() error {
+	if e.URL == "" {
+		return NewValidationError(errors.New("Article enclosure has no url"))
+	}
+
+	if u, err := url.Parse(e.URL); err != nil || !u.IsAbs() {
+		return NewValidationError(errors.New("Article enclosure url is not absolute"))
+	}
+
+	return nil
+}
+
+func (e Enclosure) String() string {
+	return fmt.Sprintf("%d: %s", e.ArticleID, e.URL)
+}
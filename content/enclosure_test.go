@@ -0,0 +1,29 @@
+package content_test
+
+import (
+	"testing"
+
+	"github.com/urandom/readeef/content"
+)
+
+func TestEnclosure_Validate(t *testing.T) {
+	tests := []struct {
+		name    string
+		URL     string
+		wantErr bool
+	}{
+		{"valid", "http://example.com/episode.mp3", false},
+		{"not absolute", "episode.mp3", true},
+		{"no url", "", true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			e := content.Enclosure{
+				URL: tt.URL,
+			}
+			if err := e.Validate(); (err != nil) != tt.wantErr {
+				t.Errorf("Enclosure.Validate() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
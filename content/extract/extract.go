@@ -14,10 +14,20 @@ type Generator interface {
 	Generate(link string) (content.Extract, error)
 }
 
+// AuthGenerator is implemented by generators that download the link
+// themselves, and can therefore use the auth of a private feed. Generators
+// relying on third-party services should not implement it, so that the
+// credentials are not leaked to them.
+type AuthGenerator interface {
+	GenerateWithAuth(link string, auth content.FeedAuth) (content.Extract, error)
+}
+
 // Get retrieves the extract from the repository. If it doesn't exist, a new
-// one is created and stored before returning.
+// one is created and stored before returning. The auth of the article's
+// feed is used if the generator supports it.
 func Get(
 	article content.Article,
+	auth content.FeedAuth,
 	repo repo.Extract,
 	generator Generator,
 	processors []processor.Article,
@@ -29,7 +39,13 @@ func Get(
 			return extract, errors.WithMessage(err, fmt.Sprintf("getting extract for %s from repo", article))
 		}
 
-		if extract, err = generator.Generate(article.Link); err != nil {
+		if ag, ok := generator.(AuthGenerator); ok && !auth.Empty() {
+			extract, err = ag.GenerateWithAuth(article.Link, auth)
+		} else {
+			extract, err = generator.Generate(article.Link)
+		}
+
+		if err != nil {
 			return extract, errors.WithMessage(err, fmt.Sprintf("generating extract from %s", article.Link))
 		}
 
@@ -21,19 +21,53 @@ const (
 
 type goose struct {
 	template *template.Template
+	client   *http.Client
 	buf      bytes.Buffer
 }
 
-func WithGoose(templateDir string, fs http.FileSystem) (Generator, error) {
+// WithGoose creates a generator that downloads articles with the given client
+// and extracts their content using GoOse.
+func WithGoose(templateDir string, fs http.FileSystem, client *http.Client) (Generator, error) {
 	tmpl, err := prepareTemplate(template.New("goose").Delims("{%", "%}"), fs, rawTmpl, gooseTmpl)
 	if err != nil {
 		return nil, errors.Wrap(err, "parsing goose template")
 	}
 
-	return goose{template: tmpl}, nil
+	return goose{template: tmpl, client: client}, nil
 }
 
-func (e goose) Generate(link string) (extract content.Extract, err error) {
+func (e goose) Generate(link string) (content.Extract, error) {
+	return e.GenerateWithAuth(link, content.FeedAuth{})
+}
+
+// GenerateWithAuth downloads the link using the auth of its feed, and
+// generates the extract from the downloaded html.
+func (e goose) GenerateWithAuth(link string, auth content.FeedAuth) (content.Extract, error) {
+	req, err := http.NewRequest("GET", link, nil)
+	if err != nil {
+		return content.Extract{}, errors.Wrapf(err, "creating request for %s", link)
+	}
+	auth.Apply(req)
+
+	resp, err := auth.Client(e.client).Do(req)
+	if err != nil {
+		return content.Extract{}, errors.Wrapf(err, "getting %s", link)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return content.Extract{}, errors.Errorf("getting %s: HTTP Status: %d", link, resp.StatusCode)
+	}
+
+	b, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return content.Extract{}, errors.Wrapf(err, "reading %s", link)
+	}
+
+	return e.generate(link, string(b))
+}
+
+func (e goose) generate(link, raw string) (extract content.Extract, err error) {
 	defer func() {
 		if r := recover(); r != nil {
 			err = errors.New(fmt.Sprintf("%v", r))
@@ -42,7 +76,8 @@ func (e goose) Generate(link string) (extract content.Extract, err error) {
 
 	g := goOse.New()
 	/* TODO: preserve links */
-	formatted, err := g.ExtractFromURL(link)
+	var formatted *goOse.Article
+	formatted, err = g.ExtractFromRawHTML(link, raw)
 
 	content := formatted.CleanedText
 	e.buf.Reset()
@@ -13,7 +13,8 @@ import (
 )
 
 type readability struct {
-	key string
+	key    string
+	client *http.Client
 }
 
 type readabilityData struct {
@@ -22,11 +23,11 @@ type readabilityData struct {
 	LeadImage string `json:"lead_image_url"`
 }
 
-func WithReadability(key string) (Generator, error) {
+func WithReadability(key string, client *http.Client) (Generator, error) {
 	if key == "" {
 		return nil, errors.New("Readability API key cannot be empty")
 	}
-	return readability{key: key}, nil
+	return readability{key: key, client: client}, nil
 }
 
 func (e readability) Generate(link string) (content.Extract, error) {
@@ -36,7 +37,7 @@ func (e readability) Generate(link string) (content.Extract, error) {
 
 	var r readabilityData
 
-	resp, err := http.Get(url)
+	resp, err := e.client.Get(url)
 
 	if err != nil {
 		return content.Extract{}, errors.Wrap(err, "getting url response")
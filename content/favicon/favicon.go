@@ -0,0 +1,192 @@
+package favicon
+
+import (
+	"encoding/base64"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/PuerkitoBio/goquery"
+	"github.com/pkg/errors"
+	"github.com/urandom/readeef/content"
+	"github.com/urandom/readeef/content/repo"
+	"github.com/urandom/readeef/log"
+)
+
+const (
+	// DefaultMaxSize is the largest icon that will be cached, in bytes,
+	// when no other limit is configured.
+	DefaultMaxSize = 64 * 1024
+
+	// refreshInterval is the time after which a cached icon, or the lack
+	// of one, is looked up again.
+	refreshInterval = 7 * 24 * time.Hour
+
+	// maxPageSize limits how much of the site's page is read when looking
+	// for icon links.
+	maxPageSize = 512 * 1024
+)
+
+// Fetcher discovers the favicons of the sites feeds belong to and caches
+// them in the repository.
+type Fetcher struct {
+	repo    repo.FeedImage
+	client  *http.Client
+	maxSize int64
+	log     log.Log
+}
+
+// NewFetcher creates a fetcher that caches icons no larger than maxSize
+// bytes.
+func NewFetcher(repo repo.FeedImage, client *http.Client, maxSize int64, log log.Log) Fetcher {
+	if client == nil {
+		client = http.DefaultClient
+	}
+
+	if maxSize <= 0 {
+		maxSize = DefaultMaxSize
+	}
+
+	return Fetcher{repo: repo, client: client, maxSize: maxSize, log: log}
+}
+
+// Fetch looks up and stores the favicon of the feed's site, unless a recent
+// result is already cached.
+func (f Fetcher) Fetch(feed content.Feed) error {
+	if cached, err := f.repo.Favicon(feed); err == nil {
+		if time.Since(cached.UpdateDate) < refreshInterval {
+			return nil
+		}
+	} else if !content.IsNoContent(err) {
+		return errors.WithMessage(err, "getting cached favicon")
+	}
+
+	site := feed.SiteLink
+	if site == "" {
+		site = feed.Link
+	}
+
+	base, err := url.Parse(site)
+	if err != nil || !base.IsAbs() {
+		return errors.Errorf("feed %s has no valid site link", feed)
+	}
+
+	f.log.Infof("Looking up the favicon of feed %s from %s", feed, base)
+
+	favicon := content.Favicon{FeedID: feed.ID, UpdateDate: time.Now()}
+	for _, candidate := range f.candidates(base) {
+		data, err := f.download(candidate)
+		if err != nil {
+			f.log.Debugf("Skipping favicon candidate %s: %v", candidate, err)
+			continue
+		}
+
+		favicon.Link = candidate
+		favicon.Data = data
+		break
+	}
+
+	// Sites without an icon are stored as well, so that they aren't looked
+	// up on every feed update.
+	if err := f.repo.UpdateFavicon(favicon); err != nil {
+		return errors.WithMessage(err, "updating favicon")
+	}
+
+	return nil
+}
+
+// candidates returns the icon links declared by the site's page, followed
+// by the conventional /favicon.ico location.
+func (f Fetcher) candidates(base *url.URL) []string {
+	fallback := (&url.URL{Scheme: base.Scheme, Host: base.Host, Path: "/favicon.ico"}).String()
+
+	resp, err := f.client.Get(base.String())
+	if err != nil {
+		return []string{fallback}
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return []string{fallback}
+	}
+
+	doc, err := goquery.NewDocumentFromReader(io.LimitReader(resp.Body, maxPageSize))
+	if err != nil {
+		return []string{fallback}
+	}
+
+	links := []string{}
+	doc.Find("link[rel][href]").Each(func(i int, s *goquery.Selection) {
+		if !isIconRel(s.AttrOr("rel", "")) {
+			return
+		}
+
+		if u, err := resp.Request.URL.Parse(s.AttrOr("href", "")); err == nil {
+			links = append(links, u.String())
+		}
+	})
+
+	return append(links, fallback)
+}
+
+// download fetches the icon and returns it as a data uri.
+func (f Fetcher) download(link string) (string, error) {
+	resp, err := f.client.Get(link)
+	if err != nil {
+		return "", errors.Wrap(err, "getting icon")
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return "", errors.Errorf("unexpected status %s", resp.Status)
+	}
+
+	if resp.ContentLength > f.maxSize {
+		return "", errors.Errorf("icon size %d exceeds the limit of %d", resp.ContentLength, f.maxSize)
+	}
+
+	b, err := ioutil.ReadAll(io.LimitReader(resp.Body, f.maxSize+1))
+	if err != nil {
+		return "", errors.Wrap(err, "reading icon")
+	}
+
+	if int64(len(b)) > f.maxSize {
+		return "", errors.Errorf("icon exceeds the limit of %d bytes", f.maxSize)
+	}
+
+	if len(b) == 0 {
+		return "", errors.New("empty icon")
+	}
+
+	mimeType := resp.Header.Get("Content-Type")
+	if i := strings.Index(mimeType, ";"); i != -1 {
+		mimeType = mimeType[:i]
+	}
+
+	mimeType = strings.ToLower(strings.TrimSpace(mimeType))
+
+	if !strings.HasPrefix(mimeType, "image/") {
+		mimeType = http.DetectContentType(b)
+	}
+
+	// Only raster images are kept, since the icons are served from the
+	// application's own origin.
+	if !content.IsRasterImage(mimeType) {
+		return "", errors.Errorf("unexpected content type %s", mimeType)
+	}
+
+	return "data:" + mimeType + ";base64," + base64.StdEncoding.EncodeToString(b), nil
+}
+
+func isIconRel(rel string) bool {
+	for _, r := range strings.Fields(strings.ToLower(rel)) {
+		if r == "icon" || r == "apple-touch-icon" {
+			return true
+		}
+	}
+
+	return false
+}
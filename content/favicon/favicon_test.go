@@ -0,0 +1,110 @@
+package favicon
+
+import (
+	"bytes"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/urandom/readeef/config"
+	"github.com/urandom/readeef/content"
+	"github.com/urandom/readeef/content/repo/mock_repo"
+	"github.com/urandom/readeef/log"
+)
+
+var (
+	pngIcon = append([]byte("\x89PNG\x0D\x0A\x1A\x0A"), bytes.Repeat([]byte{0}, 8)...)
+
+	logger log.Log
+)
+
+func init() {
+	cfg := config.Log{}
+	cfg.Converted.Writer = os.Stderr
+	cfg.Converted.Prefix = "[testing] "
+	logger = log.WithStd(cfg)
+}
+
+func TestFetcher_Fetch(t *testing.T) {
+	tests := []struct {
+		name     string
+		page     string
+		files    map[string][]byte
+		cached   *content.Favicon
+		wantLink string
+		wantData bool
+		skip     bool
+	}{
+		{"link element", `<html><head><link rel="shortcut icon" href="/static/icon.png"></head></html>`,
+			map[string][]byte{"/static/icon.png": pngIcon}, nil, "/static/icon.png", true, false},
+		{"fallback", `<html><head></head></html>`,
+			map[string][]byte{"/favicon.ico": pngIcon}, nil, "/favicon.ico", true, false},
+		{"too large", `<html><head><link rel="icon" href="/big.png"></head></html>`,
+			map[string][]byte{"/big.png": append(pngIcon, make([]byte, 64)...)}, nil, "", false, false},
+		{"not an image", `<html><head></head></html>`,
+			map[string][]byte{"/favicon.ico": []byte("<html></html>")}, nil, "", false, false},
+		{"svg", `<html><head><link rel="icon" href="/icon.svg"></head></html>`,
+			map[string][]byte{"/icon.svg": []byte("<svg></svg>")}, nil, "", false, false},
+		{"recently cached", ``, nil, &content.Favicon{FeedID: 1, UpdateDate: time.Now()}, "", false, true},
+		{"stale cache", `<html><head></head></html>`,
+			map[string][]byte{"/favicon.ico": pngIcon}, &content.Favicon{FeedID: 1, UpdateDate: time.Now().Add(-2 * refreshInterval)}, "/favicon.ico", true, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				if r.URL.Path == "/" {
+					w.Write([]byte(tt.page))
+					return
+				}
+
+				if b, ok := tt.files[r.URL.Path]; ok {
+					if strings.HasSuffix(r.URL.Path, ".svg") {
+						w.Header().Set("Content-Type", "image/svg+xml")
+					}
+					w.Write(b)
+					return
+				}
+
+				http.NotFound(w, r)
+			}))
+			defer server.Close()
+
+			feed := content.Feed{ID: 1, Link: server.URL + "/feed", SiteLink: server.URL + "/"}
+
+			r := mock_repo.NewMockFeedImage(ctrl)
+			if tt.cached == nil {
+				r.EXPECT().Favicon(feed).Return(content.Favicon{}, content.ErrNoContent)
+			} else {
+				r.EXPECT().Favicon(feed).Return(*tt.cached, nil)
+			}
+
+			if !tt.skip {
+				r.EXPECT().UpdateFavicon(gomock.Any()).Do(func(f content.Favicon) {
+					wantLink := ""
+					if tt.wantLink != "" {
+						wantLink = server.URL + tt.wantLink
+					}
+
+					if f.FeedID != feed.ID || f.Link != wantLink || (f.Data != "") != tt.wantData {
+						t.Errorf("Fetcher.Fetch() stored %v, want link %q", f, wantLink)
+					}
+
+					if f.UpdateDate.IsZero() {
+						t.Errorf("Fetcher.Fetch() stored no update date")
+					}
+				}).Return(nil)
+			}
+
+			if err := NewFetcher(r, nil, int64(len(pngIcon)), logger).Fetch(feed); err != nil {
+				t.Errorf("Fetcher.Fetch() error = %v", err)
+			}
+		})
+	}
+}
@@ -25,6 +25,34 @@ type Feed struct {
 	TTL            time.Duration   `json:"-"`
 	SkipHours      map[int]bool    `json:"-"`
 	SkipDays       map[string]bool `json:"-"`
+	Image          FeedImage       `db:"-" json:"-"`
+
+	// ETag and LastModified are the http cache validators of the last
+	// downloaded content.
+	ETag         string `db:"etag" json:"-"`
+	LastModified string `db:"last_modified" json:"-"`
+
+	// Dead feeds are no longer available at their link, and are not
+	// updated anymore.
+	Dead bool `db:"dead" json:"dead"`
+
+	// Credentials are the encrypted FeedAuth of a private feed.
+	Credentials []byte `db:"credentials" json:"-"`
+
+	// Scrape holds the rules of a feed whose articles are scraped from the
+	// html page at its link.
+	Scrape ScrapeRules `db:"scrape_rules" json:"-"`
+
+	// Diagnostic describes the charset conversion and the repairs that were
+	// needed to parse the last downloaded content of the feed.
+	Diagnostic string `db:"diagnostic" json:"diagnostic"`
+
+	// SelfLink is the canonical url of the feed, as advertised along with
+	// its hub. It is only known after the feed is downloaded.
+	SelfLink string `db:"-" json:"-"`
+
+	// NextUpdate is the time the feed is scheduled to be downloaded again.
+	NextUpdate time.Time `db:"-" json:"nextUpdate"`
 
 	parsedArticles []Article
 }
@@ -42,6 +70,10 @@ func (f Feed) Validate() error {
 		return NewValidationError(errors.New("no link"))
 	}
 
+	if !f.Scrape.Empty() {
+		return f.Scrape.Validate()
+	}
+
 	return nil
 }
 
@@ -50,7 +82,20 @@ func (f *Feed) Refresh(pf parser.Feed) {
 	f.Description = pf.Description
 	f.SiteLink = pf.SiteLink
 	f.HubLink = pf.HubLink
+	f.SelfLink = pf.SelfLink
 	f.UpdateError = ""
+	f.Diagnostic = pf.Diagnostic()
+
+	f.Image = FeedImage{}
+	if pf.Image.Url != "" {
+		f.Image = FeedImage{
+			FeedID: f.ID,
+			Title:  pf.Image.Title,
+			URL:    absoluteLink(pf.Image.Url, f.SiteLink, f.Link),
+			Width:  pf.Image.Width,
+			Height: pf.Image.Height,
+		}
+	}
 
 	f.parsedArticles = make([]Article, len(pf.Articles))
 
@@ -58,8 +103,13 @@ func (f *Feed) Refresh(pf parser.Feed) {
 		a := Article{
 			Title:       pf.Articles[i].Title,
 			Description: pf.Articles[i].Description,
+			Summary:     pf.Articles[i].Summary,
 			Link:        pf.Articles[i].Link,
 			Date:        pf.Articles[i].Date,
+			Author:      pf.Articles[i].Author,
+			Categories:  pf.Articles[i].Categories,
+
+			DateInferred: pf.Articles[i].DateInferred,
 		}
 		a.FeedID = f.ID
 
@@ -68,10 +118,40 @@ func (f *Feed) Refresh(pf parser.Feed) {
 			a.Guid.String = pf.Articles[i].Guid
 		}
 
+		for _, e := range pf.Articles[i].Enclosures {
+			enclosure := Enclosure{
+				URL:      e.Url,
+				MIMEType: e.Type,
+				Length:   e.Length,
+				Duration: int64(e.Duration / time.Second),
+			}
+
+			if enclosure.Validate() == nil {
+				a.Enclosures = append(a.Enclosures, enclosure)
+			}
+		}
+
 		f.parsedArticles[i] = a
 	}
 }
 
+// absoluteLink resolves the link against the first base that is an absolute
+// url.
+func absoluteLink(link string, bases ...string) string {
+	u, err := url.Parse(link)
+	if err != nil || u.IsAbs() {
+		return link
+	}
+
+	for _, b := range bases {
+		if base, err := url.Parse(b); err == nil && base.IsAbs() {
+			return base.ResolveReference(u).String()
+		}
+	}
+
+	return link
+}
+
 func (f Feed) ParsedArticles() (a []Article) {
 	return f.parsedArticles
 }
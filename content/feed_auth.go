@@ -0,0 +1,228 @@
+package content
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/json"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+// FeedAuth holds the credentials and custom request headers needed to
+// download a private feed. Cookies can be provided via a Cookie header.
+type FeedAuth struct {
+	Username string            `json:"username,omitempty"`
+	Password string            `json:"password,omitempty"`
+	Headers  map[string]string `json:"headers,omitempty"`
+}
+
+// Empty returns true if the auth contains neither credentials nor headers.
+func (a FeedAuth) Empty() bool {
+	return a.Username == "" && a.Password == "" && len(a.Headers) == 0
+}
+
+// Validate checks whether the header names and values can be sent in a
+// request.
+func (a FeedAuth) Validate() error {
+	for name, value := range a.Headers {
+		if name == "" || strings.ContainsAny(name, " \t\r\n:") {
+			return NewValidationError(errors.Errorf("invalid header name '%s'", name))
+		}
+
+		if strings.ContainsAny(value, "\r\n") {
+			return NewValidationError(errors.Errorf("invalid value for header '%s'", name))
+		}
+	}
+
+	return nil
+}
+
+// Apply sets the credentials and headers to the request.
+func (a FeedAuth) Apply(req *http.Request) {
+	if a.Username != "" || a.Password != "" {
+		req.SetBasicAuth(a.Username, a.Password)
+	}
+
+	for name, value := range a.Headers {
+		req.Header.Set(name, value)
+	}
+}
+
+// Client returns a copy of the client that drops the credentials and
+// headers of the auth when a request is redirected to another scheme or
+// host. The client itself is returned if the auth is empty.
+func (a FeedAuth) Client(c *http.Client) *http.Client {
+	if c == nil {
+		c = http.DefaultClient
+	}
+
+	if a.Empty() {
+		return c
+	}
+
+	client := *c
+	client.CheckRedirect = func(req *http.Request, via []*http.Request) error {
+		if len(via) >= 10 {
+			return errors.New("stopped after 10 redirects")
+		}
+
+		if !SameOrigin(req.URL, via[0].URL) {
+			req.Header.Del("Authorization")
+			for name := range a.Headers {
+				req.Header.Del(name)
+			}
+		}
+
+		if c.CheckRedirect != nil {
+			return c.CheckRedirect(req, via)
+		}
+
+		return nil
+	}
+
+	return &client
+}
+
+// Equal returns true if both auths hold the same credentials and headers.
+func (a FeedAuth) Equal(o FeedAuth) bool {
+	if a.Username != o.Username || a.Password != o.Password || len(a.Headers) != len(o.Headers) {
+		return false
+	}
+
+	for name, value := range a.Headers {
+		if v, ok := o.Headers[name]; !ok || v != value {
+			return false
+		}
+	}
+
+	return true
+}
+
+// ForLink returns the auth if the link has the same scheme and host as the
+// feed link, and an empty auth otherwise, so that the credentials of a feed
+// are not sent to other sites.
+func (a FeedAuth) ForLink(feedLink, link string) FeedAuth {
+	if a.Empty() || !SameOriginLinks(feedLink, link) {
+		return FeedAuth{}
+	}
+
+	return a
+}
+
+// SameOrigin returns true if both urls have the same scheme and host.
+func SameOrigin(a, b *url.URL) bool {
+	return strings.EqualFold(a.Scheme, b.Scheme) && strings.EqualFold(a.Host, b.Host)
+}
+
+// SameOriginLinks returns true if both links are valid urls with the same
+// scheme and host.
+func SameOriginLinks(a, b string) bool {
+	au, err := url.Parse(a)
+	if err != nil {
+		return false
+	}
+
+	bu, err := url.Parse(b)
+	if err != nil {
+		return false
+	}
+
+	return SameOrigin(au, bu)
+}
+
+// SplitLinkAuth removes the user info from the link, returning it as the
+// credentials of the auth.
+func SplitLinkAuth(link string, auth FeedAuth) (string, FeedAuth) {
+	u, err := url.Parse(link)
+	if err != nil || u.User == nil {
+		return link, auth
+	}
+
+	auth.Username = u.User.Username()
+	auth.Password, _ = u.User.Password()
+	u.User = nil
+
+	return u.String(), auth
+}
+
+// SetAuth encrypts the auth using the secret, and stores it in the feed.
+func (f *Feed) SetAuth(auth FeedAuth, secret []byte) error {
+	if err := auth.Validate(); err != nil {
+		return err
+	}
+
+	if auth.Empty() {
+		f.Credentials = nil
+		return nil
+	}
+
+	data, err := json.Marshal(auth)
+	if err != nil {
+		return errors.Wrap(err, "marshaling feed auth")
+	}
+
+	gcm, err := authCipher(secret)
+	if err != nil {
+		return err
+	}
+
+	nonce := make([]byte, gcm.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return errors.Wrap(err, "generating nonce")
+	}
+
+	f.Credentials = gcm.Seal(nonce, nonce, data, nil)
+
+	return nil
+}
+
+// Auth decrypts the auth stored in the feed using the secret.
+func (f Feed) Auth(secret []byte) (FeedAuth, error) {
+	var auth FeedAuth
+
+	if len(f.Credentials) == 0 {
+		return auth, nil
+	}
+
+	gcm, err := authCipher(secret)
+	if err != nil {
+		return auth, err
+	}
+
+	if len(f.Credentials) < gcm.NonceSize() {
+		return auth, errors.New("feed credentials too short")
+	}
+
+	nonce, sealed := f.Credentials[:gcm.NonceSize()], f.Credentials[gcm.NonceSize():]
+	data, err := gcm.Open(nil, nonce, sealed, nil)
+	if err != nil {
+		return auth, errors.Wrap(err, "decrypting feed credentials")
+	}
+
+	if err := json.Unmarshal(data, &auth); err != nil {
+		return auth, errors.Wrap(err, "unmarshaling feed auth")
+	}
+
+	return auth, nil
+}
+
+func authCipher(secret []byte) (cipher.AEAD, error) {
+	key := sha256.Sum256(secret)
+
+	block, err := aes.NewCipher(key[:])
+	if err != nil {
+		return nil, errors.Wrap(err, "creating auth cipher")
+	}
+
+	gcm, err := cipher.NewGCM(block)
+	if err != nil {
+		return nil, errors.Wrap(err, "creating auth cipher")
+	}
+
+	return gcm, nil
+}
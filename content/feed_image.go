@@ -0,0 +1,100 @@
+package content
+
+import (
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"net/url"
+	"strings"
+	"time"
+)
+
+// FeedImage is the logo advertised by the feed itself.
+type FeedImage struct {
+	FeedID FeedID `db:"feed_id" json:"-"`
+	Title  string `json:"title,omitempty"`
+	URL    string `json:"url"`
+	Width  int    `json:"width,omitempty"`
+	Height int    `json:"height,omitempty"`
+}
+
+// Favicon is the locally cached icon of the site a feed belongs to. An empty
+// Data field marks a site without a usable icon.
+type Favicon struct {
+	FeedID     FeedID    `db:"feed_id"`
+	Link       string    `db:"link"`
+	Data       string    `db:"data"`
+	UpdateDate time.Time `db:"update_date"`
+}
+
+func (i FeedImage) Validate() error {
+	if i.FeedID == 0 {
+		return NewValidationError(errors.New("Feed image has no feed id"))
+	}
+
+	if u, err := url.Parse(i.URL); err != nil || !u.IsAbs() {
+		return NewValidationError(errors.New("Feed image has no valid url"))
+	}
+
+	return nil
+}
+
+func (i FeedImage) String() string {
+	return fmt.Sprintf("%d: %s", i.FeedID, i.URL)
+}
+
+func (f Favicon) Validate() error {
+	if f.FeedID == 0 {
+		return NewValidationError(errors.New("Favicon has no feed id"))
+	}
+
+	if f.Data != "" && !strings.HasPrefix(f.Data, "data:") {
+		return NewValidationError(errors.New("Favicon data is not a data uri"))
+	}
+
+	return nil
+}
+
+func (f Favicon) String() string {
+	return fmt.Sprintf("%d: %s", f.FeedID, f.Link)
+}
+
+// rasterImageTypes are the icon types that are safe to serve from the
+// application's origin. Vector formats, such as SVG, may contain scripts.
+var rasterImageTypes = map[string]bool{
+	"image/png":                true,
+	"image/x-icon":             true,
+	"image/vnd.microsoft.icon": true,
+	"image/gif":                true,
+	"image/jpeg":               true,
+	"image/webp":               true,
+}
+
+// IsRasterImage reports whether the mime type is one of the allowed raster
+// icon types.
+func IsRasterImage(mimeType string) bool {
+	return rasterImageTypes[strings.ToLower(strings.TrimSpace(mimeType))]
+}
+
+// Decode returns the mime type and raw bytes of the cached icon.
+func (f Favicon) Decode() (string, []byte, error) {
+	if f.Data == "" {
+		return "", nil, ErrNoContent
+	}
+
+	meta, data := f.Data, ""
+	if i := strings.Index(meta, ","); i != -1 {
+		meta, data = meta[:i], meta[i+1:]
+	}
+
+	if !strings.HasPrefix(meta, "data:") || !strings.HasSuffix(meta, ";base64") {
+		return "", nil, errors.New("invalid favicon data uri")
+	}
+
+	b, err := base64.StdEncoding.DecodeString(data)
+	if err != nil {
+		return "", nil, err
+	}
+
+	return strings.TrimSuffix(strings.TrimPrefix(meta, "data:"), ";base64"), b, nil
+}
@@ -0,0 +1,57 @@
+package content_test
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/urandom/readeef/content"
+)
+
+func TestFeedImage_Validate(t *testing.T) {
+	tests := []struct {
+		name    string
+		FeedID  content.FeedID
+		URL     string
+		wantErr bool
+	}{
+		{"valid", 1, "http://sugr.org/logo.png", false},
+		{"no feed id", 0, "http://sugr.org/logo.png", true},
+		{"relative url", 1, "/logo.png", true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			i := content.FeedImage{FeedID: tt.FeedID, URL: tt.URL}
+			if err := i.Validate(); (err != nil) != tt.wantErr {
+				t.Errorf("FeedImage.Validate() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestFavicon_Decode(t *testing.T) {
+	tests := []struct {
+		name     string
+		data     string
+		mimeType string
+		b        []byte
+		wantErr  bool
+	}{
+		{"valid", "data:image/png;base64,AQID", "image/png", []byte{1, 2, 3}, false},
+		{"empty", "", "", nil, true},
+		{"not base64", "data:image/png,AQID", "", nil, true},
+		{"invalid base64", "data:image/png;base64,!!", "", nil, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mimeType, b, err := content.Favicon{FeedID: 1, Data: tt.data}.Decode()
+			if (err != nil) != tt.wantErr {
+				t.Errorf("Favicon.Decode() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+
+			if mimeType != tt.mimeType || !bytes.Equal(b, tt.b) {
+				t.Errorf("Favicon.Decode() = %s %v, want %s %v", mimeType, b, tt.mimeType, tt.b)
+			}
+		})
+	}
+}
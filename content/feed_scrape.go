@@ -0,0 +1,62 @@
+package content
+
+import (
+	"database/sql/driver"
+	"encoding/json"
+	"fmt"
+
+	"github.com/pkg/errors"
+	"github.com/urandom/readeef/parser"
+)
+
+// ScrapeRules are the CSS selectors of a feed that is scraped from an html
+// page, for sites that do not publish a feed.
+type ScrapeRules parser.ScrapeRules
+
+// Empty returns true if the feed is not scraped.
+func (r ScrapeRules) Empty() bool {
+	return parser.ScrapeRules(r).Empty()
+}
+
+// Validate checks whether the selectors of the rules are valid.
+func (r ScrapeRules) Validate() error {
+	if err := parser.ScrapeRules(r).Validate(); err != nil {
+		return NewValidationError(err)
+	}
+
+	return nil
+}
+
+func (r *ScrapeRules) Scan(src interface{}) error {
+	var data []byte
+	switch t := src.(type) {
+	case nil:
+		return nil
+	case string:
+		data = []byte(t)
+	case []byte:
+		data = t
+	default:
+		return fmt.Errorf("Scan source '%#v' (%T) was not of type string (ScrapeRules)", src, src)
+	}
+
+	if len(data) == 0 {
+		*r = ScrapeRules{}
+		return nil
+	}
+
+	return errors.Wrap(json.Unmarshal(data, r), "unmarshaling scrape rules")
+}
+
+func (r ScrapeRules) Value() (driver.Value, error) {
+	if r.Empty() {
+		return nil, nil
+	}
+
+	b, err := json.Marshal(r)
+	if err != nil {
+		return nil, errors.Wrap(err, "marshaling scrape rules")
+	}
+
+	return string(b), nil
+}
@@ -0,0 +1,64 @@
+package content_test
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/urandom/readeef/content"
+)
+
+func TestScrapeRules_Validate(t *testing.T) {
+	tests := []struct {
+		name    string
+		rules   content.ScrapeRules
+		wantErr bool
+	}{
+		{"valid", content.ScrapeRules{Item: "article", Title: "h2", Date: "time"}, false},
+		{"no item", content.ScrapeRules{Title: "h2"}, true},
+		{"invalid selector", content.ScrapeRules{Item: "article", Body: "div["}, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.rules.Validate()
+			if (err != nil) != tt.wantErr {
+				t.Errorf("ScrapeRules.Validate() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+
+			if tt.wantErr && !content.IsValidationError(err) {
+				t.Errorf("ScrapeRules.Validate() error = %v, want a validation error", err)
+			}
+		})
+	}
+}
+
+func TestScrapeRules_Value(t *testing.T) {
+	tests := []struct {
+		name  string
+		rules content.ScrapeRules
+	}{
+		{"empty", content.ScrapeRules{}},
+		{"rules", content.ScrapeRules{Item: "article", Title: "h2", Link: "a.more", Date: "time", Body: ".content"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			v, err := tt.rules.Value()
+			if err != nil {
+				t.Fatalf("ScrapeRules.Value() error = %v", err)
+			}
+
+			if tt.rules.Empty() != (v == nil) {
+				t.Errorf("ScrapeRules.Value() = %v, empty %v", v, tt.rules.Empty())
+			}
+
+			var got content.ScrapeRules
+			if err := got.Scan(v); err != nil {
+				t.Fatalf("ScrapeRules.Scan() error = %v", err)
+			}
+
+			if !reflect.DeepEqual(got, tt.rules) {
+				t.Errorf("ScrapeRules.Scan() = %v, want %v", got, tt.rules)
+			}
+		})
+	}
+}
@@ -1,6 +1,7 @@
 package content_test
 
 import (
+	"reflect"
 	"testing"
 
 	"github.com/urandom/readeef/content"
@@ -48,6 +49,10 @@ func TestFeed_Refresh(t *testing.T) {
 			{Title: "Title 1"},
 			{Title: "Title 2"},
 		}}},
+		{"with authors and categories", content.Feed{}, parser.Feed{Title: "Title", Articles: []parser.Article{
+			{Title: "Title 1", Author: "John Doe", Categories: []string{"Go", "News"}},
+		}}},
+		{"repaired", content.Feed{Diagnostic: "converted from koi8-r"}, parser.Feed{Title: "Title", Charset: "windows-1251", Repairs: []parser.Repair{parser.RepairAmpersands}}},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
@@ -70,6 +75,10 @@ func TestFeed_Refresh(t *testing.T) {
 				t.Errorf("Feed.Refresh() hubLink want = %v, got %v", tt.parsed.HubLink, f.HubLink)
 			}
 
+			if f.Diagnostic != tt.parsed.Diagnostic() {
+				t.Errorf("Feed.Refresh() diagnostic want = %v, got %v", tt.parsed.Diagnostic(), f.Diagnostic)
+			}
+
 			if len(f.ParsedArticles()) != len(tt.parsed.Articles) {
 				t.Errorf("Feed.Refresh() articles want = %v, got %v", len(tt.parsed.Articles), len(f.ParsedArticles()))
 			}
@@ -78,6 +87,38 @@ func TestFeed_Refresh(t *testing.T) {
 				if a.Title != tt.parsed.Articles[i].Title {
 					t.Errorf("Feed.Refresh() article %d title want = %v, got %v", i, tt.parsed.Articles[i].Title, a.Title)
 				}
+
+				if a.Author != tt.parsed.Articles[i].Author {
+					t.Errorf("Feed.Refresh() article %d author want = %v, got %v", i, tt.parsed.Articles[i].Author, a.Author)
+				}
+
+				if !reflect.DeepEqual(a.Categories, tt.parsed.Articles[i].Categories) {
+					t.Errorf("Feed.Refresh() article %d categories want = %v, got %v", i, tt.parsed.Articles[i].Categories, a.Categories)
+				}
+			}
+		})
+	}
+}
+
+func TestFeed_RefreshImage(t *testing.T) {
+	tests := []struct {
+		name   string
+		parsed parser.Feed
+		want   content.FeedImage
+	}{
+		{"no image", parser.Feed{SiteLink: "http://sugr.org"}, content.FeedImage{}},
+		{"absolute", parser.Feed{SiteLink: "http://sugr.org", Image: parser.Image{Title: "Logo", Url: "http://cdn.sugr.org/logo.png", Width: 32, Height: 32}},
+			content.FeedImage{FeedID: 1, Title: "Logo", URL: "http://cdn.sugr.org/logo.png", Width: 32, Height: 32}},
+		{"relative", parser.Feed{SiteLink: "http://sugr.org/blog/", Image: parser.Image{Url: "logo.png"}},
+			content.FeedImage{FeedID: 1, URL: "http://sugr.org/blog/logo.png"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			f := content.Feed{ID: 1, Link: "http://sugr.org/feed"}
+			f.Refresh(tt.parsed)
+
+			if f.Image != tt.want {
+				t.Errorf("Feed.Refresh() image want = %v, got %v", tt.want, f.Image)
 			}
 		})
 	}
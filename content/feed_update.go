@@ -0,0 +1,43 @@
+package content
+
+import (
+	"errors"
+	"fmt"
+	"time"
+)
+
+// FeedUpdate records a single download of a feed, including the ones that
+// found the feed unchanged, so that the feeds that fail intermittently can be
+// told apart from the ones that rarely publish.
+type FeedUpdate struct {
+	FeedID FeedID    `db:"feed_id" json:"feedID"`
+	Date   time.Time `db:"update_date" json:"date"`
+	// Duration is the time taken to download the feed, in nanoseconds.
+	Duration time.Duration `db:"duration" json:"duration"`
+	// Status is the http status of the response, or zero if no response
+	// was received.
+	Status int `db:"status" json:"status,omitempty"`
+	// Bytes is the size of the downloaded content.
+	Bytes int64 `db:"bytes" json:"bytes"`
+
+	NewArticles     int `db:"new_articles" json:"newArticles"`
+	UpdatedArticles int `db:"updated_articles" json:"updatedArticles"`
+
+	Error string `db:"error" json:"error,omitempty"`
+}
+
+func (u FeedUpdate) Validate() error {
+	if u.FeedID == 0 {
+		return NewValidationError(errors.New("Feed update has no feed id"))
+	}
+
+	if u.Date.IsZero() {
+		return NewValidationError(errors.New("Feed update has no date"))
+	}
+
+	return nil
+}
+
+func (u FeedUpdate) String() string {
+	return fmt.Sprintf("%d: %s", u.FeedID, u.Date.Format(time.RFC3339))
+}
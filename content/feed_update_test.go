@@ -0,0 +1,29 @@
+package content_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/urandom/readeef/content"
+)
+
+func TestFeedUpdate_Validate(t *testing.T) {
+	tests := []struct {
+		name    string
+		FeedID  content.FeedID
+		Date    time.Time
+		wantErr bool
+	}{
+		{"valid", 1, time.Now(), false},
+		{"no feed id", 0, time.Now(), true},
+		{"no date", 1, time.Time{}, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			u := content.FeedUpdate{FeedID: tt.FeedID, Date: tt.Date}
+			if err := u.Validate(); (err != nil) != tt.wantErr {
+				t.Errorf("FeedUpdate.Validate() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
@@ -0,0 +1,72 @@
+package monitor
+
+import (
+	"context"
+	"sync"
+
+	"github.com/urandom/readeef/content"
+	"github.com/urandom/readeef/content/favicon"
+	"github.com/urandom/readeef/content/repo/eventable"
+	"github.com/urandom/readeef/log"
+)
+
+// faviconWorkers is the number of favicons looked up at the same time.
+const faviconWorkers = 4
+
+// Limiter bounds the number of simultaneous downloads, in total and per
+// host. The function returned by Acquire releases the acquired slot.
+type Limiter interface {
+	Acquire(ctx context.Context, link string) (func(), bool)
+}
+
+// Favicons caches the site icons of updated feeds. The icons are fetched by
+// a few workers at most, within the download limits of the limiter, and a
+// feed whose icon is already being fetched is skipped.
+func Favicons(ctx context.Context, service eventable.Service, fetcher favicon.Fetcher, limiter Limiter, log log.Log) {
+	var mu sync.Mutex
+	inFlight := map[content.FeedID]bool{}
+	workers := make(chan struct{}, faviconWorkers)
+
+	for event := range service.Listener() {
+		switch data := event.Data.(type) {
+		case eventable.FeedUpdateData:
+			mu.Lock()
+			if inFlight[data.Feed.ID] {
+				mu.Unlock()
+				continue
+			}
+			inFlight[data.Feed.ID] = true
+			mu.Unlock()
+
+			go func(feed content.Feed) {
+				defer func() {
+					mu.Lock()
+					delete(inFlight, feed.ID)
+					mu.Unlock()
+				}()
+
+				select {
+				case workers <- struct{}{}:
+					defer func() { <-workers }()
+				case <-ctx.Done():
+					return
+				}
+
+				link := feed.SiteLink
+				if link == "" {
+					link = feed.Link
+				}
+
+				release, ok := limiter.Acquire(ctx, link)
+				if !ok {
+					return
+				}
+				defer release()
+
+				if err := fetcher.Fetch(feed); err != nil {
+					log.Printf("Error fetching favicon for feed %s: %+v", feed, err)
+				}
+			}(data.Feed)
+		}
+	}
+}
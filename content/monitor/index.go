@@ -1,6 +1,7 @@
 package monitor
 
 import (
+	"github.com/urandom/readeef/content"
 	"github.com/urandom/readeef/content/repo/eventable"
 	"github.com/urandom/readeef/content/search"
 	"github.com/urandom/readeef/log"
@@ -20,7 +21,10 @@ func Index(service eventable.Service, provider search.Provider, log log.Log) {
 func processIndexUpdateEvent(data eventable.FeedUpdateData, provider search.Provider, log log.Log) {
 	log.Infof("Updating article search index for feed %s", data.Feed)
 
-	if err := provider.BatchIndex(data.NewArticles, search.BatchAdd); err != nil {
+	// Edited articles are indexed again, replacing their old content.
+	articles := append(append([]content.Article{}, data.NewArticles...), data.UpdatedArticles...)
+
+	if err := provider.BatchIndex(articles, search.BatchAdd); err != nil {
 		log.Printf("Error adding articles from %s to search index: %+v", data.Feed, err)
 	}
 }
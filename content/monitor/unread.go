@@ -9,7 +9,11 @@ import (
 	"github.com/urandom/readeef/log"
 )
 
-func Unread(ctx context.Context, service eventable.Service, log log.Log) {
+// Unread marks the new articles of each feed update as unread for all
+// users. If markUpdated is set, the articles edited by their publishers are
+// marked as unread again as well. Duplicates of articles the user has
+// already read in another feed are left as read.
+func Unread(ctx context.Context, service eventable.Service, markUpdated bool, log log.Log) {
 	// Grab the non-eventable article repo. We don't want to notify on the
 	// initial unread mark.
 	articleRepo := service.Service.ArticleRepo()
@@ -30,11 +34,20 @@ func Unread(ctx context.Context, service eventable.Service, log log.Log) {
 	for event := range service.Listener() {
 		switch data := event.Data.(type) {
 		case eventable.FeedUpdateData:
+			articles := data.NewArticles
+			if markUpdated {
+				articles = append(append([]content.Article{}, articles...), data.UpdatedArticles...)
+			}
+
+			if len(articles) == 0 {
+				continue
+			}
+
 			log.Infof("Setting new feed %s articles to unread", data.Feed)
 
-			ids := make([]content.ArticleID, len(data.NewArticles))
-			for i := range data.NewArticles {
-				ids[i] = data.NewArticles[i].ID
+			ids := make([]content.ArticleID, len(articles))
+			for i := range articles {
+				ids[i] = articles[i].ID
 			}
 
 			users, err := userRepo.All()
@@ -47,6 +60,7 @@ func Unread(ctx context.Context, service eventable.Service, log log.Log) {
 				if err := articleRepo.Read(
 					false, user, content.IDs(ids),
 					content.Filters(content.GetUserFilters(user)),
+					content.ExcludeReadDuplicates,
 				); err != nil {
 					log.Printf("Error marking new articles as unread: %+v", err)
 				}
@@ -27,38 +27,37 @@ func (p Cleanup) ProcessFeed(f parser.Feed) parser.Feed {
 	p.log.Infof("Cleaning up feed '%s'\n", f.Title)
 
 	for i := range f.Articles {
-		f.Articles[i].Description = strings.TrimSpace(f.Articles[i].Description)
+		f.Articles[i].Description = p.cleanupContent(f.Articles[i].Description)
+		f.Articles[i].Summary = p.cleanupContent(f.Articles[i].Summary)
+	}
 
-		// html.Parse breaks on self-closing iframe tags
-		f.Articles[i].Description =
-			iframeFixer.ReplaceAllString(f.Articles[i].Description, "$1></iframe>")
+	return f
+}
 
-		if nodes, err := html.ParseFragment(strings.NewReader(f.Articles[i].Description), nil); err == nil {
-			if nodesCleanup(nodes) {
-				if len(nodes) == 0 {
-					break
-				}
+func (p Cleanup) cleanupContent(content string) string {
+	content = strings.TrimSpace(content)
 
-				buf := pool.Buffer.Get()
-				defer pool.Buffer.Put(buf)
+	// html.Parse breaks on self-closing iframe tags
+	content = iframeFixer.ReplaceAllString(content, "$1></iframe>")
 
-				for _, n := range nodes {
-					err = html.Render(buf, n)
-					if err != nil {
-						break
-					}
-				}
+	nodes, err := html.ParseFragment(strings.NewReader(content), nil)
+	if err != nil || !nodesCleanup(nodes) || len(nodes) == 0 {
+		return content
+	}
 
-				content := buf.String()
+	buf := pool.Buffer.Get()
+	defer pool.Buffer.Put(buf)
 
-				// net/http tries to provide valid html, adding html, head and body tags
-				content = content[strings.Index(content, "<body>")+6 : strings.LastIndex(content, "</body>")]
-				f.Articles[i].Description = content
-			}
+	for _, n := range nodes {
+		if err = html.Render(buf, n); err != nil {
+			return content
 		}
 	}
 
-	return f
+	rendered := buf.String()
+
+	// net/http tries to provide valid html, adding html, head and body tags
+	return rendered[strings.Index(rendered, "<body>")+6 : strings.LastIndex(rendered, "</body>")]
 }
 
 func nodesCleanup(nodes []*html.Node) bool {
@@ -18,6 +18,11 @@ func TestCleanup_ProcessFeed(t *testing.T) {
 		}}, parser.Feed{Articles: []parser.Article{
 			{Description: exp1},
 		}}},
+		{"summary", parser.Feed{Articles: []parser.Article{
+			{Description: "<p>Body</p>", Summary: `<p onclick="alert(1)">Teaser</p>`},
+		}}, parser.Feed{Articles: []parser.Article{
+			{Description: "<p>Body</p>", Summary: "<p>Teaser</p>"},
+		}}},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
@@ -38,6 +43,10 @@ func TestCleanup_ProcessFeed(t *testing.T) {
 					t.Errorf("Cleanup.ProcessFeed() = \n%s, want \n%s", g, w)
 					return
 				}
+
+				if got.Articles[i].Summary != tt.want.Articles[i].Summary {
+					t.Errorf("Cleanup.ProcessFeed() summary = %s, want %s", got.Articles[i].Summary, tt.want.Articles[i].Summary)
+				}
 			}
 		})
 	}
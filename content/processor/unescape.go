@@ -25,6 +25,7 @@ func (p Unescape) ProcessArticles(articles []content.Article) []content.Article
 
 	for i := range articles {
 		articles[i].Description = p.processDescription(articles[i].Description)
+		articles[i].Summary = p.processDescription(articles[i].Summary)
 	}
 
 	return articles
@@ -33,6 +34,7 @@ func (p Unescape) ProcessArticles(articles []content.Article) []content.Article
 func (p Unescape) ProcessFeed(f parser.Feed) parser.Feed {
 	for i := range f.Articles {
 		f.Articles[i].Description = p.processDescription(f.Articles[i].Description)
+		f.Articles[i].Summary = p.processDescription(f.Articles[i].Summary)
 	}
 
 	return f
@@ -1,6 +1,10 @@
 package repo
 
-import "github.com/urandom/readeef/content"
+import (
+	"time"
+
+	"github.com/urandom/readeef/content"
+)
 
 // Article allows fetching and manipulating content.Article objects
 type Article interface {
@@ -15,4 +19,6 @@ type Article interface {
 	Favor(bool, content.User, ...content.QueryOpt) error
 
 	RemoveStaleUnreadRecords() error
+
+	Purge(feed content.Feed, before time.Time, keep int) ([]content.Article, error)
 }
@@ -0,0 +1,8 @@
+package repo
+
+import "github.com/urandom/readeef/content"
+
+// ArticleRevision allows fetching content.ArticleRevision objects
+type ArticleRevision interface {
+	ForArticle(content.Article) ([]content.ArticleRevision, error)
+}
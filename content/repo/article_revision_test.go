@@ -0,0 +1,83 @@
+package repo_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/urandom/readeef/content"
+	"github.com/urandom/readeef/parser"
+)
+
+func Test_articleRevisionRepo_ForArticle(t *testing.T) {
+	skipTest(t)
+	setupFeed()
+
+	date := time.Date(2018, time.March, 1, 12, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name          string
+		article       parser.Article
+		wantUpdated   bool
+		wantRevisions []string
+	}{
+		{"created", parser.Article{Title: "Original", Description: "Original text"}, false, nil},
+		{"unchanged", parser.Article{Title: "Original", Description: "Original text"}, false, nil},
+		{"edited", parser.Article{Title: "Edited", Description: "Edited text"}, true, []string{"Original"}},
+		{"edited again", parser.Article{Title: "Edited", Description: "Edited text again"}, true, []string{"Original", "Edited"}},
+		{"unchanged after edit", parser.Article{Title: "Edited", Description: "Edited text again"}, false, []string{"Original", "Edited"}},
+	}
+
+	feed := content.Feed{Link: "http://sugr.org/revisions"}
+	r := service.FeedRepo()
+	defer func() { r.Delete(feed) }()
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.article.Link = "http://sugr.org/revisions/article"
+			tt.article.Date = date
+
+			feed.Refresh(parser.Feed{Title: "revisions", Articles: []parser.Article{tt.article}})
+			articles, err := r.Update(&feed)
+			if err != nil {
+				t.Fatalf("feedRepo.Update() error = %v", err)
+			}
+
+			updated := len(articles) == 1 && articles[0].Updated
+			if updated != tt.wantUpdated {
+				t.Errorf("feedRepo.Update() updated = %v, want %v", updated, tt.wantUpdated)
+			}
+
+			stored, err := service.ArticleRepo().All(content.FeedIDs([]content.FeedID{feed.ID}))
+			if err != nil || len(stored) != 1 {
+				t.Fatalf("articleRepo.All() = %v, error = %v", stored, err)
+			}
+
+			if stored[0].Title != tt.article.Title || stored[0].Description != tt.article.Description {
+				t.Errorf("articleRepo.All() = %v, want %v", stored[0], tt.article)
+			}
+
+			if len(articles) == 1 && articles[0].ID != stored[0].ID {
+				t.Errorf("feedRepo.Update() article id = %d, want %d", articles[0].ID, stored[0].ID)
+			}
+
+			if stored[0].Updated != (len(tt.wantRevisions) > 0) || stored[0].Updated != (stored[0].UpdateDate != nil) {
+				t.Errorf("articleRepo.All() updated = %v, update date = %v", stored[0].Updated, stored[0].UpdateDate)
+			}
+
+			revisions, err := service.ArticleRevisionRepo().ForArticle(stored[0])
+			if err != nil {
+				t.Fatalf("articleRevisionRepo.ForArticle() error = %v", err)
+			}
+
+			if len(revisions) != len(tt.wantRevisions) {
+				t.Fatalf("articleRevisionRepo.ForArticle() = %v, want %v", revisions, tt.wantRevisions)
+			}
+
+			for i := range revisions {
+				if revisions[i].Title != tt.wantRevisions[i] || revisions[i].ArticleID != stored[0].ID {
+					t.Errorf("articleRevisionRepo.ForArticle() revision %d = %v, want %s", i, revisions[i], tt.wantRevisions[i])
+				}
+			}
+		})
+	}
+}
@@ -1,6 +1,7 @@
 package repo_test
 
 import (
+	"reflect"
 	"sort"
 	"strings"
 	"sync"
@@ -8,6 +9,7 @@ import (
 	"time"
 
 	"github.com/urandom/readeef/content"
+	"github.com/urandom/readeef/parser"
 )
 
 var (
@@ -485,3 +487,240 @@ func Test_articleRepo_Favor(t *testing.T) {
 		})
 	}
 }
+
+func Test_articleRepo_Duplicates(t *testing.T) {
+	skipTest(t)
+	setupFeed()
+
+	text := `The city council approved the new budget on Tuesday evening after a long
+debate about the funding of public transport, the renovation of several schools and the
+maintenance of the old bridge across the river, which has been closed since spring.`
+
+	dupFeed1 := content.Feed{Link: "http://sugr.org/duplicates/1"}
+	dupFeed2 := content.Feed{Link: "http://sugr.org/duplicates/2"}
+
+	dupFeed1.Refresh(parser.Feed{Title: "duplicates 1", Articles: []parser.Article{
+		{Title: "Story", Description: "Story", Link: "http://example.com/story?utm_source=one", Date: time.Now()},
+		{Title: "Budget", Description: text, Link: "http://example.com/budget", Date: time.Now()},
+	}})
+	dupFeed2.Refresh(parser.Feed{Title: "duplicates 2", Articles: []parser.Article{
+		{Title: "Story copy", Description: "Story", Link: "https://www.example.com/story/?fbclid=two", Date: time.Now()},
+		{Title: "Budget copy", Description: text + " Read more", Link: "http://aggregator.com/budget", Date: time.Now()},
+		{Title: "Another", Description: "Another", Link: "http://aggregator.com/another", Date: time.Now()},
+	}})
+
+	u1 := content.User{Login: user1}
+	u2 := content.User{Login: user2}
+
+	createFeed(&dupFeed1, u1)
+	defer func() { service.FeedRepo().Delete(dupFeed1) }()
+	createFeed(&dupFeed2, u1, u2)
+	defer func() { service.FeedRepo().Delete(dupFeed2) }()
+
+	r := service.ArticleRepo()
+	stored, err := r.All(content.FeedIDs([]content.FeedID{dupFeed1.ID, dupFeed2.ID}))
+	if err != nil {
+		t.Fatalf("articleRepo.All() error = %v", err)
+	}
+
+	byTitle := map[string]content.Article{}
+	for _, a := range stored {
+		byTitle[a.Title] = a
+	}
+
+	for title, canonical := range map[string]string{
+		"Story": "", "Budget": "", "Another": "",
+		"Story copy": "Story", "Budget copy": "Budget",
+	} {
+		want := content.ArticleID(0)
+		if canonical != "" {
+			want = byTitle[canonical].ID
+		}
+
+		if got := byTitle[title].DuplicateOf; got != want {
+			t.Errorf("articleRepo.All() article %s duplicate of %d, want %d", title, got, want)
+		}
+	}
+
+	ids := func(title string) content.QueryOpt {
+		return content.IDs([]content.ArticleID{byTitle[title].ID})
+	}
+
+	tests := []struct {
+		name         string
+		set          func() error
+		user         content.User
+		title        string
+		wantRead     bool
+		wantFavorite bool
+	}{
+		{"unread propagates", func() error { return r.Read(false, u1, ids("Story")) }, u1, "Story copy", false, false},
+		{"state is per user", func() error { return nil }, u2, "Story copy", true, false},
+		{"read propagates", func() error { return r.Read(true, u1, ids("Story copy")) }, u1, "Story", true, false},
+		{"read duplicate excluded", func() error {
+			return r.Read(false, u1, ids("Story copy"), content.ExcludeReadDuplicates)
+		}, u1, "Story copy", true, false},
+		{"unsubscribed duplicate", func() error { return r.Read(false, u2, ids("Story copy")) }, u1, "Story", true, false},
+		{"favorite propagates", func() error { return r.Favor(true, u1, ids("Budget copy")) }, u1, "Budget", true, true},
+		{"not a duplicate", func() error { return nil }, u1, "Another", true, false},
+		{"unfavorite propagates", func() error { return r.Favor(false, u1, ids("Budget")) }, u1, "Budget copy", true, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := tt.set(); err != nil {
+				t.Fatalf("articleRepo state error = %v", err)
+			}
+
+			articles, err := r.ForUser(tt.user, ids(tt.title))
+			if err != nil || len(articles) != 1 {
+				t.Fatalf("articleRepo.ForUser() = %v, error = %v", articles, err)
+			}
+
+			if articles[0].Read != tt.wantRead || articles[0].Favorite != tt.wantFavorite {
+				t.Errorf("articleRepo.ForUser() read = %v, favorite = %v, want %v, %v",
+					articles[0].Read, articles[0].Favorite, tt.wantRead, tt.wantFavorite)
+			}
+		})
+	}
+
+	// Purging the canonical articles leaves their duplicates canonical.
+	if _, err := r.Purge(dupFeed1, time.Now().Add(time.Hour), 0); err != nil {
+		t.Fatalf("articleRepo.Purge() error = %v", err)
+	}
+
+	stored, err = r.All(content.FeedIDs([]content.FeedID{dupFeed2.ID}))
+	if err != nil {
+		t.Fatalf("articleRepo.All() error = %v", err)
+	}
+
+	for _, a := range stored {
+		if a.DuplicateOf != 0 {
+			t.Errorf("articleRepo.All() article %s duplicate of %d, want 0", a.Title, a.DuplicateOf)
+		}
+	}
+}
+
+func Test_articleRepo_Purge(t *testing.T) {
+	skipTest(t)
+	setupFeed()
+
+	now := time.Now()
+	purgeFeed := content.Feed{Link: "http://sugr.org/purge"}
+	parsed := parser.Feed{Title: "purge", Articles: []parser.Article{
+		{Title: "Purge 0", Link: "http://sugr.org/purge/0", Date: now},
+		{Title: "Purge 1", Link: "http://sugr.org/purge/1", Date: now.Add(-1 * time.Hour)},
+		{Title: "Purge 2", Link: "http://sugr.org/purge/2", Date: now.Add(-2 * time.Hour)},
+		{Title: "Purge 3", Link: "http://sugr.org/purge/3", Date: now.Add(-3 * time.Hour)},
+		{Title: "Purge 4", Link: "http://sugr.org/purge/4", Date: now.Add(-4 * time.Hour)},
+	}}
+	purgeFeed.Refresh(parsed)
+
+	u1 := content.User{Login: user1}
+	createFeed(&purgeFeed, u1)
+	defer func() { service.FeedRepo().Delete(purgeFeed) }()
+
+	r := service.ArticleRepo()
+	stored, err := r.All(content.FeedIDs([]content.FeedID{purgeFeed.ID}))
+	if err != nil {
+		t.Fatalf("articleRepo.All() error = %v", err)
+	}
+
+	byTitle := map[string]content.Article{}
+	for _, a := range stored {
+		byTitle[a.Title] = a
+	}
+
+	if err := r.Favor(true, u1, content.IDs([]content.ArticleID{byTitle["Purge 4"].ID})); err != nil {
+		t.Fatalf("articleRepo.Favor() error = %v", err)
+	}
+
+	if err := service.ThumbnailRepo().Update(content.Thumbnail{ArticleID: byTitle["Purge 3"].ID, Thumbnail: "thumb"}); err != nil {
+		t.Fatalf("thumbnailRepo.Update() error = %v", err)
+	}
+
+	tests := []struct {
+		name      string
+		feed      content.Feed
+		before    time.Time
+		keep      int
+		want      []string
+		remaining []string
+		wantErr   bool
+	}{
+		{"nothing", purgeFeed, time.Time{}, 0, nil, []string{"Purge 0", "Purge 1", "Purge 2", "Purge 3", "Purge 4"}, false},
+		{"by date", purgeFeed, now.Add(-150 * time.Minute), 0, []string{"Purge 3"}, []string{"Purge 0", "Purge 1", "Purge 2", "Purge 4"}, false},
+		{"by count", purgeFeed, time.Time{}, 2, []string{"Purge 2"}, []string{"Purge 0", "Purge 1", "Purge 4"}, false},
+		{"by date and count", purgeFeed, now.Add(-30 * time.Minute), 1, []string{"Purge 1"}, []string{"Purge 0", "Purge 4"}, false},
+		{"invalid feed", content.Feed{}, now, 0, nil, []string{"Purge 0", "Purge 4"}, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := r.Purge(tt.feed, tt.before, tt.keep)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("articleRepo.Purge() error = %v, wantErr %v", err, tt.wantErr)
+			}
+
+			if len(got) != len(tt.want) {
+				t.Fatalf("articleRepo.Purge() = %v, want %v", got, tt.want)
+			}
+
+			for i := range got {
+				if got[i].ID != byTitle[tt.want[i]].ID || got[i].FeedID != purgeFeed.ID {
+					t.Errorf("articleRepo.Purge() article %d = %v, want %s", i, got[i], tt.want[i])
+				}
+			}
+
+			remaining, err := r.All(content.FeedIDs([]content.FeedID{purgeFeed.ID}))
+			if err != nil {
+				t.Fatalf("articleRepo.All() error = %v", err)
+			}
+
+			titles := []string{}
+			for _, a := range remaining {
+				titles = append(titles, a.Title)
+			}
+			sort.Strings(titles)
+
+			if !reflect.DeepEqual(titles, tt.remaining) {
+				t.Errorf("articleRepo.Purge() remaining = %v, want %v", titles, tt.remaining)
+			}
+		})
+	}
+
+	if _, err := service.ThumbnailRepo().Get(byTitle["Purge 3"]); !content.IsNoContent(err) {
+		t.Errorf("thumbnailRepo.Get() error = %v, want no content", err)
+	}
+
+	// Purged articles, and new ones older than them, aren't created again
+	// while the feed still lists them.
+	parsed.Articles = append(parsed.Articles,
+		parser.Article{Title: "Purge 5", Link: "http://sugr.org/purge/5", Date: now.Add(-90 * time.Minute)},
+		parser.Article{Title: "Purge 6", Link: "http://sugr.org/purge/6", Date: now.Add(time.Minute)},
+	)
+	purgeFeed.Refresh(parsed)
+
+	created, err := service.FeedRepo().Update(&purgeFeed)
+	if err != nil {
+		t.Fatalf("feedRepo.Update() error = %v", err)
+	}
+
+	titles := []string{}
+	for _, a := range created {
+		if a.IsNew {
+			titles = append(titles, a.Title)
+		}
+	}
+
+	if !reflect.DeepEqual(titles, []string{"Purge 6"}) {
+		t.Errorf("feedRepo.Update() created = %v, want [Purge 6]", titles)
+	}
+
+	remaining, err := r.All(content.FeedIDs([]content.FeedID{purgeFeed.ID}))
+	if err != nil {
+		t.Fatalf("articleRepo.All() error = %v", err)
+	}
+
+	if len(remaining) != 3 {
+		t.Errorf("articleRepo.All() = %v, want 3 articles", remaining)
+	}
+}
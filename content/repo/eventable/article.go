@@ -9,8 +9,10 @@ import (
 const (
 	ArticleStateEvent = "article-state-change"
 
-	read  = "read"
-	favor = "favor"
+	// ReadState and FavorState are the article states changed by the
+	// ArticleStateEvent.
+	ReadState  = "read"
+	FavorState = "favor"
 )
 
 type ArticleStateData struct {
@@ -41,7 +43,7 @@ func (r articleRepo) Read(state bool, user content.User, opts ...content.QueryOp
 
 		r.eventBus.Dispatch(
 			ArticleStateEvent,
-			ArticleStateData{user.Login, read, state, convertOptions(o)},
+			ArticleStateData{user.Login, ReadState, state, convertOptions(o)},
 		)
 
 		r.log.Debugf("Dispatch of article read state event end")
@@ -61,7 +63,7 @@ func (r articleRepo) Favor(state bool, user content.User, opts ...content.QueryO
 
 		r.eventBus.Dispatch(
 			ArticleStateEvent,
-			ArticleStateData{user.Login, favor, state, convertOptions(o)},
+			ArticleStateData{user.Login, FavorState, state, convertOptions(o)},
 		)
 
 		r.log.Debugf("Dispatch of article favor state event end")
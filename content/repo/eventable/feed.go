@@ -12,11 +12,15 @@ const (
 	FeedUpdateEvent  = "feed-update"
 	FeedDeleteEvent  = "feed-delete"
 	FeedSetTagsEvent = "feed-set-tags"
+	FeedDeadEvent    = "feed-dead"
 )
 
 type FeedUpdateData struct {
 	Feed        content.Feed
 	NewArticles []content.Article
+	// UpdatedArticles are the existing articles whose content was edited
+	// by the publisher.
+	UpdatedArticles []content.Article
 }
 
 func (f FeedUpdateData) MarshalJSON() ([]byte, error) {
@@ -30,6 +34,14 @@ func (f FeedUpdateData) MarshalJSON() ([]byte, error) {
 	}
 	data["articleIDs"] = ids
 
+	if len(f.UpdatedArticles) > 0 {
+		updated := make([]content.ArticleID, len(f.UpdatedArticles))
+		for i := range f.UpdatedArticles {
+			updated[i] = f.UpdatedArticles[i].ID
+		}
+		data["updatedArticleIDs"] = updated
+	}
+
 	return json.Marshal(data)
 }
 
@@ -53,6 +65,23 @@ func (f FeedDeleteData) FeedID() content.FeedID {
 	return f.Feed.ID
 }
 
+type FeedDeadData struct {
+	Feed content.Feed
+}
+
+func (f FeedDeadData) MarshalJSON() ([]byte, error) {
+	data := map[string]interface{}{}
+
+	data["feedID"] = f.Feed.ID
+	data["link"] = f.Feed.Link
+
+	return json.Marshal(data)
+}
+
+func (f FeedDeadData) FeedID() content.FeedID {
+	return f.Feed.ID
+}
+
 type FeedSetTagsData struct {
 	Feed content.Feed
 	User content.User
@@ -86,14 +115,39 @@ type feedRepo struct {
 }
 
 func (r feedRepo) Update(feed *content.Feed) ([]content.Article, error) {
+	wasDead := true
+	if feed.Dead && feed.ID != 0 {
+		if current, err := r.Feed.Get(feed.ID, content.User{}); err == nil {
+			wasDead = current.Dead
+		}
+	}
+
 	articles, err := r.Feed.Update(feed)
 
+	if err == nil && feed.Dead && !wasDead {
+		r.log.Debugf("Dispatching feed dead event")
+
+		r.eventBus.Dispatch(
+			FeedDeadEvent,
+			FeedDeadData{*feed},
+		)
+	}
+
 	if err == nil && len(articles) > 0 {
 		r.log.Debugf("Dispatching feed update event")
 
+		data := FeedUpdateData{Feed: *feed}
+		for _, a := range articles {
+			if a.IsNew {
+				data.NewArticles = append(data.NewArticles, a)
+			} else {
+				data.UpdatedArticles = append(data.UpdatedArticles, a)
+			}
+		}
+
 		r.eventBus.Dispatch(
 			FeedUpdateEvent,
-			FeedUpdateData{*feed, articles},
+			data,
 		)
 
 		r.log.Debugf("Dispatch of feed update event end")
@@ -102,6 +156,21 @@ func (r feedRepo) Update(feed *content.Feed) ([]content.Article, error) {
 	return articles, err
 }
 
+func (r feedRepo) Merge(from, to content.Feed) error {
+	err := r.Feed.Merge(from, to)
+
+	if err == nil {
+		r.log.Debugf("Dispatching feed delete event for merged feed")
+
+		r.eventBus.Dispatch(
+			FeedDeleteEvent,
+			FeedDeleteData{from},
+		)
+	}
+
+	return err
+}
+
 func (r feedRepo) Delete(feed content.Feed) error {
 	err := r.Feed.Delete(feed)
 
@@ -0,0 +1,14 @@
+package repo
+
+import "github.com/urandom/readeef/content"
+
+// FeedImage allows fetching and manipulating content.FeedImage and
+// content.Favicon objects
+type FeedImage interface {
+	Get(content.Feed) (content.FeedImage, error)
+	Update(content.FeedImage) error
+
+	Favicon(content.Feed) (content.Favicon, error)
+	Favicons(content.User) ([]content.Favicon, error)
+	UpdateFavicon(content.Favicon) error
+}
@@ -0,0 +1,115 @@
+package repo_test
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/urandom/readeef/content"
+)
+
+func Test_feedImageRepo_Update(t *testing.T) {
+	skipTest(t)
+	setupFeed()
+
+	tests := []struct {
+		name    string
+		feed    content.Feed
+		image   content.FeedImage
+		wantErr bool
+	}{
+		{"valid", feed1, content.FeedImage{FeedID: feed1.ID, Title: "Logo", URL: "http://sugr.org/logo.png", Width: 32, Height: 32}, false},
+		{"replaced", feed1, content.FeedImage{FeedID: feed1.ID, URL: "http://sugr.org/logo2.png"}, false},
+		{"relative", feed1, content.FeedImage{FeedID: feed1.ID, URL: "/logo.png"}, true},
+		{"invalid", content.Feed{}, content.FeedImage{URL: "http://sugr.org/logo.png"}, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := service.FeedImageRepo()
+			if err := r.Update(tt.image); (err != nil) != tt.wantErr {
+				t.Errorf("feedImageRepo.Update() error = %v, wantErr %v", err, tt.wantErr)
+			}
+
+			if tt.wantErr {
+				return
+			}
+
+			got, err := r.Get(tt.feed)
+			if err != nil {
+				t.Errorf("feedImageRepo.Update() post fetch error = %v", err)
+				return
+			}
+
+			if !reflect.DeepEqual(got, tt.image) {
+				t.Errorf("feedImageRepo.Update() = %v, want %v", got, tt.image)
+			}
+		})
+	}
+}
+
+func Test_feedImageRepo_UpdateFavicon(t *testing.T) {
+	skipTest(t)
+	setupFeed()
+
+	now := time.Now().Truncate(time.Second)
+
+	tests := []struct {
+		name    string
+		feed    content.Feed
+		favicon content.Favicon
+		wantErr bool
+	}{
+		{"valid", feed2, content.Favicon{FeedID: feed2.ID, Link: "http://sugr.org/favicon.ico", Data: "data:image/png;base64,AQID", UpdateDate: now}, false},
+		{"missing", feed1, content.Favicon{FeedID: feed1.ID, UpdateDate: now}, false},
+		{"not a data uri", feed1, content.Favicon{FeedID: feed1.ID, Data: "AQID"}, true},
+		{"invalid", content.Feed{}, content.Favicon{}, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := service.FeedImageRepo()
+			if err := r.UpdateFavicon(tt.favicon); (err != nil) != tt.wantErr {
+				t.Errorf("feedImageRepo.UpdateFavicon() error = %v, wantErr %v", err, tt.wantErr)
+			}
+
+			if tt.wantErr {
+				return
+			}
+
+			got, err := r.Favicon(tt.feed)
+			if err != nil {
+				t.Errorf("feedImageRepo.UpdateFavicon() post fetch error = %v", err)
+				return
+			}
+
+			if got.Link != tt.favicon.Link || got.Data != tt.favicon.Data || !got.UpdateDate.Equal(tt.favicon.UpdateDate) {
+				t.Errorf("feedImageRepo.UpdateFavicon() = %v, want %v", got, tt.favicon)
+			}
+		})
+	}
+
+	// Only icons that were found are listed
+	favicons, err := service.FeedImageRepo().Favicons(content.User{Login: user1})
+	if err != nil {
+		t.Fatalf("feedImageRepo.Favicons() error = %v", err)
+	}
+
+	if len(favicons) != 1 || favicons[0].FeedID != feed2.ID {
+		t.Errorf("feedImageRepo.Favicons() = %v, want the favicon of feed %s", favicons, feed2)
+	}
+}
+
+func Test_feedImageRepo_Favicon(t *testing.T) {
+	skipTest(t)
+	setupFeed()
+
+	if _, err := service.FeedImageRepo().Favicon(content.Feed{}); err == nil {
+		t.Errorf("feedImageRepo.Favicon() wanted a validation error")
+	}
+
+	feed := content.Feed{ID: feed1.ID + feed2.ID, Link: "http://sugr.org/none"}
+
+	if _, err := service.FeedImageRepo().Favicon(feed); errors.Cause(err) != content.ErrNoContent {
+		t.Errorf("feedImageRepo.Favicon() error = %v, wanted no content", err)
+	}
+}
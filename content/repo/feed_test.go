@@ -293,6 +293,208 @@ func Test_feedRepo_UpdateDelete(t *testing.T) {
 	}
 }
 
+func Test_feedRepo_Credentials(t *testing.T) {
+	skipTest(t)
+	setupFeed()
+
+	secret := []byte("secret")
+	auth := content.FeedAuth{Username: "user", Password: "pass", Headers: map[string]string{"X-Token": "abc"}}
+
+	feed := content.Feed{Link: "http://sugr.org/private"}
+	feed.Refresh(parser.Feed{Title: "private"})
+	if err := feed.SetAuth(auth, secret); err != nil {
+		t.Fatalf("Feed.SetAuth() error = %v", err)
+	}
+
+	r := service.FeedRepo()
+	if _, err := r.Update(&feed); err != nil {
+		t.Fatalf("feedRepo.Update() error = %v", err)
+	}
+	defer r.Delete(feed)
+
+	got, err := r.Get(feed.ID, content.User{})
+	if err != nil {
+		t.Fatalf("feedRepo.Get() error = %v", err)
+	}
+
+	gotAuth, err := got.Auth(secret)
+	if err != nil {
+		t.Fatalf("Feed.Auth() error = %v", err)
+	}
+
+	if !reflect.DeepEqual(gotAuth, auth) {
+		t.Errorf("feedRepo.Get() auth = %v, want %v", gotAuth, auth)
+	}
+}
+
+func Test_feedRepo_ScrapeRules(t *testing.T) {
+	skipTest(t)
+	setupFeed()
+
+	rules := content.ScrapeRules{Item: "article", Title: "h2", Date: "time"}
+
+	feed := content.Feed{Link: "http://sugr.org/scraped", Scrape: rules}
+	feed.Refresh(parser.Feed{Title: "scraped"})
+
+	r := service.FeedRepo()
+	if _, err := r.Update(&feed); err != nil {
+		t.Fatalf("feedRepo.Update() error = %v", err)
+	}
+	defer r.Delete(feed)
+
+	got, err := r.FindByLink(feed.Link)
+	if err != nil {
+		t.Fatalf("feedRepo.FindByLink() error = %v", err)
+	}
+
+	if !reflect.DeepEqual(got.Scrape, rules) {
+		t.Errorf("feedRepo.FindByLink() scrape = %v, want %v", got.Scrape, rules)
+	}
+
+	got, err = r.Get(feed.ID, content.User{})
+	if err != nil {
+		t.Fatalf("feedRepo.Get() error = %v", err)
+	}
+
+	if !reflect.DeepEqual(got.Scrape, rules) {
+		t.Errorf("feedRepo.Get() scrape = %v, want %v", got.Scrape, rules)
+	}
+}
+
+func Test_feedRepo_InferredDates(t *testing.T) {
+	skipTest(t)
+	setupFeed()
+
+	seen := time.Date(2018, time.March, 1, 12, 0, 0, 0, time.UTC)
+	published := time.Date(2018, time.February, 1, 12, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name         string
+		article      parser.Article
+		wantDate     time.Time
+		wantInferred bool
+	}{
+		{"first seen", parser.Article{Date: seen, DateInferred: true}, seen, true},
+		{"seen again", parser.Article{Date: seen.Add(time.Hour), DateInferred: true}, seen, true},
+		{"published", parser.Article{Date: published}, published, false},
+		{"lost date", parser.Article{Date: seen.Add(2 * time.Hour), DateInferred: true}, published, false},
+	}
+
+	feed := content.Feed{Link: "http://sugr.org/inferred"}
+	r := service.FeedRepo()
+	defer func() { r.Delete(feed) }()
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.article.Title = "Undated"
+			tt.article.Link = "http://sugr.org/inferred/article"
+
+			feed.Refresh(parser.Feed{Title: "inferred", Articles: []parser.Article{tt.article}})
+			if _, err := r.Update(&feed); err != nil {
+				t.Fatalf("feedRepo.Update() error = %v", err)
+			}
+
+			got, err := service.ArticleRepo().All(content.FeedIDs([]content.FeedID{feed.ID}))
+			if err != nil {
+				t.Fatalf("articleRepo.All() error = %v", err)
+			}
+
+			if len(got) != 1 {
+				t.Fatalf("articleRepo.All() articles %d, want 1", len(got))
+			}
+
+			if !got[0].Date.Equal(tt.wantDate) {
+				t.Errorf("articleRepo.All() date = %v, want %v", got[0].Date, tt.wantDate)
+			}
+
+			if got[0].DateInferred != tt.wantInferred {
+				t.Errorf("articleRepo.All() date inferred = %v, want %v", got[0].DateInferred, tt.wantInferred)
+			}
+		})
+	}
+}
+
+func Test_feedRepo_Merge(t *testing.T) {
+	skipTest(t)
+	setupFeed()
+
+	u1 := content.User{Login: user1}
+	u2 := content.User{Login: user2}
+
+	from := content.Feed{Link: "http://sugr.org/merge/from"}
+	from.Refresh(parser.Feed{Title: "from", Articles: []parser.Article{
+		{Title: "Article 1", Link: "http://sugr.org/merge/a/1", Date: time.Now()},
+		{Title: "Article 2", Link: "http://sugr.org/merge/a/2", Date: time.Now()},
+	}})
+	createFeed(&from, u2)
+
+	to := content.Feed{Link: "http://sugr.org/merge/to"}
+	to.Refresh(parser.Feed{Title: "to", Articles: []parser.Article{
+		{Title: "Article 2", Link: "http://sugr.org/merge/a/2", Date: time.Now()},
+	}})
+	createFeed(&to, u1)
+
+	fromArticles, err := service.ArticleRepo().ForUser(u2, content.FeedIDs([]content.FeedID{from.ID}))
+	if err != nil {
+		t.Fatalf("articleRepo.ForUser() error = %v", err)
+	}
+
+	ids := []content.ArticleID{}
+	for _, a := range fromArticles {
+		ids = append(ids, a.ID)
+	}
+
+	if err := service.ArticleRepo().Read(false, u2, content.IDs(ids)); err != nil {
+		t.Fatalf("articleRepo.Read() error = %v", err)
+	}
+
+	if err := service.ArticleRepo().Favor(true, u2, content.IDs(ids)); err != nil {
+		t.Fatalf("articleRepo.Favor() error = %v", err)
+	}
+
+	r := service.FeedRepo()
+
+	if err := r.Merge(from, from); err == nil {
+		t.Errorf("feedRepo.Merge() wanted an error when merging a feed with itself")
+	}
+
+	if err := r.Merge(from, to); err != nil {
+		t.Fatalf("feedRepo.Merge() error = %v", err)
+	}
+
+	if _, err := r.FindByLink(from.Link); !content.IsNoContent(err) {
+		t.Errorf("feedRepo.Merge() source feed still exists, err = %v", err)
+	}
+
+	users, err := r.Users(to)
+	if err != nil {
+		t.Fatalf("feedRepo.Users() error = %v", err)
+	}
+
+	if len(users) != 2 {
+		t.Errorf("feedRepo.Merge() users = %v, want %s and %s", users, u1, u2)
+	}
+
+	articles, err := service.ArticleRepo().ForUser(u2, content.FeedIDs([]content.FeedID{to.ID}))
+	if err != nil {
+		t.Fatalf("articleRepo.ForUser() error = %v", err)
+	}
+
+	if len(articles) != 2 {
+		t.Errorf("feedRepo.Merge() articles = %v, want 2", articles)
+	}
+
+	for _, a := range articles {
+		if a.Read || !a.Favorite {
+			t.Errorf("feedRepo.Merge() article %s read = %v, favorite = %v, want unread favorite", a, a.Read, a.Favorite)
+		}
+	}
+
+	if err := r.Delete(to); err != nil {
+		t.Errorf("feedRepo.Delete() error %v", err)
+	}
+}
+
 func Test_feedRepo_Users(t *testing.T) {
 	skipTest(t)
 	setupFeed()
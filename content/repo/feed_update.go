@@ -0,0 +1,15 @@
+package repo
+
+import (
+	"time"
+
+	"github.com/urandom/readeef/content"
+)
+
+// FeedUpdate allows fetching and storing the content.FeedUpdate history of
+// feeds.
+type FeedUpdate interface {
+	ForFeed(feed content.Feed, limit int) ([]content.FeedUpdate, error)
+	Create(content.FeedUpdate) error
+	DeleteStale(before time.Time) error
+}
@@ -0,0 +1,76 @@
+package repo_test
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/urandom/readeef/content"
+)
+
+func Test_feedUpdateRepo(t *testing.T) {
+	skipTest(t)
+	setupFeed()
+
+	date := time.Date(2018, time.March, 1, 12, 0, 0, 0, time.UTC)
+	updates := []content.FeedUpdate{
+		{FeedID: feed1.ID, Date: date.Add(-2 * time.Hour), Duration: time.Second, Status: 200, Bytes: 2048, NewArticles: 3},
+		{FeedID: feed1.ID, Date: date.Add(-time.Hour), Duration: 2 * time.Second, Status: 500, Error: "HTTP Status: 500"},
+		{FeedID: feed1.ID, Date: date, Duration: time.Second, Status: 200, Bytes: 1024, NewArticles: 1, UpdatedArticles: 2},
+	}
+
+	r := service.FeedUpdateRepo()
+	defer r.DeleteStale(date.Add(time.Hour))
+
+	if err := r.Create(content.FeedUpdate{Date: date}); err == nil {
+		t.Errorf("feedUpdateRepo.Create() expected an error for an update without a feed")
+	}
+
+	for _, u := range updates {
+		if err := r.Create(u); err != nil {
+			t.Fatalf("feedUpdateRepo.Create() error = %v", err)
+		}
+	}
+
+	tests := []struct {
+		name   string
+		feed   content.Feed
+		limit  int
+		before time.Time
+		want   []content.FeedUpdate
+	}{
+		{"all", feed1, 10, time.Time{}, []content.FeedUpdate{updates[2], updates[1], updates[0]}},
+		{"limited", feed1, 2, time.Time{}, []content.FeedUpdate{updates[2], updates[1]}},
+		{"other feed", feed2, 10, time.Time{}, nil},
+		{"stale removed", feed1, 10, date.Add(-time.Hour), []content.FeedUpdate{updates[2], updates[1]}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if !tt.before.IsZero() {
+				if err := r.DeleteStale(tt.before); err != nil {
+					t.Fatalf("feedUpdateRepo.DeleteStale() error = %v", err)
+				}
+			}
+
+			got, err := r.ForFeed(tt.feed, tt.limit)
+			if err != nil {
+				t.Fatalf("feedUpdateRepo.ForFeed() error = %v", err)
+			}
+
+			if len(got) != len(tt.want) {
+				t.Fatalf("feedUpdateRepo.ForFeed() = %v, want %v", got, tt.want)
+			}
+
+			for i := range got {
+				if !got[i].Date.Equal(tt.want[i].Date) {
+					t.Errorf("feedUpdateRepo.ForFeed() date = %v, want %v", got[i].Date, tt.want[i].Date)
+				}
+				got[i].Date = tt.want[i].Date
+
+				if !reflect.DeepEqual(got[i], tt.want[i]) {
+					t.Errorf("feedUpdateRepo.ForFeed() = %v, want %v", got[i], tt.want[i])
+				}
+			}
+		})
+	}
+}
@@ -83,3 +83,13 @@ func (r articleRepo) RemoveStaleUnreadRecords() error {
 
 	return err
 }
+
+func (r articleRepo) Purge(feed content.Feed, before time.Time, keep int) ([]content.Article, error) {
+	start := time.Now()
+
+	articles, err := r.Article.Purge(feed, before, keep)
+
+	r.log.Infof("repo.Article.Purge took %s", time.Now().Sub(start))
+
+	return articles, err
+}
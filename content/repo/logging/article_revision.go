@@ -0,0 +1,25 @@
+package logging
+
+import (
+	"time"
+
+	"github.com/urandom/readeef/content"
+	"github.com/urandom/readeef/content/repo"
+	"github.com/urandom/readeef/log"
+)
+
+type articleRevisionRepo struct {
+	repo.ArticleRevision
+
+	log log.Log
+}
+
+func (r articleRevisionRepo) ForArticle(article content.Article) ([]content.ArticleRevision, error) {
+	start := time.Now()
+
+	revisions, err := r.ArticleRevision.ForArticle(article)
+
+	r.log.Infof("repo.ArticleRevision.ForArticle took %s", time.Now().Sub(start))
+
+	return revisions, err
+}
@@ -0,0 +1,65 @@
+package logging
+
+import (
+	"time"
+
+	"github.com/urandom/readeef/content"
+	"github.com/urandom/readeef/content/repo"
+	"github.com/urandom/readeef/log"
+)
+
+type feedImageRepo struct {
+	repo.FeedImage
+
+	log log.Log
+}
+
+func (r feedImageRepo) Get(feed content.Feed) (content.FeedImage, error) {
+	start := time.Now()
+
+	image, err := r.FeedImage.Get(feed)
+
+	r.log.Infof("repo.FeedImage.Get took %s", time.Now().Sub(start))
+
+	return image, err
+}
+
+func (r feedImageRepo) Update(image content.FeedImage) error {
+	start := time.Now()
+
+	err := r.FeedImage.Update(image)
+
+	r.log.Infof("repo.FeedImage.Update took %s", time.Now().Sub(start))
+
+	return err
+}
+
+func (r feedImageRepo) Favicon(feed content.Feed) (content.Favicon, error) {
+	start := time.Now()
+
+	favicon, err := r.FeedImage.Favicon(feed)
+
+	r.log.Infof("repo.FeedImage.Favicon took %s", time.Now().Sub(start))
+
+	return favicon, err
+}
+
+func (r feedImageRepo) Favicons(user content.User) ([]content.Favicon, error) {
+	start := time.Now()
+
+	favicons, err := r.FeedImage.Favicons(user)
+
+	r.log.Infof("repo.FeedImage.Favicons took %s", time.Now().Sub(start))
+
+	return favicons, err
+}
+
+func (r feedImageRepo) UpdateFavicon(favicon content.Favicon) error {
+	start := time.Now()
+
+	err := r.FeedImage.UpdateFavicon(favicon)
+
+	r.log.Infof("repo.FeedImage.UpdateFavicon took %s", time.Now().Sub(start))
+
+	return err
+}
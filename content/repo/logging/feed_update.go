@@ -0,0 +1,45 @@
+package logging
+
+import (
+	"time"
+
+	"github.com/urandom/readeef/content"
+	"github.com/urandom/readeef/content/repo"
+	"github.com/urandom/readeef/log"
+)
+
+type feedUpdateRepo struct {
+	repo.FeedUpdate
+
+	log log.Log
+}
+
+func (r feedUpdateRepo) ForFeed(feed content.Feed, limit int) ([]content.FeedUpdate, error) {
+	start := time.Now()
+
+	updates, err := r.FeedUpdate.ForFeed(feed, limit)
+
+	r.log.Infof("repo.FeedUpdate.ForFeed took %s", time.Now().Sub(start))
+
+	return updates, err
+}
+
+func (r feedUpdateRepo) Create(update content.FeedUpdate) error {
+	start := time.Now()
+
+	err := r.FeedUpdate.Create(update)
+
+	r.log.Infof("repo.FeedUpdate.Create took %s", time.Now().Sub(start))
+
+	return err
+}
+
+func (r feedUpdateRepo) DeleteStale(before time.Time) error {
+	start := time.Now()
+
+	err := r.FeedUpdate.DeleteStale(before)
+
+	r.log.Infof("repo.FeedUpdate.DeleteStale took %s", time.Now().Sub(start))
+
+	return err
+}
@@ -9,8 +9,11 @@ type Service struct {
 	repo.Service
 
 	article      articleRepo
+	revision     articleRevisionRepo
 	extract      extractRepo
 	feed         feedRepo
+	feedImage    feedImageRepo
+	feedUpdate   feedUpdateRepo
 	scores       scoresRepo
 	subscription subscriptionRepo
 	tag          tagRepo
@@ -22,8 +25,11 @@ func NewService(s repo.Service, log log.Log) Service {
 	return Service{
 		s,
 		articleRepo{s.ArticleRepo(), log},
+		articleRevisionRepo{s.ArticleRevisionRepo(), log},
 		extractRepo{s.ExtractRepo(), log},
 		feedRepo{s.FeedRepo(), log},
+		feedImageRepo{s.FeedImageRepo(), log},
+		feedUpdateRepo{s.FeedUpdateRepo(), log},
 		scoresRepo{s.ScoresRepo(), log},
 		subscriptionRepo{s.SubscriptionRepo(), log},
 		tagRepo{s.TagRepo(), log},
@@ -36,6 +42,10 @@ func (s Service) ArticleRepo() repo.Article {
 	return s.article
 }
 
+func (s Service) ArticleRevisionRepo() repo.ArticleRevision {
+	return s.revision
+}
+
 func (s Service) ExtractRepo() repo.Extract {
 	return s.extract
 }
@@ -44,6 +54,14 @@ func (s Service) FeedRepo() repo.Feed {
 	return s.feed
 }
 
+func (s Service) FeedImageRepo() repo.FeedImage {
+	return s.feedImage
+}
+
+func (s Service) FeedUpdateRepo() repo.FeedUpdate {
+	return s.feedUpdate
+}
+
 func (s Service) ScoresRepo() repo.Scores {
 	return s.scores
 }
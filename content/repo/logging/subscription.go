@@ -43,3 +43,33 @@ func (r subscriptionRepo) Update(subscription content.Subscription) error {
 
 	return err
 }
+
+func (r subscriptionRepo) Subscribers(topic string) ([]content.Subscriber, error) {
+	start := time.Now()
+
+	subscribers, err := r.Subscription.Subscribers(topic)
+
+	r.log.Infof("repo.Subscription.Subscribers took %s", time.Now().Sub(start))
+
+	return subscribers, err
+}
+
+func (r subscriptionRepo) UpdateSubscriber(subscriber content.Subscriber) error {
+	start := time.Now()
+
+	err := r.Subscription.UpdateSubscriber(subscriber)
+
+	r.log.Infof("repo.Subscription.UpdateSubscriber took %s", time.Now().Sub(start))
+
+	return err
+}
+
+func (r subscriptionRepo) DeleteSubscriber(subscriber content.Subscriber) error {
+	start := time.Now()
+
+	err := r.Subscription.DeleteSubscriber(subscriber)
+
+	r.log.Infof("repo.Subscription.DeleteSubscriber took %s", time.Now().Sub(start))
+
+	return err
+}
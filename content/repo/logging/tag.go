@@ -53,3 +53,53 @@ func (r tagRepo) FeedIDs(tag content.Tag, user content.User) ([]content.FeedID,
 
 	return ids, err
 }
+
+func (r tagRepo) Rename(tag content.Tag, value content.TagValue, user content.User) (content.Tag, error) {
+	start := time.Now()
+
+	renamed, err := r.Tag.Rename(tag, value, user)
+
+	r.log.Infof("repo.Tag.Rename took %s", time.Now().Sub(start))
+
+	return renamed, err
+}
+
+func (r tagRepo) Merge(from []content.Tag, to content.Tag, user content.User) error {
+	start := time.Now()
+
+	err := r.Tag.Merge(from, to, user)
+
+	r.log.Infof("repo.Tag.Merge took %s", time.Now().Sub(start))
+
+	return err
+}
+
+func (r tagRepo) Delete(tag content.Tag, user content.User) error {
+	start := time.Now()
+
+	err := r.Tag.Delete(tag, user)
+
+	r.log.Infof("repo.Tag.Delete took %s", time.Now().Sub(start))
+
+	return err
+}
+
+func (r tagRepo) SetOrder(ids []content.TagID, user content.User) error {
+	start := time.Now()
+
+	err := r.Tag.SetOrder(ids, user)
+
+	r.log.Infof("repo.Tag.SetOrder took %s", time.Now().Sub(start))
+
+	return err
+}
+
+func (r tagRepo) SetParent(tag, parent content.Tag, user content.User) error {
+	start := time.Now()
+
+	err := r.Tag.SetParent(tag, parent, user)
+
+	r.log.Infof("repo.Tag.SetParent took %s", time.Now().Sub(start))
+
+	return err
+}
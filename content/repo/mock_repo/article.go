@@ -8,6 +8,7 @@ import (
 	gomock "github.com/golang/mock/gomock"
 	content "github.com/urandom/readeef/content"
 	reflect "reflect"
+	time "time"
 )
 
 // MockArticle is a mock of Article interface
@@ -121,6 +122,19 @@ func (mr *MockArticleMockRecorder) IDs(arg0 interface{}, arg1 ...interface{}) *g
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "IDs", reflect.TypeOf((*MockArticle)(nil).IDs), varargs...)
 }
 
+// Purge mocks base method
+func (m *MockArticle) Purge(arg0 content.Feed, arg1 time.Time, arg2 int) ([]content.Article, error) {
+	ret := m.ctrl.Call(m, "Purge", arg0, arg1, arg2)
+	ret0, _ := ret[0].([]content.Article)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Purge indicates an expected call of Purge
+func (mr *MockArticleMockRecorder) Purge(arg0, arg1, arg2 interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Purge", reflect.TypeOf((*MockArticle)(nil).Purge), arg0, arg1, arg2)
+}
+
 // Read mocks base method
 func (m *MockArticle) Read(arg0 bool, arg1 content.User, arg2 ...content.QueryOpt) error {
 	varargs := []interface{}{arg0, arg1}
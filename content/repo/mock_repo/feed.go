@@ -147,6 +147,18 @@ func (mr *MockFeedMockRecorder) IDs() *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "IDs", reflect.TypeOf((*MockFeed)(nil).IDs))
 }
 
+// Merge mocks base method
+func (m *MockFeed) Merge(arg0, arg1 content.Feed) error {
+	ret := m.ctrl.Call(m, "Merge", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Merge indicates an expected call of Merge
+func (mr *MockFeedMockRecorder) Merge(arg0, arg1 interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Merge", reflect.TypeOf((*MockFeed)(nil).Merge), arg0, arg1)
+}
+
 // SetUserTags mocks base method
 func (m *MockFeed) SetUserTags(arg0 content.Feed, arg1 content.User, arg2 []*content.Tag) error {
 	ret := m.ctrl.Call(m, "SetUserTags", arg0, arg1, arg2)
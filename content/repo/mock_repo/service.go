@@ -45,6 +45,18 @@ func (mr *MockServiceMockRecorder) ArticleRepo() *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ArticleRepo", reflect.TypeOf((*MockService)(nil).ArticleRepo))
 }
 
+// ArticleRevisionRepo mocks base method
+func (m *MockService) ArticleRevisionRepo() repo.ArticleRevision {
+	ret := m.ctrl.Call(m, "ArticleRevisionRepo")
+	ret0, _ := ret[0].(repo.ArticleRevision)
+	return ret0
+}
+
+// ArticleRevisionRepo indicates an expected call of ArticleRevisionRepo
+func (mr *MockServiceMockRecorder) ArticleRevisionRepo() *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ArticleRevisionRepo", reflect.TypeOf((*MockService)(nil).ArticleRevisionRepo))
+}
+
 // ExtractRepo mocks base method
 func (m *MockService) ExtractRepo() repo.Extract {
 	ret := m.ctrl.Call(m, "ExtractRepo")
@@ -69,6 +81,30 @@ func (mr *MockServiceMockRecorder) FeedRepo() *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FeedRepo", reflect.TypeOf((*MockService)(nil).FeedRepo))
 }
 
+// FeedImageRepo mocks base method
+func (m *MockService) FeedImageRepo() repo.FeedImage {
+	ret := m.ctrl.Call(m, "FeedImageRepo")
+	ret0, _ := ret[0].(repo.FeedImage)
+	return ret0
+}
+
+// FeedImageRepo indicates an expected call of FeedImageRepo
+func (mr *MockServiceMockRecorder) FeedImageRepo() *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FeedImageRepo", reflect.TypeOf((*MockService)(nil).FeedImageRepo))
+}
+
+// FeedUpdateRepo mocks base method
+func (m *MockService) FeedUpdateRepo() repo.FeedUpdate {
+	ret := m.ctrl.Call(m, "FeedUpdateRepo")
+	ret0, _ := ret[0].(repo.FeedUpdate)
+	return ret0
+}
+
+// FeedUpdateRepo indicates an expected call of FeedUpdateRepo
+func (mr *MockServiceMockRecorder) FeedUpdateRepo() *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FeedUpdateRepo", reflect.TypeOf((*MockService)(nil).FeedUpdateRepo))
+}
+
 // ScoresRepo mocks base method
 func (m *MockService) ScoresRepo() repo.Scores {
 	ret := m.ctrl.Call(m, "ScoresRepo")
@@ -46,6 +46,18 @@ func (mr *MockSubscriptionMockRecorder) All() *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "All", reflect.TypeOf((*MockSubscription)(nil).All))
 }
 
+// DeleteSubscriber mocks base method
+func (m *MockSubscription) DeleteSubscriber(arg0 content.Subscriber) error {
+	ret := m.ctrl.Call(m, "DeleteSubscriber", arg0)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteSubscriber indicates an expected call of DeleteSubscriber
+func (mr *MockSubscriptionMockRecorder) DeleteSubscriber(arg0 interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteSubscriber", reflect.TypeOf((*MockSubscription)(nil).DeleteSubscriber), arg0)
+}
+
 // Get mocks base method
 func (m *MockSubscription) Get(arg0 content.Feed) (content.Subscription, error) {
 	ret := m.ctrl.Call(m, "Get", arg0)
@@ -59,6 +71,19 @@ func (mr *MockSubscriptionMockRecorder) Get(arg0 interface{}) *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Get", reflect.TypeOf((*MockSubscription)(nil).Get), arg0)
 }
 
+// Subscribers mocks base method
+func (m *MockSubscription) Subscribers(arg0 string) ([]content.Subscriber, error) {
+	ret := m.ctrl.Call(m, "Subscribers", arg0)
+	ret0, _ := ret[0].([]content.Subscriber)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Subscribers indicates an expected call of Subscribers
+func (mr *MockSubscriptionMockRecorder) Subscribers(arg0 interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Subscribers", reflect.TypeOf((*MockSubscription)(nil).Subscribers), arg0)
+}
+
 // Update mocks base method
 func (m *MockSubscription) Update(arg0 content.Subscription) error {
 	ret := m.ctrl.Call(m, "Update", arg0)
@@ -70,3 +95,15 @@ func (m *MockSubscription) Update(arg0 content.Subscription) error {
 func (mr *MockSubscriptionMockRecorder) Update(arg0 interface{}) *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Update", reflect.TypeOf((*MockSubscription)(nil).Update), arg0)
 }
+
+// UpdateSubscriber mocks base method
+func (m *MockSubscription) UpdateSubscriber(arg0 content.Subscriber) error {
+	ret := m.ctrl.Call(m, "UpdateSubscriber", arg0)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpdateSubscriber indicates an expected call of UpdateSubscriber
+func (mr *MockSubscriptionMockRecorder) UpdateSubscriber(arg0 interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateSubscriber", reflect.TypeOf((*MockSubscription)(nil).UpdateSubscriber), arg0)
+}
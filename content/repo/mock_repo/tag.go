@@ -33,6 +33,18 @@ func (m *MockTag) EXPECT() *MockTagMockRecorder {
 	return m.recorder
 }
 
+// Delete mocks base method
+func (m *MockTag) Delete(arg0 content.Tag, arg1 content.User) error {
+	ret := m.ctrl.Call(m, "Delete", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Delete indicates an expected call of Delete
+func (mr *MockTagMockRecorder) Delete(arg0, arg1 interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Delete", reflect.TypeOf((*MockTag)(nil).Delete), arg0, arg1)
+}
+
 // FeedIDs mocks base method
 func (m *MockTag) FeedIDs(arg0 content.Tag, arg1 content.User) ([]content.FeedID, error) {
 	ret := m.ctrl.Call(m, "FeedIDs", arg0, arg1)
@@ -84,3 +96,52 @@ func (m *MockTag) Get(arg0 content.TagID, arg1 content.User) (content.Tag, error
 func (mr *MockTagMockRecorder) Get(arg0, arg1 interface{}) *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Get", reflect.TypeOf((*MockTag)(nil).Get), arg0, arg1)
 }
+
+// Merge mocks base method
+func (m *MockTag) Merge(arg0 []content.Tag, arg1 content.Tag, arg2 content.User) error {
+	ret := m.ctrl.Call(m, "Merge", arg0, arg1, arg2)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Merge indicates an expected call of Merge
+func (mr *MockTagMockRecorder) Merge(arg0, arg1, arg2 interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Merge", reflect.TypeOf((*MockTag)(nil).Merge), arg0, arg1, arg2)
+}
+
+// Rename mocks base method
+func (m *MockTag) Rename(arg0 content.Tag, arg1 content.TagValue, arg2 content.User) (content.Tag, error) {
+	ret := m.ctrl.Call(m, "Rename", arg0, arg1, arg2)
+	ret0, _ := ret[0].(content.Tag)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Rename indicates an expected call of Rename
+func (mr *MockTagMockRecorder) Rename(arg0, arg1, arg2 interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Rename", reflect.TypeOf((*MockTag)(nil).Rename), arg0, arg1, arg2)
+}
+
+// SetOrder mocks base method
+func (m *MockTag) SetOrder(arg0 []content.TagID, arg1 content.User) error {
+	ret := m.ctrl.Call(m, "SetOrder", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SetOrder indicates an expected call of SetOrder
+func (mr *MockTagMockRecorder) SetOrder(arg0, arg1 interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetOrder", reflect.TypeOf((*MockTag)(nil).SetOrder), arg0, arg1)
+}
+
+// SetParent mocks base method
+func (m *MockTag) SetParent(arg0 content.Tag, arg1 content.Tag, arg2 content.User) error {
+	ret := m.ctrl.Call(m, "SetParent", arg0, arg1, arg2)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SetParent indicates an expected call of SetParent
+func (mr *MockTagMockRecorder) SetParent(arg0, arg1, arg2 interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetParent", reflect.TypeOf((*MockTag)(nil).SetParent), arg0, arg1, arg2)
+}
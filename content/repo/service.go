@@ -5,8 +5,11 @@ type Service interface {
 	UserRepo() User
 	TagRepo() Tag
 	FeedRepo() Feed
+	FeedImageRepo() FeedImage
+	FeedUpdateRepo() FeedUpdate
 	SubscriptionRepo() Subscription
 	ArticleRepo() Article
+	ArticleRevisionRepo() ArticleRevision
 	ExtractRepo() Extract
 	ThumbnailRepo() Thumbnail
 	ScoresRepo() Scores
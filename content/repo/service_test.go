@@ -17,6 +17,14 @@ func TestService(t *testing.T) {
 		t.Fatal("service.FeedRepo() = nil")
 	}
 
+	if service.FeedImageRepo() == nil {
+		t.Fatal("service.FeedImageRepo() = nil")
+	}
+
+	if service.FeedUpdateRepo() == nil {
+		t.Fatal("service.FeedUpdateRepo() = nil")
+	}
+
 	if service.SubscriptionRepo() == nil {
 		t.Fatal("service.SubscriptionRepo() = nil")
 	}
@@ -25,6 +33,10 @@ func TestService(t *testing.T) {
 		t.Fatal("service.ArticleRepo() = nil")
 	}
 
+	if service.ArticleRevisionRepo() == nil {
+		t.Fatal("service.ArticleRevisionRepo() = nil")
+	}
+
 	if service.ExtractRepo() == nil {
 		t.Fatal("service.ExtractRepo() = nil")
 	}
@@ -24,6 +24,8 @@ var (
 	readStateDeleteTemplate     *template.Template
 	favoriteStateInsertTemplate *template.Template
 	favoriteStateDeleteTemplate *template.Template
+	getEnclosuresTemplate       *template.Template
+	getCategoriesTemplate       *template.Template
 )
 
 type articleRepo struct {
@@ -53,6 +55,9 @@ const (
 	filterURLPrefix   = "filterURL"
 	filterTitlePrefix = "filterTitle"
 	filterIDPrefix    = "filterID"
+	enclosurePrefix   = "enclosure"
+	categoryPrefix    = "category"
+	authorPrefix      = "author"
 )
 
 // ForUser returns all user articles restricted by the QueryOptions
@@ -115,7 +120,15 @@ func (r articleRepo) All(opts ...content.QueryOpt) ([]content.Article, error) {
 	if err = r.db.WithNamedStmt(sql, nil, func(stmt *sqlx.NamedStmt) error {
 		return stmt.Select(&articles, args)
 	}); err != nil {
-		err = errors.Wrap(err, "getting articles")
+		return articles, errors.Wrap(err, "getting articles")
+	}
+
+	if err = getArticleEnclosures(articles, r.db, r.log); err != nil {
+		return articles, errors.WithMessage(err, "getting article enclosures")
+	}
+
+	if err = getArticleCategories(articles, r.db, r.log); err != nil {
+		err = errors.WithMessage(err, "getting article categories")
 	}
 
 	return articles, err
@@ -290,6 +303,104 @@ func (r articleRepo) RemoveStaleUnreadRecords() error {
 	return nil
 }
 
+type purgeArgs struct {
+	ID         content.ArticleID `db:"id"`
+	FeedID     content.FeedID    `db:"feed_id"`
+	BeforeDate time.Time         `db:"before_date"`
+	StaleDate  time.Time         `db:"stale_date"`
+	Limit      int               `db:"limit"`
+}
+
+// purgedRetention is how long the links of purged articles are remembered,
+// counting from their dates. The newest purged article of a feed is always
+// remembered, as its date marks the retention cutoff of the feed.
+const purgedRetention = 90 * 24 * time.Hour
+
+// Purge removes the articles of the feed dated before the given time, if it
+// isn't zero, as well as the ones beyond the newest keep articles, if keep is
+// positive. Favorite articles are never removed. The removed articles are
+// returned, so that they can be dropped from the search index, and are
+// remembered, so that they aren't created again by later feed updates.
+func (r articleRepo) Purge(feed content.Feed, before time.Time, keep int) ([]content.Article, error) {
+	if err := feed.Validate(); err != nil {
+		return []content.Article{}, errors.WithMessage(err, "validating feed")
+	}
+
+	r.log.Infof("Purging articles of feed %s", feed)
+
+	s := r.db.SQL()
+	args := purgeArgs{
+		FeedID:     feed.ID,
+		BeforeDate: before,
+		StaleDate:  time.Now().Add(-purgedRetention),
+		Limit:      keep,
+	}
+	purged := []content.Article{}
+
+	if err := r.db.WithTx(func(tx *sqlx.Tx) error {
+		seen := map[content.ArticleID]bool{}
+		for _, q := range []struct {
+			query   string
+			enabled bool
+		}{
+			{s.Article.GetStaleByDate, !before.IsZero()},
+			{s.Article.GetStaleByCount, keep > 0},
+		} {
+			if !q.enabled {
+				continue
+			}
+
+			var stale []content.Article
+			if err := r.db.WithNamedStmt(q.query, tx, func(stmt *sqlx.NamedStmt) error {
+				return stmt.Select(&stale, args)
+			}); err != nil {
+				return errors.Wrap(err, "getting stale articles")
+			}
+
+			for _, a := range stale {
+				if !seen[a.ID] {
+					seen[a.ID] = true
+					purged = append(purged, a)
+				}
+			}
+		}
+
+		if len(purged) == 0 {
+			return nil
+		}
+
+		queries := append([]string{s.Article.CreatePurged}, s.Article.DeleteDependents...)
+		queries = append(queries, s.Article.Delete)
+		for _, query := range queries {
+			if err := r.db.WithNamedStmt(query, tx, func(stmt *sqlx.NamedStmt) error {
+				for _, a := range purged {
+					args.ID = a.ID
+					if _, err := stmt.Exec(args); err != nil {
+						return errors.Wrapf(err, "deleting article %s", a)
+					}
+				}
+
+				return nil
+			}); err != nil {
+				return err
+			}
+		}
+
+		if err := r.db.WithNamedStmt(s.Article.DeleteStalePurged, tx, func(stmt *sqlx.NamedStmt) error {
+			_, err := stmt.Exec(args)
+			return err
+		}); err != nil {
+			return errors.Wrap(err, "deleting stale purged articles")
+		}
+
+		return nil
+	}); err != nil {
+		return []content.Article{}, errors.Wrapf(err, "purging articles of feed %s", feed)
+	}
+
+	return purged, nil
+}
+
 func getArticles(login content.Login, dbo *db.DB, log log.Log, opts content.QueryOptions) ([]content.Article, error) {
 	var err error
 	if getArticlesTemplate == nil {
@@ -365,9 +476,130 @@ func internalGetArticles(login content.Login, dbo *db.DB, log log.Log, opts cont
 		return []content.Article{}, errors.Wrap(err, "getting articles")
 	}
 
+	if err := getArticleEnclosures(articles, dbo, log); err != nil {
+		return []content.Article{}, errors.WithMessage(err, "getting article enclosures")
+	}
+
+	if err := getArticleCategories(articles, dbo, log); err != nil {
+		return []content.Article{}, errors.WithMessage(err, "getting article categories")
+	}
+
 	return articles, nil
 }
 
+func getArticleEnclosures(articles []content.Article, dbo *db.DB, log log.Log) error {
+	if len(articles) == 0 {
+		return nil
+	}
+
+	var err error
+	if getEnclosuresTemplate == nil {
+		getEnclosuresTemplate, err = template.New("get-enclosures-sql").
+			Parse(dbo.SQL().Article.GetEnclosuresTemplate)
+
+		if err != nil {
+			return errors.Wrap(err, "generating get-enclosures template")
+		}
+	}
+
+	index := make(map[content.ArticleID]int, len(articles))
+	args := make(map[string]interface{}, len(articles))
+	for i := range articles {
+		index[articles[i].ID] = i
+		args[fmt.Sprintf("%s%d", enclosurePrefix, i)] = articles[i].ID
+	}
+
+	renderData := getArticlesData{
+		Where: "WHERE " + dbo.WhereMultipleORs("ae.article_id", enclosurePrefix, len(articles), true),
+	}
+
+	buf := pool.Buffer.Get()
+	defer pool.Buffer.Put(buf)
+
+	if err := getEnclosuresTemplate.Execute(buf, renderData); err != nil {
+		return errors.Wrap(err, "executing get-enclosures template")
+	}
+
+	var enclosures []content.Enclosure
+
+	log.Debugf("Article enclosures SQL:\n%s\nArgs:%v\n", buf.String(), args)
+
+	if err := dbo.WithNamedStmt(buf.String(), nil, func(stmt *sqlx.NamedStmt) error {
+		return stmt.Select(&enclosures, args)
+	}); err != nil {
+		return errors.Wrap(err, "getting enclosures")
+	}
+
+	for _, e := range enclosures {
+		if i, ok := index[e.ArticleID]; ok {
+			articles[i].Enclosures = append(articles[i].Enclosures, e)
+		}
+	}
+
+	return nil
+}
+
+type articleCategory struct {
+	ArticleID content.ArticleID `db:"article_id"`
+	Category  string            `db:"category"`
+
+	FeedID content.FeedID `db:"feed_id"`
+	Guid   sql.NullString `db:"guid"`
+	Link   string         `db:"link"`
+}
+
+func getArticleCategories(articles []content.Article, dbo *db.DB, log log.Log) error {
+	if len(articles) == 0 {
+		return nil
+	}
+
+	var err error
+	if getCategoriesTemplate == nil {
+		getCategoriesTemplate, err = template.New("get-categories-sql").
+			Parse(dbo.SQL().Article.GetCategoriesTemplate)
+
+		if err != nil {
+			return errors.Wrap(err, "generating get-categories template")
+		}
+	}
+
+	index := make(map[content.ArticleID]int, len(articles))
+	args := make(map[string]interface{}, len(articles))
+	for i := range articles {
+		index[articles[i].ID] = i
+		args[fmt.Sprintf("%s%d", categoryPrefix, i)] = articles[i].ID
+	}
+
+	renderData := getArticlesData{
+		Where: "WHERE " + dbo.WhereMultipleORs("ac.article_id", categoryPrefix, len(articles), true),
+	}
+
+	buf := pool.Buffer.Get()
+	defer pool.Buffer.Put(buf)
+
+	if err := getCategoriesTemplate.Execute(buf, renderData); err != nil {
+		return errors.Wrap(err, "executing get-categories template")
+	}
+
+	var categories []articleCategory
+
+	log.Debugf("Article categories SQL:\n%s\nArgs:%v\n", buf.String(), args)
+
+	if err := dbo.WithNamedStmt(buf.String(), nil, func(stmt *sqlx.NamedStmt) error {
+		return stmt.Select(&categories, args)
+	}); err != nil {
+		return errors.Wrap(err, "getting categories")
+	}
+
+	for _, c := range categories {
+		if i, ok := index[c.ArticleID]; ok {
+			articles[i].Categories = append(articles[i].Categories, c.Category)
+		}
+	}
+
+	return nil
+}
+
 type stateType int
 
 const (
@@ -484,6 +716,10 @@ func constructSQLQueryOptions(
 		if opts.FavoriteOnly {
 			whereSlice = append(whereSlice, "af.article_id IS NOT NULL")
 		}
+
+		if opts.ExcludeReadDuplicates {
+			whereSlice = append(whereSlice, s.Article.ExcludeReadDuplicates)
+		}
 	}
 
 	if opts.BeforeID > 0 {
@@ -521,6 +757,29 @@ func constructSQLQueryOptions(
 		}
 	}
 
+	if len(opts.Authors) > 0 {
+		orSlice := make([]string, len(opts.Authors))
+		for i := range opts.Authors {
+			orSlice[i] = fmt.Sprintf("LOWER(a.author) = :%s%d", authorPrefix, i)
+			args[fmt.Sprintf("%s%d", authorPrefix, i)] = strings.ToLower(opts.Authors[i])
+		}
+
+		whereSlice = append(whereSlice, "("+strings.Join(orSlice, " OR ")+")")
+	}
+
+	if len(opts.Categories) > 0 {
+		orSlice := make([]string, len(opts.Categories))
+		for i := range opts.Categories {
+			orSlice[i] = fmt.Sprintf("LOWER(ac.category) = :%s%d", categoryPrefix, i)
+			args[fmt.Sprintf("%s%d", categoryPrefix, i)] = strings.ToLower(opts.Categories[i])
+		}
+
+		whereSlice = append(whereSlice, fmt.Sprintf(
+			"a.id IN (SELECT ac.article_id FROM articles_categories ac WHERE %s)",
+			strings.Join(orSlice, " OR "),
+		))
+	}
+
 	for i, f := range opts.Filters {
 		if !f.Valid() {
 			continue
@@ -635,7 +894,55 @@ func constructSQLQueryOptions(
 	return join, where, order, paging, args
 }
 
-func updateArticle(a content.Article, tx *sqlx.Tx, db *db.DB, log log.Log) (content.Article, error) {
+// purgedArticles holds the links and guids of the purged articles of a feed,
+// along with the date of the newest one.
+type purgedArticles struct {
+	links  map[string]bool
+	guids  map[string]bool
+	cutoff time.Time
+}
+
+// has reports whether the article has been purged, or is older than the
+// newest purged article of its feed and would only be purged again.
+func (p purgedArticles) has(a content.Article) bool {
+	if p.links[a.Link] || a.Guid.Valid && p.guids[a.Guid.String] {
+		return true
+	}
+
+	return a.Date.Before(p.cutoff)
+}
+
+func getPurgedArticles(feedID content.FeedID, tx *sqlx.Tx, db *db.DB) (purgedArticles, error) {
+	var rows []struct {
+		Link string     `db:"link"`
+		Guid string     `db:"guid"`
+		Date *time.Time `db:"date"`
+	}
+
+	if err := db.WithNamedStmt(db.SQL().Article.GetPurged, tx, func(stmt *sqlx.NamedStmt) error {
+		return stmt.Select(&rows, purgeArgs{FeedID: feedID})
+	}); err != nil {
+		return purgedArticles{}, errors.Wrap(err, "getting purged articles")
+	}
+
+	purged := purgedArticles{links: map[string]bool{}, guids: map[string]bool{}}
+	for _, r := range rows {
+		purged.links[r.Link] = true
+		if r.Guid != "" {
+			purged.guids[r.Guid] = true
+		}
+
+		if r.Date != nil && r.Date.After(purged.cutoff) {
+			purged.cutoff = *r.Date
+		}
+	}
+
+	return purged, nil
+}
+
+// updateArticle stores the parsed article, creating it unless it has been
+// purged before.
+func updateArticle(a content.Article, purged purgedArticles, tx *sqlx.Tx, db *db.DB, log log.Log) (content.Article, error) {
 	if err := a.Validate(); err != nil && a.ID != 0 {
 		return content.Article{}, errors.WithMessage(err, "validating article")
 	}
@@ -644,13 +951,45 @@ func updateArticle(a content.Article, tx *sqlx.Tx, db *db.DB, log log.Log) (cont
 
 	s := db.SQL()
 
-	db.WithNamedStmt(s.Article.Update, tx, func(stmt *sqlx.NamedStmt) error {
+	// The current content of an edited article is kept as a revision,
+	// before it is overwritten.
+	now := time.Now()
+	a.ContentHash, a.UpdateDate = a.Hash(), &now
+	if err := db.WithNamedStmt(s.Article.CreateRevision, tx, func(stmt *sqlx.NamedStmt) error {
+		res, err := stmt.Exec(a)
+		if err != nil {
+			return err
+		}
+
+		if num, err := res.RowsAffected(); err == nil && num > 0 {
+			log.Infof("Article %s has been updated\n", a)
+			a.Updated = true
+		}
+
+		return nil
+	}); err != nil {
+		return content.Article{}, errors.Wrapf(err, "creating article %s revision", a)
+	}
+
+	if !a.Updated {
+		a.UpdateDate = nil
+	}
+
+	skipped := false
+	if err := db.WithNamedStmt(s.Article.Update, tx, func(stmt *sqlx.NamedStmt) error {
 		res, err := stmt.Exec(a)
 		if err != nil {
 			return errors.Wrap(err, "executing article update statement")
 		}
 
 		if num, err := res.RowsAffected(); err != nil && err == sql.ErrNoRows || num == 0 {
+			if purged.has(a) {
+				log.Debugf("Skipping purged article %s\n", a)
+				skipped = true
+
+				return nil
+			}
+
 			log.Infof("Creating article %s\n", a)
 
 			id, err := db.CreateWithID(tx, s.Article.Create, a)
@@ -664,11 +1003,224 @@ func updateArticle(a content.Article, tx *sqlx.Tx, db *db.DB, log log.Log) (cont
 		}
 
 		return nil
-	})
+	}); err != nil {
+		return content.Article{}, err
+	}
+
+	if skipped {
+		return a, nil
+	}
+
+	// Parsed articles don't know the id of their stored counterpart.
+	if a.ID == 0 {
+		if err := db.WithNamedStmt(s.Article.GetID, tx, func(stmt *sqlx.NamedStmt) error {
+			return stmt.Get(&a.ID, a)
+		}); err != nil {
+			return content.Article{}, errors.Wrapf(err, "getting article %s id", a)
+		}
+	}
+
+	if a.IsNew {
+		var err error
+		if a, err = groupArticleDuplicates(a, tx, db, log); err != nil {
+			return content.Article{}, errors.WithMessage(err, "grouping article duplicates")
+		}
+	}
+
+	if err := updateArticleEnclosures(a, tx, db); err != nil {
+		return content.Article{}, errors.WithMessage(err, "updating article enclosures")
+	}
+
+	if err := updateArticleCategories(a, tx, db); err != nil {
+		return content.Article{}, errors.WithMessage(err, "updating article categories")
+	}
+
+	return a, nil
+}
+
+// duplicateWindow is the maximum time between the dates of two articles
+// with similar content for them to be considered duplicates.
+const duplicateWindow = 72 * time.Hour
+
+// duplicateCandidates limits the number of fingerprints compared with the
+// one of a new article.
+const duplicateCandidates = 100
+
+// fingerprintBands is the number of 16 bit bands the fingerprints are split
+// into. Since it is larger than content.MaxFingerprintDistance, the
+// fingerprints of duplicates share at least one band, which is indexed.
+const fingerprintBands = 4
+
+type articleDuplicate struct {
+	ArticleID     content.ArticleID `db:"article_id"`
+	CanonicalID   content.ArticleID `db:"canonical_id"`
+	CanonicalLink string            `db:"canonical_link"`
+	Fingerprint   int64             `db:"fingerprint"`
+
+	FeedID     content.FeedID `db:"feed_id"`
+	AfterDate  time.Time      `db:"after_date"`
+	BeforeDate time.Time      `db:"before_date"`
+	Band0      int64          `db:"band0"`
+	Band1      int64          `db:"band1"`
+	Band2      int64          `db:"band2"`
+	Band3      int64          `db:"band3"`
+	Limit      int            `db:"limit"`
+}
+
+// setBands splits the fingerprint into the bands used to look up the
+// fingerprints of possible duplicates.
+func (d *articleDuplicate) setBands() {
+	var bands [fingerprintBands]int64
+	for i := range bands {
+		bands[i] = int64(uint64(d.Fingerprint) >> uint(16*i) & 0xFFFF)
+	}
+
+	d.Band0, d.Band1, d.Band2, d.Band3 = bands[0], bands[1], bands[2], bands[3]
+}
+
+// groupArticleDuplicates looks for a copy of the new article in the other
+// feeds, first by its canonical link, and then by its content fingerprint.
+// The article is grouped under the canonical article of the first match, or
+// becomes the canonical article of its own group if none is found.
+func groupArticleDuplicates(a content.Article, tx *sqlx.Tx, db *db.DB, log log.Log) (content.Article, error) {
+	s := db.SQL()
+	data := articleDuplicate{
+		ArticleID:     a.ID,
+		CanonicalLink: content.CanonicalLink(a.Link),
+		Fingerprint:   a.Fingerprint(),
+		FeedID:        a.FeedID,
+		AfterDate:     a.Date.Add(-duplicateWindow),
+		BeforeDate:    a.Date.Add(duplicateWindow),
+		Limit:         duplicateCandidates,
+	}
+	data.setBands()
+
+	if err := db.WithNamedStmt(s.Article.GetDuplicateByLink, tx, func(stmt *sqlx.NamedStmt) error {
+		return stmt.Get(&data.CanonicalID, data)
+	}); err != nil && errors.Cause(err) != sql.ErrNoRows {
+		return content.Article{}, errors.Wrapf(err, "getting article %s duplicates by link", a)
+	}
+
+	if data.CanonicalID == 0 && data.Fingerprint != 0 {
+		var candidates []articleDuplicate
+		if err := db.WithNamedStmt(s.Article.GetDuplicateFingerprints, tx, func(stmt *sqlx.NamedStmt) error {
+			return stmt.Select(&candidates, data)
+		}); err != nil {
+			return content.Article{}, errors.Wrapf(err, "getting article %s duplicate fingerprints", a)
+		}
+
+		for _, c := range candidates {
+			if content.FingerprintDistance(c.Fingerprint, data.Fingerprint) <= content.MaxFingerprintDistance {
+				data.CanonicalID = c.CanonicalID
+				break
+			}
+		}
+	}
+
+	if data.CanonicalID == 0 {
+		data.CanonicalID = a.ID
+	} else {
+		log.Infof("Article %s is a duplicate of %d\n", a, data.CanonicalID)
+		a.DuplicateOf = data.CanonicalID
+	}
+
+	if err := db.WithNamedStmt(s.Article.CreateDuplicate, tx, func(stmt *sqlx.NamedStmt) error {
+		_, err := stmt.Exec(data)
+		return err
+	}); err != nil {
+		return content.Article{}, errors.Wrapf(err, "creating article %s duplicate record", a)
+	}
 
 	return a, nil
 }
 
+type articleEnclosure struct {
+	content.Enclosure
+
+	FeedID content.FeedID `db:"feed_id"`
+	Guid   sql.NullString `db:"guid"`
+	Link   string         `db:"link"`
+}
+
+// updateArticleEnclosures replaces the stored enclosures of the article with
+// the ones it currently holds. The article is matched in the same way as in
+// the update statement, since the ids of existing articles aren't known.
+func updateArticleEnclosures(a content.Article, tx *sqlx.Tx, db *db.DB) error {
+	if a.IsNew && len(a.Enclosures) == 0 {
+		return nil
+	}
+
+	s := db.SQL()
+	data := articleEnclosure{FeedID: a.FeedID, Guid: a.Guid, Link: a.Link}
+
+	if err := db.WithNamedStmt(s.Article.DeleteEnclosures, tx, func(stmt *sqlx.NamedStmt) error {
+		_, err := stmt.Exec(data)
+		return err
+	}); err != nil {
+		return errors.Wrapf(err, "deleting article %s enclosures", a)
+	}
+
+	if len(a.Enclosures) == 0 {
+		return nil
+	}
+
+	return db.WithNamedStmt(s.Article.CreateEnclosure, tx, func(stmt *sqlx.NamedStmt) error {
+		seen := map[string]bool{}
+		for _, e := range a.Enclosures {
+			if seen[e.URL] {
+				continue
+			}
+			seen[e.URL] = true
+
+			data.Enclosure = e
+			if _, err := stmt.Exec(data); err != nil {
+				return errors.Wrapf(err, "creating article %s enclosure %s", a, e)
+			}
+		}
+
+		return nil
+	})
+}
+
+// updateArticleCategories replaces the stored categories of the article,
+// matching it the same way as updateArticleEnclosures.
+func updateArticleCategories(a content.Article, tx *sqlx.Tx, db *db.DB) error {
+	if a.IsNew && len(a.Categories) == 0 {
+		return nil
+	}
+
+	s := db.SQL()
+	data := articleCategory{FeedID: a.FeedID, Guid: a.Guid, Link: a.Link}
+
+	if err := db.WithNamedStmt(s.Article.DeleteCategories, tx, func(stmt *sqlx.NamedStmt) error {
+		_, err := stmt.Exec(data)
+		return err
+	}); err != nil {
+		return errors.Wrapf(err, "deleting article %s categories", a)
+	}
+
+	if len(a.Categories) == 0 {
+		return nil
+	}
+
+	return db.WithNamedStmt(s.Article.CreateCategory, tx, func(stmt *sqlx.NamedStmt) error {
+		seen := map[string]bool{}
+		for _, c := range a.Categories {
+			if seen[c] {
+				continue
+			}
+			seen[c] = true
+
+			data.Category = c
+			if _, err := stmt.Exec(data); err != nil {
+				return errors.Wrapf(err, "creating article %s category %s", a, c)
+			}
+		}
+
+		return nil
+	})
+}
+
 func instantiateStateTemplates(s db.SqlStmts) error {
 	var err error
 	if readStateInsertTemplate == nil {
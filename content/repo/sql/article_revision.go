@@ -0,0 +1,33 @@
+package sql
+
+import (
+	"github.com/jmoiron/sqlx"
+	"github.com/pkg/errors"
+	"github.com/urandom/readeef/content"
+	"github.com/urandom/readeef/content/repo/sql/db"
+	"github.com/urandom/readeef/log"
+)
+
+type articleRevisionRepo struct {
+	db *db.DB
+
+	log log.Log
+}
+
+// ForArticle returns the revisions of the article, oldest first.
+func (r articleRevisionRepo) ForArticle(article content.Article) ([]content.ArticleRevision, error) {
+	if err := article.Validate(); err != nil {
+		return []content.ArticleRevision{}, errors.WithMessage(err, "validating article")
+	}
+
+	r.log.Infof("Getting revisions for article %s", article)
+
+	var revisions []content.ArticleRevision
+	if err := r.db.WithNamedStmt(r.db.SQL().Article.GetRevisions, nil, func(stmt *sqlx.NamedStmt) error {
+		return stmt.Select(&revisions, content.ArticleRevision{ArticleID: article.ID})
+	}); err != nil {
+		return []content.ArticleRevision{}, errors.Wrapf(err, "getting revisions for article %s", article)
+	}
+
+	return revisions, nil
+}
@@ -3,6 +3,7 @@ package base
 func init() {
 	sqlStmts.Article.Create = createFeedArticle
 	sqlStmts.Article.Update = updateFeedArticle
+	sqlStmts.Article.GetID = getFeedArticleID
 
 	sqlStmts.Article.CountTemplate = articleCountTemplate
 	sqlStmts.Article.GetUserlessTemplate = getArticlesUserlessTemplate
@@ -13,6 +14,13 @@ func init() {
 	sqlStmts.Article.StateFavoriteJoin = stateFavoriteJoin
 	sqlStmts.Article.GetIDsTemplate = getArticleIDsTemplate
 	sqlStmts.Article.DeleteStaleUnreadRecords = deleteStaleUnreadRecords
+	sqlStmts.Article.GetStaleByDate = getStaleArticlesByDate
+	sqlStmts.Article.GetStaleByCount = getStaleArticlesByCount
+	sqlStmts.Article.Delete = deleteArticle
+	sqlStmts.Article.DeleteDependents = deleteArticleDependents
+	sqlStmts.Article.GetPurged = getPurgedArticles
+	sqlStmts.Article.CreatePurged = createPurgedArticle
+	sqlStmts.Article.DeleteStalePurged = deleteStalePurgedArticles
 	sqlStmts.Article.GetScoreJoin = getArticlesScoreJoin
 	sqlStmts.Article.GetUntaggedJoin = getArticlesUntaggedJoin
 
@@ -20,19 +28,103 @@ func init() {
 	sqlStmts.Article.ReadStateDeleteTemplate = readStateDeleteTemplate
 	sqlStmts.Article.FavoriteStateInsertTemplate = favoriteStateInsertTemplate
 	sqlStmts.Article.FavoriteStateDeleteTemplate = favoriteStateDeleteTemplate
+
+	sqlStmts.Article.GetEnclosuresTemplate = getArticleEnclosuresTemplate
+	sqlStmts.Article.CreateEnclosure = createArticleEnclosure
+	sqlStmts.Article.DeleteEnclosures = deleteArticleEnclosures
+
+	sqlStmts.Article.GetCategoriesTemplate = getArticleCategoriesTemplate
+	sqlStmts.Article.CreateCategory = createArticleCategory
+	sqlStmts.Article.DeleteCategories = deleteArticleCategories
+
+	sqlStmts.Article.GetRevisions = getArticleRevisions
+	sqlStmts.Article.CreateRevision = createArticleRevision
+
+	sqlStmts.Article.GetDuplicateByLink = getArticleDuplicateByLink
+	sqlStmts.Article.GetDuplicateFingerprints = getArticleDuplicateFingerprints
+	sqlStmts.Article.CreateDuplicate = createArticleDuplicate
+	sqlStmts.Article.ExcludeReadDuplicates = excludeReadArticleDuplicates
 }
 
 const (
 	createFeedArticle = `
-INSERT INTO articles(feed_id, link, guid, title, description, date)
-	SELECT :feed_id, :link, :guid, :title, :description, :date EXCEPT
-	SELECT feed_id, link, CAST(:guid AS TEXT), CAST(:title as TEXT), CAST(:description AS TEXT), CAST(:date AS TIMESTAMP WITH TIME ZONE)
+INSERT INTO articles(feed_id, link, guid, title, description, summary, author, date, date_inferred, content_hash)
+	SELECT :feed_id, :link, :guid, :title, :description, :summary, :author, :date, :date_inferred, :content_hash EXCEPT
+	SELECT feed_id, link, CAST(:guid AS TEXT), CAST(:title as TEXT), CAST(:description AS TEXT), CAST(:summary AS TEXT), CAST(:author AS TEXT), CAST(:date AS TIMESTAMP WITH TIME ZONE), CAST(:date_inferred AS BOOLEAN), CAST(:content_hash AS TEXT)
 	FROM articles WHERE feed_id = :feed_id AND link = :link
 `
 
+	// Inferred dates are only set when the article is created, so that it
+	// keeps the time it was first seen at. The update date is only set when
+	// the content has changed.
 	updateFeedArticle = `
-UPDATE articles SET title = :title, description = :description, date = :date, guid = :guid, link = :link
+UPDATE articles SET title = :title, description = :description, summary = :summary, author = :author,
+	date = CASE WHEN :date_inferred THEN date ELSE :date END, date_inferred = date_inferred AND :date_inferred,
+	guid = :guid, link = :link, content_hash = :content_hash, update_date = COALESCE(:update_date, update_date)
 	WHERE feed_id = :feed_id AND (guid = :guid OR link = :link)
+`
+	getFeedArticleID = `
+SELECT id FROM articles WHERE feed_id = :feed_id AND (guid = :guid OR link = :link) ORDER BY id LIMIT 1
+`
+	// Articles stored before their content was hashed are not considered
+	// changed.
+	createArticleRevision = `
+INSERT INTO articles_revisions(article_id, revision_date, title, description, summary)
+	SELECT id, CAST(:update_date AS TIMESTAMP WITH TIME ZONE), title, description, summary
+	FROM articles
+	WHERE feed_id = :feed_id AND (guid = :guid OR link = :link)
+		AND content_hash <> '' AND content_hash <> :content_hash
+`
+	getArticleRevisions = `
+SELECT ar.id, ar.article_id, ar.revision_date,
+	COALESCE(ar.title, '') AS title,
+	COALESCE(ar.description, '') AS description,
+	COALESCE(ar.summary, '') AS summary
+FROM articles_revisions ar
+WHERE ar.article_id = :article_id
+ORDER BY ar.revision_date, ar.id
+`
+	// Only copies of the story from other feeds are considered duplicates.
+	getArticleDuplicateByLink = `
+SELECT ad.canonical_id
+FROM articles_duplicates ad INNER JOIN articles a
+	ON ad.article_id = a.id
+WHERE ad.canonical_link = :canonical_link AND a.feed_id <> :feed_id
+ORDER BY ad.canonical_id
+LIMIT 1
+`
+	getArticleDuplicateFingerprints = `
+SELECT ad.canonical_id, ad.fingerprint
+FROM articles_duplicates ad INNER JOIN articles a
+	ON ad.article_id = a.id
+WHERE ad.fingerprint <> 0 AND a.feed_id <> :feed_id
+	AND a.date > :after_date AND a.date < :before_date
+	AND (
+		(ad.fingerprint & 65535) = :band0
+		OR ((ad.fingerprint >> 16) & 65535) = :band1
+		OR ((ad.fingerprint >> 32) & 65535) = :band2
+		OR ((ad.fingerprint >> 48) & 65535) = :band3
+	)
+ORDER BY ad.canonical_id
+LIMIT :limit
+`
+	createArticleDuplicate = `
+INSERT INTO articles_duplicates(article_id, canonical_id, canonical_link, fingerprint)
+	VALUES(:article_id, :canonical_id, :canonical_link, :fingerprint)
+`
+	excludeReadArticleDuplicates = `
+NOT EXISTS (
+	SELECT 1
+	FROM articles_duplicates rad INNER JOIN articles_duplicates rads
+		ON rad.canonical_id = rads.canonical_id AND rad.article_id <> rads.article_id
+	INNER JOIN articles ra
+		ON rads.article_id = ra.id
+	INNER JOIN users_feeds ruf
+		ON ra.feed_id = ruf.feed_id AND ruf.user_login = :user_login
+	LEFT OUTER JOIN users_articles_unread rau
+		ON ra.id = rau.article_id AND rau.user_login = :user_login
+	WHERE rad.article_id = a.id AND rau.article_id IS NULL
+)
 `
 	articleCountTemplate = `
 SELECT count(*)
@@ -41,7 +133,11 @@ FROM articles a
 {{ .Where }}
 `
 	getArticlesUserlessTemplate = `
-SELECT a.feed_id, a.id, a.title, a.description, a.link, a.date, a.guid,
+SELECT a.feed_id, a.id, a.title, a.description, a.link, a.date, a.date_inferred, a.guid,
+	a.update_date, CASE WHEN a.update_date IS NULL THEN 0 ELSE 1 END AS updated,
+	CASE WHEN ad.canonical_id IS NULL OR ad.canonical_id = a.id THEN 0 ELSE ad.canonical_id END AS duplicate_of,
+	COALESCE(a.summary, '') AS summary,
+	COALESCE(a.author, '') AS author,
 	COALESCE(at.thumbnail, '') as thumbnail,
 	COALESCE(at.link, '') as thumbnail_link
 	{{ .Columns }}
@@ -49,12 +145,18 @@ FROM articles a
 {{ .Join }}
 LEFT OUTER JOIN articles_thumbnails at
     ON a.id = at.article_id
+LEFT OUTER JOIN articles_duplicates ad
+    ON a.id = ad.article_id
 {{ .Where }}
 {{ .Order }}
 {{ .Limit }}
 `
 	getArticlesTemplate = `
-SELECT a.feed_id, a.id, a.title, a.description, a.link, a.date, a.guid,
+SELECT a.feed_id, a.id, a.title, a.description, a.link, a.date, a.date_inferred, a.guid,
+	a.update_date, CASE WHEN a.update_date IS NULL THEN 0 ELSE 1 END AS updated,
+	CASE WHEN ad.canonical_id IS NULL OR ad.canonical_id = a.id THEN 0 ELSE ad.canonical_id END AS duplicate_of,
+	COALESCE(a.summary, '') AS summary,
+	COALESCE(a.author, '') AS author,
 	CASE WHEN au.article_id IS NULL THEN 1 ELSE 0 END AS read,
 	CASE WHEN af.article_id IS NULL THEN 0 ELSE 1 END AS favorite,
 	COALESCE(at.thumbnail, '') as thumbnail,
@@ -70,6 +172,8 @@ LEFT OUTER JOIN users_articles_favorite af
     ON a.id = af.article_id AND uf.user_login = af.user_login
 LEFT OUTER JOIN articles_thumbnails at
     ON a.id = at.article_id
+LEFT OUTER JOIN articles_duplicates ad
+    ON a.id = ad.article_id
 {{ .Where }}
 {{ .Order }}
 {{ .Limit }}
@@ -99,6 +203,44 @@ SELECT a.id FROM (
 	{{ .Limit }}
 ) a
 `
+	// Favorite articles are never stale.
+	getStaleArticlesByDate = `
+SELECT a.id, a.feed_id, a.link
+FROM articles a
+WHERE a.feed_id = :feed_id AND a.date < :before_date
+	AND NOT EXISTS (SELECT 1 FROM users_articles_favorite af WHERE af.article_id = a.id)
+`
+	getPurgedArticles = `
+SELECT ap.link, COALESCE(ap.guid, '') AS guid, ap.date
+FROM articles_purged ap
+WHERE ap.feed_id = :feed_id
+`
+	createPurgedArticle = `
+INSERT INTO articles_purged(feed_id, link, guid, date)
+SELECT a.feed_id, a.link, a.guid, a.date
+FROM articles a
+WHERE a.id = :id AND NOT EXISTS (
+	SELECT 1 FROM articles_purged ap WHERE ap.feed_id = a.feed_id AND ap.link = a.link
+)
+`
+	deleteStalePurgedArticles = `
+DELETE FROM articles_purged
+WHERE feed_id = :feed_id AND date < :stale_date
+	AND date < (SELECT MAX(ap.date) FROM articles_purged ap WHERE ap.feed_id = :feed_id)
+`
+	getStaleArticlesByCount = `
+SELECT a.id, a.feed_id, a.link
+FROM articles a
+WHERE a.feed_id = :feed_id
+	AND NOT EXISTS (SELECT 1 FROM users_articles_favorite af WHERE af.article_id = a.id)
+	AND a.id NOT IN (
+		SELECT ka.id FROM articles ka
+		WHERE ka.feed_id = :feed_id
+		ORDER BY ka.date DESC, ka.id DESC
+		LIMIT :limit
+	)
+`
+	deleteArticle            = `DELETE FROM articles WHERE id = :id`
 	deleteStaleUnreadRecords = `DELETE FROM users_articles_unread WHERE insert_date < :insert_date`
 	getArticlesScoreJoin     = `
 	INNER JOIN articles_scores asco ON a.id = asco.article_id
@@ -108,13 +250,23 @@ LEFT OUTER JOIN users_feeds_tags uft
 	ON uft.feed_id = uf.feed_id
 	AND uft.user_login = uf.user_login
 `
+	// The state of an article is shared with its duplicates from the
+	// other feeds of the user.
 	readStateInsertTemplate = `
 INSERT INTO users_articles_unread (user_login, article_id)
-SELECT uf.user_login, a.id
+SELECT uf.user_login, COALESCE(ads.article_id, a.id)
 FROM users_feeds uf
 INNER JOIN articles a
 		ON uf.feed_id = a.feed_id AND uf.user_login = :user_login
 {{ .Join }}
+LEFT OUTER JOIN articles_duplicates ad
+	ON a.id = ad.article_id
+LEFT OUTER JOIN (articles_duplicates ads
+	INNER JOIN articles sa
+		ON ads.article_id = sa.id
+	INNER JOIN users_feeds suf
+		ON sa.feed_id = suf.feed_id AND suf.user_login = :user_login)
+	ON ad.canonical_id = ads.canonical_id
 {{ .Where }}
 EXCEPT SELECT au.user_login, au.article_id
 FROM users_articles_unread au
@@ -122,21 +274,33 @@ WHERE au.user_login = :user_login
 `
 	readStateDeleteTemplate = `
 DELETE FROM users_articles_unread WHERE user_login = :user_login AND article_id IN (
-	SELECT a.id
+	SELECT COALESCE(ads.article_id, a.id)
 	FROM users_feeds uf INNER JOIN articles a
 		ON uf.feed_id = a.feed_id
 		AND uf.user_login = :user_login
 	{{ .Join }}
+	LEFT OUTER JOIN articles_duplicates ad
+		ON a.id = ad.article_id
+	LEFT OUTER JOIN articles_duplicates ads
+		ON ad.canonical_id = ads.canonical_id
 	{{ .Where }}
 )
 `
 	favoriteStateInsertTemplate = `
 INSERT INTO users_articles_favorite (user_login, article_id)
-SELECT uf.user_login, a.id
+SELECT uf.user_login, COALESCE(ads.article_id, a.id)
 FROM users_feeds uf
 INNER JOIN articles a
 	ON uf.feed_id = a.feed_id AND uf.user_login = :user_login
 {{ .Join }}
+LEFT OUTER JOIN articles_duplicates ad
+	ON a.id = ad.article_id
+LEFT OUTER JOIN (articles_duplicates ads
+	INNER JOIN articles sa
+		ON ads.article_id = sa.id
+	INNER JOIN users_feeds suf
+		ON sa.feed_id = suf.feed_id AND suf.user_login = :user_login)
+	ON ad.canonical_id = ads.canonical_id
 {{ .Where }}
 EXCEPT SELECT af.user_login, af.article_id
 FROM users_articles_favorite af
@@ -144,12 +308,71 @@ WHERE af.user_login = :user_login
 `
 	favoriteStateDeleteTemplate = `
 DELETE FROM users_articles_favorite WHERE user_login = :user_login AND article_id IN (
-	SELECT a.id
+	SELECT COALESCE(ads.article_id, a.id)
 	FROM users_feeds uf INNER JOIN articles a
 		ON uf.feed_id = a.feed_id
 		AND uf.user_login = :user_login
 	{{ .Join }}
+	LEFT OUTER JOIN articles_duplicates ad
+		ON a.id = ad.article_id
+	LEFT OUTER JOIN articles_duplicates ads
+		ON ad.canonical_id = ads.canonical_id
 	{{ .Where }}
 )
 `
+	getArticleEnclosuresTemplate = `
+SELECT ae.article_id, ae.url, ae.mime_type, ae.length, ae.duration
+FROM articles_enclosures ae
+{{ .Where }}
+ORDER BY ae.article_id
+`
+	createArticleEnclosure = `
+INSERT INTO articles_enclosures(article_id, url, mime_type, length, duration)
+SELECT a.id, CAST(:url AS TEXT), CAST(:mime_type AS TEXT), CAST(:length AS BIGINT), CAST(:duration AS BIGINT)
+FROM articles a
+WHERE a.feed_id = :feed_id AND (a.guid = :guid OR a.link = :link)
+`
+	deleteArticleEnclosures = `
+DELETE FROM articles_enclosures WHERE article_id IN (
+	SELECT a.id FROM articles a
+	WHERE a.feed_id = :feed_id AND (a.guid = :guid OR a.link = :link)
+)
+`
+	getArticleCategoriesTemplate = `
+SELECT ac.article_id, ac.category
+FROM articles_categories ac
+{{ .Where }}
+ORDER BY ac.article_id
+`
+	createArticleCategory = `
+INSERT INTO articles_categories(article_id, category)
+SELECT a.id, CAST(:category AS TEXT)
+FROM articles a
+WHERE a.feed_id = :feed_id AND (a.guid = :guid OR a.link = :link)
+`
+	deleteArticleCategories = `
+DELETE FROM articles_categories WHERE article_id IN (
+	SELECT a.id FROM articles a
+	WHERE a.feed_id = :feed_id AND (a.guid = :guid OR a.link = :link)
 )
+`
+)
+
+// deleteArticleDependents remove the rest of the article data. They do not
+// rely on the foreign key cascades, as sqlite only enforces them on the
+// connections that enable them. The duplicates of a removed canonical
+// article are grouped under the oldest remaining one.
+var deleteArticleDependents = []string{
+	`DELETE FROM users_articles_unread WHERE article_id = :id`,
+	`DELETE FROM users_articles_favorite WHERE article_id = :id`,
+	`DELETE FROM articles_scores WHERE article_id = :id`,
+	`DELETE FROM articles_thumbnails WHERE article_id = :id`,
+	`DELETE FROM articles_extracts WHERE article_id = :id`,
+	`DELETE FROM articles_revisions WHERE article_id = :id`,
+	`DELETE FROM articles_duplicates WHERE article_id = :id`,
+	`UPDATE articles_duplicates SET canonical_id = (
+		SELECT MIN(od.article_id) FROM articles_duplicates od WHERE od.canonical_id = :id
+	) WHERE canonical_id = :id`,
+	`DELETE FROM articles_enclosures WHERE article_id = :id`,
+	`DELETE FROM articles_categories WHERE article_id = :id`,
+}
@@ -18,14 +18,20 @@ func init() {
 	sqlStmts.Feed.Detach = deleteUserFeed
 	sqlStmts.Feed.CreateUserTag = createUserFeedTag
 	sqlStmts.Feed.DeleteUserTags = deleteUserFeedTags
+
+	sqlStmts.Feed.MergeUsers = mergeUserFeeds
+	sqlStmts.Feed.MergeUserTags = mergeUserFeedTags
+	sqlStmts.Feed.MergeArticles = mergeFeedArticles
+	sqlStmts.Feed.MergeUnread = mergeFeedArticlesUnread
+	sqlStmts.Feed.MergeFavorite = mergeFeedArticlesFavorite
 }
 
 const (
 	feedIDs    = `SELECT id FROM feeds`
 	createFeed = `
-INSERT INTO feeds(link, title, description, hub_link, site_link, update_error, subscribe_error)
-SELECT :link, :title, :description, :hub_link, :site_link, :update_error, :subscribe_error EXCEPT SELECT link, title, description, hub_link, site_link, update_error, subscribe_error FROM feeds WHERE link = :link`
-	updateFeed = `UPDATE feeds SET link = :link, title = :title, description = :description, hub_link = :hub_link, site_link = :site_link, update_error = :update_error, subscribe_error = :subscribe_error WHERE id = :id`
+INSERT INTO feeds(link, title, description, hub_link, site_link, update_error, subscribe_error, etag, last_modified, dead, credentials, scrape_rules, diagnostic)
+SELECT :link, :title, :description, :hub_link, :site_link, :update_error, :subscribe_error, :etag, :last_modified, :dead, :credentials, :scrape_rules, :diagnostic EXCEPT SELECT link, title, description, hub_link, site_link, update_error, subscribe_error, etag, last_modified, dead, credentials, scrape_rules, diagnostic FROM feeds WHERE link = :link`
+	updateFeed = `UPDATE feeds SET link = :link, title = :title, description = :description, hub_link = :hub_link, site_link = :site_link, update_error = :update_error, subscribe_error = :subscribe_error, etag = :etag, last_modified = :last_modified, dead = :dead, credentials = :credentials, scrape_rules = :scrape_rules, diagnostic = :diagnostic WHERE id = :id`
 	deleteFeed = `DELETE FROM feeds WHERE id = :id`
 
 	getFeedUsers = `
@@ -50,34 +56,100 @@ INSERT INTO users_feeds_tags(user_login, feed_id, tag_id)
 DELETE FROM users_feeds_tags WHERE user_login = :user_login AND feed_id = :feed_id
 `
 
-	getFeed       = `SELECT link, title, description, hub_link, site_link, update_error, subscribe_error FROM feeds WHERE id = :id`
-	getFeedByLink = `SELECT id, title, description, hub_link, site_link, update_error, subscribe_error FROM feeds WHERE link = :link`
+	mergeUserFeeds = `
+INSERT INTO users_feeds(user_login, feed_id)
+SELECT uf.user_login, :to_id FROM users_feeds uf
+WHERE uf.feed_id = :from_id AND uf.user_login NOT IN (
+	SELECT user_login FROM users_feeds WHERE feed_id = :to_id
+)
+`
+	mergeUserFeedTags = `
+INSERT INTO users_feeds_tags(user_login, feed_id, tag_id)
+SELECT uft.user_login, :to_id, uft.tag_id FROM users_feeds_tags uft
+WHERE uft.feed_id = :from_id AND NOT EXISTS (
+	SELECT 1 FROM users_feeds_tags uft2
+	WHERE uft2.user_login = uft.user_login AND uft2.feed_id = :to_id AND uft2.tag_id = uft.tag_id
+)
+`
+	mergeFeedArticles = `
+UPDATE articles SET feed_id = :to_id
+WHERE feed_id = :from_id AND NOT EXISTS (
+	SELECT 1 FROM articles a
+	WHERE a.feed_id = :to_id AND (a.link = articles.link OR a.guid = articles.guid)
+)
+`
+	mergeFeedArticlesUnread = `
+INSERT INTO users_articles_unread(user_login, article_id)
+SELECT DISTINCT uau.user_login, a.id
+FROM users_articles_unread uau
+INNER JOIN articles fa
+	ON uau.article_id = fa.id AND fa.feed_id = :from_id
+INNER JOIN articles a
+	ON a.feed_id = :to_id AND (a.link = fa.link OR a.guid = fa.guid)
+WHERE NOT EXISTS (
+	SELECT 1 FROM users_articles_unread uau2
+	WHERE uau2.user_login = uau.user_login AND uau2.article_id = a.id
+)
+`
+	mergeFeedArticlesFavorite = `
+INSERT INTO users_articles_favorite(user_login, article_id)
+SELECT DISTINCT uaf.user_login, a.id
+FROM users_articles_favorite uaf
+INNER JOIN articles fa
+	ON uaf.article_id = fa.id AND fa.feed_id = :from_id
+INNER JOIN articles a
+	ON a.feed_id = :to_id AND (a.link = fa.link OR a.guid = fa.guid)
+WHERE NOT EXISTS (
+	SELECT 1 FROM users_articles_favorite uaf2
+	WHERE uaf2.user_login = uaf.user_login AND uaf2.article_id = a.id
+)
+`
+
+	getFeed       = `SELECT link, title, description, hub_link, site_link, update_error, subscribe_error, COALESCE(etag, '') AS etag, COALESCE(last_modified, '') AS last_modified, dead, credentials, COALESCE(scrape_rules, '') AS scrape_rules, COALESCE(diagnostic, '') AS diagnostic FROM feeds WHERE id = :id`
+	getFeedByLink = `SELECT id, title, description, hub_link, site_link, update_error, subscribe_error, COALESCE(etag, '') AS etag, COALESCE(last_modified, '') AS last_modified, dead, credentials, COALESCE(scrape_rules, '') AS scrape_rules, COALESCE(diagnostic, '') AS diagnostic FROM feeds WHERE link = :link`
 	getUserFeed   = `
-SELECT f.id, f.link, f.title, f.description, f.link, f.hub_link, f.site_link, f.update_error, f.subscribe_error
+SELECT f.id, f.link, f.title, f.description, f.link, f.hub_link, f.site_link, f.update_error, f.subscribe_error,
+	COALESCE(f.etag, '') AS etag, COALESCE(f.last_modified, '') AS last_modified, f.dead, f.credentials,
+	COALESCE(f.scrape_rules, '') AS scrape_rules, COALESCE(f.diagnostic, '') AS diagnostic
 FROM feeds f, users_feeds uf
 WHERE f.id = uf.feed_id
 	AND f.id = :id AND uf.user_login = :user_login
 `
-	getFeeds     = `SELECT id, link, title, description, hub_link, site_link, update_error, subscribe_error FROM feeds`
+	getFeeds     = `SELECT id, link, title, description, hub_link, site_link, update_error, subscribe_error, COALESCE(etag, '') AS etag, COALESCE(last_modified, '') AS last_modified, dead, credentials, COALESCE(scrape_rules, '') AS scrape_rules, COALESCE(diagnostic, '') AS diagnostic FROM feeds`
 	getUserFeeds = `
-SELECT f.id, f.link, f.title, f.description, f.link, f.hub_link, f.site_link, f.update_error, f.subscribe_error
+SELECT f.id, f.link, f.title, f.description, f.link, f.hub_link, f.site_link, f.update_error, f.subscribe_error,
+	COALESCE(f.etag, '') AS etag, COALESCE(f.last_modified, '') AS last_modified, f.dead, f.credentials,
+	COALESCE(f.scrape_rules, '') AS scrape_rules, COALESCE(f.diagnostic, '') AS diagnostic
 FROM feeds f, users_feeds uf
 WHERE f.id = uf.feed_id
 	AND uf.user_login = :user_login
 ORDER BY LOWER(f.title)
 `
 	getUserTagFeeds = `
-SELECT f.id, f.link, f.title, f.description, f.link, f.hub_link, f.site_link, f.update_error, f.subscribe_error
-FROM feeds f, users_feeds_tags uft, tags t
-WHERE f.id = uft.feed_id
-	AND t.id = uft.tag_id
-	AND uft.user_login = :user_login AND t.value = :tag_value
+SELECT f.id, f.link, f.title, f.description, f.link, f.hub_link, f.site_link, f.update_error, f.subscribe_error,
+	COALESCE(f.etag, '') AS etag, COALESCE(f.last_modified, '') AS last_modified, f.dead, f.credentials,
+	COALESCE(f.scrape_rules, '') AS scrape_rules, COALESCE(f.diagnostic, '') AS diagnostic
+FROM feeds f
+WHERE f.id IN (
+	WITH RECURSIVE tag_tree(id) AS (
+		SELECT t.id FROM tags t WHERE t.value = :tag_value
+		UNION
+		SELECT utp.tag_id FROM users_tags_parents utp INNER JOIN tag_tree tt
+			ON utp.parent_id = tt.id
+		WHERE utp.user_login = :user_login
+	)
+	SELECT uft.feed_id FROM users_feeds_tags uft
+	WHERE uft.user_login = :user_login AND uft.tag_id IN (SELECT id FROM tag_tree)
+)
 ORDER BY LOWER(f.title)
 `
 	getUnsubscribedFeeds = `
-SELECT f.id, f.link, f.title, f.description, f.hub_link, f.site_link, f.update_error, f.subscribe_error
+SELECT f.id, f.link, f.title, f.description, f.hub_link, f.site_link, f.update_error, f.subscribe_error,
+	COALESCE(f.etag, '') AS etag, COALESCE(f.last_modified, '') AS last_modified, f.dead, f.credentials,
+	COALESCE(f.scrape_rules, '') AS scrape_rules, COALESCE(f.diagnostic, '') AS diagnostic
 	FROM feeds f LEFT OUTER JOIN hubbub_subscriptions hs
 	ON f.id = hs.feed_id AND hs.subscription_failure = '1'
+	WHERE NOT f.dead
 	ORDER BY f.title
 `
 )
@@ -0,0 +1,44 @@
+package base
+
+func init() {
+	sqlStmts.FeedImage.Get = getFeedImage
+	sqlStmts.FeedImage.Create = createFeedImage
+	sqlStmts.FeedImage.Delete = deleteFeedImages
+	sqlStmts.FeedImage.GetFavicon = getFeedFavicon
+	sqlStmts.FeedImage.AllFavicons = getUserFeedFavicons
+	sqlStmts.FeedImage.CreateFavicon = createFeedFavicon
+	sqlStmts.FeedImage.UpdateFavicon = updateFeedFavicon
+}
+
+const (
+	getFeedImage = `
+SELECT fi.feed_id, COALESCE(fi.title, '') AS title, fi.url, COALESCE(fi.width, 0) AS width, COALESCE(fi.height, 0) AS height
+FROM feed_images fi
+WHERE fi.feed_id = :feed_id
+ORDER BY fi.id DESC
+`
+	createFeedImage = `
+INSERT INTO feed_images(feed_id, title, url, width, height)
+	VALUES(:feed_id, :title, :url, :width, :height)
+`
+	deleteFeedImages = `DELETE FROM feed_images WHERE feed_id = :feed_id`
+
+	getFeedFavicon = `
+SELECT ff.feed_id, ff.link, ff.data, ff.update_date
+FROM feed_favicons ff
+WHERE ff.feed_id = :feed_id
+`
+	getUserFeedFavicons = `
+SELECT ff.feed_id, ff.link, ff.data, ff.update_date
+FROM feed_favicons ff, users_feeds uf
+WHERE ff.feed_id = uf.feed_id AND uf.user_login = :user_login
+	AND ff.data != ''
+ORDER BY ff.feed_id
+`
+	createFeedFavicon = `
+INSERT INTO feed_favicons(feed_id, link, data, update_date)
+	VALUES(:feed_id, :link, :data, :update_date)
+`
+	updateFeedFavicon = `
+UPDATE feed_favicons SET link = :link, data = :data, update_date = :update_date WHERE feed_id = :feed_id`
+)
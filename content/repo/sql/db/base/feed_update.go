@@ -0,0 +1,22 @@
+package base
+
+func init() {
+	sqlStmts.FeedUpdate.Get = getFeedUpdates
+	sqlStmts.FeedUpdate.Create = createFeedUpdate
+	sqlStmts.FeedUpdate.DeleteStale = deleteStaleFeedUpdates
+}
+
+const (
+	getFeedUpdates = `
+SELECT fu.feed_id, fu.update_date, fu.duration, fu.status, fu.bytes, fu.new_articles, fu.updated_articles, fu.error
+FROM feed_updates fu
+WHERE fu.feed_id = :feed_id
+ORDER BY fu.update_date DESC, fu.id DESC
+LIMIT :limit
+`
+	createFeedUpdate = `
+INSERT INTO feed_updates(feed_id, update_date, duration, status, bytes, new_articles, updated_articles, error)
+	VALUES(:feed_id, :update_date, :duration, :status, :bytes, :new_articles, :updated_articles, :error)
+`
+	deleteStaleFeedUpdates = `DELETE FROM feed_updates WHERE update_date < :before`
+)
@@ -5,24 +5,50 @@ func init() {
 	sqlStmts.Subscription.All = getHubbubSubscriptions
 	sqlStmts.Subscription.Create = createHubbubSubscription
 	sqlStmts.Subscription.Update = updateHubbubSubscription
+
+	sqlStmts.Subscription.GetSubscribers = getHubSubscribers
+	sqlStmts.Subscription.CreateSubscriber = createHubSubscriber
+	sqlStmts.Subscription.UpdateSubscriber = updateHubSubscriber
+	sqlStmts.Subscription.DeleteSubscriber = deleteHubSubscriber
 }
 
 const (
 	getFeedHubbubSubscription = `
-SELECT link, lease_duration, verification_time, subscription_failure
+SELECT link, lease_duration, verification_time, subscription_failure, COALESCE(secret, '') AS secret,
+	COALESCE(topic, '') AS topic, COALESCE(pending, '') AS pending
 FROM hubbub_subscriptions WHERE feed_id = :feed_id`
 	getHubbubSubscriptions = `
-SELECT link, feed_id, lease_duration, verification_time, subscription_failure
+SELECT link, feed_id, lease_duration, verification_time, subscription_failure, COALESCE(secret, '') AS secret,
+	COALESCE(topic, '') AS topic, COALESCE(pending, '') AS pending
 	FROM hubbub_subscriptions`
 
 	createHubbubSubscription = `
-INSERT INTO hubbub_subscriptions(feed_id, link, lease_duration, verification_time, subscription_failure)
-	SELECT :feed_id, :link, :lease_duration, :verification_time, :subscription_failure EXCEPT
-	SELECT feed_id, link, lease_duration, verification_time, subscription_failure
+INSERT INTO hubbub_subscriptions(feed_id, link, lease_duration, verification_time, subscription_failure, secret, topic, pending)
+	SELECT :feed_id, :link, :lease_duration, :verification_time, :subscription_failure, :secret, :topic, :pending EXCEPT
+	SELECT feed_id, link, lease_duration, verification_time, subscription_failure, secret, topic, pending
 		FROM hubbub_subscriptions WHERE feed_id = :feed_id
 `
 	updateHubbubSubscription = `
 UPDATE hubbub_subscriptions SET link = :link, lease_duration = :lease_duration,
-	verification_time = :verification_time, subscription_failure = :subscription_failure WHERE feed_id = :feed_id 
+	verification_time = :verification_time, subscription_failure = :subscription_failure,
+	secret = :secret, topic = :topic, pending = :pending WHERE feed_id = :feed_id
+`
+)
+
+const (
+	getHubSubscribers = `
+SELECT topic, callback, COALESCE(secret, '') AS secret, lease_duration, verification_time
+FROM hub_subscribers WHERE topic = :topic`
+
+	createHubSubscriber = `
+INSERT INTO hub_subscribers(topic, callback, secret, lease_duration, verification_time)
+	SELECT :topic, :callback, :secret, :lease_duration, :verification_time EXCEPT
+	SELECT topic, callback, secret, lease_duration, verification_time
+		FROM hub_subscribers WHERE topic = :topic AND callback = :callback
+`
+	updateHubSubscriber = `
+UPDATE hub_subscribers SET secret = :secret, lease_duration = :lease_duration,
+	verification_time = :verification_time WHERE topic = :topic AND callback = :callback
 `
+	deleteHubSubscriber = `DELETE FROM hub_subscribers WHERE topic = :topic AND callback = :callback`
 )
@@ -7,15 +7,32 @@ func init() {
 	sqlStmts.Tag.AllForUser = getUserTags
 	sqlStmts.Tag.AllForFeed = getUserFeedTags
 	sqlStmts.Tag.Create = createTag
+	sqlStmts.Tag.Merge = mergeUserTags
+	sqlStmts.Tag.MergeOrder = mergeUserTagOrder
+	sqlStmts.Tag.DeleteForUser = deleteUserTag
+	sqlStmts.Tag.CreateOrder = createUserTagOrder
+	sqlStmts.Tag.DeleteOrder = deleteUserTagOrder
+	sqlStmts.Tag.DeleteStaleUserFeeds = deleteStaleUserFeedTags
+	sqlStmts.Tag.DeleteStaleOrder = deleteStaleUserTagOrder
+	sqlStmts.Tag.GetParents = getUserTagParents
+	sqlStmts.Tag.CreateParent = createUserTagParent
+	sqlStmts.Tag.DeleteParent = deleteUserTagParent
+	sqlStmts.Tag.DeleteFromHierarchy = deleteUserTagFromHierarchy
+	sqlStmts.Tag.MergeParent = mergeUserTagParent
+	sqlStmts.Tag.MergeChildren = mergeUserTagChildren
+	sqlStmts.Tag.DeleteStaleParents = deleteStaleUserTagParents
 	sqlStmts.Tag.DeleteStale = deleteStaleTags
 }
 
 const (
 	getUserTag = `
-SELECT t.value
-FROM tags t LEFT OUTER JOIN users_feeds_tags uft
-	ON t.id = uft.tag_id
-WHERE id = :id AND uft.user_login = :user_login
+SELECT t.value, COALESCE(utp.parent_id, 0) AS parent_id
+FROM tags t LEFT OUTER JOIN users_tags_parents utp
+	ON t.id = utp.tag_id AND utp.user_login = :user_login
+WHERE t.id = :id AND (
+	EXISTS (SELECT 1 FROM users_feeds_tags uft WHERE uft.tag_id = t.id AND uft.user_login = :user_login)
+	OR EXISTS (SELECT 1 FROM users_tags_parents utp2 WHERE utp2.parent_id = t.id AND utp2.user_login = :user_login)
+)
 `
 	getTagByValue   = `SELECT id FROM tags WHERE value = :value`
 	getUserFeedTags = `
@@ -24,15 +41,29 @@ FROM users_feeds_tags uft INNER JOIN tags t
 	ON uft.tag_id = t.id
 WHERE uft.user_login = :user_login AND uft.feed_id = :feed_id`
 	getUserTags = `
-SELECT DISTINCT t.id, t.value
-FROM tags t LEFT OUTER JOIN users_feeds_tags uft
-	ON t.id = uft.tag_id
-WHERE uft.user_login = :user_login
+SELECT t.id, t.value, COALESCE(utp.parent_id, 0) AS parent_id
+FROM tags t INNER JOIN (
+	SELECT tag_id FROM users_feeds_tags WHERE user_login = :user_login
+	UNION SELECT parent_id FROM users_tags_parents WHERE user_login = :user_login
+) ut
+	ON t.id = ut.tag_id
+LEFT OUTER JOIN users_tags_parents utp
+	ON t.id = utp.tag_id AND utp.user_login = :user_login
+LEFT OUTER JOIN users_tags_order uto
+	ON t.id = uto.tag_id AND uto.user_login = :user_login
+ORDER BY CASE WHEN uto.position IS NULL THEN 1 ELSE 0 END, uto.position, t.value
 `
 	getUserTagFeedIDs = `
-SELECT uft.feed_id
+WITH RECURSIVE tag_tree(id) AS (
+	SELECT CAST(:id AS INTEGER)
+	UNION
+	SELECT utp.tag_id FROM users_tags_parents utp INNER JOIN tag_tree tt
+		ON utp.parent_id = tt.id
+	WHERE utp.user_login = :user_login
+)
+SELECT DISTINCT uft.feed_id
 FROM users_feeds_tags uft
-WHERE uft.user_login = :user_login AND uft.tag_id = :id
+WHERE uft.user_login = :user_login AND uft.tag_id IN (SELECT id FROM tag_tree)
 `
 
 	createTag = `
@@ -40,5 +71,74 @@ INSERT INTO tags (value)
 	SELECT :value EXCEPT SELECT value FROM tags WHERE value = :value
 `
 
-	deleteStaleTags = `DELETE FROM tags WHERE id NOT IN (SELECT tag_id FROM users_feeds_tags)`
+	mergeUserTags = `
+INSERT INTO users_feeds_tags(user_login, feed_id, tag_id)
+SELECT uft.user_login, uft.feed_id, :to_id FROM users_feeds_tags uft
+WHERE uft.user_login = :user_login AND uft.tag_id = :from_id AND NOT EXISTS (
+	SELECT 1 FROM users_feeds_tags uft2
+	WHERE uft2.user_login = uft.user_login AND uft2.feed_id = uft.feed_id AND uft2.tag_id = :to_id
+)
+`
+	mergeUserTagOrder = `
+UPDATE users_tags_order SET tag_id = :to_id
+WHERE user_login = :user_login AND tag_id = :from_id AND NOT EXISTS (
+	SELECT 1 FROM users_tags_order uto
+	WHERE uto.user_login = :user_login AND uto.tag_id = :to_id
+)
+`
+	deleteUserTag = `DELETE FROM users_feeds_tags WHERE user_login = :user_login AND tag_id = :id`
+
+	createUserTagOrder = `
+INSERT INTO users_tags_order(user_login, tag_id, position)
+SELECT :user_login, :id, :position
+WHERE EXISTS (SELECT 1 FROM users_feeds_tags WHERE user_login = :user_login AND tag_id = :id)
+	OR EXISTS (SELECT 1 FROM users_tags_parents WHERE user_login = :user_login AND parent_id = :id)
+`
+	deleteUserTagOrder = `DELETE FROM users_tags_order WHERE user_login = :user_login`
+
+	deleteStaleUserFeedTags = `
+DELETE FROM users_feeds_tags WHERE NOT EXISTS (
+	SELECT 1 FROM users_feeds uf
+	WHERE uf.user_login = users_feeds_tags.user_login AND uf.feed_id = users_feeds_tags.feed_id
+)`
+	deleteStaleUserTagOrder = `
+DELETE FROM users_tags_order WHERE NOT EXISTS (
+	SELECT 1 FROM users_feeds_tags uft
+	WHERE uft.user_login = users_tags_order.user_login AND uft.tag_id = users_tags_order.tag_id
+) AND NOT EXISTS (
+	SELECT 1 FROM users_tags_parents utp
+	WHERE utp.user_login = users_tags_order.user_login AND utp.parent_id = users_tags_order.tag_id
+)`
+	deleteStaleTags = `
+DELETE FROM tags
+WHERE id NOT IN (SELECT tag_id FROM users_feeds_tags)
+	AND id NOT IN (SELECT parent_id FROM users_tags_parents)
+`
+
+	getUserTagParents   = `SELECT tag_id AS id, parent_id FROM users_tags_parents WHERE user_login = :user_login`
+	createUserTagParent = `
+INSERT INTO users_tags_parents(user_login, tag_id, parent_id)
+VALUES(:user_login, :id, :parent_id)
+`
+	deleteUserTagParent        = `DELETE FROM users_tags_parents WHERE user_login = :user_login AND tag_id = :id`
+	deleteUserTagFromHierarchy = `
+DELETE FROM users_tags_parents
+WHERE user_login = :user_login AND (tag_id = :id OR parent_id = :id)
+`
+	mergeUserTagParent = `
+UPDATE users_tags_parents SET tag_id = :to_id
+WHERE user_login = :user_login AND tag_id = :from_id
+`
+	mergeUserTagChildren = `
+UPDATE users_tags_parents SET parent_id = :to_id
+WHERE user_login = :user_login AND parent_id = :from_id AND tag_id != :to_id
+`
+	deleteStaleUserTagParents = `
+DELETE FROM users_tags_parents WHERE NOT EXISTS (
+	SELECT 1 FROM users_feeds_tags uft
+	WHERE uft.user_login = users_tags_parents.user_login AND uft.tag_id = users_tags_parents.tag_id
+) AND NOT EXISTS (
+	SELECT 1 FROM users_tags_parents utp
+	WHERE utp.user_login = users_tags_parents.user_login AND utp.parent_id = users_tags_parents.tag_id
+)`
 )
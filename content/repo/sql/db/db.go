@@ -16,7 +16,7 @@ type DB struct {
 }
 
 var (
-	dbVersion = 4
+	dbVersion = 17
 
 	helpers = make(map[string]Helper)
 )
@@ -15,6 +15,7 @@ type Helper interface {
 type ArticleStmts struct {
 	Create string
 	Update string
+	GetID  string
 
 	GetUserlessTemplate      string
 	GetTemplate              string
@@ -25,6 +26,13 @@ type ArticleStmts struct {
 	StateFavoriteJoin        string
 	GetIDsTemplate           string
 	DeleteStaleUnreadRecords string
+	GetStaleByDate           string
+	GetStaleByCount          string
+	Delete                   string
+	DeleteDependents         []string
+	GetPurged                string
+	CreatePurged             string
+	DeleteStalePurged        string
 	GetScoreJoin             string
 	GetUntaggedJoin          string
 
@@ -32,6 +40,22 @@ type ArticleStmts struct {
 	ReadStateDeleteTemplate     string
 	FavoriteStateInsertTemplate string
 	FavoriteStateDeleteTemplate string
+
+	GetEnclosuresTemplate string
+	CreateEnclosure       string
+	DeleteEnclosures      string
+
+	GetCategoriesTemplate string
+	CreateCategory        string
+	DeleteCategories      string
+
+	GetRevisions   string
+	CreateRevision string
+
+	GetDuplicateByLink       string
+	GetDuplicateFingerprints string
+	CreateDuplicate          string
+	ExcludeReadDuplicates    string
 }
 
 type ExtractStmts struct {
@@ -40,6 +64,17 @@ type ExtractStmts struct {
 	Update string
 }
 
+type FeedImageStmts struct {
+	Get         string
+	Create      string
+	Delete      string
+	GetFavicon  string
+	AllFavicons string
+
+	CreateFavicon string
+	UpdateFavicon string
+}
+
 type FeedStmts struct {
 	Get          string
 	GetByLink    string
@@ -59,6 +94,18 @@ type FeedStmts struct {
 	Detach         string
 	CreateUserTag  string
 	DeleteUserTags string
+
+	MergeUsers    string
+	MergeUserTags string
+	MergeArticles string
+	MergeUnread   string
+	MergeFavorite string
+}
+
+type FeedUpdateStmts struct {
+	Get         string
+	Create      string
+	DeleteStale string
 }
 
 type ScoresStmts struct {
@@ -73,6 +120,11 @@ type SubscriptionStmts struct {
 
 	Create string
 	Update string
+
+	GetSubscribers   string
+	CreateSubscriber string
+	UpdateSubscriber string
+	DeleteSubscriber string
 }
 
 type TagStmts struct {
@@ -83,6 +135,22 @@ type TagStmts struct {
 	Create         string
 	GetUserFeedIDs string
 	DeleteStale    string
+
+	Merge                string
+	MergeOrder           string
+	DeleteForUser        string
+	CreateOrder          string
+	DeleteOrder          string
+	DeleteStaleUserFeeds string
+	DeleteStaleOrder     string
+
+	GetParents          string
+	CreateParent        string
+	DeleteParent        string
+	DeleteFromHierarchy string
+	MergeParent         string
+	MergeChildren       string
+	DeleteStaleParents  string
 }
 
 type ThumbnailStmts struct {
@@ -105,6 +173,8 @@ type SqlStmts struct {
 	Article      ArticleStmts
 	Extract      ExtractStmts
 	Feed         FeedStmts
+	FeedImage    FeedImageStmts
+	FeedUpdate   FeedUpdateStmts
 	Scores       ScoresStmts
 	Subscription SubscriptionStmts
 	Tag          TagStmts
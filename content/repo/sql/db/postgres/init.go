@@ -26,7 +26,13 @@ CREATE TABLE IF NOT EXISTS feeds (
 	hub_link TEXT,
 	site_link TEXT,
 	update_error TEXT,
-	subscribe_error TEXT
+	subscribe_error TEXT,
+	etag TEXT,
+	last_modified TEXT,
+	dead BOOLEAN NOT NULL DEFAULT 'f',
+	credentials BYTEA,
+	scrape_rules TEXT,
+	diagnostic TEXT
 )`, `
 CREATE TABLE IF NOT EXISTS feed_images (
 	id SERIAL PRIMARY KEY,
@@ -38,6 +44,28 @@ CREATE TABLE IF NOT EXISTS feed_images (
 
 	FOREIGN KEY(feed_id) REFERENCES feeds(id) ON DELETE CASCADE
 )`, `
+CREATE TABLE IF NOT EXISTS feed_favicons (
+	feed_id INTEGER,
+	link TEXT,
+	data TEXT NOT NULL DEFAULT '',
+	update_date TIMESTAMP WITH TIME ZONE,
+
+	PRIMARY KEY(feed_id),
+	FOREIGN KEY(feed_id) REFERENCES feeds(id) ON DELETE CASCADE
+)`, `
+CREATE TABLE IF NOT EXISTS feed_updates (
+	id BIGSERIAL PRIMARY KEY,
+	feed_id INTEGER NOT NULL,
+	update_date TIMESTAMP WITH TIME ZONE NOT NULL,
+	duration BIGINT NOT NULL DEFAULT 0,
+	status INTEGER NOT NULL DEFAULT 0,
+	bytes BIGINT NOT NULL DEFAULT 0,
+	new_articles INTEGER NOT NULL DEFAULT 0,
+	updated_articles INTEGER NOT NULL DEFAULT 0,
+	error TEXT NOT NULL DEFAULT '',
+
+	FOREIGN KEY(feed_id) REFERENCES feeds(id) ON DELETE CASCADE
+)`, `
 CREATE TABLE IF NOT EXISTS articles (
 	id BIGSERIAL PRIMARY KEY,
 	feed_id INTEGER,
@@ -45,7 +73,12 @@ CREATE TABLE IF NOT EXISTS articles (
 	guid TEXT,
 	title TEXT,
 	description TEXT,
+	summary TEXT,
+	author TEXT,
 	date TIMESTAMP WITH TIME ZONE,
+	date_inferred BOOLEAN NOT NULL DEFAULT 'f',
+	content_hash TEXT NOT NULL DEFAULT '',
+	update_date TIMESTAMP WITH TIME ZONE,
 
 	UNIQUE(feed_id, link),
 	UNIQUE(feed_id, guid),
@@ -73,6 +106,25 @@ CREATE TABLE IF NOT EXISTS users_feeds_tags (
 	FOREIGN KEY(feed_id) REFERENCES feeds(id) ON DELETE CASCADE,
 	FOREIGN KEY(tag_id) REFERENCES tags(id) ON DELETE CASCADE
 )`, `
+CREATE TABLE IF NOT EXISTS users_tags_parents (
+	user_login TEXT,
+	tag_id INTEGER,
+	parent_id INTEGER NOT NULL,
+
+	PRIMARY KEY(user_login, tag_id),
+	FOREIGN KEY(user_login) REFERENCES users(login) ON DELETE CASCADE,
+	FOREIGN KEY(tag_id) REFERENCES tags(id) ON DELETE CASCADE,
+	FOREIGN KEY(parent_id) REFERENCES tags(id) ON DELETE CASCADE
+)`, `
+CREATE TABLE IF NOT EXISTS users_tags_order (
+	user_login TEXT,
+	tag_id INTEGER,
+	position INTEGER NOT NULL,
+
+	PRIMARY KEY(user_login, tag_id),
+	FOREIGN KEY(user_login) REFERENCES users(login) ON DELETE CASCADE,
+	FOREIGN KEY(tag_id) REFERENCES tags(id) ON DELETE CASCADE
+)`, `
 CREATE TABLE IF NOT EXISTS users_articles_unread (
 	user_login TEXT,
 	article_id BIGINT,
@@ -121,16 +173,72 @@ CREATE TABLE IF NOT EXISTS articles_extracts (
 	PRIMARY KEY(article_id),
 	FOREIGN KEY(article_id) REFERENCES articles(id) ON DELETE CASCADE
 )`, `
+CREATE TABLE IF NOT EXISTS articles_revisions (
+	id BIGSERIAL PRIMARY KEY,
+	article_id BIGINT NOT NULL,
+	revision_date TIMESTAMP WITH TIME ZONE NOT NULL,
+	title TEXT,
+	description TEXT,
+	summary TEXT,
+
+	FOREIGN KEY(article_id) REFERENCES articles(id) ON DELETE CASCADE
+)`, `
+CREATE TABLE IF NOT EXISTS articles_duplicates (
+	article_id BIGINT PRIMARY KEY,
+	canonical_id BIGINT NOT NULL,
+	canonical_link TEXT NOT NULL DEFAULT '',
+	fingerprint BIGINT NOT NULL DEFAULT 0,
+
+	FOREIGN KEY(article_id) REFERENCES articles(id) ON DELETE CASCADE
+)`, `
+CREATE TABLE IF NOT EXISTS articles_purged (
+	feed_id INTEGER,
+	link TEXT,
+	guid TEXT,
+	date TIMESTAMP WITH TIME ZONE,
+
+	PRIMARY KEY(feed_id, link),
+	FOREIGN KEY(feed_id) REFERENCES feeds(id) ON DELETE CASCADE
+)`, `
+CREATE TABLE IF NOT EXISTS articles_enclosures (
+	article_id BIGINT,
+	url TEXT,
+	mime_type TEXT,
+	length BIGINT,
+	duration BIGINT,
+
+	PRIMARY KEY(article_id, url),
+	FOREIGN KEY(article_id) REFERENCES articles(id) ON DELETE CASCADE
+)`, `
+CREATE TABLE IF NOT EXISTS articles_categories (
+	article_id BIGINT,
+	category TEXT,
+
+	PRIMARY KEY(article_id, category),
+	FOREIGN KEY(article_id) REFERENCES articles(id) ON DELETE CASCADE
+)`, `
 CREATE TABLE IF NOT EXISTS hubbub_subscriptions (
 	feed_id INTEGER,
 	link TEXT,
 	lease_duration BIGINT,
 	verification_time TIMESTAMP WITH TIME ZONE,
 	subscription_failure BOOLEAN DEFAULT 'f',
+	secret TEXT,
+	topic TEXT,
+	pending TEXT,
 
 	PRIMARY KEY(feed_id),
 	FOREIGN KEY(feed_id) REFERENCES feeds(id) ON DELETE CASCADE
 )`, `
+CREATE TABLE IF NOT EXISTS hub_subscribers (
+	topic TEXT,
+	callback TEXT,
+	secret TEXT,
+	lease_duration BIGINT,
+	verification_time TIMESTAMP WITH TIME ZONE,
+
+	PRIMARY KEY(topic, callback)
+)`, `
 CREATE INDEX IF NOT EXISTS articles_feed_id_idx ON articles (feed_id);
 `, `
 CREATE INDEX IF NOT EXISTS articles_title_idx ON articles (LOWER(title));
@@ -138,6 +246,24 @@ CREATE INDEX IF NOT EXISTS articles_title_idx ON articles (LOWER(title));
 CREATE INDEX IF NOT EXISTS articles_link_idx ON articles (LOWER(link));
 `, `
 CREATE INDEX IF NOT EXISTS articles_date_idx ON articles (date);
+`, `
+CREATE INDEX IF NOT EXISTS articles_categories_category_idx ON articles_categories (LOWER(category));
+`, `
+CREATE INDEX IF NOT EXISTS feed_updates_feed_id_date_idx ON feed_updates (feed_id, update_date);
+`, `
+CREATE INDEX IF NOT EXISTS articles_revisions_article_id_idx ON articles_revisions (article_id);
+`, `
+CREATE INDEX IF NOT EXISTS articles_duplicates_canonical_id_idx ON articles_duplicates (canonical_id);
+`, `
+CREATE INDEX IF NOT EXISTS articles_duplicates_canonical_link_idx ON articles_duplicates (canonical_link);
+`, `
+CREATE INDEX IF NOT EXISTS articles_duplicates_band0_idx ON articles_duplicates ((fingerprint & 65535));
+`, `
+CREATE INDEX IF NOT EXISTS articles_duplicates_band1_idx ON articles_duplicates (((fingerprint >> 16) & 65535));
+`, `
+CREATE INDEX IF NOT EXISTS articles_duplicates_band2_idx ON articles_duplicates (((fingerprint >> 32) & 65535));
+`, `
+CREATE INDEX IF NOT EXISTS articles_duplicates_band3_idx ON articles_duplicates (((fingerprint >> 48) & 65535));
 `,
 	}
 )
@@ -28,6 +28,32 @@ func (h Helper) Upgrade(db *db.DB, old, new int) error {
 			err = upgrade2to3(db)
 		case 3:
 			err = upgrade3to4(db)
+		case 4:
+			err = upgrade4to5(db)
+		case 5:
+			err = upgrade5to6(db)
+		case 6:
+			err = upgrade6to7(db)
+		case 7:
+			err = upgrade7to8(db)
+		case 8:
+			err = upgrade8to9(db)
+		case 9:
+			err = upgrade9to10(db)
+		case 10:
+			err = upgrade10to11(db)
+		case 11:
+			err = upgrade11to12(db)
+		case 12:
+			err = upgrade12to13(db)
+		case 13:
+			err = upgrade13to14(db)
+		case 14:
+			err = upgrade14to15(db)
+		case 15:
+			err = upgrade15to16(db)
+		case 16:
+			err = upgrade16to17(db)
 		}
 
 		if err != nil {
@@ -138,11 +164,216 @@ func upgrade3to4(db *db.DB) error {
 	return tx.Commit()
 }
 
+func upgrade4to5(db *db.DB) error {
+	tx, err := db.Beginx()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	_, err = tx.Exec(upgrade4To5ArticleAuthor)
+	if err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+func upgrade5to6(db *db.DB) error {
+	tx, err := db.Beginx()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	_, err = tx.Exec(upgrade5To6ArticleSummary)
+	if err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+func upgrade6to7(db *db.DB) error {
+	tx, err := db.Beginx()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	_, err = tx.Exec(upgrade6To7FeedETag)
+	if err != nil {
+		return err
+	}
+
+	_, err = tx.Exec(upgrade6To7FeedLastModified)
+	if err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+func upgrade7to8(db *db.DB) error {
+	tx, err := db.Beginx()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	_, err = tx.Exec(upgrade7To8FeedDead)
+	if err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+func upgrade8to9(db *db.DB) error {
+	tx, err := db.Beginx()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	_, err = tx.Exec(upgrade8To9FeedCredentials)
+	if err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+func upgrade9to10(db *db.DB) error {
+	tx, err := db.Beginx()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	_, err = tx.Exec(upgrade9To10SubscriptionSecret)
+	if err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+func upgrade10to11(db *db.DB) error {
+	tx, err := db.Beginx()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	_, err = tx.Exec(upgrade10To11SubscriptionTopic)
+	if err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+func upgrade11to12(db *db.DB) error {
+	tx, err := db.Beginx()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	_, err = tx.Exec(upgrade11To12HubSubscribers)
+	if err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+func upgrade12to13(db *db.DB) error {
+	tx, err := db.Beginx()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	_, err = tx.Exec(upgrade12To13FeedScrapeRules)
+	if err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+func upgrade13to14(db *db.DB) error {
+	tx, err := db.Beginx()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	_, err = tx.Exec(upgrade13To14FeedDiagnostic)
+	if err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+func upgrade14to15(db *db.DB) error {
+	tx, err := db.Beginx()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	_, err = tx.Exec(upgrade14To15ArticleDateInferred)
+	if err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+func upgrade15to16(db *db.DB) error {
+	tx, err := db.Beginx()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	_, err = tx.Exec(upgrade15To16ArticleContentHash)
+	if err != nil {
+		return err
+	}
+
+	_, err = tx.Exec(upgrade15To16ArticleUpdateDate)
+	if err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+func upgrade16to17(db *db.DB) error {
+	tx, err := db.Beginx()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	_, err = tx.Exec(upgrade16To17SubscriptionPending)
+	if err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
 func init() {
 	helper := &Helper{Helper: base.NewHelper()}
 
 	helper.Set(db.SqlStmts{
-		Article: db.ArticleStmts{Create: createFeedArticle},
+		Article: db.ArticleStmts{Create: createFeedArticle, CreateRevision: createArticleRevision},
 		Feed:    db.FeedStmts{AllForUser: getUserFeeds},
 	})
 
@@ -152,13 +383,22 @@ func init() {
 const (
 	// Casting to timestamp produces only the year
 	createFeedArticle = `
-INSERT INTO articles(feed_id, link, guid, title, description, date)
-	SELECT :feed_id, :link, :guid, :title, :description, :date EXCEPT
-	SELECT feed_id, link, :guid, :title, :description, :date 
+INSERT INTO articles(feed_id, link, guid, title, description, summary, author, date, date_inferred, content_hash)
+	SELECT :feed_id, :link, :guid, :title, :description, :summary, :author, :date, :date_inferred, :content_hash EXCEPT
+	SELECT feed_id, link, :guid, :title, :description, :summary, :author, :date, :date_inferred, :content_hash 
 		FROM articles WHERE feed_id = :feed_id AND link = :link 
+`
+	createArticleRevision = `
+INSERT INTO articles_revisions(article_id, revision_date, title, description, summary)
+	SELECT id, :update_date, title, description, summary
+	FROM articles
+	WHERE feed_id = :feed_id AND (guid = :guid OR link = :link)
+		AND content_hash <> '' AND content_hash <> :content_hash
 `
 	getUserFeeds = `
-SELECT f.id, f.link, f.title, f.description, f.link, f.hub_link, f.site_link, f.update_error, f.subscribe_error
+SELECT f.id, f.link, f.title, f.description, f.link, f.hub_link, f.site_link, f.update_error, f.subscribe_error,
+	COALESCE(f.etag, '') AS etag, COALESCE(f.last_modified, '') AS last_modified, f.dead, f.credentials,
+	COALESCE(f.scrape_rules, '') AS scrape_rules, COALESCE(f.diagnostic, '') AS diagnostic
 FROM feeds f, users_feeds uf
 WHERE f.id = uf.feed_id
 	AND uf.user_login = :user_login
@@ -203,4 +443,40 @@ SELECT uft.user_login, uft.feed_id, t.id
 FROM tags t INNER JOIN users_feeds_tags2 uft
 	ON t.value = uft.tag
 `
+
+	upgrade4To5ArticleAuthor  = `ALTER TABLE articles ADD COLUMN author TEXT`
+	upgrade5To6ArticleSummary = `ALTER TABLE articles ADD COLUMN summary TEXT`
+
+	upgrade6To7FeedETag         = `ALTER TABLE feeds ADD COLUMN etag TEXT`
+	upgrade6To7FeedLastModified = `ALTER TABLE feeds ADD COLUMN last_modified TEXT`
+
+	upgrade7To8FeedDead = `ALTER TABLE feeds ADD COLUMN dead INTEGER NOT NULL DEFAULT 0`
+
+	upgrade8To9FeedCredentials = `ALTER TABLE feeds ADD COLUMN credentials BLOB`
+
+	upgrade9To10SubscriptionSecret = `ALTER TABLE hubbub_subscriptions ADD COLUMN secret TEXT`
+
+	upgrade10To11SubscriptionTopic = `ALTER TABLE hubbub_subscriptions ADD COLUMN topic TEXT`
+
+	upgrade11To12HubSubscribers = `
+CREATE TABLE IF NOT EXISTS hub_subscribers (
+	topic TEXT,
+	callback TEXT,
+	secret TEXT,
+	lease_duration INTEGER,
+	verification_time TIMESTAMP,
+
+	PRIMARY KEY(topic, callback)
+)`
+
+	upgrade12To13FeedScrapeRules = `ALTER TABLE feeds ADD COLUMN scrape_rules TEXT`
+
+	upgrade13To14FeedDiagnostic = `ALTER TABLE feeds ADD COLUMN diagnostic TEXT`
+
+	upgrade14To15ArticleDateInferred = `ALTER TABLE articles ADD COLUMN date_inferred INTEGER NOT NULL DEFAULT 0`
+
+	upgrade15To16ArticleContentHash = `ALTER TABLE articles ADD COLUMN content_hash TEXT NOT NULL DEFAULT ''`
+	upgrade15To16ArticleUpdateDate  = `ALTER TABLE articles ADD COLUMN update_date TIMESTAMP`
+
+	upgrade16To17SubscriptionPending = `ALTER TABLE hubbub_subscriptions ADD COLUMN pending TEXT`
 )
@@ -145,7 +145,8 @@ func (r feedRepo) Unsubscribed() ([]content.Feed, error) {
 }
 
 // Update updates or creates the feed data in the database.
-// It returns a list of all new articles, or an error.
+// It returns a list of all new articles, along with the existing ones whose
+// content has changed, or an error. The latter are marked as updated.
 func (r feedRepo) Update(feed *content.Feed) ([]content.Article, error) {
 	newArticles := []content.Article{}
 
@@ -187,6 +188,17 @@ func (r feedRepo) Update(feed *content.Feed) ([]content.Article, error) {
 			feed.ID = content.FeedID(id)
 		}
 
+		if feed.Image.URL != "" {
+			feed.Image.FeedID = feed.ID
+			if err = feed.Image.Validate(); err == nil {
+				if err = updateFeedImage(feed.Image, tx, r.db); err != nil {
+					return errors.WithMessage(err, "updating feed image")
+				}
+			} else {
+				r.log.Infof("Skipping invalid image of feed %s: %v", feed, err)
+			}
+		}
+
 		if newArticles, err = r.updateFeedArticles(*feed, tx); err != nil {
 			return errors.WithMessage(err, "updating feed articles")
 		}
@@ -196,7 +208,7 @@ func (r feedRepo) Update(feed *content.Feed) ([]content.Article, error) {
 		return newArticles, err
 	}
 
-	r.log.Debugf("Feed %s new articles: %d", feed, len(newArticles))
+	r.log.Debugf("Feed %s new and updated articles: %d", feed, len(newArticles))
 
 	return newArticles, nil
 }
@@ -209,11 +221,66 @@ func (r feedRepo) Delete(feed content.Feed) error {
 
 	r.log.Infof("Deleting feed %s", feed)
 
-	return r.db.WithNamedTx(r.db.SQL().Feed.Delete, func(stmt *sqlx.NamedStmt) error {
-		if _, err := stmt.Exec(feed); err != nil {
-			return errors.Wrap(err, "executing feed delete stmt")
+	return r.db.WithTx(func(tx *sqlx.Tx) error {
+		if err := r.db.WithNamedStmt(r.db.SQL().Feed.Delete, tx, func(stmt *sqlx.NamedStmt) error {
+			if _, err := stmt.Exec(feed); err != nil {
+				return errors.Wrap(err, "executing feed delete stmt")
+			}
+			return nil
+		}); err != nil {
+			return err
 		}
-		return nil
+
+		return deleteStaleTags(tx, r.db)
+	})
+}
+
+type feedMerge struct {
+	FromID content.FeedID `db:"from_id"`
+	ToID   content.FeedID `db:"to_id"`
+}
+
+// Merge moves the subscribers, tags and articles of the first feed to the
+// second one, and deletes the first feed. Articles that already exist in
+// the second feed are dropped along with the first one, after their unread
+// and favorite states are copied to their matches.
+func (r feedRepo) Merge(from, to content.Feed) error {
+	if err := from.Validate(); err != nil {
+		return errors.WithMessage(err, "validating source feed")
+	}
+
+	if err := to.Validate(); err != nil {
+		return errors.WithMessage(err, "validating target feed")
+	}
+
+	if from.ID == to.ID {
+		return errors.Errorf("cannot merge feed %s with itself", from)
+	}
+
+	r.log.Infof("Merging feed %s into %s", from, to)
+
+	s := r.db.SQL()
+	args := feedMerge{FromID: from.ID, ToID: to.ID}
+
+	return r.db.WithTx(func(tx *sqlx.Tx) error {
+		for _, query := range []string{
+			s.Feed.MergeUsers, s.Feed.MergeUserTags,
+			s.Feed.MergeUnread, s.Feed.MergeFavorite, s.Feed.MergeArticles,
+		} {
+			if err := r.db.WithNamedStmt(query, tx, func(stmt *sqlx.NamedStmt) error {
+				_, err := stmt.Exec(args)
+				return err
+			}); err != nil {
+				return errors.Wrapf(err, "merging feed %s into %s", from, to)
+			}
+		}
+
+		return r.db.WithNamedStmt(s.Feed.Delete, tx, func(stmt *sqlx.NamedStmt) error {
+			if _, err := stmt.Exec(from); err != nil {
+				return errors.Wrap(err, "executing feed delete stmt")
+			}
+			return nil
+		})
 	})
 }
 
@@ -266,14 +333,16 @@ func (r feedRepo) DetachFrom(feed content.Feed, user content.User) error {
 
 	r.log.Infof("Detaching feed %s from %s", feed, user)
 
-	if err := r.db.WithNamedTx(r.db.SQL().Feed.Detach, func(stmt *sqlx.NamedStmt) error {
-		_, err := stmt.Exec(feedQuery{UserLogin: user.Login, ID: feed.ID})
-		return err
-	}); err != nil {
-		return errors.Wrap(err, "executing feed detach stmt")
-	}
+	return r.db.WithTx(func(tx *sqlx.Tx) error {
+		if err := r.db.WithNamedStmt(r.db.SQL().Feed.Detach, tx, func(stmt *sqlx.NamedStmt) error {
+			_, err := stmt.Exec(feedQuery{UserLogin: user.Login, ID: feed.ID})
+			return err
+		}); err != nil {
+			return errors.Wrap(err, "executing feed detach stmt")
+		}
 
-	return nil
+		return deleteStaleTags(tx, r.db)
+	})
 }
 
 type userFeedTag struct {
@@ -352,25 +421,26 @@ func (r feedRepo) SetUserTags(feed content.Feed, user content.User, tags []*cont
 			}
 		}
 
-		return r.db.WithStmt(s.Tag.DeleteStale, tx, func(stmt *sqlx.Stmt) error {
-			_, err := stmt.Exec()
-			return err
-		})
+		return deleteStaleTags(tx, r.db)
 	})
 }
 
 func (r feedRepo) updateFeedArticles(feed content.Feed, tx *sqlx.Tx) ([]content.Article, error) {
 	articles := []content.Article{}
 
+	purged, err := getPurgedArticles(feed.ID, tx, r.db)
+	if err != nil {
+		return []content.Article{}, errors.WithMessage(err, "updating feed articles")
+	}
+
 	for _, a := range feed.ParsedArticles() {
 		a.FeedID = feed.ID
 
-		var err error
-		if a, err = updateArticle(a, tx, r.db, r.log); err != nil {
+		if a, err = updateArticle(a, purged, tx, r.db, r.log); err != nil {
 			return []content.Article{}, errors.Wrap(err, "updating feed articles")
 		}
 
-		if a.IsNew {
+		if a.IsNew || a.Updated {
 			articles = append(articles, a)
 		}
 	}
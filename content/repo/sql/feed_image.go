@@ -0,0 +1,138 @@
+package sql
+
+import (
+	"database/sql"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/pkg/errors"
+	"github.com/urandom/readeef/content"
+	"github.com/urandom/readeef/content/repo/sql/db"
+	"github.com/urandom/readeef/log"
+)
+
+type feedImageRepo struct {
+	db *db.DB
+
+	log log.Log
+}
+
+func (r feedImageRepo) Get(feed content.Feed) (content.FeedImage, error) {
+	if err := feed.Validate(); err != nil {
+		return content.FeedImage{}, errors.WithMessage(err, "validating feed")
+	}
+
+	r.log.Infof("Getting image for feed %s", feed)
+
+	image := content.FeedImage{FeedID: feed.ID}
+	if err := r.db.WithNamedStmt(r.db.SQL().FeedImage.Get, nil, func(stmt *sqlx.NamedStmt) error {
+		return stmt.Get(&image, image)
+	}); err != nil {
+		if err == sql.ErrNoRows {
+			err = content.ErrNoContent
+		}
+
+		return content.FeedImage{}, errors.Wrapf(err, "getting image for feed %s", feed)
+	}
+
+	return image, nil
+}
+
+func (r feedImageRepo) Update(image content.FeedImage) error {
+	if err := image.Validate(); err != nil {
+		return errors.WithMessage(err, "validating feed image")
+	}
+
+	r.log.Infof("Updating feed image %s", image)
+
+	return r.db.WithTx(func(tx *sqlx.Tx) error {
+		return updateFeedImage(image, tx, r.db)
+	})
+}
+
+func (r feedImageRepo) Favicon(feed content.Feed) (content.Favicon, error) {
+	if err := feed.Validate(); err != nil {
+		return content.Favicon{}, errors.WithMessage(err, "validating feed")
+	}
+
+	r.log.Infof("Getting favicon for feed %s", feed)
+
+	favicon := content.Favicon{FeedID: feed.ID}
+	if err := r.db.WithNamedStmt(r.db.SQL().FeedImage.GetFavicon, nil, func(stmt *sqlx.NamedStmt) error {
+		return stmt.Get(&favicon, favicon)
+	}); err != nil {
+		if err == sql.ErrNoRows {
+			err = content.ErrNoContent
+		}
+
+		return content.Favicon{}, errors.Wrapf(err, "getting favicon for feed %s", feed)
+	}
+
+	return favicon, nil
+}
+
+func (r feedImageRepo) Favicons(user content.User) ([]content.Favicon, error) {
+	if err := user.Validate(); err != nil {
+		return []content.Favicon{}, errors.WithMessage(err, "validating user")
+	}
+
+	r.log.Infof("Getting user %s feed favicons", user)
+
+	var favicons []content.Favicon
+	if err := r.db.WithNamedStmt(r.db.SQL().FeedImage.AllFavicons, nil, func(stmt *sqlx.NamedStmt) error {
+		return stmt.Select(&favicons, feedQuery{UserLogin: user.Login})
+	}); err != nil {
+		return []content.Favicon{}, errors.Wrapf(err, "getting user %s feed favicons", user)
+	}
+
+	return favicons, nil
+}
+
+func (r feedImageRepo) UpdateFavicon(favicon content.Favicon) error {
+	if err := favicon.Validate(); err != nil {
+		return errors.WithMessage(err, "validating favicon")
+	}
+
+	r.log.Infof("Updating favicon %s", favicon)
+
+	return r.db.WithTx(func(tx *sqlx.Tx) error {
+		s := r.db.SQL()
+		return r.db.WithNamedStmt(s.FeedImage.UpdateFavicon, tx, func(stmt *sqlx.NamedStmt) error {
+			res, err := stmt.Exec(favicon)
+			if err != nil {
+				return errors.Wrap(err, "executing favicon update stmt")
+			}
+
+			if num, err := res.RowsAffected(); err == nil && num > 0 {
+				return nil
+			}
+
+			return r.db.WithNamedStmt(s.FeedImage.CreateFavicon, tx, func(stmt *sqlx.NamedStmt) error {
+				if _, err := stmt.Exec(favicon); err != nil {
+					return errors.Wrap(err, "executing favicon create stmt")
+				}
+
+				return nil
+			})
+		})
+	})
+}
+
+// updateFeedImage replaces the stored logo of the feed.
+func updateFeedImage(image content.FeedImage, tx *sqlx.Tx, db *db.DB) error {
+	s := db.SQL()
+
+	if err := db.WithNamedStmt(s.FeedImage.Delete, tx, func(stmt *sqlx.NamedStmt) error {
+		_, err := stmt.Exec(image)
+		return err
+	}); err != nil {
+		return errors.Wrapf(err, "deleting feed image %s", image)
+	}
+
+	return db.WithNamedStmt(s.FeedImage.Create, tx, func(stmt *sqlx.NamedStmt) error {
+		if _, err := stmt.Exec(image); err != nil {
+			return errors.Wrapf(err, "creating feed image %s", image)
+		}
+
+		return nil
+	})
+}
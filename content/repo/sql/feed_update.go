@@ -0,0 +1,69 @@
+package sql
+
+import (
+	"time"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/pkg/errors"
+	"github.com/urandom/readeef/content"
+	"github.com/urandom/readeef/content/repo/sql/db"
+	"github.com/urandom/readeef/log"
+)
+
+type feedUpdateRepo struct {
+	db *db.DB
+
+	log log.Log
+}
+
+type feedUpdateQuery struct {
+	FeedID content.FeedID `db:"feed_id"`
+	Limit  int            `db:"limit"`
+	Before time.Time      `db:"before"`
+}
+
+func (r feedUpdateRepo) ForFeed(feed content.Feed, limit int) ([]content.FeedUpdate, error) {
+	if err := feed.Validate(); err != nil {
+		return []content.FeedUpdate{}, errors.WithMessage(err, "validating feed")
+	}
+
+	r.log.Infof("Getting update history for feed %s", feed)
+
+	var updates []content.FeedUpdate
+	if err := r.db.WithNamedStmt(r.db.SQL().FeedUpdate.Get, nil, func(stmt *sqlx.NamedStmt) error {
+		return stmt.Select(&updates, feedUpdateQuery{FeedID: feed.ID, Limit: limit})
+	}); err != nil {
+		return []content.FeedUpdate{}, errors.Wrapf(err, "getting update history for feed %s", feed)
+	}
+
+	return updates, nil
+}
+
+func (r feedUpdateRepo) Create(update content.FeedUpdate) error {
+	if err := update.Validate(); err != nil {
+		return errors.WithMessage(err, "validating feed update")
+	}
+
+	r.log.Infof("Creating feed update %s", update)
+
+	return r.db.WithNamedTx(r.db.SQL().FeedUpdate.Create, func(stmt *sqlx.NamedStmt) error {
+		if _, err := stmt.Exec(update); err != nil {
+			return errors.Wrapf(err, "creating feed update %s", update)
+		}
+
+		return nil
+	})
+}
+
+func (r feedUpdateRepo) DeleteStale(before time.Time) error {
+	r.log.Infof("Removing feed updates older than %s", before)
+
+	if err := r.db.WithNamedTx(r.db.SQL().FeedUpdate.DeleteStale, func(stmt *sqlx.NamedStmt) error {
+		_, err := stmt.Exec(feedUpdateQuery{Before: before})
+		return err
+	}); err != nil {
+		return errors.Wrap(err, "removing stale feed updates")
+	}
+
+	return nil
+}
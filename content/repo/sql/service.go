@@ -13,8 +13,11 @@ type Service struct {
 	user         repo.User
 	tag          repo.Tag
 	feed         repo.Feed
+	feedImage    repo.FeedImage
+	feedUpdate   repo.FeedUpdate
 	subscription repo.Subscription
 	article      repo.Article
+	revision     repo.ArticleRevision
 	extract      repo.Extract
 	scores       repo.Scores
 	thumbnail    repo.Thumbnail
@@ -32,8 +35,11 @@ func NewService(driver, source string, log log.Log) (Service, error) {
 			user:         userRepo{db, log},
 			tag:          tagRepo{db, log},
 			feed:         feedRepo{db, log},
+			feedImage:    feedImageRepo{db, log},
+			feedUpdate:   feedUpdateRepo{db, log},
 			subscription: subscriptionRepo{db, log},
 			article:      articleRepo{db, log},
+			revision:     articleRevisionRepo{db, log},
 			extract:      extractRepo{db, log},
 			scores:       scoresRepo{db, log},
 			thumbnail:    thumbnailRepo{db, log},
@@ -55,6 +61,14 @@ func (s Service) FeedRepo() repo.Feed {
 	return s.feed
 }
 
+func (s Service) FeedImageRepo() repo.FeedImage {
+	return s.feedImage
+}
+
+func (s Service) FeedUpdateRepo() repo.FeedUpdate {
+	return s.feedUpdate
+}
+
 func (s Service) SubscriptionRepo() repo.Subscription {
 	return s.subscription
 }
@@ -63,6 +77,10 @@ func (s Service) ArticleRepo() repo.Article {
 	return s.article
 }
 
+func (s Service) ArticleRevisionRepo() repo.ArticleRevision {
+	return s.revision
+}
+
 func (s Service) ExtractRepo() repo.Extract {
 	return s.extract
 }
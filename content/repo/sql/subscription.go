@@ -81,3 +81,63 @@ func (r subscriptionRepo) Update(subscription content.Subscription) error {
 		return nil
 	})
 }
+
+func (r subscriptionRepo) Subscribers(topic string) ([]content.Subscriber, error) {
+	r.log.Infof("Getting subscribers of %s", topic)
+
+	var subscribers []content.Subscriber
+	if err := r.db.WithNamedStmt(r.db.SQL().Subscription.GetSubscribers, nil, func(stmt *sqlx.NamedStmt) error {
+		return stmt.Select(&subscribers, content.Subscriber{Topic: topic})
+	}); err != nil {
+		return []content.Subscriber{}, errors.Wrapf(err, "getting subscribers of %s", topic)
+	}
+
+	return subscribers, nil
+}
+
+func (r subscriptionRepo) UpdateSubscriber(subscriber content.Subscriber) error {
+	if err := subscriber.Validate(); err != nil {
+		return errors.WithMessage(err, "validating subscriber")
+	}
+
+	r.log.Infof("Updating subscriber %s", subscriber)
+
+	return r.db.WithTx(func(tx *sqlx.Tx) error {
+		s := r.db.SQL()
+
+		return r.db.WithNamedStmt(s.Subscription.UpdateSubscriber, tx, func(stmt *sqlx.NamedStmt) error {
+			res, err := stmt.Exec(subscriber)
+			if err != nil {
+				return errors.Wrap(err, "executing subscriber update stmt")
+			}
+
+			if num, err := res.RowsAffected(); err == nil && num > 0 {
+				return nil
+			}
+
+			return r.db.WithNamedStmt(s.Subscription.CreateSubscriber, tx, func(stmt *sqlx.NamedStmt) error {
+				if _, err := stmt.Exec(subscriber); err != nil {
+					return errors.Wrap(err, "executing subscriber create stmt")
+				}
+
+				return nil
+			})
+		})
+	})
+}
+
+func (r subscriptionRepo) DeleteSubscriber(subscriber content.Subscriber) error {
+	if err := subscriber.Validate(); err != nil {
+		return errors.WithMessage(err, "validating subscriber")
+	}
+
+	r.log.Infof("Deleting subscriber %s", subscriber)
+
+	return r.db.WithNamedTx(r.db.SQL().Subscription.DeleteSubscriber, func(stmt *sqlx.NamedStmt) error {
+		if _, err := stmt.Exec(subscriber); err != nil {
+			return errors.Wrap(err, "executing subscriber delete stmt")
+		}
+
+		return nil
+	})
+}
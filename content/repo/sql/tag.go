@@ -21,6 +21,19 @@ type tagQuery struct {
 	Value     content.TagValue `db:"value"`
 	UserLogin content.Login    `db:"user_login"`
 	FeedID    content.FeedID   `db:"feed_id"`
+	ParentID  content.TagID    `db:"parent_id"`
+}
+
+type tagMerge struct {
+	FromID    content.TagID `db:"from_id"`
+	ToID      content.TagID `db:"to_id"`
+	UserLogin content.Login `db:"user_login"`
+}
+
+type tagOrder struct {
+	ID        content.TagID `db:"id"`
+	UserLogin content.Login `db:"user_login"`
+	Position  int           `db:"position"`
 }
 
 func (r tagRepo) Get(id content.TagID, user content.User) (content.Tag, error) {
@@ -82,6 +95,8 @@ func (r tagRepo) ForFeed(feed content.Feed, user content.User) ([]content.Tag, e
 	return tags, nil
 }
 
+// FeedIDs returns the ids of the user's feeds with the given tag, or any of
+// its nested tags.
 func (r tagRepo) FeedIDs(tag content.Tag, user content.User) ([]content.FeedID, error) {
 	if err := tag.Validate(); err != nil {
 		return []content.FeedID{}, errors.WithMessage(err, "validating tag")
@@ -103,6 +118,221 @@ func (r tagRepo) FeedIDs(tag content.Tag, user content.User) ([]content.FeedID,
 	return ids, nil
 }
 
+// Rename changes the value of the user's tag. Since tags are shared between
+// users, the user's feeds are moved to the tag with the new value, which is
+// created if needed. If the user already has such a tag, the two are merged.
+func (r tagRepo) Rename(tag content.Tag, value content.TagValue, user content.User) (content.Tag, error) {
+	if err := tag.Validate(); err != nil {
+		return content.Tag{}, errors.WithMessage(err, "validating tag")
+	}
+
+	if err := user.Validate(); err != nil {
+		return content.Tag{}, errors.WithMessage(err, "validating user")
+	}
+
+	renamed := content.Tag{Value: value}
+	if err := renamed.Validate(); err != nil {
+		return content.Tag{}, errors.WithMessage(err, "validating new tag value")
+	}
+
+	if tag.Value == value {
+		return tag, nil
+	}
+
+	r.log.Infof("Renaming tag %s of user %s to %s", tag, user, value)
+
+	if err := r.db.WithTx(func(tx *sqlx.Tx) error {
+		var err error
+		if renamed, err = findOrCreateTag(renamed, tx, r.db); err != nil {
+			return err
+		}
+
+		if err := mergeTag(tag, renamed, user, tx, r.db); err != nil {
+			return err
+		}
+
+		parents, err := tagParents(user, tx, r.db)
+		if err != nil {
+			return err
+		}
+		renamed.ParentID = parents[renamed.ID]
+
+		return deleteStaleTags(tx, r.db)
+	}); err != nil {
+		return content.Tag{}, errors.Wrapf(err, "renaming tag %s to %s", tag, value)
+	}
+
+	return renamed, nil
+}
+
+// Merge moves the user's feeds and nested tags from the source tags to the
+// target one, within a single transaction. The position and parent of the
+// first source tag are kept, unless the target tag already has them. A tag
+// cannot be merged into one of its nested tags.
+func (r tagRepo) Merge(from []content.Tag, to content.Tag, user content.User) error {
+	for _, t := range from {
+		if err := t.Validate(); err != nil {
+			return errors.WithMessage(err, "validating source tag")
+		}
+
+		if t.ID == to.ID {
+			return content.NewValidationError(errors.Errorf("cannot merge tag %s with itself", t))
+		}
+	}
+
+	if err := to.Validate(); err != nil {
+		return errors.WithMessage(err, "validating target tag")
+	}
+
+	if err := user.Validate(); err != nil {
+		return errors.WithMessage(err, "validating user")
+	}
+
+	r.log.Infof("Merging tags %v of user %s into %s", from, user, to)
+
+	return r.db.WithTx(func(tx *sqlx.Tx) error {
+		for _, t := range from {
+			if err := mergeTag(t, to, user, tx, r.db); err != nil {
+				return err
+			}
+		}
+
+		return deleteStaleTags(tx, r.db)
+	})
+}
+
+// Delete removes the tag from all of the user's feeds. Its nested tags are
+// moved to the top level.
+func (r tagRepo) Delete(tag content.Tag, user content.User) error {
+	if err := tag.Validate(); err != nil {
+		return errors.WithMessage(err, "validating tag")
+	}
+
+	if err := user.Validate(); err != nil {
+		return errors.WithMessage(err, "validating user")
+	}
+
+	r.log.Infof("Deleting tag %s of user %s", tag, user)
+
+	return r.db.WithTx(func(tx *sqlx.Tx) error {
+		if err := r.db.WithNamedStmt(r.db.SQL().Tag.DeleteForUser, tx, func(stmt *sqlx.NamedStmt) error {
+			_, err := stmt.Exec(tagQuery{ID: tag.ID, UserLogin: user.Login})
+			return err
+		}); err != nil {
+			return errors.Wrapf(err, "deleting tag %s", tag)
+		}
+
+		if err := r.db.WithNamedStmt(r.db.SQL().Tag.DeleteFromHierarchy, tx, func(stmt *sqlx.NamedStmt) error {
+			_, err := stmt.Exec(tagQuery{ID: tag.ID, UserLogin: user.Login})
+			return err
+		}); err != nil {
+			return errors.Wrapf(err, "deleting tag %s from the hierarchy", tag)
+		}
+
+		return deleteStaleTags(tx, r.db)
+	})
+}
+
+// SetOrder replaces the user's tag order with the given one. Tags that are
+// left out are listed after the ordered ones, by value.
+func (r tagRepo) SetOrder(ids []content.TagID, user content.User) error {
+	if err := user.Validate(); err != nil {
+		return errors.WithMessage(err, "validating user")
+	}
+
+	r.log.Infof("Setting tag order of user %s", user)
+
+	return r.db.WithTx(func(tx *sqlx.Tx) error {
+		s := r.db.SQL()
+
+		if err := r.db.WithNamedStmt(s.Tag.DeleteOrder, tx, func(stmt *sqlx.NamedStmt) error {
+			_, err := stmt.Exec(tagOrder{UserLogin: user.Login})
+			return err
+		}); err != nil {
+			return errors.Wrap(err, "deleting tag order")
+		}
+
+		return r.db.WithNamedStmt(s.Tag.CreateOrder, tx, func(stmt *sqlx.NamedStmt) error {
+			seen := map[content.TagID]bool{}
+			for _, id := range ids {
+				if seen[id] {
+					continue
+				}
+				seen[id] = true
+
+				if _, err := stmt.Exec(tagOrder{ID: id, UserLogin: user.Login, Position: len(seen)}); err != nil {
+					return errors.Wrapf(err, "setting tag %d position", id)
+				}
+			}
+
+			return nil
+		})
+	})
+}
+
+// SetParent nests the tag under the parent one, or moves it to the top level
+// if the parent is a zero tag. Tags without an id are looked up by value, and
+// created if needed. Nesting a tag under itself, or any of its nested tags,
+// results in a validation error.
+func (r tagRepo) SetParent(tag, parent content.Tag, user content.User) error {
+	if err := tag.Validate(); err != nil {
+		return errors.WithMessage(err, "validating tag")
+	}
+
+	if err := user.Validate(); err != nil {
+		return errors.WithMessage(err, "validating user")
+	}
+
+	topLevel := parent.ID == 0 && parent.Value == ""
+	if !topLevel {
+		if err := parent.Validate(); err != nil {
+			return errors.WithMessage(err, "validating parent tag")
+		}
+	}
+
+	r.log.Infof("Setting tag %s parent of user %s to %s", tag, user, parent)
+
+	return r.db.WithTx(func(tx *sqlx.Tx) error {
+		s := r.db.SQL()
+
+		var err error
+		if tag, err = findOrCreateTag(tag, tx, r.db); err != nil {
+			return err
+		}
+
+		if err := r.db.WithNamedStmt(s.Tag.DeleteParent, tx, func(stmt *sqlx.NamedStmt) error {
+			_, err := stmt.Exec(tagQuery{ID: tag.ID, UserLogin: user.Login})
+			return err
+		}); err != nil {
+			return errors.Wrapf(err, "deleting tag %s parent", tag)
+		}
+
+		if !topLevel {
+			if parent, err = findOrCreateTag(parent, tx, r.db); err != nil {
+				return err
+			}
+
+			parents, err := tagParents(user, tx, r.db)
+			if err != nil {
+				return err
+			}
+
+			if parent.ID == tag.ID || isTagAncestor(tag.ID, parent.ID, parents) {
+				return content.NewValidationError(errors.Errorf("tag %s cannot be nested under %s", tag, parent))
+			}
+
+			if err := r.db.WithNamedStmt(s.Tag.CreateParent, tx, func(stmt *sqlx.NamedStmt) error {
+				_, err := stmt.Exec(tagQuery{ID: tag.ID, UserLogin: user.Login, ParentID: parent.ID})
+				return err
+			}); err != nil {
+				return errors.Wrapf(err, "setting tag %s parent to %s", tag, parent)
+			}
+		}
+
+		return deleteStaleTags(tx, r.db)
+	})
+}
+
 func findTagByValue(value content.TagValue, stmt string, db *db.DB, tx *sqlx.Tx) (content.Tag, error) {
 	var tag content.Tag
 	if err := db.WithNamedStmt(stmt, tx, func(stmt *sqlx.NamedStmt) error {
@@ -128,3 +358,143 @@ func createTag(tag content.Tag, tx *sqlx.Tx, db *db.DB) (content.Tag, error) {
 
 	return tag, nil
 }
+
+func findOrCreateTag(tag content.Tag, tx *sqlx.Tx, db *db.DB) (content.Tag, error) {
+	if tag.ID != 0 {
+		return tag, nil
+	}
+
+	existing, err := findTagByValue(tag.Value, db.SQL().Tag.GetByValue, db, tx)
+	if err == nil {
+		tag.ID = existing.ID
+		return tag, nil
+	}
+
+	if !content.IsNoContent(err) {
+		return content.Tag{}, err
+	}
+
+	return createTag(tag, tx, db)
+}
+
+// tagParents returns the parent ids of the user's nested tags.
+func tagParents(user content.User, tx *sqlx.Tx, db *db.DB) (map[content.TagID]content.TagID, error) {
+	var tags []content.Tag
+	if err := db.WithNamedStmt(db.SQL().Tag.GetParents, tx, func(stmt *sqlx.NamedStmt) error {
+		return stmt.Select(&tags, tagQuery{UserLogin: user.Login})
+	}); err != nil {
+		return nil, errors.Wrapf(err, "getting user %s tag parents", user)
+	}
+
+	parents := make(map[content.TagID]content.TagID, len(tags))
+	for _, t := range tags {
+		parents[t.ID] = t.ParentID
+	}
+
+	return parents, nil
+}
+
+// isTagAncestor reports whether the first tag is among the ancestors of the
+// second one.
+func isTagAncestor(ancestor, id content.TagID, parents map[content.TagID]content.TagID) bool {
+	// The walk is bounded, in case the hierarchy already contains a cycle.
+	for i := 0; i <= len(parents); i++ {
+		parent, ok := parents[id]
+		if !ok {
+			return false
+		}
+
+		if parent == ancestor {
+			return true
+		}
+
+		id = parent
+	}
+
+	return false
+}
+
+func mergeTag(from, to content.Tag, user content.User, tx *sqlx.Tx, db *db.DB) error {
+	s := db.SQL()
+	args := tagMerge{FromID: from.ID, ToID: to.ID, UserLogin: user.Login}
+
+	parents, err := tagParents(user, tx, db)
+	if err != nil {
+		return err
+	}
+
+	if isTagAncestor(from.ID, to.ID, parents) {
+		return content.NewValidationError(errors.Errorf("tag %s cannot be merged into its nested tag %s", from, to))
+	}
+
+	queries := []string{s.Tag.Merge, s.Tag.MergeOrder, s.Tag.MergeChildren}
+	if _, ok := parents[to.ID]; !ok && !isTagAncestor(to.ID, from.ID, parents) {
+		queries = append(queries, s.Tag.MergeParent)
+	}
+
+	for _, query := range queries {
+		if err := db.WithNamedStmt(query, tx, func(stmt *sqlx.NamedStmt) error {
+			_, err := stmt.Exec(args)
+			return err
+		}); err != nil {
+			return errors.Wrapf(err, "merging tag %s into %s", from, to)
+		}
+	}
+
+	if err := db.WithNamedStmt(s.Tag.DeleteForUser, tx, func(stmt *sqlx.NamedStmt) error {
+		_, err := stmt.Exec(tagQuery{ID: from.ID, UserLogin: user.Login})
+		return err
+	}); err != nil {
+		return errors.Wrapf(err, "deleting tag %s", from)
+	}
+
+	if err := db.WithNamedStmt(s.Tag.DeleteFromHierarchy, tx, func(stmt *sqlx.NamedStmt) error {
+		_, err := stmt.Exec(tagQuery{ID: from.ID, UserLogin: user.Login})
+		return err
+	}); err != nil {
+		return errors.Wrapf(err, "deleting tag %s from the hierarchy", from)
+	}
+
+	return nil
+}
+
+// deleteStaleTags removes the tags of feeds the users are no longer
+// subscribed to, as well as any tags that are no longer in use. Tags without
+// feeds are kept as long as they have nested tags.
+func deleteStaleTags(tx *sqlx.Tx, db *db.DB) error {
+	s := db.SQL()
+
+	if err := db.WithStmt(s.Tag.DeleteStaleUserFeeds, tx, func(stmt *sqlx.Stmt) error {
+		_, err := stmt.Exec()
+		return err
+	}); err != nil {
+		return errors.Wrap(err, "deleting stale feed tags")
+	}
+
+	// Each pass only removes the innermost of the empty nested tags.
+	if err := db.WithStmt(s.Tag.DeleteStaleParents, tx, func(stmt *sqlx.Stmt) error {
+		for {
+			res, err := stmt.Exec()
+			if err != nil {
+				return err
+			}
+
+			if count, err := res.RowsAffected(); err != nil || count == 0 {
+				return err
+			}
+		}
+	}); err != nil {
+		return errors.Wrap(err, "deleting stale tag parents")
+	}
+
+	for _, query := range []string{s.Tag.DeleteStaleOrder, s.Tag.DeleteStale} {
+		if err := db.WithStmt(query, tx, func(stmt *sqlx.Stmt) error {
+			_, err := stmt.Exec()
+			return err
+		}); err != nil {
+			return errors.Wrap(err, "deleting stale tags")
+		}
+	}
+
+	return nil
+}
@@ -5,14 +5,20 @@ package repo_test
 import (
 	"os"
 	"testing"
+	"time"
 
+	"github.com/urandom/readeef/content"
 	"github.com/urandom/readeef/content/repo/sql"
 	"github.com/urandom/readeef/content/repo/sql/db"
 	_ "github.com/urandom/readeef/content/repo/sql/db/sqlite3"
+	"github.com/urandom/readeef/parser"
 )
 
+var sqliteDB *db.DB
+
 func TestMain(m *testing.M) {
 	db := db.New(logger)
+	sqliteDB = db
 	if err := db.Open("sqlite3", "file:/tmp/readeef-test.sqlite3?cache=shared"); err != nil {
 		// if err := db.Open("sqlite3", "file::memory:?cache=shared"); err != nil {
 		panic(err)
@@ -39,3 +45,78 @@ func TestMain(m *testing.M) {
 
 	os.Exit(ret)
 }
+
+// Test_articleRepo_PurgeOrphans checks that purging removes the dependent
+// rows of the articles, which are not cascaded in sqlite connections
+// without foreign keys enabled.
+func Test_articleRepo_PurgeOrphans(t *testing.T) {
+	skipTest(t)
+	setupFeed()
+
+	now := time.Now()
+	f := content.Feed{Link: "http://sugr.org/purge-orphans"}
+	f.Refresh(parser.Feed{Title: "purge orphans", Articles: []parser.Article{
+		{Title: "Orphans 0", Link: "http://sugr.org/purge-orphans/0", Date: now},
+		{Title: "Orphans 1", Link: "http://sugr.org/purge-orphans/1", Date: now.Add(-time.Hour),
+			Categories: []string{"category"},
+			Enclosures: []parser.Enclosure{{Url: "http://sugr.org/purge-orphans/1.mp3", Type: "audio/mpeg"}},
+		},
+	}})
+
+	u1 := content.User{Login: user1}
+	createFeed(&f, u1)
+	defer func() { service.FeedRepo().Delete(f) }()
+
+	r := service.ArticleRepo()
+	stored, err := r.All(content.FeedIDs([]content.FeedID{f.ID}))
+	if err != nil {
+		t.Fatalf("articleRepo.All() error = %v", err)
+	}
+
+	var old content.Article
+	for _, a := range stored {
+		if a.Title == "Orphans 1" {
+			old = a
+		}
+	}
+
+	if err := r.Read(false, u1, content.IDs([]content.ArticleID{old.ID})); err != nil {
+		t.Fatalf("articleRepo.Read() error = %v", err)
+	}
+
+	if err := service.ThumbnailRepo().Update(content.Thumbnail{ArticleID: old.ID, Thumbnail: "thumb"}); err != nil {
+		t.Fatalf("thumbnailRepo.Update() error = %v", err)
+	}
+
+	if err := service.ExtractRepo().Update(content.Extract{ArticleID: old.ID, Title: "extract"}); err != nil {
+		t.Fatalf("extractRepo.Update() error = %v", err)
+	}
+
+	if err := service.ScoresRepo().Update(content.Scores{ArticleID: old.ID, Score: 1}); err != nil {
+		t.Fatalf("scoresRepo.Update() error = %v", err)
+	}
+
+	purged, err := r.Purge(f, time.Time{}, 1)
+	if err != nil {
+		t.Fatalf("articleRepo.Purge() error = %v", err)
+	}
+
+	if len(purged) != 1 || purged[0].ID != old.ID {
+		t.Fatalf("articleRepo.Purge() = %v, want %v", purged, old)
+	}
+
+	for _, table := range []string{
+		"users_articles_unread", "users_articles_favorite", "articles_scores",
+		"articles_thumbnails", "articles_extracts", "articles_revisions",
+		"articles_duplicates", "articles_enclosures", "articles_categories",
+	} {
+		var count int
+		if err := sqliteDB.Get(&count, "SELECT COUNT(*) FROM "+table+" WHERE article_id = $1", old.ID); err != nil {
+			t.Fatalf("counting %s rows error = %v", table, err)
+		}
+
+		if count != 0 {
+			t.Errorf("articleRepo.Purge() left %d orphans in %s", count, table)
+		}
+	}
+}
@@ -8,4 +8,10 @@ type Subscription interface {
 	All() ([]content.Subscription, error)
 
 	Update(content.Subscription) error
+
+	// Subscribers returns the downstream subscribers of a feed published
+	// by readeef.
+	Subscribers(topic string) ([]content.Subscriber, error)
+	UpdateSubscriber(content.Subscriber) error
+	DeleteSubscriber(content.Subscriber) error
 }
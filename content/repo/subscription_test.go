@@ -4,6 +4,7 @@ import (
 	"sort"
 	"sync"
 	"testing"
+	"time"
 
 	"github.com/pkg/errors"
 	"github.com/urandom/readeef/content"
@@ -127,11 +128,84 @@ func Test_subscriptionRepo_Update(t *testing.T) {
 	}
 }
 
+func Test_subscriptionRepo_Subscribers(t *testing.T) {
+	skipTest(t)
+
+	r := service.SubscriptionRepo()
+	now := time.Now().Round(time.Second)
+
+	subscriber1 := content.Subscriber{Topic: "http://sugr.org/feed/1", Callback: "http://example.com/1", Secret: "secret", LeaseDuration: int64(time.Hour), VerificationTime: now}
+	subscriber2 := content.Subscriber{Topic: "http://sugr.org/feed/1", Callback: "http://example.com/2"}
+	subscriber3 := content.Subscriber{Topic: "http://sugr.org/feed/2", Callback: "http://example.com/1"}
+
+	for _, s := range []content.Subscriber{subscriber1, subscriber2, subscriber3} {
+		if err := r.UpdateSubscriber(s); err != nil {
+			t.Fatalf("subscriptionRepo.UpdateSubscriber() error = %v", err)
+		}
+	}
+
+	// Updating an existing subscriber doesn't create a new one.
+	subscriber2.Secret = "other"
+	subscriber2.LeaseDuration = int64(2 * time.Hour)
+	subscriber2.VerificationTime = now
+	if err := r.UpdateSubscriber(subscriber2); err != nil {
+		t.Fatalf("subscriptionRepo.UpdateSubscriber() error = %v", err)
+	}
+
+	if err := r.UpdateSubscriber(content.Subscriber{Topic: "http://sugr.org/feed/1"}); err == nil {
+		t.Errorf("subscriptionRepo.UpdateSubscriber() expected validation error")
+	}
+
+	tests := []struct {
+		name    string
+		topic   string
+		delete  []content.Subscriber
+		want    []content.Subscriber
+		wantErr bool
+	}{
+		{"topic 1", "http://sugr.org/feed/1", nil, []content.Subscriber{subscriber1, subscriber2}, false},
+		{"topic 2", "http://sugr.org/feed/2", nil, []content.Subscriber{subscriber3}, false},
+		{"unknown topic", "http://sugr.org/feed/3", nil, nil, false},
+		{"deleted", "http://sugr.org/feed/1", []content.Subscriber{subscriber1}, []content.Subscriber{subscriber2}, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			for _, s := range tt.delete {
+				if err := r.DeleteSubscriber(s); err != nil {
+					t.Errorf("subscriptionRepo.DeleteSubscriber() error = %v", err)
+					return
+				}
+			}
+
+			got, err := r.Subscribers(tt.topic)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("subscriptionRepo.Subscribers() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+
+			sort.Slice(got, func(i, j int) bool {
+				return got[i].Callback < got[j].Callback
+			})
+
+			if len(got) != len(tt.want) {
+				t.Errorf("subscriptionRepo.Subscribers() = %v, want %v", got, tt.want)
+				return
+			}
+
+			for i := range got {
+				if !subscribersEqual(got[i], tt.want[i]) {
+					t.Errorf("subscriptionRepo.Subscribers() = %#v, want %#v", got[i], tt.want[i])
+				}
+			}
+		})
+	}
+}
+
 func setupSubscription() {
 	setupFeed()
 
 	subscriptionSync.Do(func() {
-		subscription1 = content.Subscription{Link: "http://sugr.org", FeedID: feed1.ID}
+		subscription1 = content.Subscription{Link: "http://sugr.org", FeedID: feed1.ID, Secret: "secret", Topic: "http://sugr.org/feed", Pending: "subscribe"}
 		subscription2 = content.Subscription{Link: "http://sugr.org", FeedID: feed2.ID}
 
 		if err := service.SubscriptionRepo().Update(subscription1); err != nil {
@@ -149,5 +223,16 @@ func subscriptionsEqual(a, b content.Subscription) bool {
 		a.Link == b.Link &&
 		a.LeaseDuration == b.LeaseDuration &&
 		a.SubscriptionFailure == b.SubscriptionFailure &&
+		a.Secret == b.Secret &&
+		a.Topic == b.Topic &&
+		a.Pending == b.Pending &&
+		a.VerificationTime.Equal(b.VerificationTime)
+}
+
+func subscribersEqual(a, b content.Subscriber) bool {
+	return a.Topic == b.Topic &&
+		a.Callback == b.Callback &&
+		a.Secret == b.Secret &&
+		a.LeaseDuration == b.LeaseDuration &&
 		a.VerificationTime.Equal(b.VerificationTime)
 }
@@ -10,4 +10,10 @@ type Tag interface {
 	ForFeed(content.Feed, content.User) ([]content.Tag, error)
 
 	FeedIDs(content.Tag, content.User) ([]content.FeedID, error)
+
+	Rename(content.Tag, content.TagValue, content.User) (content.Tag, error)
+	Merge(from []content.Tag, to content.Tag, user content.User) error
+	Delete(content.Tag, content.User) error
+	SetOrder([]content.TagID, content.User) error
+	SetParent(tag, parent content.Tag, user content.User) error
 }
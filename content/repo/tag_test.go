@@ -126,3 +126,302 @@ func Test_tagRepo_ForFeed(t *testing.T) {
 		})
 	}
 }
+
+func Test_tagRepo_Manage(t *testing.T) {
+	skipTest(t)
+	setupFeed()
+
+	u1 := content.User{Login: user1}
+	u2 := content.User{Login: user2}
+
+	feedA := content.Feed{Link: "http://sugr.org/tags/a"}
+	feedB := content.Feed{Link: "http://sugr.org/tags/b"}
+	createFeed(&feedA, u2)
+	createFeed(&feedB, u2)
+	defer func() {
+		service.FeedRepo().Delete(feedA)
+		service.FeedRepo().Delete(feedB)
+	}()
+
+	x, y, w := content.Tag{Value: "tag x"}, content.Tag{Value: "tag y"}, content.Tag{Value: "tag w"}
+	if err := service.FeedRepo().SetUserTags(feedA, u2, []*content.Tag{&x, &y}); err != nil {
+		t.Fatalf("feedRepo.SetUserTags() error = %v", err)
+	}
+	if err := service.FeedRepo().SetUserTags(feedB, u2, []*content.Tag{&y, &w}); err != nil {
+		t.Fatalf("feedRepo.SetUserTags() error = %v", err)
+	}
+
+	r := service.TagRepo()
+
+	values := func(user content.User) []content.TagValue {
+		tags, err := r.ForUser(user)
+		if err != nil {
+			t.Fatalf("tagRepo.ForUser() error = %v", err)
+		}
+
+		values := []content.TagValue{}
+		for _, tag := range tags {
+			values = append(values, tag.Value)
+		}
+
+		return values
+	}
+
+	feedIDs := func(tag content.Tag) []content.FeedID {
+		ids, err := r.FeedIDs(tag, u2)
+		if err != nil {
+			t.Fatalf("tagRepo.FeedIDs() error = %v", err)
+		}
+
+		sort.Slice(ids, func(i, j int) bool {
+			return ids[i] < ids[j]
+		})
+
+		return ids
+	}
+
+	tests := []struct {
+		name    string
+		action  func() error
+		want    []content.TagValue
+		wantErr bool
+	}{
+		{"by value", func() error { return nil }, []content.TagValue{"tag w", "tag x", "tag y"}, false},
+		{"order", func() error {
+			return r.SetOrder([]content.TagID{y.ID, x.ID, y.ID}, u2)
+		}, []content.TagValue{"tag y", "tag x", "tag w"}, false},
+		{"order foreign tag", func() error {
+			return r.SetOrder([]content.TagID{tag1.ID, x.ID}, u2)
+		}, []content.TagValue{"tag x", "tag w", "tag y"}, false},
+		{"rename", func() error {
+			renamed, err := r.Rename(x, "tag z", u2)
+			if err == nil && (renamed.ID == x.ID || renamed.Value != "tag z") {
+				t.Errorf("tagRepo.Rename() = %v", renamed)
+			}
+			x = renamed
+			return err
+		}, []content.TagValue{"tag z", "tag w", "tag y"}, false},
+		{"rename empty", func() error {
+			_, err := r.Rename(x, "", u2)
+			return err
+		}, []content.TagValue{"tag z", "tag w", "tag y"}, true},
+		{"rename to existing", func() error {
+			renamed, err := r.Rename(x, "tag y", u2)
+			if err == nil && renamed.ID != y.ID {
+				t.Errorf("tagRepo.Rename() = %v, want %v", renamed, y)
+			}
+			return err
+		}, []content.TagValue{"tag y", "tag w"}, false},
+		{"merge self", func() error {
+			return r.Merge([]content.Tag{w, y}, y, u2)
+		}, []content.TagValue{"tag y", "tag w"}, true},
+		{"merge", func() error {
+			return r.Merge([]content.Tag{w}, y, u2)
+		}, []content.TagValue{"tag y"}, false},
+		{"delete other user", func() error {
+			return r.Delete(y, u1)
+		}, []content.TagValue{"tag y"}, false},
+		{"delete", func() error {
+			return r.Delete(y, u2)
+		}, []content.TagValue{}, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := tt.action(); (err != nil) != tt.wantErr {
+				t.Errorf("action error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+
+			if got := values(u2); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("tagRepo.ForUser() = %v, want %v", got, tt.want)
+			}
+
+			if tt.name == "merge" {
+				if got, want := feedIDs(y), []content.FeedID{feedA.ID, feedB.ID}; !reflect.DeepEqual(got, want) {
+					t.Errorf("tagRepo.FeedIDs() = %v, want %v", got, want)
+				}
+			}
+		})
+	}
+
+	if got, want := values(u1), []content.TagValue{tag1.Value, tag2.Value}; !reflect.DeepEqual(got, want) {
+		t.Errorf("tagRepo.ForUser() = %v, want %v", got, want)
+	}
+
+	v := content.Tag{Value: "tag v"}
+	if err := service.FeedRepo().SetUserTags(feedA, u2, []*content.Tag{&v}); err != nil {
+		t.Fatalf("feedRepo.SetUserTags() error = %v", err)
+	}
+
+	if err := service.FeedRepo().DetachFrom(feedA, u2); err != nil {
+		t.Fatalf("feedRepo.DetachFrom() error = %v", err)
+	}
+
+	if got := values(u2); len(got) != 0 {
+		t.Errorf("tagRepo.ForUser() = %v after detaching the feed", got)
+	}
+}
+
+func Test_tagRepo_Hierarchy(t *testing.T) {
+	skipTest(t)
+	setupFeed()
+
+	u2 := content.User{Login: user2}
+
+	feedA := content.Feed{Link: "http://sugr.org/nested/a"}
+	feedB := content.Feed{Link: "http://sugr.org/nested/b"}
+	createFeed(&feedA, u2)
+	createFeed(&feedB, u2)
+	defer func() {
+		service.FeedRepo().Delete(feedA)
+		service.FeedRepo().Delete(feedB)
+	}()
+
+	gadgets, tech := content.Tag{Value: "gadgets"}, content.Tag{Value: "tech"}
+	if err := service.FeedRepo().SetUserTags(feedA, u2, []*content.Tag{&gadgets}); err != nil {
+		t.Fatalf("feedRepo.SetUserTags() error = %v", err)
+	}
+	if err := service.FeedRepo().SetUserTags(feedB, u2, []*content.Tag{&tech}); err != nil {
+		t.Fatalf("feedRepo.SetUserTags() error = %v", err)
+	}
+
+	r := service.TagRepo()
+
+	tree := func() map[content.TagValue]content.TagValue {
+		tags, err := r.ForUser(u2)
+		if err != nil {
+			t.Fatalf("tagRepo.ForUser() error = %v", err)
+		}
+
+		values := map[content.TagID]content.TagValue{}
+		for _, tag := range tags {
+			values[tag.ID] = tag.Value
+		}
+
+		tree := map[content.TagValue]content.TagValue{}
+		for _, tag := range tags {
+			tree[tag.Value] = values[tag.ParentID]
+		}
+
+		return tree
+	}
+
+	feedIDs := func(value content.TagValue) []content.FeedID {
+		tags, err := r.ForUser(u2)
+		if err != nil {
+			t.Fatalf("tagRepo.ForUser() error = %v", err)
+		}
+
+		for _, tag := range tags {
+			if tag.Value == value {
+				ids, err := r.FeedIDs(tag, u2)
+				if err != nil {
+					t.Fatalf("tagRepo.FeedIDs() error = %v", err)
+				}
+
+				sort.Slice(ids, func(i, j int) bool {
+					return ids[i] < ids[j]
+				})
+
+				return ids
+			}
+		}
+
+		return nil
+	}
+
+	both := []content.FeedID{feedA.ID, feedB.ID}
+	tests := []struct {
+		name    string
+		action  func() error
+		want    map[content.TagValue]content.TagValue
+		feedIDs map[content.TagValue][]content.FeedID
+		wantErr bool
+	}{
+		{"nest", func() error {
+			return r.SetParent(gadgets, tech, u2)
+		}, map[content.TagValue]content.TagValue{"gadgets": "tech", "tech": ""},
+			map[content.TagValue][]content.FeedID{"gadgets": {feedA.ID}, "tech": both}, false},
+		{"nest under new tag", func() error {
+			return r.SetParent(tech, content.Tag{Value: "news"}, u2)
+		}, map[content.TagValue]content.TagValue{"gadgets": "tech", "tech": "news", "news": ""},
+			map[content.TagValue][]content.FeedID{"news": both, "tech": both}, false},
+		{"parent feeds", func() error {
+			feeds, err := service.FeedRepo().ForTag(content.Tag{Value: "news"}, u2)
+			if len(feeds) != 2 {
+				t.Errorf("feedRepo.ForTag() = %v, want 2 feeds", feeds)
+			}
+			return err
+		}, map[content.TagValue]content.TagValue{"gadgets": "tech", "tech": "news", "news": ""}, nil, false},
+		{"cycle", func() error {
+			tags, _ := r.ForUser(u2)
+			for _, tag := range tags {
+				if tag.Value == "news" {
+					return r.SetParent(tag, gadgets, u2)
+				}
+			}
+			return nil
+		}, map[content.TagValue]content.TagValue{"gadgets": "tech", "tech": "news", "news": ""}, nil, true},
+		{"self", func() error {
+			return r.SetParent(tech, tech, u2)
+		}, map[content.TagValue]content.TagValue{"gadgets": "tech", "tech": "news", "news": ""}, nil, true},
+		{"merge into nested", func() error {
+			return r.Merge([]content.Tag{tech}, gadgets, u2)
+		}, map[content.TagValue]content.TagValue{"gadgets": "tech", "tech": "news", "news": ""}, nil, true},
+		{"merge atomically", func() error {
+			tags, _ := r.ForUser(u2)
+			for _, tag := range tags {
+				if tag.Value == "news" {
+					return r.Merge([]content.Tag{gadgets, tag}, tech, u2)
+				}
+			}
+			return nil
+		}, map[content.TagValue]content.TagValue{"gadgets": "tech", "tech": "news", "news": ""},
+			map[content.TagValue][]content.FeedID{"gadgets": {feedA.ID}, "tech": both}, true},
+		{"rename", func() error {
+			renamed, err := r.Rename(tech, "technology", u2)
+			if err == nil && renamed.ParentID == 0 {
+				t.Errorf("tagRepo.Rename() = %v, want a parent", renamed)
+			}
+			tech = renamed
+			return err
+		}, map[content.TagValue]content.TagValue{"gadgets": "technology", "technology": "news", "news": ""},
+			map[content.TagValue][]content.FeedID{"news": both, "technology": both}, false},
+		{"top level", func() error {
+			return r.SetParent(tech, content.Tag{}, u2)
+		}, map[content.TagValue]content.TagValue{"gadgets": "technology", "technology": ""},
+			map[content.TagValue][]content.FeedID{"technology": both}, false},
+		{"delete parent", func() error {
+			return r.Delete(tech, u2)
+		}, map[content.TagValue]content.TagValue{"gadgets": ""},
+			map[content.TagValue][]content.FeedID{"gadgets": {feedA.ID}}, false},
+		{"nest under folders", func() error {
+			if err := r.SetParent(gadgets, content.Tag{Value: "inner"}, u2); err != nil {
+				return err
+			}
+			return r.SetParent(content.Tag{Value: "inner"}, content.Tag{Value: "outer"}, u2)
+		}, map[content.TagValue]content.TagValue{"gadgets": "inner", "inner": "outer", "outer": ""},
+			map[content.TagValue][]content.FeedID{"outer": {feedA.ID}}, false},
+		{"stale folders", func() error {
+			return service.FeedRepo().SetUserTags(feedA, u2, nil)
+		}, map[content.TagValue]content.TagValue{}, nil, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := tt.action(); (err != nil) != tt.wantErr {
+				t.Errorf("action error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+
+			if got := tree(); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("tagRepo.ForUser() = %v, want %v", got, tt.want)
+			}
+
+			for value, want := range tt.feedIDs {
+				if got := feedIDs(value); !reflect.DeepEqual(got, want) {
+					t.Errorf("tagRepo.FeedIDs(%s) = %v, want %v", value, got, want)
+				}
+			}
+		})
+	}
+}
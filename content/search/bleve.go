@@ -33,6 +33,8 @@ type indexArticle struct {
 	Title       string    `json:"title"`
 	Description string    `json:"description"`
 	Link        string    `json:"link"`
+	Author      string    `json:"author"`
+	Categories  []string  `json:"categories"`
 	Date        time.Time `json:"date"`
 }
 
@@ -181,6 +183,12 @@ func (b bleveSearch) Search(
 	if o.UnreadOnly {
 		queryOpts = append(queryOpts, content.UnreadOnly)
 	}
+	if len(o.Authors) > 0 {
+		queryOpts = append(queryOpts, content.Authors(o.Authors))
+	}
+	if len(o.Categories) > 0 {
+		queryOpts = append(queryOpts, content.Categories(o.Categories))
+	}
 
 	articles, err := b.service.ArticleRepo().ForUser(u, queryOpts...)
 	if err != nil {
@@ -277,6 +285,8 @@ func prepareArticle(article content.Article) (string, indexArticle) {
 		ArticleID:   int64(article.ID),
 		Title:       html.UnescapeString(StripTags(article.Title)),
 		Description: html.UnescapeString(StripTags(article.Description)),
+		Author:      article.Author,
+		Categories:  article.Categories,
 		Link:        article.Link, Date: article.Date,
 	}
 
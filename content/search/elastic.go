@@ -142,6 +142,12 @@ func (e elasticSearch) Search(
 	if o.UnreadOnly {
 		queryOpts = append(queryOpts, content.UnreadOnly)
 	}
+	if len(o.Authors) > 0 {
+		queryOpts = append(queryOpts, content.Authors(o.Authors))
+	}
+	if len(o.Categories) > 0 {
+		queryOpts = append(queryOpts, content.Categories(o.Categories))
+	}
 
 	articles, err := e.service.ArticleRepo().ForUser(u, queryOpts...)
 	if err != nil {
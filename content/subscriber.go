@@ -0,0 +1,67 @@
+package content
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"net/url"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+// Subscriber is a downstream WebSub subscriber of a feed published by
+// readeef, to which the new content of the feed is pushed.
+type Subscriber struct {
+	// Topic is the url of the published feed.
+	Topic string `db:"topic"`
+	// Callback is the url the content is pushed to.
+	Callback string `db:"callback"`
+	// Secret is provided by the subscriber, and is used to sign the pushed
+	// content.
+	Secret           string    `db:"secret"`
+	LeaseDuration    int64     `db:"lease_duration"`
+	VerificationTime time.Time `db:"verification_time"`
+}
+
+func (s Subscriber) Validate() error {
+	if u, err := url.Parse(s.Topic); err != nil || !u.IsAbs() {
+		return NewValidationError(errors.New("Invalid subscriber topic"))
+	}
+
+	if u, err := url.Parse(s.Callback); err != nil || !u.IsAbs() {
+		return NewValidationError(errors.New("Invalid subscriber callback"))
+	}
+
+	return nil
+}
+
+// LeaseExpiry returns the time the lease granted to the subscriber runs out.
+func (s Subscriber) LeaseExpiry() time.Time {
+	return s.VerificationTime.Add(time.Duration(s.LeaseDuration))
+}
+
+// Expired returns true if the lease of the subscriber ran out by the given
+// time.
+func (s Subscriber) Expired(now time.Time) bool {
+	return !now.Before(s.LeaseExpiry())
+}
+
+// Signature returns the X-Hub-Signature header value of the body, signed
+// with the subscriber secret. It is empty if the subscriber did not provide
+// a secret.
+func (s Subscriber) Signature(body []byte) string {
+	if s.Secret == "" {
+		return ""
+	}
+
+	mac := hmac.New(sha256.New, []byte(s.Secret))
+	mac.Write(body)
+
+	return "sha256=" + hex.EncodeToString(mac.Sum(nil))
+}
+
+func (s Subscriber) String() string {
+	return fmt.Sprintf("%s: %s", s.Callback, s.Topic)
+}
@@ -0,0 +1,86 @@
+package content_test
+
+import (
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/urandom/readeef/content"
+)
+
+func TestSubscriber_Validate(t *testing.T) {
+	tests := []struct {
+		name     string
+		topic    string
+		callback string
+		wantErr  bool
+	}{
+		{"valid", "http://sugr.org/feed", "http://example.com/callback", false},
+		{"topic not absolute", "sugr.org/feed", "http://example.com/callback", true},
+		{"callback not absolute", "http://sugr.org/feed", "example.com/callback", true},
+		{"no topic", "", "http://example.com/callback", true},
+		{"no callback", "http://sugr.org/feed", "", true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := content.Subscriber{Topic: tt.topic, Callback: tt.callback}
+			if err := s.Validate(); (err != nil) != tt.wantErr {
+				t.Errorf("Subscriber.Validate() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestSubscriber_Expired(t *testing.T) {
+	now := time.Now()
+	lease := int64(10 * time.Hour)
+
+	tests := []struct {
+		name string
+		s    content.Subscriber
+		want bool
+	}{
+		{"active", content.Subscriber{VerificationTime: now, LeaseDuration: lease}, false},
+		{"expired", content.Subscriber{VerificationTime: now.Add(-11 * time.Hour), LeaseDuration: lease}, true},
+		{"no lease", content.Subscriber{VerificationTime: now}, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.s.Expired(now); got != tt.want {
+				t.Errorf("Subscriber.Expired() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSubscriber_Signature(t *testing.T) {
+	body := []byte("<feed></feed>")
+
+	tests := []struct {
+		name    string
+		secret  string
+		empty   bool
+		wantErr bool
+	}{
+		{"signed", "secret", false, false},
+		{"no secret", "", true, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			signature := content.Subscriber{Secret: tt.secret}.Signature(body)
+			if (signature == "") != tt.empty {
+				t.Errorf("Subscriber.Signature() = %v, want empty %v", signature, tt.empty)
+				return
+			}
+
+			// The signature must be accepted by readeef's own subscriptions.
+			header := http.Header{}
+			header.Set("X-Hub-Signature", signature)
+
+			err := content.Subscription{Secret: tt.secret}.VerifySignature(header, body)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("Subscription.VerifySignature() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
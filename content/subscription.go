@@ -1,10 +1,19 @@
 package content
 
 import (
-	"errors"
+	"crypto/hmac"
+	"crypto/sha1"
+	"crypto/sha256"
+	"crypto/sha512"
+	"encoding/hex"
 	"fmt"
+	"hash"
+	"net/http"
 	"net/url"
+	"strings"
 	"time"
+
+	"github.com/pkg/errors"
 )
 
 type Subscription struct {
@@ -13,8 +22,37 @@ type Subscription struct {
 	LeaseDuration       int64     `db:"lease_duration"`
 	VerificationTime    time.Time `db:"verification_time"`
 	SubscriptionFailure bool      `db:"subscription_failure"`
+	// Secret is shared with the hub, which uses it to sign the distributed
+	// content.
+	Secret string `db:"secret"`
+	// Topic is the url the subscription was made for, which is the self
+	// link advertised by the feed, or the feed link.
+	Topic string `db:"topic"`
+	// Pending is the mode of the last request sent to the hub, subscribe
+	// or unsubscribe, until the hub verifies it.
+	Pending string `db:"pending"`
 }
 
+// SubscriptionStatus describes whether the hub currently pushes the updates
+// of the subscribed feed.
+type SubscriptionStatus string
+
+const (
+	// SubscriptionActive subscriptions are verified and their lease hasn't
+	// run out.
+	SubscriptionActive SubscriptionStatus = "active"
+	// SubscriptionExpired subscriptions were verified, but their lease ran
+	// out before being renewed.
+	SubscriptionExpired SubscriptionStatus = "expired"
+	// SubscriptionInactive subscriptions are pending verification, were
+	// denied by the hub, or were unsubscribed.
+	SubscriptionInactive SubscriptionStatus = "inactive"
+)
+
+var (
+	ErrInvalidSignature = errors.New("Invalid content signature")
+)
+
 func (s Subscription) Validate() error {
 	if s.FeedID == 0 {
 		return NewValidationError(errors.New("Invalid feed id"))
@@ -31,6 +69,99 @@ func (s Subscription) Validate() error {
 	return nil
 }
 
+// LeaseExpiry returns the time the lease granted by the hub runs out. It is
+// zero for unverified subscriptions, or when the hub did not specify a
+// lease.
+func (s Subscription) LeaseExpiry() time.Time {
+	if s.VerificationTime.IsZero() || s.LeaseDuration <= 0 {
+		return time.Time{}
+	}
+
+	return s.VerificationTime.Add(time.Duration(s.LeaseDuration))
+}
+
+// RenewTime returns the time at which the subscription should be renewed,
+// which leaves a tenth of the lease for the hub to verify the renewal.
+func (s Subscription) RenewTime() time.Time {
+	expiry := s.LeaseExpiry()
+	if expiry.IsZero() {
+		return expiry
+	}
+
+	return expiry.Add(-time.Duration(s.LeaseDuration) / 10)
+}
+
+// Status returns the state of the subscription at the given time.
+func (s Subscription) Status(now time.Time) SubscriptionStatus {
+	if s.SubscriptionFailure || s.VerificationTime.IsZero() {
+		return SubscriptionInactive
+	}
+
+	if expiry := s.LeaseExpiry(); !expiry.IsZero() && !now.Before(expiry) {
+		return SubscriptionExpired
+	}
+
+	return SubscriptionActive
+}
+
+// Pushed returns true if the hub pushes the updates of the subscribed feed
+// at the given time. Subscriptions without a secret are not pushed, since
+// their unsigned content is rejected.
+func (s Subscription) Pushed(now time.Time) bool {
+	return s.Secret != "" && s.Status(now) == SubscriptionActive
+}
+
+// VerifySignature checks that the body was signed by the hub using the
+// subscription secret. The X-Hub-Signature-256 header is preferred over
+// X-Hub-Signature, which may use any of the methods allowed by WebSub.
+func (s Subscription) VerifySignature(header http.Header, body []byte) error {
+	if s.Secret == "" {
+		return errors.Wrap(ErrInvalidSignature, "subscription has no secret")
+	}
+
+	signature := header.Get("X-Hub-Signature-256")
+	if signature == "" {
+		signature = header.Get("X-Hub-Signature")
+	}
+
+	if signature == "" {
+		return errors.Wrap(ErrInvalidSignature, "content is not signed")
+	}
+
+	parts := strings.SplitN(signature, "=", 2)
+	if len(parts) != 2 {
+		return errors.Wrapf(ErrInvalidSignature, "malformed signature '%s'", signature)
+	}
+
+	var method func() hash.Hash
+	switch parts[0] {
+	case "sha1":
+		method = sha1.New
+	case "sha256":
+		method = sha256.New
+	case "sha384":
+		method = sha512.New384
+	case "sha512":
+		method = sha512.New
+	default:
+		return errors.Wrapf(ErrInvalidSignature, "unsupported signature method '%s'", parts[0])
+	}
+
+	expected, err := hex.DecodeString(parts[1])
+	if err != nil {
+		return errors.Wrapf(ErrInvalidSignature, "decoding signature '%s'", signature)
+	}
+
+	mac := hmac.New(method, []byte(s.Secret))
+	mac.Write(body)
+
+	if !hmac.Equal(mac.Sum(nil), expected) {
+		return errors.Wrap(ErrInvalidSignature, "signature mismatch")
+	}
+
+	return nil
+}
+
 func (s Subscription) String() string {
 	return fmt.Sprintf("%s: %d", s.Link, s.FeedID)
 }
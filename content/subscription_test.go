@@ -1,8 +1,17 @@
 package content_test
 
 import (
+	"crypto/hmac"
+	"crypto/sha1"
+	"crypto/sha256"
+	"crypto/sha512"
+	"encoding/hex"
+	"hash"
+	"net/http"
 	"testing"
+	"time"
 
+	"github.com/pkg/errors"
 	"github.com/urandom/readeef/content"
 )
 
@@ -34,3 +43,97 @@ func TestSubscription_Validate(t *testing.T) {
 		})
 	}
 }
+
+func TestSubscription_Status(t *testing.T) {
+	now := time.Now()
+	lease := int64(10 * time.Hour)
+
+	tests := []struct {
+		name      string
+		s         content.Subscription
+		want      content.SubscriptionStatus
+		wantRenew time.Time
+	}{
+		{"pending", content.Subscription{SubscriptionFailure: true}, content.SubscriptionInactive, time.Time{}},
+		{"unsubscribed", content.Subscription{VerificationTime: now, LeaseDuration: lease, SubscriptionFailure: true}, content.SubscriptionInactive, now.Add(9 * time.Hour)},
+		{"active", content.Subscription{VerificationTime: now, LeaseDuration: lease}, content.SubscriptionActive, now.Add(9 * time.Hour)},
+		{"no lease", content.Subscription{VerificationTime: now.Add(-100 * time.Hour)}, content.SubscriptionActive, time.Time{}},
+		{"expired", content.Subscription{VerificationTime: now.Add(-10 * time.Hour), LeaseDuration: lease}, content.SubscriptionExpired, now.Add(-time.Hour)},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.s.Status(now); got != tt.want {
+				t.Errorf("Subscription.Status() = %v, want %v", got, tt.want)
+			}
+
+			if got := tt.s.RenewTime(); !got.Equal(tt.wantRenew) {
+				t.Errorf("Subscription.RenewTime() = %v, want %v", got, tt.wantRenew)
+			}
+		})
+	}
+}
+
+func TestSubscription_Pushed(t *testing.T) {
+	now := time.Now()
+
+	tests := []struct {
+		name string
+		s    content.Subscription
+		want bool
+	}{
+		{"active", content.Subscription{VerificationTime: now, Secret: "secret"}, true},
+		{"no secret", content.Subscription{VerificationTime: now}, false},
+		{"pending", content.Subscription{VerificationTime: now, Secret: "secret", SubscriptionFailure: true}, false},
+		{"expired", content.Subscription{VerificationTime: now.Add(-2 * time.Hour), LeaseDuration: int64(time.Hour), Secret: "secret"}, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.s.Pushed(now); got != tt.want {
+				t.Errorf("Subscription.Pushed() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSubscription_VerifySignature(t *testing.T) {
+	body := []byte("<feed></feed>")
+	sign := func(h func() hash.Hash, secret string) string {
+		mac := hmac.New(h, []byte(secret))
+		mac.Write(body)
+		return hex.EncodeToString(mac.Sum(nil))
+	}
+
+	tests := []struct {
+		name    string
+		secret  string
+		header  http.Header
+		wantErr bool
+	}{
+		{"sha256", "secret", http.Header{"X-Hub-Signature-256": {"sha256=" + sign(sha256.New, "secret")}}, false},
+		{"sha1", "secret", http.Header{"X-Hub-Signature": {"sha1=" + sign(sha1.New, "secret")}}, false},
+		{"sha512", "secret", http.Header{"X-Hub-Signature": {"sha512=" + sign(sha512.New, "secret")}}, false},
+		{"prefers sha256", "secret", http.Header{
+			"X-Hub-Signature-256": {"sha256=" + sign(sha256.New, "secret")},
+			"X-Hub-Signature":     {"sha1=0000"},
+		}, false},
+		{"wrong secret", "secret", http.Header{"X-Hub-Signature-256": {"sha256=" + sign(sha256.New, "other")}}, true},
+		{"unsupported method", "secret", http.Header{"X-Hub-Signature": {"md5=" + sign(sha1.New, "secret")}}, true},
+		{"malformed", "secret", http.Header{"X-Hub-Signature": {sign(sha1.New, "secret")}}, true},
+		{"not hex", "secret", http.Header{"X-Hub-Signature": {"sha1=zz"}}, true},
+		{"unsigned", "secret", http.Header{}, true},
+		{"no secret", "", http.Header{"X-Hub-Signature-256": {"sha256=" + sign(sha256.New, "")}}, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := content.Subscription{FeedID: 1, Link: "http://sugr.org", Secret: tt.secret}
+			err := s.VerifySignature(tt.header, body)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("Subscription.VerifySignature() error = %v, wantErr %v", err, tt.wantErr)
+			}
+
+			if err != nil && errors.Cause(err) != content.ErrInvalidSignature {
+				t.Errorf("Subscription.VerifySignature() error = %v, want %v", err, content.ErrInvalidSignature)
+			}
+		})
+	}
+}
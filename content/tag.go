@@ -10,8 +10,9 @@ type TagID int64
 type TagValue string
 
 type Tag struct {
-	ID    TagID    `json:"id"`
-	Value TagValue `json:"value"`
+	ID       TagID    `json:"id"`
+	Value    TagValue `json:"value"`
+	ParentID TagID    `json:"parentID,omitempty" db:"parent_id"`
 }
 
 func (t Tag) Validate() error {
@@ -2,6 +2,7 @@ package thumbnail
 
 import (
 	"fmt"
+	"net/http"
 	"strings"
 
 	"github.com/pkg/errors"
@@ -11,12 +12,15 @@ import (
 )
 
 type description struct {
-	repo repo.Thumbnail
-	log  log.Log
+	repo   repo.Thumbnail
+	client *http.Client
+	log    log.Log
 }
 
-func FromDescription(repo repo.Thumbnail, log log.Log) Generator {
-	return description{repo: repo, log: log}
+// FromDescription creates a generator that downloads the images in the
+// article description with the given client.
+func FromDescription(repo repo.Thumbnail, client *http.Client, log log.Log) Generator {
+	return description{repo: repo, client: client, log: log}
 }
 
 func (t description) Generate(a content.Article) error {
@@ -25,7 +29,7 @@ func (t description) Generate(a content.Article) error {
 	t.log.Debugf("Generating thumbnail for article %s from description", a)
 
 	thumbnail.Thumbnail, thumbnail.Link =
-		generateThumbnailFromDescription(strings.NewReader(a.Description))
+		generateThumbnailFromDescription(strings.NewReader(a.Description), t.client)
 
 	if err := t.repo.Update(thumbnail); err != nil {
 		return errors.WithMessage(err, fmt.Sprintf("saving thumbnail of %s", a))
@@ -3,6 +3,7 @@ package thumbnail
 import (
 	"fmt"
 	_ "image/png"
+	"net/http"
 	"strings"
 
 	"github.com/pkg/errors"
@@ -16,16 +17,25 @@ import (
 type ext struct {
 	repo        repo.Thumbnail
 	extractRepo repo.Extract
+	feedRepo    repo.Feed
 	generator   extract.Generator
 	processors  []processor.Article
+	client      *http.Client
+	secret      []byte
 	log         log.Log
 }
 
+// FromExtract creates a generator that uses the top image of the article
+// extract, downloaded with the given client. The secret is used to decrypt
+// the credentials of private feeds.
 func FromExtract(
 	repo repo.Thumbnail,
 	extractRepo repo.Extract,
+	feedRepo repo.Feed,
 	g extract.Generator,
 	processors []processor.Article,
+	client *http.Client,
+	secret []byte,
 	log log.Log,
 ) (Generator, error) {
 	if g == nil {
@@ -34,7 +44,10 @@ func FromExtract(
 
 	processors = filterProcessors(processors)
 
-	return ext{repo: repo, extractRepo: extractRepo, generator: g, processors: processors, log: log}, nil
+	return ext{
+		repo: repo, extractRepo: extractRepo, feedRepo: feedRepo,
+		generator: g, processors: processors, client: client, secret: secret, log: log,
+	}, nil
 }
 
 func (t ext) Generate(a content.Article) error {
@@ -43,12 +56,24 @@ func (t ext) Generate(a content.Article) error {
 	t.log.Debugf("Generating thumbnail for article %s from extract", a)
 
 	thumbnail.Thumbnail, thumbnail.Link =
-		generateThumbnailFromDescription(strings.NewReader(a.Description))
+		generateThumbnailFromDescription(strings.NewReader(a.Description), t.client)
 
 	if thumbnail.Link == "" {
 		t.log.Debugf("%s description doesn't contain suitable link, getting extract\n", a)
 
-		extract, err := extract.Get(a, t.extractRepo, t.generator, t.processors)
+		feed, err := t.feedRepo.Get(a.FeedID, content.User{})
+		if err != nil {
+			return errors.WithMessage(err, fmt.Sprintf("getting feed of article %s", a))
+		}
+
+		auth, err := feed.Auth(t.secret)
+		if err != nil {
+			return errors.WithMessage(err, fmt.Sprintf("getting auth of feed %s", feed))
+		}
+
+		auth = auth.ForLink(feed.Link, a.Link)
+
+		extract, err := extract.Get(a, auth, t.extractRepo, t.generator, t.processors)
 		if err != nil {
 			return errors.WithMessage(err, fmt.Sprintf("getting article extract for %s", a))
 		}
@@ -57,7 +82,7 @@ func (t ext) Generate(a content.Article) error {
 			t.log.Debugf("Extract for %s doesn't contain a top image", a)
 		} else {
 			t.log.Debugf("Generating thumbnail from top image %s of %s\n", extract.TopImage, a)
-			thumbnail.Thumbnail = generateThumbnailFromImageLink(extract.TopImage)
+			thumbnail.Thumbnail = generateThumbnailFromImageLink(extract.TopImage, t.client)
 			thumbnail.Link = extract.TopImage
 		}
 	}
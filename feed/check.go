@@ -0,0 +1,213 @@
+package feed
+
+import (
+	"fmt"
+	"net/http"
+	"sort"
+	"strings"
+
+	"github.com/urandom/readeef/content"
+	"github.com/urandom/readeef/content/processor"
+	"github.com/urandom/readeef/parser"
+	"github.com/urandom/readeef/pool"
+)
+
+// Report describes how the content of a feed was downloaded, parsed and
+// processed, to help diagnose feeds that fail to update.
+type Report struct {
+	Link string `json:"link"`
+
+	Status int         `json:"status,omitempty"`
+	Header http.Header `json:"header,omitempty"`
+
+	// Format is the format of the feed content, as detected by the parser
+	// chain.
+	Format string `json:"format,omitempty"`
+	// Diagnostic describes the charset conversion and repairs needed to
+	// parse the content.
+	Diagnostic string `json:"diagnostic,omitempty"`
+	// Discovered are the feed links found in the content, if it is an html
+	// page instead of a feed.
+	Discovered []string `json:"discovered,omitempty"`
+
+	Articles int `json:"articles"`
+	// DateFailures are the articles whose date could not be parsed.
+	DateFailures []string `json:"dateFailures,omitempty"`
+	// DuplicateGuids are the guids shared by more than one article.
+	DuplicateGuids []string `json:"duplicateGuids,omitempty"`
+
+	Processors []ProcessorReport `json:"processors,omitempty"`
+
+	Error string `json:"error,omitempty"`
+}
+
+// ProcessorReport lists the articles changed by a feed processor.
+type ProcessorReport struct {
+	Name    string   `json:"name"`
+	Changed []string `json:"changed,omitempty"`
+}
+
+// reportHeaders are the response headers included in a report.
+var reportHeaders = []string{
+	"Age", "Cache-Control", "Content-Encoding", "Content-Length", "Content-Type",
+	"Date", "Etag", "Expires", "Last-Modified", "Link", "Retry-After", "Server", "Vary",
+}
+
+// feedFormats are the parsers of the scheduler chain, in the order they are
+// tried.
+var feedFormats = []struct {
+	name  string
+	parse func([]byte) (parser.Feed, error)
+}{
+	{"json", parser.ParseJSON},
+	{"rss 2.0", parser.ParseRss2},
+	{"atom", parser.ParseAtom},
+	{"rss 1.0", parser.ParseRss1},
+}
+
+// Check downloads and parses the feed the same way its scheduled updates
+// do, and runs the processors on the parsed content. Content that is not a
+// feed is searched for feed links instead. Unlike the updates, the content
+// is always downloaded, ignoring any cache validators.
+func (s Scheduler) Check(feed content.Feed, processors []processor.Feed) Report {
+	report := Report{Link: feed.Link}
+
+	buf := pool.Buffer.Get()
+	defer pool.Buffer.Put(buf)
+
+	data, resp, _ := s.download(feed, downloadState{link: feed.Link}, buf)
+	if resp != nil {
+		report.Status = resp.StatusCode
+		report.Header = reportHeader(resp.Header)
+	}
+
+	if data.message != "" {
+		report.Error = data.message
+
+		// Content that is not a feed may link to one.
+		if resp != nil && resp.StatusCode == http.StatusOK && feed.Scrape.Empty() {
+			report.Discovered = s.discover(feed)
+		}
+
+		return report
+	}
+
+	pf := data.Feed
+
+	report.Format = contentFormat(feed, resp.Header.Get("Content-Type"), buf.Bytes())
+	report.Diagnostic = pf.Diagnostic()
+	report.Articles = len(pf.Articles)
+
+	guids := map[string]int{}
+	for _, a := range pf.Articles {
+		if a.DateInferred {
+			report.DateFailures = append(report.DateFailures, articleName(a))
+		}
+
+		if a.Guid != "" {
+			guids[a.Guid]++
+		}
+	}
+
+	for guid, count := range guids {
+		if count > 1 {
+			report.DuplicateGuids = append(report.DuplicateGuids, guid)
+		}
+	}
+	sort.Strings(report.DuplicateGuids)
+
+	for _, p := range processors {
+		report.Processors = append(report.Processors, processFeed(p, &pf))
+	}
+
+	return report
+}
+
+// reportHeader returns the response headers that are relevant when
+// diagnosing a feed. The rest, such as cookies, are left out of the report.
+func reportHeader(header http.Header) http.Header {
+	report := http.Header{}
+	for _, name := range reportHeaders {
+		if values, ok := header[name]; ok {
+			report[name] = values
+		}
+	}
+
+	return report
+}
+
+// discover returns the feed links found by searching the feed link.
+func (s Scheduler) discover(feed content.Feed) []string {
+	auth, err := feed.Auth(s.secret)
+	if err != nil {
+		return nil
+	}
+
+	feeds, err := Search(feed.Link, auth, s.client, s.log)
+	if err != nil {
+		return nil
+	}
+
+	var links []string
+	for link := range feeds {
+		if link != feed.Link {
+			links = append(links, link)
+		}
+	}
+	sort.Strings(links)
+
+	return links
+}
+
+// contentFormat returns the name of the first parser of the chain that
+// accepts the content.
+func contentFormat(feed content.Feed, contentType string, b []byte) string {
+	if !feed.Scrape.Empty() {
+		return "scraped html"
+	}
+
+	for _, f := range feedFormats {
+		if _, err := parser.ParseContent(b, contentType, f.parse); err == nil {
+			return f.name
+		}
+	}
+
+	return ""
+}
+
+// processFeed runs the processor on the feed, reporting the articles it
+// changed.
+func processFeed(p processor.Feed, pf *parser.Feed) ProcessorReport {
+	report := ProcessorReport{Name: strings.TrimPrefix(fmt.Sprintf("%T", p), "processor.")}
+
+	// Processors may change the articles in place.
+	original := make([]parser.Article, len(pf.Articles))
+	copy(original, pf.Articles)
+
+	*pf = p.ProcessFeed(*pf)
+
+	for i := range pf.Articles {
+		if i >= len(original) {
+			break
+		}
+
+		a, o := pf.Articles[i], original[i]
+		if a.Title != o.Title || a.Link != o.Link || a.Description != o.Description || a.Summary != o.Summary {
+			report.Changed = append(report.Changed, articleName(a))
+		}
+	}
+
+	return report
+}
+
+func articleName(a parser.Article) string {
+	if a.Link != "" {
+		return a.Link
+	}
+
+	if a.Guid != "" {
+		return a.Guid
+	}
+
+	return a.Title
+}
@@ -0,0 +1,130 @@
+package feed
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/urandom/readeef/config"
+	"github.com/urandom/readeef/content"
+	"github.com/urandom/readeef/content/processor"
+	"github.com/urandom/readeef/log"
+	"github.com/urandom/readeef/parser"
+)
+
+type upperTitles struct{}
+
+func (p upperTitles) ProcessFeed(f parser.Feed) parser.Feed {
+	for i := range f.Articles {
+		if strings.HasPrefix(f.Articles[i].Title, "Second") {
+			f.Articles[i].Title = strings.ToUpper(f.Articles[i].Title)
+		}
+	}
+
+	return f
+}
+
+func TestScheduler_Check(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/feed":
+			w.Header().Set("Content-Type", "application/rss+xml; charset=utf-8")
+			w.Header().Set("Set-Cookie", "session=secret")
+			w.Write([]byte(checkRss))
+		case "/page":
+			w.Write([]byte(`<html><head><link rel="alternate" type="application/rss+xml" href="/feed"></head></html>`))
+		case "/blog":
+			w.Write([]byte(scrapeHTML))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer ts.Close()
+
+	tests := []struct {
+		name string
+		feed content.Feed
+		want Report
+	}{
+		{"feed", content.Feed{Link: ts.URL + "/feed"}, Report{
+			Link: ts.URL + "/feed", Status: http.StatusOK, Format: "rss 2.0", Articles: 3,
+			DateFailures:   []string{"http://example.com/third"},
+			DuplicateGuids: []string{"guid"},
+			Processors:     []ProcessorReport{{Name: "feed.upperTitles", Changed: []string{"http://example.com/second"}}},
+		}},
+		{"html page", content.Feed{Link: ts.URL + "/page"}, Report{
+			Link: ts.URL + "/page", Status: http.StatusOK,
+			Discovered: []string{ts.URL + "/feed"},
+		}},
+		{"scraped", content.Feed{Link: ts.URL + "/blog", Scrape: content.ScrapeRules{Item: "article", Title: "h2", Date: "time"}}, Report{
+			Link: ts.URL + "/blog", Status: http.StatusOK, Format: "scraped html", Articles: 2,
+			DateFailures: []string{ts.URL + "/second"},
+			Processors:   []ProcessorReport{{Name: "feed.upperTitles", Changed: []string{ts.URL + "/second"}}},
+		}},
+		{"missing", content.Feed{Link: ts.URL + "/missing"}, Report{
+			Link: ts.URL + "/missing", Status: http.StatusNotFound, Error: "HTTP Status: 404",
+		}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := config.Log{}
+			cfg.Converted.Writer = os.Stderr
+			s := Scheduler{
+				client: &http.Client{Timeout: time.Second},
+				log:    log.WithStd(cfg),
+			}
+
+			got := s.Check(tt.feed, []processor.Feed{upperTitles{}})
+
+			if got.Header == nil {
+				t.Errorf("Scheduler.Check() no response header")
+			}
+
+			if got.Header.Get("Set-Cookie") != "" {
+				t.Errorf("Scheduler.Check() response header includes cookies")
+			}
+
+			if got.Status == http.StatusOK && got.Header.Get("Content-Type") == "" {
+				t.Errorf("Scheduler.Check() response header without a content type")
+			}
+			got.Header = nil
+
+			if got.Error != "" && tt.want.Error == "" {
+				tt.want.Error = got.Error
+			}
+
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Scheduler.Check() = %#v, want %#v", got, tt.want)
+			}
+		})
+	}
+}
+
+const checkRss = `<?xml version="1.0"?>
+<rss version="2.0">
+	<channel>
+		<title>Check</title>
+		<link>http://example.com/</link>
+		<item>
+			<title>First</title>
+			<link>http://example.com/first</link>
+			<guid>guid</guid>
+			<pubDate>Tue, 03 Jun 2003 09:39:21 GMT</pubDate>
+		</item>
+		<item>
+			<title>Second</title>
+			<link>http://example.com/second</link>
+			<guid>guid</guid>
+			<pubDate>Tue, 03 Jun 2003 10:39:21 GMT</pubDate>
+		</item>
+		<item>
+			<title>Third</title>
+			<link>http://example.com/third</link>
+			<pubDate>yesterday</pubDate>
+		</item>
+	</channel>
+</rss>`
@@ -0,0 +1,115 @@
+package feed
+
+import (
+	"context"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/urandom/readeef/content"
+)
+
+// refreshLimit is the minimum time between two manual refreshes of the same
+// feed.
+const refreshLimit = time.Minute
+
+var ErrNotScheduled = errors.New("feed not scheduled for updates")
+
+// RefreshLimitError is returned when a feed is refreshed again too soon.
+type RefreshLimitError struct {
+	RetryAfter time.Duration
+}
+
+func (e RefreshLimitError) Error() string {
+	return "feed refreshed too recently, retry after " + e.RetryAfter.String()
+}
+
+// RefreshResult is the outcome of a manual feed refresh.
+type RefreshResult struct {
+	// Updated is set when the feed had new content.
+	Updated bool
+
+	// NewArticles is the number of articles that were added by the refresh.
+	NewArticles int
+
+	// Err is the download or parse error of the refresh, if any.
+	Err error
+}
+
+type refreshRequest struct {
+	result chan RefreshResult
+}
+
+// Refresh downloads the feed right away, instead of waiting for its next
+// update, and returns the result once it has been processed. The feed is
+// then updated at its usual interval.
+func (s Scheduler) Refresh(ctx context.Context, id content.FeedID) (RefreshResult, error) {
+	errc := make(chan error, 1)
+	req := refreshRequest{result: make(chan RefreshResult, 1)}
+
+	var refresh chan refreshRequest
+	op := func(feedMap feedMap) {
+		payload, ok := feedMap[id]
+		if !ok {
+			errc <- ErrNotScheduled
+			return
+		}
+
+		now := time.Now()
+		if wait := payload.lastRefresh.Add(refreshLimit).Sub(now); wait > 0 {
+			errc <- RefreshLimitError{RetryAfter: wait}
+			return
+		}
+
+		payload.lastRefresh = now
+		feedMap[id] = payload
+		refresh = payload.refresh
+
+		errc <- nil
+	}
+
+	select {
+	case s.ops <- op:
+	case <-ctx.Done():
+		return RefreshResult{}, ctx.Err()
+	}
+
+	if err := <-errc; err != nil {
+		return RefreshResult{}, err
+	}
+
+	select {
+	case refresh <- req:
+	case <-ctx.Done():
+		return RefreshResult{}, ctx.Err()
+	}
+
+	select {
+	case result := <-req.result:
+		return result, nil
+	case <-ctx.Done():
+		return RefreshResult{}, ctx.Err()
+	}
+}
+
+// reply sends the result of the refresh, after waiting for the update to be
+// processed, if it was sent. Requests made by the scheduler itself have no
+// result to send.
+func (r refreshRequest) reply(ctx context.Context, data UpdateData, sent bool) {
+	if r.result == nil {
+		return
+	}
+
+	result := RefreshResult{Updated: data.IsUpdated()}
+	if data.IsErr() {
+		result.Err = data
+	}
+
+	if sent {
+		select {
+		case result.NewArticles = <-data.processed:
+		case <-ctx.Done():
+		}
+	}
+
+	r.result <- result
+}
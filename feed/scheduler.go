@@ -6,10 +6,15 @@ import (
 	"crypto/md5"
 	"io"
 	"io/ioutil"
+	"math/rand"
 	"net/http"
+	"net/url"
+	"sort"
 	"strconv"
+	"sync"
 	"time"
 
+	"github.com/urandom/readeef/config"
 	"github.com/urandom/readeef/content"
 	"github.com/urandom/readeef/log"
 	"github.com/urandom/readeef/parser"
@@ -17,21 +22,95 @@ import (
 )
 
 type Scheduler struct {
-	ops    chan feedOp
-	client *http.Client
-	log    log.Log
+	ops         chan feedOp
+	client      *http.Client
+	minInterval time.Duration
+	maxInterval time.Duration
+	nextUpdates *updateTimes
+	workers     chan struct{}
+	hosts       *hostLimiter
+	pushed      *pushedFeeds
+	random      *rand.Rand
+	secret      []byte
+	unchanged   func(content.Feed, UpdateData)
+	log         log.Log
 }
 
 type UpdateData struct {
-	Feed    parser.Feed
-	message string
+	Feed parser.Feed
+
+	// ETag and LastModified are the cache validators sent along with the
+	// feed content, set whenever the content was downloaded, even if it
+	// was unchanged.
+	ETag         string
+	LastModified string
+
+	// Link is the new location of a permanently redirected feed.
+	Link string
+
+	// Dead is set when the feed is no longer available, after which it is
+	// not updated anymore.
+	Dead bool
+
+	// Date, Duration, Status and Bytes describe the download: when it
+	// started, how long it took, the http status of the response and the
+	// size of its content.
+	Date     time.Time
+	Duration time.Duration
+	Status   int
+	Bytes    int64
+
+	message   string
+	processed chan int
 }
 
-func NewScheduler(log log.Log) Scheduler {
+const (
+	// publishSamples is the number of most recent article dates used to
+	// estimate how often a feed publishes.
+	publishSamples = 10
+
+	// maxBackoffShift caps the exponential backoff multiplier.
+	maxBackoffShift = 10
+
+	// deadAfter is the number of consecutive 404 or 410 responses after
+	// which a feed is considered dead.
+	deadAfter = 5
+)
+
+// NewScheduler creates a scheduler that adapts the update interval of each
+// feed to its publishing rate and failures, keeping it between the
+// configured minimum and maximum. A zero maximum disables the adaptation to
+// the publishing rate. Feeds pushed by a hub are only polled at the maximum
+// interval.
+//
+// The number of simultaneous downloads, in total and per host, is limited
+// by the configured concurrency, with zero meaning no limit. The first
+// download of each feed is delayed by a random part of its interval, so
+// that feeds scheduled together do not all get downloaded at once.
+//
+// Feeds are downloaded with the given client, and the secret is used to
+// decrypt the credentials of private feeds. Downloads that find a feed
+// unchanged are not sent as updates, but are passed to the unchanged
+// function instead, if one is given.
+func NewScheduler(config config.FeedManager, client *http.Client, secret []byte, unchanged func(content.Feed, UpdateData), log log.Log) Scheduler {
+	var workers chan struct{}
+	if config.FetchConcurrency > 0 {
+		workers = make(chan struct{}, config.FetchConcurrency)
+	}
+
 	return Scheduler{
-		ops:    make(chan feedOp),
-		client: &http.Client{Timeout: 30 * time.Second},
-		log:    log,
+		ops:         make(chan feedOp),
+		client:      client,
+		minInterval: config.Converted.MinUpdateInterval,
+		maxInterval: config.Converted.MaxUpdateInterval,
+		nextUpdates: newUpdateTimes(),
+		workers:     workers,
+		hosts:       newHostLimiter(config.HostConcurrency),
+		pushed:      &pushedFeeds{feeds: map[content.FeedID]bool{}},
+		random:      rand.New(rand.NewSource(time.Now().UnixNano())),
+		secret:      secret,
+		unchanged:   unchanged,
+		log:         log,
 	}
 }
 
@@ -39,16 +118,79 @@ type feedOp func(feedMap)
 type feedMap map[content.FeedID]schedulePayload
 
 type schedulePayload struct {
-	feed       content.Feed
-	update     time.Duration
-	updateData chan UpdateData
+	feed        content.Feed
+	update      time.Duration
+	updateData  chan UpdateData
+	refresh     chan refreshRequest
+	lastRefresh time.Time
+}
+
+// downloadState holds what is known about the last downloaded content of a
+// feed, in order to detect unchanged content and to pick the time of the
+// next download.
+type downloadState struct {
+	link         string
+	checked      bool
+	contentHash  []byte
+	etag         string
+	lastModified string
+
+	failures   int
+	missing    int
+	retryAfter time.Duration
+	ttl        time.Duration
+	published  []time.Time
+
+	status int
+	bytes  int64
+}
+
+type updateTimes struct {
+	sync.RWMutex
+	times map[content.FeedID]time.Time
+}
+
+func newUpdateTimes() *updateTimes {
+	return &updateTimes{times: map[content.FeedID]time.Time{}}
+}
+
+// pushedFeeds holds the feeds whose updates are pushed by a hub.
+type pushedFeeds struct {
+	sync.RWMutex
+	feeds map[content.FeedID]bool
+}
+
+// hostLimiter limits the number of simultaneous downloads from each host.
+type hostLimiter struct {
+	sync.Mutex
+	limit int
+	hosts map[string]*hostSlots
+}
+
+type hostSlots struct {
+	slots chan struct{}
+	users int
+}
+
+func newHostLimiter(limit int) *hostLimiter {
+	if limit <= 0 {
+		return nil
+	}
+
+	return &hostLimiter{limit: limit, hosts: map[string]*hostSlots{}}
 }
 
+// ScheduleFeed starts the periodic updates of the feed, which are sent to
+// the returned channel until the context is done. The channel of a feed
+// that is already scheduled is closed right away.
 func (s Scheduler) ScheduleFeed(ctx context.Context, feed content.Feed, update time.Duration) <-chan UpdateData {
 	ret := make(chan UpdateData)
 
 	s.ops <- func(feedMap feedMap) {
 		if _, ok := feedMap[feed.ID]; ok {
+			// The feed's updates are sent to the channel returned when
+			// it was first scheduled.
+			close(ret)
 			return
 		}
 
@@ -56,37 +198,151 @@ func (s Scheduler) ScheduleFeed(ctx context.Context, feed content.Feed, update t
 			feed:       feed,
 			update:     update,
 			updateData: ret,
+			refresh:    make(chan refreshRequest),
 		}
 		feedMap[feed.ID] = payload
 
-		go s.updateFeed(ctx, payload, []byte{})
+		var delay time.Duration
+		if s.random != nil && update > 0 {
+			delay = time.Duration(s.random.Int63n(int64(update)))
+		}
+
+		go s.startFeed(ctx, payload, delay)
 	}
 
 	return ret
 }
 
+// NextUpdate returns the time at which the feed is going to be downloaded
+// next, if it is scheduled.
+func (s Scheduler) NextUpdate(id content.FeedID) (time.Time, bool) {
+	s.nextUpdates.RLock()
+	defer s.nextUpdates.RUnlock()
+
+	t, ok := s.nextUpdates.times[id]
+	return t, ok
+}
+
+// SetPushed marks whether a hub pushes the updates of the feed. When a feed
+// stops being pushed, it is downloaded right away and then polled as usual.
+func (s Scheduler) SetPushed(id content.FeedID, pushed bool) {
+	if wasPushed := s.pushed.set(id, pushed); !wasPushed || pushed {
+		return
+	}
+
+	s.ops <- func(feedMap feedMap) {
+		payload, ok := feedMap[id]
+		if !ok {
+			return
+		}
+
+		s.log.Infof("Feed %s is no longer pushed, polling it", payload.feed)
+
+		go func() {
+			select {
+			case payload.refresh <- refreshRequest{}:
+			case <-time.After(time.Minute):
+			}
+		}()
+	}
+}
+
 func (s Scheduler) unscheduleFeed(ctx context.Context, feed content.Feed) {
 	s.ops <- func(feedMap feedMap) {
 		s.log.Infof("Unscheduling updates for feed %s", feed)
 		payload := feedMap[feed.ID]
+
+		s.nextUpdates.Lock()
+		delete(s.nextUpdates.times, feed.ID)
+		s.nextUpdates.Unlock()
+
+		s.pushed.set(feed.ID, false)
+
 		close(payload.updateData)
 
 		delete(feedMap, feed.ID)
 	}
 }
 
-func (s Scheduler) updateFeed(ctx context.Context, payload schedulePayload, contentHash []byte) {
+func (s Scheduler) startFeed(ctx context.Context, payload schedulePayload, delay time.Duration) {
+	feed := payload.feed
+	state := downloadState{link: feed.Link, etag: feed.ETag, lastModified: feed.LastModified, ttl: feed.TTL}
+
+	if delay > 0 {
+		s.log.Debugf("Delaying the first update of feed %s by %s", feed, delay)
+		s.setNextUpdate(feed.ID, time.Now().Add(delay))
+
+		select {
+		case <-ctx.Done():
+			s.unscheduleFeed(ctx, feed)
+			return
+		case req := <-payload.refresh:
+			s.updateFeed(ctx, payload, state, &req)
+			return
+		case <-time.After(delay):
+		}
+	}
+
+	s.updateFeed(ctx, payload, state, nil)
+}
+
+// updateFeed downloads the feed and waits for the next update. When the
+// download was requested by a manual refresh, its result is sent back to
+// the requester.
+func (s Scheduler) updateFeed(ctx context.Context, payload schedulePayload, state downloadState, req *refreshRequest) {
 	select {
 	case <-ctx.Done():
 		s.unscheduleFeed(ctx, payload.feed)
 		return
 	default:
 		var data UpdateData
+		var revalidated bool
 		feed := payload.feed
 		now := time.Now()
 
-		if len(contentHash) == 0 || (!feed.SkipHours[now.Hour()] && !feed.SkipDays[now.Weekday().String()]) {
-			data, contentHash = s.downloadFeed(payload, contentHash)
+		if req != nil || !state.checked || (!feed.SkipHours[now.Hour()] && !feed.SkipDays[now.Weekday().String()]) {
+			release, ok := s.Acquire(ctx, feed.Link)
+			if !ok {
+				s.unscheduleFeed(ctx, feed)
+				return
+			}
+
+			start := time.Now()
+			etag, lastModified := state.etag, state.lastModified
+			data, state = s.downloadFeed(payload, state)
+			release()
+
+			// New validators for unchanged content are sent as well, so
+			// that they are stored with the feed.
+			revalidated = state.etag != etag || state.lastModified != lastModified
+
+			data.Date, data.Duration = start, time.Since(start)
+			data.Status, data.Bytes = state.status, state.bytes
+
+			if data.IsErr() {
+				state.failures++
+			} else {
+				state.failures = 0
+			}
+
+			if state.link != feed.Link {
+				s.log.Infof("Feed %s moved permanently to %s", feed, state.link)
+
+				// The credentials of the feed are not sent to another
+				// site.
+				if !content.SameOriginLinks(feed.Link, state.link) {
+					payload.feed.Credentials = nil
+				}
+
+				data.Link = state.link
+				payload.feed.Link = state.link
+			}
+
+			if state.missing >= deadAfter {
+				s.log.Infof("Feed %s is gone", feed)
+
+				data.Dead = true
+			}
 		}
 
 		select {
@@ -95,57 +351,430 @@ func (s Scheduler) updateFeed(ctx context.Context, payload schedulePayload, cont
 			return
 		default:
 			s.log.Debugf("Sending update data for feed %s", payload.feed)
-			if data.isUpdated() || data.IsErr() {
+			sent := data.IsUpdated() || data.IsErr() || data.Link != "" || revalidated
+			if sent {
+				if req != nil {
+					data.processed = make(chan int, 1)
+				}
+
 				payload.updateData <- data
+			} else if s.unchanged != nil && !data.Date.IsZero() {
+				s.unchanged(payload.feed, data)
+			}
+
+			if req != nil {
+				req.reply(ctx, data, sent)
 			}
 
-			<-time.After(payload.update)
-			s.updateFeed(ctx, payload, contentHash)
+			if data.Dead {
+				s.unscheduleFeed(ctx, payload.feed)
+				return
+			}
+
+			interval := s.nextInterval(payload.update, state, time.Now())
+			if s.pushed.has(feed.ID) && interval < s.maxInterval {
+				interval = s.maxInterval
+			}
+			s.log.Debugf("Next update of feed %s in %s", payload.feed, interval)
+
+			s.setNextUpdate(feed.ID, time.Now().Add(interval))
+
+			select {
+			case <-ctx.Done():
+				s.unscheduleFeed(ctx, payload.feed)
+				return
+			case req := <-payload.refresh:
+				s.updateFeed(ctx, payload, state, &req)
+			case <-time.After(interval):
+				s.updateFeed(ctx, payload, state, nil)
+			}
 		}
 	}
 }
 
-func (s Scheduler) downloadFeed(payload schedulePayload, contentHash []byte) (UpdateData, []byte) {
-	feed := payload.feed
+// Acquire waits for a free download slot, both overall and for the host of
+// the link, so that other downloads share the limits of the feed updates.
+// The returned function releases the slots.
+func (s Scheduler) Acquire(ctx context.Context, link string) (func(), bool) {
+	var host string
+	if u, err := url.Parse(link); err == nil {
+		host = u.Host
+	}
+
+	if !s.hosts.acquire(ctx, host) {
+		return nil, false
+	}
+
+	if s.workers != nil {
+		select {
+		case s.workers <- struct{}{}:
+		case <-ctx.Done():
+			s.hosts.release(host)
+			return nil, false
+		}
+	}
+
+	return func() {
+		if s.workers != nil {
+			<-s.workers
+		}
+
+		s.hosts.release(host)
+	}, true
+}
+
+func (s Scheduler) setNextUpdate(id content.FeedID, t time.Time) {
+	s.nextUpdates.Lock()
+	s.nextUpdates.times[id] = t
+	s.nextUpdates.Unlock()
+}
 
+func (s Scheduler) downloadFeed(payload schedulePayload, state downloadState) (UpdateData, downloadState) {
+	buf := pool.Buffer.Get()
+	defer pool.Buffer.Put(buf)
+
+	data, _, state := s.download(payload.feed, state, buf)
+
+	return data, state
+}
+
+// download fetches the feed content into the buffer and parses it. The
+// response is returned, with its body already consumed, if the server
+// responded.
+func (s Scheduler) download(feed content.Feed, state downloadState, buf *bytes.Buffer) (UpdateData, *http.Response, downloadState) {
 	s.log.Infof("Downloading content for feed %s", feed)
-	resp, err := s.client.Get(feed.Link)
+
+	state.retryAfter = 0
+	state.status, state.bytes = 0, 0
+
+	req, auth, err := s.newRequest(feed, state)
+	if err != nil {
+		return UpdateData{message: err.Error()}, nil, state
+	}
+
+	resp, err := auth.Client(s.client).Do(req)
+
+	if err == nil {
+		state.status = resp.StatusCode
+
+		if location := permanentLocation(resp); location != "" {
+			state.link = location
+		}
+
+		if resp.StatusCode == http.StatusNotFound || resp.StatusCode == http.StatusGone {
+			state.missing++
+		} else {
+			state.missing = 0
+		}
+	}
 
 	if err != nil {
-		return UpdateData{message: err.Error()}, contentHash
+		return UpdateData{message: err.Error()}, nil, state
+	} else if resp.StatusCode == http.StatusNotModified {
+		io.Copy(ioutil.Discard, resp.Body)
+		resp.Body.Close()
+
+		s.log.Debugf("Feed %s not modified", feed)
+		state.checked = true
+
+		return UpdateData{}, resp, state
 	} else if resp.StatusCode != http.StatusOK {
 		io.Copy(ioutil.Discard, resp.Body)
 		resp.Body.Close()
 
-		return UpdateData{message: "HTTP Status: " + strconv.Itoa(resp.StatusCode)}, contentHash
+		if resp.StatusCode == http.StatusTooManyRequests || resp.StatusCode == http.StatusServiceUnavailable {
+			state.retryAfter = parseRetryAfter(resp.Header.Get("Retry-After"), time.Now())
+		}
+
+		return UpdateData{message: "HTTP Status: " + strconv.Itoa(resp.StatusCode)}, resp, state
 	} else {
 		defer resp.Body.Close()
 
-		buf := pool.Buffer.Get()
-		defer pool.Buffer.Put(buf)
+		n, err := buf.ReadFrom(resp.Body)
+		state.bytes = n
+
+		if err == nil {
+			state.checked = true
+			state.etag = resp.Header.Get("ETag")
+			state.lastModified = resp.Header.Get("Last-Modified")
 
-		if _, err := buf.ReadFrom(resp.Body); err == nil {
 			hash := md5.Sum(buf.Bytes())
-			if bytes.Equal(contentHash, hash[:]) {
-				return UpdateData{}, contentHash
+			if bytes.Equal(state.contentHash, hash[:]) {
+				return UpdateData{ETag: state.etag, LastModified: state.lastModified}, resp, state
 			}
 
-			contentHash = hash[:]
-			if pf, err := parser.ParseFeed(buf.Bytes(), parser.ParseRss2, parser.ParseAtom, parser.ParseRss1); err == nil {
-				return UpdateData{Feed: pf}, contentHash
+			state.contentHash = hash[:]
+			if pf, err := parseContent(feed, state.link, resp.Header.Get("Content-Type"), buf.Bytes()); err == nil {
+				state.ttl = pf.TTL
+				state.published = publishDates(pf.Articles)
+
+				applyHubLinks(&pf, resp)
+				inferDates(&pf, resp)
+
+				return UpdateData{Feed: pf, ETag: state.etag, LastModified: state.lastModified}, resp, state
 			} else {
-				return UpdateData{message: err.Error()}, contentHash
+				return UpdateData{message: err.Error()}, resp, state
 			}
 		} else {
-			return UpdateData{message: err.Error()}, contentHash
+			return UpdateData{message: err.Error()}, resp, state
 		}
 	}
 }
 
-func (u UpdateData) isUpdated() bool {
+// newRequest creates the request for downloading the feed content from the
+// link of the state, using the cache validators of the last download. The
+// feed credentials are only used, and returned, if the link is on the same
+// site as the feed.
+func (s Scheduler) newRequest(feed content.Feed, state downloadState) (*http.Request, content.FeedAuth, error) {
+	req, err := http.NewRequest("GET", state.link, nil)
+	if err != nil {
+		return nil, content.FeedAuth{}, err
+	}
+
+	auth, err := feed.Auth(s.secret)
+	if err != nil {
+		return nil, content.FeedAuth{}, err
+	}
+
+	auth = auth.ForLink(feed.Link, state.link)
+	auth.Apply(req)
+
+	if state.etag != "" {
+		req.Header.Set("If-None-Match", state.etag)
+	}
+
+	if state.lastModified != "" {
+		req.Header.Set("If-Modified-Since", state.lastModified)
+	}
+
+	return req, auth, nil
+}
+
+// parseContent parses the downloaded content as a feed, or scrapes it as an
+// html page if the feed has scrape rules.
+func parseContent(feed content.Feed, link, contentType string, b []byte) (parser.Feed, error) {
+	if !feed.Scrape.Empty() {
+		return parser.ScrapeHTML(b, link, parser.ScrapeRules(feed.Scrape))
+	}
+
+	return parser.ParseContent(b, contentType, parser.ParseJSON, parser.ParseRss2, parser.ParseAtom, parser.ParseRss1)
+}
+
+// inferDates dates the articles without a valid date using the
+// Last-Modified header of the response, or the current time if the header
+// is missing.
+func inferDates(pf *parser.Feed, resp *http.Response) {
+	t := time.Now()
+	if modified, err := http.ParseTime(resp.Header.Get("Last-Modified")); err == nil && modified.Before(t) {
+		t = modified
+	}
+
+	pf.InferDates(t)
+}
+
+// nextInterval returns the time to wait before downloading the feed again.
+// Consecutive failures back off exponentially from the base interval, or
+// follow the server's Retry-After delay. Otherwise, the interval follows the
+// rate at which the feed's recent articles were published, without going
+// below the feed's own TTL.
+func (s Scheduler) nextInterval(base time.Duration, state downloadState, now time.Time) time.Duration {
+	interval := base
+
+	if state.failures > 0 {
+		shift := state.failures - 1
+		if shift > maxBackoffShift {
+			shift = maxBackoffShift
+		}
+
+		interval = base << uint(shift)
+
+		if state.retryAfter > interval {
+			interval = state.retryAfter
+		}
+	} else if s.maxInterval > 0 && len(state.published) > 0 {
+		oldest := state.published[len(state.published)-1]
+
+		if span := now.Sub(oldest); span > 0 {
+			interval = span / time.Duration(len(state.published))
+		}
+
+		if interval < state.ttl {
+			interval = state.ttl
+		}
+	}
+
+	if s.minInterval > 0 && interval < s.minInterval {
+		interval = s.minInterval
+	}
+
+	if s.maxInterval > 0 && interval > s.maxInterval {
+		interval = s.maxInterval
+	}
+
+	return interval
+}
+
+// permanentLocation returns the location the response was permanently
+// redirected to, following the leading chain of permanent redirects.
+func permanentLocation(resp *http.Response) string {
+	// Requests are chained from the last one, through the redirect
+	// responses that caused them.
+	chain := []*http.Request{}
+	for req := resp.Request; req != nil; {
+		chain = append(chain, req)
+
+		if req.Response == nil {
+			break
+		}
+		req = req.Response.Request
+	}
+
+	location := ""
+	for i := len(chain) - 1; i > 0; i-- {
+		redirect := chain[i-1]
+
+		status := redirect.Response.StatusCode
+		if status != http.StatusMovedPermanently && status != http.StatusPermanentRedirect {
+			break
+		}
+
+		location = redirect.URL.String()
+	}
+
+	return location
+}
+
+// publishDates returns the most recent article dates, newest first.
+func publishDates(articles []parser.Article) []time.Time {
+	dates := make([]time.Time, 0, len(articles))
+	for _, a := range articles {
+		if !a.Date.IsZero() && !a.DateInferred {
+			dates = append(dates, a.Date)
+		}
+	}
+
+	sort.Slice(dates, func(i, j int) bool {
+		return dates[i].After(dates[j])
+	})
+
+	if len(dates) > publishSamples {
+		dates = dates[:publishSamples]
+	}
+
+	return dates
+}
+
+// parseRetryAfter returns the delay specified by a Retry-After header,
+// which is either a number of seconds, or an http date.
+func parseRetryAfter(value string, now time.Time) time.Duration {
+	if value == "" {
+		return 0
+	}
+
+	if seconds, err := strconv.Atoi(value); err == nil {
+		if seconds < 0 {
+			return 0
+		}
+
+		return time.Duration(seconds) * time.Second
+	}
+
+	if t, err := http.ParseTime(value); err == nil && t.After(now) {
+		return t.Sub(now)
+	}
+
+	return 0
+}
+
+// set marks whether the feed is pushed, and returns its previous state.
+func (p *pushedFeeds) set(id content.FeedID, pushed bool) bool {
+	if p == nil {
+		return false
+	}
+
+	p.Lock()
+	defer p.Unlock()
+
+	was := p.feeds[id]
+	if pushed {
+		p.feeds[id] = true
+	} else {
+		delete(p.feeds, id)
+	}
+
+	return was
+}
+
+func (p *pushedFeeds) has(id content.FeedID) bool {
+	if p == nil {
+		return false
+	}
+
+	p.RLock()
+	defer p.RUnlock()
+
+	return p.feeds[id]
+}
+
+func (l *hostLimiter) acquire(ctx context.Context, host string) bool {
+	if l == nil {
+		return true
+	}
+
+	l.Lock()
+	h, ok := l.hosts[host]
+	if !ok {
+		h = &hostSlots{slots: make(chan struct{}, l.limit)}
+		l.hosts[host] = h
+	}
+	h.users++
+	l.Unlock()
+
+	select {
+	case h.slots <- struct{}{}:
+		return true
+	case <-ctx.Done():
+		l.Lock()
+		h.users--
+		if h.users == 0 {
+			delete(l.hosts, host)
+		}
+		l.Unlock()
+
+		return false
+	}
+}
+
+func (l *hostLimiter) release(host string) {
+	if l == nil {
+		return
+	}
+
+	l.Lock()
+	defer l.Unlock()
+
+	h := l.hosts[host]
+	<-h.slots
+
+	h.users--
+	if h.users == 0 {
+		delete(l.hosts, host)
+	}
+}
+
+func (u UpdateData) IsUpdated() bool {
 	return len(u.Feed.Articles) > 0 && !u.IsErr()
 }
 
+// Processed reports that the update has been stored, along with the number
+// of new articles it contained. It should be called for every received
+// update, since a manual refresh waits for it.
+func (u UpdateData) Processed(newArticles int) {
+	if u.processed != nil {
+		u.processed <- newArticles
+	}
+}
+
 func (u UpdateData) IsErr() bool {
 	return u.message != ""
 }
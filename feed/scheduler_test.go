@@ -2,6 +2,7 @@ package feed
 
 import (
 	"context"
+	"fmt"
 	"net/http"
 	"net/http/httptest"
 	"os"
@@ -11,6 +12,7 @@ import (
 	"github.com/urandom/readeef/config"
 	"github.com/urandom/readeef/content"
 	"github.com/urandom/readeef/log"
+	"github.com/urandom/readeef/parser"
 )
 
 func TestScheduler_ScheduleFeed(t *testing.T) {
@@ -32,6 +34,8 @@ func TestScheduler_ScheduleFeed(t *testing.T) {
 		{"404", time.Second, args{2 * time.Second, content.Feed{ID: 100, Link: "/404"}, time.Second}, []int{-1}},
 		{"http error then update", time.Second, args{2 * time.Second, content.Feed{ID: 100, Link: "/error-update"}, time.Second}, []int{-1, 2}},
 		{"same content", time.Second, args{2 * time.Second, content.Feed{ID: 100, Link: "/same-content"}, 100 * time.Millisecond}, []int{2, 1}},
+		{"not modified", time.Second, args{2 * time.Second, content.Feed{ID: 100, Link: "/not-modified"}, 100 * time.Millisecond}, []int{2, 1}},
+		{"stored validators", time.Second, args{2 * time.Second, content.Feed{ID: 100, Link: "/not-modified", ETag: `"v1"`}, 100 * time.Millisecond}, []int{1}},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
@@ -60,6 +64,19 @@ func TestScheduler_ScheduleFeed(t *testing.T) {
 					} else {
 						w.Write([]byte(rss2Xmlv2))
 					}
+				case "/not-modified":
+					switch {
+					case iter > 1:
+						w.Header().Set("ETag", `"v2"`)
+						w.Write([]byte(rss2Xmlv2))
+					case r.Header.Get("If-None-Match") == `"v1"`:
+						w.WriteHeader(http.StatusNotModified)
+					case iter == 0:
+						w.Header().Set("ETag", `"v1"`)
+						w.Write([]byte(rss2Xml))
+					default:
+						w.WriteHeader(http.StatusInternalServerError)
+					}
 				}
 				iter++
 			}))
@@ -71,9 +88,10 @@ func TestScheduler_ScheduleFeed(t *testing.T) {
 			cfg := config.Log{}
 			cfg.Converted.Writer = os.Stderr
 			s := Scheduler{
-				ops:    make(chan feedOp),
-				client: &http.Client{Timeout: tt.connectTimeout},
-				log:    log.WithStd(cfg),
+				ops:         make(chan feedOp),
+				client:      &http.Client{Timeout: tt.connectTimeout},
+				nextUpdates: newUpdateTimes(),
+				log:         log.WithStd(cfg),
 			}
 
 			go s.Start(ctx)
@@ -131,7 +149,716 @@ func TestScheduler_ScheduleFeed(t *testing.T) {
 	}
 }
 
+func TestScheduler_ScheduleFeed_scheduled(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	cfg := config.Log{}
+	cfg.Converted.Writer = os.Stderr
+	s := Scheduler{
+		ops:         make(chan feedOp),
+		client:      &http.Client{Timeout: time.Second},
+		nextUpdates: newUpdateTimes(),
+		log:         log.WithStd(cfg),
+	}
+
+	go s.Start(ctx)
+
+	feed := content.Feed{ID: 100, Link: "http://127.0.0.1:0/feed"}
+	scheduled := s.ScheduleFeed(ctx, feed, time.Hour)
+	go func() {
+		for range scheduled {
+		}
+	}()
+
+	select {
+	case _, ok := <-s.ScheduleFeed(ctx, feed, time.Hour):
+		if ok {
+			t.Errorf("Scheduler.ScheduleFeed() unexpected update for an already scheduled feed")
+		}
+	case <-time.After(time.Second):
+		t.Errorf("Scheduler.ScheduleFeed() expected a closed channel for an already scheduled feed")
+	}
+}
+
+func TestScheduler_ScheduleFeed_moved(t *testing.T) {
+	tests := []struct {
+		name     string
+		link     string
+		wantLink string
+	}{
+		{"permanent", "/old", "/feed"},
+		{"permanent chain", "/older", "/feed"},
+		{"temporary", "/temp", ""},
+		{"permanent then temporary", "/old-temp", "/temp"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				switch r.URL.Path {
+				case "/feed":
+					w.Write([]byte(rss2Xml))
+				case "/old":
+					http.Redirect(w, r, "/feed", http.StatusMovedPermanently)
+				case "/older":
+					http.Redirect(w, r, "/old", http.StatusPermanentRedirect)
+				case "/temp":
+					http.Redirect(w, r, "/feed", http.StatusFound)
+				case "/old-temp":
+					http.Redirect(w, r, "/temp", http.StatusMovedPermanently)
+				}
+			}))
+			defer ts.Close()
+
+			ctx, cancel := context.WithCancel(context.Background())
+			defer cancel()
+
+			cfg := config.Log{}
+			cfg.Converted.Writer = os.Stderr
+			s := Scheduler{
+				ops:         make(chan feedOp),
+				client:      &http.Client{Timeout: time.Second},
+				nextUpdates: newUpdateTimes(),
+				log:         log.WithStd(cfg),
+			}
+
+			go s.Start(ctx)
+
+			up := s.ScheduleFeed(ctx, content.Feed{ID: 100, Link: ts.URL + tt.link}, time.Second)
+
+			select {
+			case data := <-up:
+				wantLink := ""
+				if tt.wantLink != "" {
+					wantLink = ts.URL + tt.wantLink
+				}
+
+				if data.Link != wantLink {
+					t.Errorf("Scheduler.ScheduleFeed() link = %q, want %q", data.Link, wantLink)
+				}
+
+				if !data.IsUpdated() {
+					t.Errorf("Scheduler.ScheduleFeed() expected an update")
+				}
+			case <-time.After(time.Second):
+				t.Errorf("Scheduler.ScheduleFeed() timeout waiting for data")
+			}
+		})
+	}
+}
+
+func TestScheduler_ScheduleFeed_dead(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusGone)
+	}))
+	defer ts.Close()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	cfg := config.Log{}
+	cfg.Converted.Writer = os.Stderr
+	s := Scheduler{
+		ops:         make(chan feedOp),
+		client:      &http.Client{Timeout: time.Second},
+		nextUpdates: newUpdateTimes(),
+		log:         log.WithStd(cfg),
+	}
+
+	go s.Start(ctx)
+
+	up := s.ScheduleFeed(ctx, content.Feed{ID: 100, Link: ts.URL + "/feed"}, 5*time.Millisecond)
+
+	for i := 1; ; i++ {
+		select {
+		case data, ok := <-up:
+			if !ok {
+				if i != deadAfter+1 {
+					t.Errorf("Scheduler.ScheduleFeed() unscheduled after %d updates, want %d", i-1, deadAfter)
+				}
+
+				if _, ok := s.NextUpdate(100); ok {
+					t.Errorf("Scheduler.ScheduleFeed() dead feed still has a next update")
+				}
+				return
+			}
+
+			if !data.IsErr() {
+				t.Fatalf("Scheduler.ScheduleFeed() expected an error on update")
+			}
+
+			if data.Dead != (i == deadAfter) {
+				t.Fatalf("Scheduler.ScheduleFeed() update %d dead = %v", i, data.Dead)
+			}
+		case <-time.After(time.Second):
+			t.Fatalf("Scheduler.ScheduleFeed() timeout waiting for data")
+		}
+	}
+}
+
+func TestScheduler_ScheduleFeed_revalidated(t *testing.T) {
+	iter := 0
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		// The content stays the same, but the validators change.
+		w.Header().Set("ETag", fmt.Sprintf(`"v%d"`, iter))
+		w.Write([]byte(rss2Xml))
+		iter++
+	}))
+	defer ts.Close()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	cfg := config.Log{}
+	cfg.Converted.Writer = os.Stderr
+	s := Scheduler{
+		ops:         make(chan feedOp),
+		client:      &http.Client{Timeout: time.Second},
+		nextUpdates: newUpdateTimes(),
+		log:         log.WithStd(cfg),
+	}
+
+	go s.Start(ctx)
+
+	up := s.ScheduleFeed(ctx, content.Feed{ID: 100, Link: ts.URL + "/feed"}, 5*time.Millisecond)
+
+	for i, want := range []string{`"v0"`, `"v1"`} {
+		select {
+		case data := <-up:
+			if data.IsErr() {
+				t.Fatalf("Scheduler.ScheduleFeed() unexpected error = %v", data.Error())
+			}
+
+			if data.IsUpdated() != (i == 0) {
+				t.Errorf("Scheduler.ScheduleFeed() update %d updated = %v", i, data.IsUpdated())
+			}
+
+			if data.ETag != want {
+				t.Errorf("Scheduler.ScheduleFeed() update %d etag = %s, want %s", i, data.ETag, want)
+			}
+		case <-time.After(time.Second):
+			t.Fatalf("Scheduler.ScheduleFeed() timeout waiting for data")
+		}
+	}
+}
+
+func TestScheduler_ScheduleFeed_stats(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(rss2Xml))
+	}))
+	defer ts.Close()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	unchanged := make(chan UpdateData, 1)
+
+	cfg := config.Log{}
+	cfg.Converted.Writer = os.Stderr
+	s := Scheduler{
+		ops:         make(chan feedOp),
+		client:      &http.Client{Timeout: time.Second},
+		nextUpdates: newUpdateTimes(),
+		unchanged: func(feed content.Feed, data UpdateData) {
+			select {
+			case unchanged <- data:
+			default:
+			}
+		},
+		log: log.WithStd(cfg),
+	}
+
+	go s.Start(ctx)
+
+	start := time.Now()
+	up := s.ScheduleFeed(ctx, content.Feed{ID: 100, Link: ts.URL + "/feed"}, 5*time.Millisecond)
+
+	check := func(data UpdateData) {
+		if data.Date.Before(start) || data.Duration <= 0 {
+			t.Errorf("Scheduler.ScheduleFeed() date = %v, duration = %v", data.Date, data.Duration)
+		}
+
+		if data.Status != http.StatusOK {
+			t.Errorf("Scheduler.ScheduleFeed() status = %d, want %d", data.Status, http.StatusOK)
+		}
+
+		if data.Bytes != int64(len(rss2Xml)) {
+			t.Errorf("Scheduler.ScheduleFeed() bytes = %d, want %d", data.Bytes, len(rss2Xml))
+		}
+	}
+
+	select {
+	case data := <-up:
+		if !data.IsUpdated() {
+			t.Fatalf("Scheduler.ScheduleFeed() expected an updated feed")
+		}
+		check(data)
+	case <-time.After(time.Second):
+		t.Fatalf("Scheduler.ScheduleFeed() timeout waiting for data")
+	}
+
+	select {
+	case data := <-up:
+		t.Fatalf("Scheduler.ScheduleFeed() unexpected update for unchanged content: %v", data)
+	case data := <-unchanged:
+		if data.IsUpdated() || data.IsErr() {
+			t.Errorf("Scheduler.ScheduleFeed() unchanged download reported as an update")
+		}
+		check(data)
+	case <-time.After(time.Second):
+		t.Fatalf("Scheduler.ScheduleFeed() timeout waiting for the unchanged download")
+	}
+}
+
+func TestScheduler_ScheduleFeed_auth(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		user, pass, ok := r.BasicAuth()
+		if !ok || user != "user" || pass != "pass" || r.Header.Get("X-Token") != "abc" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+
+		w.Write([]byte(rss2Xml))
+	}))
+	defer ts.Close()
+
+	secret := []byte("secret")
+	tests := []struct {
+		name    string
+		auth    content.FeedAuth
+		secret  []byte
+		wantErr bool
+	}{
+		{"no auth", content.FeedAuth{}, secret, true},
+		{"auth", content.FeedAuth{Username: "user", Password: "pass", Headers: map[string]string{"X-Token": "abc"}}, secret, false},
+		{"wrong secret", content.FeedAuth{Username: "user", Password: "pass", Headers: map[string]string{"X-Token": "abc"}}, []byte("other"), true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx, cancel := context.WithCancel(context.Background())
+			defer cancel()
+
+			cfg := config.Log{}
+			cfg.Converted.Writer = os.Stderr
+			s := Scheduler{
+				ops:         make(chan feedOp),
+				client:      &http.Client{Timeout: time.Second},
+				nextUpdates: newUpdateTimes(),
+				secret:      tt.secret,
+				log:         log.WithStd(cfg),
+			}
+
+			go s.Start(ctx)
+
+			feed := content.Feed{ID: 100, Link: ts.URL}
+			if err := feed.SetAuth(tt.auth, secret); err != nil {
+				t.Fatalf("Feed.SetAuth() error = %v", err)
+			}
+
+			select {
+			case data := <-s.ScheduleFeed(ctx, feed, time.Hour):
+				if data.IsErr() != tt.wantErr {
+					t.Errorf("Scheduler.ScheduleFeed() error = %v, wantErr %v", data.Error(), tt.wantErr)
+				}
+			case <-time.After(time.Second):
+				t.Errorf("Scheduler.ScheduleFeed() timeout waiting for data")
+			}
+		})
+	}
+}
+
+func TestScheduler_ScheduleFeed_authMoved(t *testing.T) {
+	leaked := make(chan string, 10)
+	iter := 0
+	other := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") != "" || r.Header.Get("X-Token") != "" {
+			leaked <- r.URL.Path
+		}
+
+		if iter == 0 {
+			w.Write([]byte(rss2Xml))
+		} else {
+			w.Write([]byte(rss2Xmlv2))
+		}
+		iter++
+	}))
+	defer other.Close()
+
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.Redirect(w, r, other.URL+"/feed", http.StatusMovedPermanently)
+	}))
+	defer ts.Close()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	secret := []byte("secret")
+
+	cfg := config.Log{}
+	cfg.Converted.Writer = os.Stderr
+	s := Scheduler{
+		ops:         make(chan feedOp),
+		client:      &http.Client{Timeout: time.Second},
+		nextUpdates: newUpdateTimes(),
+		secret:      secret,
+		log:         log.WithStd(cfg),
+	}
+
+	go s.Start(ctx)
+
+	feed := content.Feed{ID: 100, Link: ts.URL + "/feed"}
+	if err := feed.SetAuth(content.FeedAuth{Username: "user", Password: "pass", Headers: map[string]string{"X-Token": "abc"}}, secret); err != nil {
+		t.Fatalf("Feed.SetAuth() error = %v", err)
+	}
+
+	up := s.ScheduleFeed(ctx, feed, 5*time.Millisecond)
+
+	// The first download follows the redirect, and the second one uses the
+	// new link directly.
+	for i := 0; i < 2; i++ {
+		select {
+		case data := <-up:
+			if data.IsErr() {
+				t.Fatalf("Scheduler.ScheduleFeed() unexpected error = %v", data.Error())
+			}
+		case <-time.After(time.Second):
+			t.Fatalf("Scheduler.ScheduleFeed() timeout waiting for data")
+		}
+	}
+
+	select {
+	case path := <-leaked:
+		t.Errorf("Scheduler.ScheduleFeed() sent the feed credentials to %s%s", other.URL, path)
+	default:
+	}
+}
+
+func TestScheduler_ScheduleFeed_scrape(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(scrapeHTML))
+	}))
+	defer ts.Close()
+
+	tests := []struct {
+		name      string
+		rules     content.ScrapeRules
+		wantTitle string
+		wantErr   bool
+	}{
+		{"rules", content.ScrapeRules{Item: "article", Title: "h2", Date: "time"}, "First post", false},
+		{"no match", content.ScrapeRules{Item: "section"}, "", true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx, cancel := context.WithCancel(context.Background())
+			defer cancel()
+
+			cfg := config.Log{}
+			cfg.Converted.Writer = os.Stderr
+			s := Scheduler{
+				ops:         make(chan feedOp),
+				client:      &http.Client{Timeout: time.Second},
+				nextUpdates: newUpdateTimes(),
+				log:         log.WithStd(cfg),
+			}
+
+			go s.Start(ctx)
+
+			feed := content.Feed{ID: 100, Link: ts.URL + "/blog/", Scrape: tt.rules}
+
+			select {
+			case data := <-s.ScheduleFeed(ctx, feed, time.Hour):
+				if data.IsErr() != tt.wantErr {
+					t.Errorf("Scheduler.ScheduleFeed() error = %v, wantErr %v", data.Error(), tt.wantErr)
+					return
+				}
+
+				if tt.wantErr {
+					return
+				}
+
+				if len(data.Feed.Articles) != 2 || data.Feed.Articles[0].Title != tt.wantTitle {
+					t.Errorf("Scheduler.ScheduleFeed() articles = %#v", data.Feed.Articles)
+				}
+
+				if want := ts.URL + "/blog/first"; data.Feed.Articles[0].Link != want {
+					t.Errorf("Scheduler.ScheduleFeed() link = %v, want %v", data.Feed.Articles[0].Link, want)
+				}
+			case <-time.After(time.Second):
+				t.Errorf("Scheduler.ScheduleFeed() timeout waiting for data")
+			}
+		})
+	}
+}
+
+func TestScheduler_Refresh(t *testing.T) {
+	iter := 0
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if iter == 0 {
+			w.Write([]byte(rss2Xml))
+		} else {
+			w.Write([]byte(rss2Xmlv2))
+		}
+		iter++
+	}))
+	defer ts.Close()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	cfg := config.Log{}
+	cfg.Converted.Writer = os.Stderr
+	s := Scheduler{
+		ops:         make(chan feedOp),
+		client:      &http.Client{Timeout: time.Second},
+		nextUpdates: newUpdateTimes(),
+		log:         log.WithStd(cfg),
+	}
+
+	go s.Start(ctx)
+
+	up := s.ScheduleFeed(ctx, content.Feed{ID: 100, Link: ts.URL}, time.Hour)
+
+	received := make(chan struct{}, 2)
+	go func() {
+		for data := range up {
+			data.Processed(len(data.Feed.Articles) * 10)
+			received <- struct{}{}
+		}
+	}()
+
+	if _, err := s.Refresh(ctx, 200); err != ErrNotScheduled {
+		t.Errorf("Scheduler.Refresh() error = %v, want %v", err, ErrNotScheduled)
+	}
+
+	select {
+	case <-received:
+	case <-time.After(time.Second):
+		t.Fatalf("Scheduler.ScheduleFeed() timeout waiting for the initial update")
+	}
+
+	refreshCtx, refreshCancel := context.WithTimeout(ctx, time.Second)
+	defer refreshCancel()
+
+	result, err := s.Refresh(refreshCtx, 100)
+	if err != nil {
+		t.Fatalf("Scheduler.Refresh() error = %v", err)
+	}
+
+	if !result.Updated || result.NewArticles != 10 || result.Err != nil {
+		t.Errorf("Scheduler.Refresh() = %#v", result)
+	}
+
+	if next, ok := s.NextUpdate(100); !ok || time.Until(next) < 59*time.Minute {
+		t.Errorf("Scheduler.Refresh() next update = %v, want it rescheduled", next)
+	}
+
+	if _, err := s.Refresh(refreshCtx, 100); err == nil {
+		t.Errorf("Scheduler.Refresh() expected a rate limit error")
+	} else if _, ok := err.(RefreshLimitError); !ok {
+		t.Errorf("Scheduler.Refresh() error = %v, want a rate limit error", err)
+	}
+}
+
+func TestScheduler_SetPushed(t *testing.T) {
+	iter := 0
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if iter == 0 {
+			w.Write([]byte(rss2Xml))
+		} else {
+			w.Write([]byte(rss2Xmlv2))
+		}
+		iter++
+	}))
+	defer ts.Close()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	cfg := config.Log{}
+	cfg.Converted.Writer = os.Stderr
+	s := Scheduler{
+		ops:         make(chan feedOp),
+		client:      &http.Client{Timeout: time.Second},
+		maxInterval: time.Hour,
+		nextUpdates: newUpdateTimes(),
+		pushed:      &pushedFeeds{feeds: map[content.FeedID]bool{}},
+		log:         log.WithStd(cfg),
+	}
+
+	go s.Start(ctx)
+
+	s.SetPushed(100, true)
+	up := s.ScheduleFeed(ctx, content.Feed{ID: 100, Link: ts.URL}, 100*time.Millisecond)
+
+	select {
+	case data := <-up:
+		data.Processed(0)
+	case <-time.After(time.Second):
+		t.Fatalf("Scheduler.ScheduleFeed() timeout waiting for the initial update")
+	}
+
+	time.Sleep(10 * time.Millisecond)
+	if next, ok := s.NextUpdate(100); !ok || time.Until(next) < 59*time.Minute {
+		t.Errorf("Scheduler.SetPushed() next update = %v, want the maximum interval", next)
+	}
+
+	s.SetPushed(100, false)
+
+	select {
+	case data := <-up:
+		data.Processed(0)
+	case <-time.After(time.Second):
+		t.Fatalf("Scheduler.SetPushed() timeout waiting for the fallback update")
+	}
+}
+
+func TestScheduler_nextInterval(t *testing.T) {
+	now := time.Date(2018, time.March, 1, 12, 0, 0, 0, time.UTC)
+	recent := []time.Time{now.Add(-time.Hour), now.Add(-2 * time.Hour), now.Add(-3 * time.Hour), now.Add(-4 * time.Hour)}
+
+	tests := []struct {
+		name  string
+		min   time.Duration
+		max   time.Duration
+		base  time.Duration
+		state downloadState
+		want  time.Duration
+	}{
+		{"fixed", 0, 0, 30 * time.Minute, downloadState{published: recent}, 30 * time.Minute},
+		{"unknown rate", 5 * time.Minute, 12 * time.Hour, 30 * time.Minute, downloadState{}, 30 * time.Minute},
+		{"publishing rate", 5 * time.Minute, 12 * time.Hour, 30 * time.Minute, downloadState{published: recent}, time.Hour},
+		{"frequent", 5 * time.Minute, 12 * time.Hour, 30 * time.Minute, downloadState{published: []time.Time{now.Add(-time.Minute), now.Add(-2 * time.Minute)}}, 5 * time.Minute},
+		{"rare", 5 * time.Minute, 12 * time.Hour, 30 * time.Minute, downloadState{published: []time.Time{now.AddDate(0, -1, 0)}}, 12 * time.Hour},
+		{"ttl", 5 * time.Minute, 12 * time.Hour, 30 * time.Minute, downloadState{published: recent, ttl: 2 * time.Hour}, 2 * time.Hour},
+		{"first failure", 5 * time.Minute, 12 * time.Hour, 30 * time.Minute, downloadState{published: recent, failures: 1}, 30 * time.Minute},
+		{"backoff", 5 * time.Minute, 12 * time.Hour, 30 * time.Minute, downloadState{failures: 3}, 2 * time.Hour},
+		{"backoff limit", 5 * time.Minute, 12 * time.Hour, 30 * time.Minute, downloadState{failures: 100}, 12 * time.Hour},
+		{"retry after", 5 * time.Minute, 12 * time.Hour, 30 * time.Minute, downloadState{failures: 1, retryAfter: 3 * time.Hour}, 3 * time.Hour},
+		{"retry after limit", 5 * time.Minute, 12 * time.Hour, 30 * time.Minute, downloadState{failures: 1, retryAfter: 48 * time.Hour}, 12 * time.Hour},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := Scheduler{minInterval: tt.min, maxInterval: tt.max}
+			if got := s.nextInterval(tt.base, tt.state, now); got != tt.want {
+				t.Errorf("Scheduler.nextInterval() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestScheduler_Acquire(t *testing.T) {
+	s := Scheduler{workers: make(chan struct{}, 2), hosts: newHostLimiter(1)}
+
+	tryAcquire := func(link string) (func(), bool) {
+		ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+		defer cancel()
+
+		return s.Acquire(ctx, link)
+	}
+
+	releaseA, ok := tryAcquire("http://a.example.com/feed")
+	if !ok {
+		t.Fatalf("Scheduler.Acquire() expected a free slot")
+	}
+
+	if _, ok := tryAcquire("http://a.example.com/other"); ok {
+		t.Fatalf("Scheduler.Acquire() expected the host limit to be reached")
+	}
+
+	releaseB, ok := tryAcquire("http://b.example.com/feed")
+	if !ok {
+		t.Fatalf("Scheduler.Acquire() expected a free slot for another host")
+	}
+
+	if _, ok := tryAcquire("http://c.example.com/feed"); ok {
+		t.Fatalf("Scheduler.Acquire() expected the total limit to be reached")
+	}
+
+	releaseA()
+	releaseB()
+
+	release, ok := tryAcquire("http://a.example.com/other")
+	if !ok {
+		t.Fatalf("Scheduler.Acquire() expected a released slot")
+	}
+	release()
+
+	if len(s.hosts.hosts) != 0 {
+		t.Errorf("Scheduler.Acquire() left %d unused hosts", len(s.hosts.hosts))
+	}
+}
+
+func Test_parseRetryAfter(t *testing.T) {
+	now := time.Date(2018, time.March, 1, 12, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name  string
+		value string
+		want  time.Duration
+	}{
+		{"empty", "", 0},
+		{"seconds", "120", 2 * time.Minute},
+		{"negative", "-5", 0},
+		{"date", now.Add(time.Hour).Format(http.TimeFormat), time.Hour},
+		{"past date", now.Add(-time.Hour).Format(http.TimeFormat), 0},
+		{"invalid", "soon", 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := parseRetryAfter(tt.value, now); got != tt.want {
+				t.Errorf("parseRetryAfter() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func Test_inferDates(t *testing.T) {
+	modified := time.Date(2018, time.March, 1, 12, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name     string
+		modified string
+		want     time.Time
+	}{
+		{"last modified", modified.Format(http.TimeFormat), modified},
+		{"future last modified", time.Now().Add(time.Hour).Format(http.TimeFormat), time.Now()},
+		{"no last modified", "", time.Now()},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			resp := &http.Response{Header: http.Header{}}
+			if tt.modified != "" {
+				resp.Header.Set("Last-Modified", tt.modified)
+			}
+
+			pf, err := parser.ParseRss2([]byte(`<rss version="2.0"><channel><title>Feed</title><item><title>Undated</title><link>http://example.com/undated</link></item></channel></rss>`))
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			inferDates(&pf, resp)
+
+			if got := pf.Articles[0].Date; got.Sub(tt.want) > time.Minute || tt.want.Sub(got) > time.Minute {
+				t.Errorf("inferDates() date = %v, want %v", got, tt.want)
+			}
+
+			if !pf.Articles[0].DateInferred {
+				t.Errorf("inferDates() date not marked as inferred")
+			}
+		})
+	}
+}
+
 const (
+	scrapeHTML = `<html>
+<head><title>Blog</title></head>
+<body>
+	<article><h2><a href="first">First post</a></h2><time datetime="2017-05-17T08:02:12Z"></time></article>
+	<article><h2><a href="second">Second post</a></h2></article>
+</body>
+</html>`
+
 	rss2Xml = `
 
 <?xml version="1.0"?>
@@ -0,0 +1,50 @@
+package feed
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/pkg/errors"
+	"github.com/urandom/readeef/content"
+	"github.com/urandom/readeef/log"
+	"github.com/urandom/readeef/parser"
+	"github.com/urandom/readeef/pool"
+)
+
+// Scrape downloads the html page at the link, and converts it into a feed
+// using the scrape rules.
+func Scrape(link string, rules content.ScrapeRules, auth content.FeedAuth, client *http.Client, log log.Log) (parser.Feed, error) {
+	log.Infof("Scraping feed from %s", link)
+
+	req, err := http.NewRequest("GET", link, nil)
+	if err != nil {
+		return parser.Feed{}, errors.Wrapf(err, "creating request for link %s", link)
+	}
+	auth.Apply(req)
+
+	resp, err := auth.Client(client).Do(req)
+	if err != nil {
+		return parser.Feed{}, errors.Wrapf(err, "getting link %s", link)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return parser.Feed{}, errors.Errorf("getting link %s: HTTP Status: %s", link, strconv.Itoa(resp.StatusCode))
+	}
+
+	buf := pool.Buffer.Get()
+	defer pool.Buffer.Put(buf)
+
+	if _, err := buf.ReadFrom(resp.Body); err != nil {
+		return parser.Feed{}, errors.Wrapf(err, "reading content of %s", link)
+	}
+
+	pf, err := parser.ScrapeHTML(buf.Bytes(), link, parser.ScrapeRules(rules))
+	if err != nil {
+		return parser.Feed{}, errors.Wrapf(err, "scraping %s", link)
+	}
+
+	inferDates(&pf, resp)
+
+	return pf, nil
+}
@@ -9,6 +9,7 @@ import (
 
 	"github.com/PuerkitoBio/goquery"
 	"github.com/pkg/errors"
+	"github.com/urandom/readeef/content"
 	"github.com/urandom/readeef/log"
 	"github.com/urandom/readeef/parser"
 	"github.com/urandom/readeef/pool"
@@ -18,34 +19,39 @@ var (
 	domainPattern  = regexp.MustCompile(`^(?:[a-zA-Z0-9-]+\.)+[a-zA-Z]{2,}$`)
 	commentPattern = regexp.MustCompile("<!--.*?-->")
 	linkPattern    = regexp.MustCompile(`<link ([^>]+)>`)
+
+	feedTypes = []string{"application/rss+xml", "application/atom+xml", "application/feed+json"}
 )
 
-func Search(query string, log log.Log) (map[string]parser.Feed, error) {
+// Search looks for feeds at the url given by the query, or via a web search
+// if the query is not a url. The auth is only used when downloading from the
+// host of the url.
+func Search(query string, auth content.FeedAuth, client *http.Client, log log.Log) (map[string]parser.Feed, error) {
 	if u, err := url.Parse(query); err == nil && (u.IsAbs() || domainPattern.MatchString(u.String())) {
 		if u.Scheme == "" {
 			u.Scheme = "http"
 		}
 
-		return searchByURL(u, log)
+		return searchByURL(u, auth, client, log)
 	}
 
 	// Assume the query is not a url
-	return searchByQuery(query, log)
+	return searchByQuery(query, client, log)
 }
 
-func searchByURL(u *url.URL, log log.Log) (map[string]parser.Feed, error) {
+func searchByURL(u *url.URL, auth content.FeedAuth, client *http.Client, log log.Log) (map[string]parser.Feed, error) {
 	log.Infof("Searching for feeds from url %s", u)
 	if u.Scheme == "http" {
 		u.Scheme = "https"
 
-		if feeds, err := downloadLinkContent(u, log); err == nil {
+		if feeds, err := downloadLinkContent(u, auth, client, log); err == nil {
 			return feeds, nil
 		}
 
 		u.Scheme = "http"
 	}
 
-	feeds, err := downloadLinkContent(u, log)
+	feeds, err := downloadLinkContent(u, auth, client, log)
 	if err != nil {
 		return nil, errors.WithMessage(err, "searching by url "+u.String())
 	}
@@ -55,12 +61,18 @@ func searchByURL(u *url.URL, log log.Log) (map[string]parser.Feed, error) {
 	return feeds, nil
 }
 
-func searchByQuery(query string, log log.Log) (map[string]parser.Feed, error) {
+func searchByQuery(query string, client *http.Client, log log.Log) (map[string]parser.Feed, error) {
 	log.Infof("Searching for feeds via %s", query)
-	doc, err := goquery.NewDocument("https://www.google.com/search?q=" + url.QueryEscape(query))
+	resp, err := client.Get("https://www.google.com/search?q=" + url.QueryEscape(query))
 	if err != nil {
 		return nil, errors.Wrapf(err, "querying google with %s", query)
 	}
+	defer resp.Body.Close()
+
+	doc, err := goquery.NewDocumentFromReader(resp.Body)
+	if err != nil {
+		return nil, errors.Wrapf(err, "parsing google results for %s", query)
+	}
 
 	links := doc.Find("div.g .r a").Map(func(i int, s *goquery.Selection) string {
 		href := s.AttrOr("data-href", s.AttrOr("href", ""))
@@ -114,7 +126,7 @@ func searchByQuery(query string, log log.Log) (map[string]parser.Feed, error) {
 	for i := 0; i < numProviders; i++ {
 		go func() {
 			for u := range input {
-				res, err := downloadLinkContent(u, log)
+				res, err := downloadLinkContent(u, content.FeedAuth{}, client, log)
 				output <- out{res, err}
 			}
 			wg.Done()
@@ -151,9 +163,15 @@ func searchByQuery(query string, log log.Log) (map[string]parser.Feed, error) {
 	return parsed, nil
 }
 
-func downloadLinkContent(u *url.URL, log log.Log) (map[string]parser.Feed, error) {
+func downloadLinkContent(u *url.URL, auth content.FeedAuth, client *http.Client, log log.Log) (map[string]parser.Feed, error) {
 	log.Debugf("Downloading content from %s", u)
-	resp, err := http.Get(u.String())
+	req, err := http.NewRequest("GET", u.String(), nil)
+	if err != nil {
+		return nil, errors.Wrapf(err, "creating request for link %s", u)
+	}
+	auth.Apply(req)
+
+	resp, err := auth.Client(client).Do(req)
 	if err != nil {
 		return nil, errors.Wrapf(err, "getting link %s", u)
 	}
@@ -164,7 +182,10 @@ func downloadLinkContent(u *url.URL, log log.Log) (map[string]parser.Feed, error
 
 	buf.ReadFrom(resp.Body)
 
-	if feed, err := parser.ParseFeed(buf.Bytes(), parser.ParseRss2, parser.ParseAtom, parser.ParseRss1); err == nil {
+	if feed, err := parser.ParseContent(buf.Bytes(), resp.Header.Get("Content-Type"), parser.ParseJSON, parser.ParseRss2, parser.ParseAtom, parser.ParseRss1); err == nil {
+		applyHubLinks(&feed, resp)
+		inferDates(&feed, resp)
+
 		return map[string]parser.Feed{u.String(): feed}, nil
 	}
 
@@ -174,7 +195,7 @@ func downloadLinkContent(u *url.URL, log log.Log) (map[string]parser.Feed, error
 	feeds := map[string]parser.Feed{}
 	for _, l := range links {
 		attrs := l[1]
-		if strings.Contains(attrs, `"application/rss+xml"`) || strings.Contains(attrs, `'application/rss+xml'`) {
+		if isFeedLink(attrs) {
 			index := strings.Index(attrs, "href=")
 			attr := attrs[index+6:]
 			index = strings.IndexByte(attr, attrs[index+5])
@@ -192,7 +213,10 @@ func downloadLinkContent(u *url.URL, log log.Log) (map[string]parser.Feed, error
 
 				}
 
-				feedMap, err := downloadLinkContent(docURL, log)
+				// Only send the credentials to the same site
+				docAuth := auth.ForLink(u.String(), docURL.String())
+
+				feedMap, err := downloadLinkContent(docURL, docAuth, client, log)
 				if err != nil {
 					return nil, err
 				}
@@ -206,3 +230,13 @@ func downloadLinkContent(u *url.URL, log log.Log) (map[string]parser.Feed, error
 
 	return feeds, nil
 }
+
+func isFeedLink(attrs string) bool {
+	for _, t := range feedTypes {
+		if strings.Contains(attrs, `"`+t+`"`) || strings.Contains(attrs, `'`+t+`'`) {
+			return true
+		}
+	}
+
+	return false
+}
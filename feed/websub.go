@@ -0,0 +1,98 @@
+package feed
+
+import (
+	"net/http"
+	"strings"
+
+	"github.com/urandom/readeef/parser"
+)
+
+type headerLink struct {
+	url  string
+	rels []string
+}
+
+// applyHubLinks sets the hub and self links of the feed from the Link
+// headers of the response. WebSub publishers may advertise them in either
+// place, and the headers take precedence over the content.
+func applyHubLinks(pf *parser.Feed, resp *http.Response) {
+	hub, self := hubLinks(resp)
+
+	if hub != "" {
+		pf.HubLink = hub
+	}
+
+	if self != "" {
+		pf.SelfLink = self
+	}
+}
+
+// hubLinks returns the first hub and self links of the response Link
+// headers, resolved against the request url.
+func hubLinks(resp *http.Response) (hub, self string) {
+	for _, l := range parseLinkHeaders(resp.Header["Link"]) {
+		link := l.url
+		if resp.Request != nil {
+			if u, err := resp.Request.URL.Parse(link); err == nil {
+				link = u.String()
+			}
+		}
+
+		for _, rel := range l.rels {
+			switch {
+			case strings.EqualFold(rel, "hub") && hub == "":
+				hub = link
+			case strings.EqualFold(rel, "self") && self == "":
+				self = link
+			}
+		}
+	}
+
+	return hub, self
+}
+
+// parseLinkHeaders extracts the links and their relation types from Link
+// header values, such as: <http://hub.example.com>; rel="hub", </feed>;
+// rel=self
+func parseLinkHeaders(values []string) []headerLink {
+	var links []headerLink
+
+	for _, v := range values {
+		for {
+			start := strings.IndexByte(v, '<')
+			if start == -1 {
+				break
+			}
+
+			end := strings.IndexByte(v[start:], '>')
+			if end == -1 {
+				break
+			}
+			end += start
+
+			link := headerLink{url: strings.TrimSpace(v[start+1 : end])}
+
+			// The parameters of the link extend up to the next one.
+			params := v[end+1:]
+			if next := strings.IndexByte(params, '<'); next == -1 {
+				v = ""
+			} else {
+				v = params[next:]
+				params = params[:next]
+			}
+
+			for _, param := range strings.Split(params, ";") {
+				parts := strings.SplitN(param, "=", 2)
+				if len(parts) != 2 || !strings.EqualFold(strings.TrimSpace(parts[0]), "rel") {
+					continue
+				}
+
+				link.rels = strings.Fields(strings.Trim(strings.TrimSpace(parts[1]), `",`))
+			}
+
+			links = append(links, link)
+		}
+	}
+
+	return links
+}
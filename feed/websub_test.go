@@ -0,0 +1,39 @@
+package feed
+
+import (
+	"net/http"
+	"net/url"
+	"testing"
+)
+
+func Test_hubLinks(t *testing.T) {
+	tests := []struct {
+		name     string
+		header   []string
+		wantHub  string
+		wantSelf string
+	}{
+		{"none", nil, "", ""},
+		{"single header", []string{`<http://hub.example.com/>; rel="hub", <http://example.com/feed>; rel="self"`}, "http://hub.example.com/", "http://example.com/feed"},
+		{"multiple headers", []string{`<http://hub.example.com/>; rel=hub`, `<http://example.com/feed>; rel=self`}, "http://hub.example.com/", "http://example.com/feed"},
+		{"relative", []string{`</hub>; rel="hub", </feed.xml>; rel="self"`}, "http://example.com/hub", "http://example.com/feed.xml"},
+		{"multiple rels", []string{`<http://example.com/feed>; title="Feed"; rel="alternate SELF"`}, "", "http://example.com/feed"},
+		{"first hub", []string{`<http://hub1.example.com/>; rel="hub", <http://hub2.example.com/>; rel="hub"`}, "http://hub1.example.com/", ""},
+		{"other rels", []string{`<http://example.com/page/2>; rel="next"`}, "", ""},
+		{"malformed", []string{`http://hub.example.com/; rel="hub"`, `<http://example.com/feed; rel="self"`}, "", ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			u, _ := url.Parse("http://example.com/feed")
+			resp := &http.Response{Header: http.Header{"Link": tt.header}, Request: &http.Request{URL: u}}
+
+			hub, self := hubLinks(resp)
+			if hub != tt.wantHub {
+				t.Errorf("hubLinks() hub = %v, want %v", hub, tt.wantHub)
+			}
+			if self != tt.wantSelf {
+				t.Errorf("hubLinks() self = %v, want %v", self, tt.wantSelf)
+			}
+		})
+	}
+}
@@ -3,6 +3,7 @@ package readeef
 import (
 	"context"
 	"fmt"
+	"net/http"
 	"regexp"
 	"time"
 
@@ -23,9 +24,11 @@ import (
 type FeedManager struct {
 	config           config.Config
 	repo             repo.Feed
+	client           *http.Client
 	ops              chan func(context.Context, *FeedManager)
 	log              log.Log
 	hubbub           *Hubbub
+	updateRepo       repo.FeedUpdate
 	scheduler        feed.Scheduler
 	parserProcessors []processor.Feed
 }
@@ -39,18 +42,30 @@ var (
 	httpStatusPrefix = "HTTP Status: "
 )
 
-func NewFeedManager(repo repo.Feed, c config.Config, l log.Log) *FeedManager {
-	return &FeedManager{
-		repo: repo, config: c, log: l,
-		ops:       make(chan func(context.Context, *FeedManager)),
-		scheduler: feed.NewScheduler(l),
+func NewFeedManager(repo repo.Feed, c config.Config, client *http.Client, l log.Log) *FeedManager {
+	fm := &FeedManager{
+		repo: repo, config: c, client: client, log: l,
+		ops: make(chan func(context.Context, *FeedManager)),
 	}
+
+	fm.scheduler = feed.NewScheduler(c.FeedManager, client, []byte(c.Auth.Secret), func(f content.Feed, data feed.UpdateData) {
+		fm.recordUpdate(f, data, 0, 0)
+	}, l)
+
+	return fm
 }
 
 func (fm *FeedManager) SetHubbub(hubbub *Hubbub) {
 	fm.hubbub = hubbub
 }
 
+// SetUpdateRepo sets the repository in which the outcome of every feed
+// download is recorded. Without it, only the last errors of each feed are
+// kept.
+func (fm *FeedManager) SetUpdateRepo(r repo.FeedUpdate) {
+	fm.updateRepo = r
+}
+
 func (fm *FeedManager) AddFeedProcessor(p processor.Feed) {
 	fm.parserProcessors = append(fm.parserProcessors, p)
 }
@@ -61,6 +76,10 @@ func (fm *FeedManager) Start(ctx context.Context) error {
 	go fm.loop(ctx)
 	go fm.scheduler.Start(ctx)
 
+	if fm.updateRepo != nil && fm.config.FeedManager.Converted.UpdateHistoryRetention > 0 {
+		go fm.pruneUpdates(ctx, fm.config.FeedManager.Converted.UpdateHistoryRetention)
+	}
+
 	feeds, err := fm.repo.Unsubscribed()
 	if err != nil {
 		return err
@@ -87,7 +106,38 @@ func (fm *FeedManager) RemoveFeed(feed content.Feed) {
 	}
 }
 
-func (fm *FeedManager) AddFeedByLink(link string) (content.Feed, error) {
+// RefreshFeed downloads the feed immediately and stores any new content,
+// returning the outcome of the download.
+func (fm *FeedManager) RefreshFeed(ctx context.Context, f content.Feed) (feed.RefreshResult, error) {
+	fm.log.Infof("Refreshing feed %s", f)
+
+	return fm.scheduler.Refresh(ctx, f.ID)
+}
+
+// NextUpdate returns the time of the next scheduled update of the feed.
+func (fm *FeedManager) NextUpdate(feed content.Feed) (time.Time, bool) {
+	return fm.scheduler.NextUpdate(feed.ID)
+}
+
+// Acquire waits for a free download slot for the link, within the limits
+// of the feed updates. The returned function releases the slot.
+func (fm *FeedManager) Acquire(ctx context.Context, link string) (func(), bool) {
+	return fm.scheduler.Acquire(ctx, link)
+}
+
+// SetFeedPushed marks whether a hub pushes the updates of the feed, which
+// is then polled less often.
+func (fm *FeedManager) SetFeedPushed(id content.FeedID, pushed bool) {
+	fm.scheduler.SetPushed(id, pushed)
+}
+
+// AddFeedByLink adds the feed found at the link to the manager. New feeds
+// are downloaded using the auth, which is also stored with them for their
+// future updates. Credentials embedded in the link take precedence over the
+// auth ones.
+func (fm *FeedManager) AddFeedByLink(link string, auth content.FeedAuth) (content.Feed, error) {
+	link, auth = content.SplitLinkAuth(link, auth)
+
 	u, err := url.Parse(link)
 	if err == nil {
 		if !u.IsAbs() {
@@ -104,10 +154,14 @@ func (fm *FeedManager) AddFeedByLink(link string) (content.Feed, error) {
 		return f, err
 	}
 
-	if err != nil {
+	if err == nil {
+		if err = fm.checkExistingAuth(f, auth); err != nil {
+			return content.Feed{}, err
+		}
+	} else {
 		fm.log.Infoln("Discovering feeds in " + link)
 
-		parsedFeeds, err := feed.Search(link, fm.log)
+		parsedFeeds, err := feed.Search(link, auth, fm.client, fm.log)
 		if err != nil {
 			return content.Feed{}, errors.WithMessage(err, "searching for feeds")
 		}
@@ -119,6 +173,10 @@ func (fm *FeedManager) AddFeedByLink(link string) (content.Feed, error) {
 			break
 		}
 
+		if err = f.SetAuth(auth, []byte(fm.config.Auth.Secret)); err != nil {
+			return content.Feed{}, errors.WithMessage(err, "setting feed auth")
+		}
+
 		if _, err = fm.repo.Update(&f); err != nil {
 			return content.Feed{}, errors.WithMessage(err, "updating feed with parsed data")
 		}
@@ -130,6 +188,81 @@ func (fm *FeedManager) AddFeedByLink(link string) (content.Feed, error) {
 	return f, nil
 }
 
+// AddScrapedFeed adds a feed whose articles are scraped from the html page
+// at the link using the rules. The page is downloaded using the auth, which
+// is also stored with the feed for its future updates.
+func (fm *FeedManager) AddScrapedFeed(link string, rules content.ScrapeRules, auth content.FeedAuth) (content.Feed, error) {
+	link, auth = content.SplitLinkAuth(link, auth)
+
+	if err := rules.Validate(); err != nil {
+		return content.Feed{}, err
+	}
+
+	u, err := url.Parse(link)
+	if err != nil {
+		return content.Feed{}, err
+	}
+
+	if !u.IsAbs() {
+		return content.Feed{}, errors.New("link not absolute")
+	}
+	u.Fragment = ""
+	link = u.String()
+
+	f, err := fm.repo.FindByLink(link)
+	if err != nil && !content.IsNoContent(err) {
+		return f, err
+	}
+
+	if err == nil {
+		if f.Scrape != rules {
+			return content.Feed{}, errors.Errorf("feed %s already exists with different scrape rules", f)
+		}
+
+		if err = fm.checkExistingAuth(f, auth); err != nil {
+			return content.Feed{}, err
+		}
+	} else {
+		pf, err := feed.Scrape(link, rules, auth, fm.client, fm.log)
+		if err != nil {
+			return content.Feed{}, errors.WithMessage(err, "scraping feed")
+		}
+
+		f.Link = link
+		f.Scrape = rules
+		f.Refresh(fm.processParserFeed(pf))
+
+		if err = f.SetAuth(auth, []byte(fm.config.Auth.Secret)); err != nil {
+			return content.Feed{}, errors.WithMessage(err, "setting feed auth")
+		}
+
+		if _, err = fm.repo.Update(&f); err != nil {
+			return content.Feed{}, errors.WithMessage(err, "updating feed with scraped data")
+		}
+	}
+
+	fm.log.Infoln("Adding scraped feed " + f.String() + " to manager")
+	fm.AddFeed(f)
+
+	return f, nil
+}
+
+// checkExistingAuth refuses the addition of an existing feed, unless the
+// supplied auth matches the stored one, so that a private feed is not shared
+// with users that do not know its credentials.
+func (fm *FeedManager) checkExistingAuth(f content.Feed, auth content.FeedAuth) error {
+	stored, err := f.Auth([]byte(fm.config.Auth.Secret))
+	if err != nil {
+		return errors.WithMessage(err, "getting feed auth")
+	}
+
+	if !stored.Equal(auth) {
+		return content.NewValidationError(errors.Errorf("feed %s already exists with different credentials", f))
+	}
+
+	return nil
+}
+
 func (fm *FeedManager) RemoveFeedByLink(link string) (content.Feed, error) {
 	f, err := fm.repo.FindByLink(link)
 	if err != nil && !content.IsNoContent(err) {
@@ -147,9 +280,10 @@ func (fm *FeedManager) RemoveFeedByLink(link string) (content.Feed, error) {
 	return f, nil
 }
 
-func (fm *FeedManager) DiscoverFeeds(link string) ([]content.Feed, error) {
+func (fm *FeedManager) DiscoverFeeds(link string, auth content.FeedAuth) ([]content.Feed, error) {
+	link, auth = content.SplitLinkAuth(link, auth)
 
-	parsedFeeds, err := feed.Search(link, fm.log)
+	parsedFeeds, err := feed.Search(link, auth, fm.client, fm.log)
 	if err != nil {
 		return []content.Feed{}, errors.WithMessage(err, "discovering feeds")
 	}
@@ -165,6 +299,13 @@ func (fm *FeedManager) DiscoverFeeds(link string) ([]content.Feed, error) {
 	return feeds, nil
 }
 
+// CheckFeed downloads, parses and processes the feed the same way its
+// updates do, without storing the result, and reports any problems found
+// along the way.
+func (fm *FeedManager) CheckFeed(f content.Feed) feed.Report {
+	return fm.scheduler.Check(f, fm.parserProcessors)
+}
+
 func (fm *FeedManager) loop(ctx context.Context) {
 	for {
 		select {
@@ -177,13 +318,7 @@ func (fm *FeedManager) loop(ctx context.Context) {
 }
 
 func (fm *FeedManager) startUpdatingFeed(ctx context.Context, feed content.Feed) {
-	if feed.HubLink != "" && fm.hubbub != nil {
-		err := fm.hubbub.Subscribe(feed)
-
-		if err != nil && err != ErrSubscribed {
-			fm.log.Printf("Error subscribing to feed hublink: %+v\n", err)
-		}
-	}
+	fm.subscribe(feed)
 
 	d := 30 * time.Minute
 	if fm.config.FeedManager.Converted.UpdateInterval != 0 {
@@ -198,19 +333,149 @@ func (fm *FeedManager) startUpdatingFeed(ctx context.Context, feed content.Feed)
 }
 
 func (fm *FeedManager) scheduleFeed(ctx context.Context, feed content.Feed, update time.Duration) {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
 	fm.log.Infof("Scheduling update of feed %s", feed)
 	for update := range fm.scheduler.ScheduleFeed(ctx, feed, update) {
 		fm.log.Infof("Update for feed %s", feed)
+
+		if update.Link != "" && update.Link != feed.Link {
+			if merged := fm.moveFeed(&feed, update.Link); merged {
+				// Stop the updates of the merged feed, and drain the
+				// remaining data until the scheduler closes the channel.
+				cancel()
+				update.Processed(0)
+				continue
+			}
+		}
+
 		if update.IsErr() {
 			feed.AddUpdateError(fmt.Sprintf("%s: %s", time.Now().Format(time.UnixDate), update.Error()))
 		} else {
-			feed.Refresh(fm.processParserFeed(update.Feed))
+			if update.IsUpdated() {
+				feed.Refresh(fm.processParserFeed(update.Feed))
+			}
+
+			if update.Status == http.StatusOK {
+				feed.ETag, feed.LastModified = update.ETag, update.LastModified
+			}
+		}
+
+		feed.Dead = update.Dead
+		if feed.Dead {
+			fm.log.Infof("Feed %s is dead and will no longer be updated", feed)
 		}
 
-		fm.updateFeed(feed)
+		newArticles, updatedArticles := fm.updateFeed(feed)
+		fm.recordUpdate(feed, update, newArticles, updatedArticles)
+
+		update.Processed(newArticles)
+
+		if update.IsUpdated() {
+			// The hub or topic may have been discovered by the update.
+			fm.subscribe(feed)
+		}
+	}
+}
+
+// recordUpdate stores the outcome of a feed download in the update history.
+func (fm *FeedManager) recordUpdate(f content.Feed, update feed.UpdateData, newArticles, updatedArticles int) {
+	if fm.updateRepo == nil || update.Date.IsZero() {
+		return
+	}
+
+	record := content.FeedUpdate{
+		FeedID:          f.ID,
+		Date:            update.Date,
+		Duration:        update.Duration,
+		Status:          update.Status,
+		Bytes:           update.Bytes,
+		NewArticles:     newArticles,
+		UpdatedArticles: updatedArticles,
+		Error:           update.Error(),
+	}
+
+	if err := fm.updateRepo.Create(record); err != nil {
+		fm.log.Printf("Error recording update of feed %s: %+v", f, err)
 	}
 }
 
+// pruneUpdates periodically removes the update history older than the
+// retention period.
+func (fm *FeedManager) pruneUpdates(ctx context.Context, retention time.Duration) {
+	ticker := time.NewTicker(24 * time.Hour)
+	defer ticker.Stop()
+
+	for {
+		if err := fm.updateRepo.DeleteStale(time.Now().Add(-retention)); err != nil {
+			fm.log.Printf("Error removing stale feed updates: %+v", err)
+		}
+
+		select {
+		case <-ticker.C:
+		case <-ctx.Done():
+			return
+		}
+	}
+}
+
+func (fm *FeedManager) subscribe(feed content.Feed) {
+	if feed.HubLink == "" || fm.hubbub == nil {
+		return
+	}
+
+	if err := fm.hubbub.Subscribe(feed); err != nil && err != ErrSubscribed && err != ErrInsecureHub {
+		fm.log.Printf("Error subscribing to feed hublink: %+v\n", err)
+	}
+}
+
+// moveFeed changes the link of a permanently redirected feed. If another
+// feed already exists at the new link, the redirected feed is merged into
+// it, and true is returned.
+func (fm *FeedManager) moveFeed(feed *content.Feed, link string) bool {
+	existing, err := fm.repo.FindByLink(link)
+	if err != nil {
+		if !content.IsNoContent(err) {
+			fm.log.Printf("Error looking up feed by link %s: %+v", link, err)
+		}
+
+		fm.log.Infof("Changing the link of feed %s to %s", feed, link)
+		if !content.SameOriginLinks(feed.Link, link) {
+			// The credentials were given for the old site only.
+			feed.Credentials = nil
+		}
+		feed.Link = link
+
+		return false
+	}
+
+	if existing.ID == feed.ID {
+		feed.Link = link
+		return false
+	}
+
+	// The subscription is looked up by the feed, so the hub is left
+	// before the feed is merged away.
+	if feed.HubLink != "" && fm.hubbub != nil {
+		if err := fm.hubbub.Unsubscribe(*feed); err != nil && err != ErrNotSubscribed {
+			fm.log.Printf("Error unsubscribing from feed %s: %+v", feed, err)
+		}
+	}
+
+	fm.log.Infof("Merging feed %s into %s", feed, existing)
+	if err := fm.repo.Merge(*feed, existing); err != nil {
+		fm.log.Printf("Error merging feed %s into %s: %+v", feed, existing, err)
+
+		return false
+	}
+
+	// The existing feed is only scheduled if it isn't already.
+	fm.AddFeed(existing)
+
+	return true
+}
+
 func (fm *FeedManager) stopUpdatingFeed(feed content.Feed) {
 	if feed.HubLink != "" && fm.hubbub != nil {
 		fm.hubbub.Unsubscribe(feed)
@@ -232,11 +497,24 @@ func (fm *FeedManager) stopUpdatingFeed(feed content.Feed) {
 	}
 }
 
-func (fm FeedManager) updateFeed(feed content.Feed) {
-	if _, err := fm.repo.Update(&feed); err != nil {
+// updateFeed stores the feed and returns the number of its new and updated
+// articles.
+func (fm FeedManager) updateFeed(feed content.Feed) (int, int) {
+	articles, err := fm.repo.Update(&feed)
+	if err != nil {
 		fm.log.Printf("Error updating feed '%s' database record: %+v", feed, err)
 	}
 
+	var newArticles, updatedArticles int
+	for _, a := range articles {
+		if a.IsNew {
+			newArticles++
+		} else if a.Updated {
+			updatedArticles++
+		}
+	}
+
+	return newArticles, updatedArticles
 }
 
 func (fm FeedManager) processParserFeed(pf parser.Feed) parser.Feed {
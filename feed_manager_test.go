@@ -1,5 +1,87 @@
 package readeef
 
+import (
+	"context"
+	"net/http"
+	"os"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/urandom/readeef/config"
+	"github.com/urandom/readeef/content"
+	"github.com/urandom/readeef/content/repo/mock_repo"
+	"github.com/urandom/readeef/log"
+)
+
+func TestFeedManager_addExisting(t *testing.T) {
+	secret := "secret"
+	auth := content.FeedAuth{Username: "user", Password: "pass"}
+	rules := content.ScrapeRules{Item: "article"}
+
+	tests := []struct {
+		name    string
+		stored  content.FeedAuth
+		auth    content.FeedAuth
+		scraped bool
+		wantErr bool
+	}{
+		{"public", content.FeedAuth{}, content.FeedAuth{}, false, false},
+		{"private", auth, auth, false, false},
+		{"private without auth", auth, content.FeedAuth{}, false, true},
+		{"private with other auth", auth, content.FeedAuth{Username: "user", Password: "other"}, false, true},
+		{"public with auth", content.FeedAuth{}, auth, false, true},
+		{"scraped private", auth, auth, true, false},
+		{"scraped private without auth", auth, content.FeedAuth{}, true, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			link := "http://sugr.org/feed"
+			f := content.Feed{ID: 1, Link: link}
+			if tt.scraped {
+				f.Scrape = rules
+			}
+			if err := f.SetAuth(tt.stored, []byte(secret)); err != nil {
+				t.Fatal(err)
+			}
+
+			repo := mock_repo.NewMockFeed(ctrl)
+			repo.EXPECT().FindByLink(link).Return(f, nil)
+
+			cfg := config.Log{}
+			cfg.Converted.Writer = os.Stderr
+
+			c := config.Config{}
+			c.Auth.Secret = secret
+
+			fm := NewFeedManager(repo, c, http.DefaultClient, log.WithStd(cfg))
+			fm.ops = make(chan func(context.Context, *FeedManager), 1)
+
+			var err error
+			if tt.scraped {
+				_, err = fm.AddScrapedFeed(link, rules, tt.auth)
+			} else {
+				_, err = fm.AddFeedByLink(link, tt.auth)
+			}
+
+			if (err != nil) != tt.wantErr {
+				t.Errorf("FeedManager add error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+
+			if tt.wantErr && !content.IsValidationError(err) {
+				t.Errorf("FeedManager add error = %v, want a validation error", err)
+			}
+
+			if added := len(fm.ops) == 1; added == tt.wantErr {
+				t.Errorf("FeedManager add feed added = %v", added)
+			}
+		})
+	}
+}
+
 /*
 
 import (
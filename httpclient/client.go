@@ -0,0 +1,148 @@
+package httpclient
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"io"
+	"io/ioutil"
+	"net"
+	"net/http"
+	"net/url"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/urandom/readeef/config"
+)
+
+// ErrResponseTooLarge is returned when reading a response body bigger than
+// the configured maximum size.
+var ErrResponseTooLarge = errors.New("response exceeds the maximum size")
+
+type transport struct {
+	base      http.RoundTripper
+	userAgent string
+	maxSize   int64
+}
+
+type limitedBody struct {
+	io.ReadCloser
+	remaining int64
+}
+
+// New creates a client that goes through the configured proxy, trusts the
+// additional certificates of the CA bundle, sets the User-Agent of requests
+// that do not have one, and refuses responses bigger than the maximum size.
+func New(config config.HTTPClient) (*http.Client, error) {
+	proxy := http.ProxyFromEnvironment
+	if config.Proxy != "" {
+		u, err := url.Parse(config.Proxy)
+		if err != nil {
+			return nil, errors.Wrapf(err, "parsing proxy url %s", config.Proxy)
+		}
+
+		switch u.Scheme {
+		case "http", "https", "socks5":
+		default:
+			return nil, errors.Errorf("unsupported proxy scheme '%s'", u.Scheme)
+		}
+
+		proxy = http.ProxyURL(u)
+	}
+
+	dialer := &net.Dialer{
+		Timeout:   config.Converted.ConnectTimeout,
+		KeepAlive: 30 * time.Second,
+	}
+
+	base := &http.Transport{
+		Proxy:                 proxy,
+		DialContext:           dialer.DialContext,
+		TLSHandshakeTimeout:   config.Converted.ConnectTimeout,
+		MaxIdleConns:          100,
+		IdleConnTimeout:       90 * time.Second,
+		ExpectContinueTimeout: time.Second,
+	}
+
+	if config.CABundle != "" {
+		pool, err := certPool(config.CABundle)
+		if err != nil {
+			return nil, err
+		}
+
+		base.TLSClientConfig = &tls.Config{RootCAs: pool}
+	}
+
+	return &http.Client{
+		Transport: transport{base: base, userAgent: config.UserAgent, maxSize: config.MaxResponseSize},
+		Timeout:   config.Converted.Timeout,
+	}, nil
+}
+
+func (t transport) RoundTrip(req *http.Request) (*http.Response, error) {
+	if t.userAgent != "" && req.Header.Get("User-Agent") == "" {
+		// A RoundTripper should not modify the original request.
+		r := new(http.Request)
+		*r = *req
+		r.Header = make(http.Header, len(req.Header)+1)
+		for k, v := range req.Header {
+			r.Header[k] = v
+		}
+		r.Header.Set("User-Agent", t.userAgent)
+
+		req = r
+	}
+
+	resp, err := t.base.RoundTrip(req)
+	if err != nil || t.maxSize <= 0 {
+		return resp, err
+	}
+
+	if resp.ContentLength > t.maxSize {
+		resp.Body.Close()
+
+		return nil, errors.Wrapf(ErrResponseTooLarge, "content length of %s is %d bytes", req.URL, resp.ContentLength)
+	}
+
+	resp.Body = &limitedBody{ReadCloser: resp.Body, remaining: t.maxSize}
+
+	return resp, nil
+}
+
+func (b *limitedBody) Read(p []byte) (int, error) {
+	if b.remaining <= 0 {
+		// Only fail if there is more data than the limit allows.
+		var extra [1]byte
+		if n, _ := b.ReadCloser.Read(extra[:]); n > 0 {
+			return 0, ErrResponseTooLarge
+		}
+
+		return 0, io.EOF
+	}
+
+	if int64(len(p)) > b.remaining {
+		p = p[:b.remaining]
+	}
+
+	n, err := b.ReadCloser.Read(p)
+	b.remaining -= int64(n)
+
+	return n, err
+}
+
+func certPool(path string) (*x509.CertPool, error) {
+	b, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, errors.Wrapf(err, "reading ca bundle %s", path)
+	}
+
+	pool, err := x509.SystemCertPool()
+	if err != nil || pool == nil {
+		pool = x509.NewCertPool()
+	}
+
+	if !pool.AppendCertsFromPEM(b) {
+		return nil, errors.Errorf("no certificates found in ca bundle %s", path)
+	}
+
+	return pool, nil
+}
@@ -0,0 +1,181 @@
+package httpclient
+
+import (
+	"encoding/pem"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/pkg/errors"
+	"github.com/urandom/readeef/config"
+)
+
+func TestNew(t *testing.T) {
+	tests := []struct {
+		name    string
+		config  config.HTTPClient
+		wantErr bool
+	}{
+		{"default", config.HTTPClient{}, false},
+		{"http proxy", config.HTTPClient{Proxy: "http://localhost:3128"}, false},
+		{"socks proxy", config.HTTPClient{Proxy: "socks5://localhost:1080"}, false},
+		{"unsupported proxy", config.HTTPClient{Proxy: "ftp://localhost"}, true},
+		{"missing ca bundle", config.HTTPClient{CABundle: "/non/existent/bundle.pem"}, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := New(tt.config); (err != nil) != tt.wantErr {
+				t.Errorf("New() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestClient_userAgent(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(r.UserAgent()))
+	}))
+	defer ts.Close()
+
+	client, err := New(config.HTTPClient{UserAgent: "readeef"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name      string
+		userAgent string
+		want      string
+	}{
+		{"configured", "", "readeef"},
+		{"request", "custom", "custom"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req, _ := http.NewRequest("GET", ts.URL, nil)
+			if tt.userAgent != "" {
+				req.Header.Set("User-Agent", tt.userAgent)
+			}
+
+			resp, err := client.Do(req)
+			if err != nil {
+				t.Fatal(err)
+			}
+			defer resp.Body.Close()
+
+			b, _ := ioutil.ReadAll(resp.Body)
+			if string(b) != tt.want {
+				t.Errorf("User-Agent = %s, want %s", b, tt.want)
+			}
+		})
+	}
+}
+
+func TestClient_maxResponseSize(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		size := 10
+		if r.URL.Path == "/large" {
+			size = 20
+		}
+
+		if r.URL.Path == "/stream" {
+			// Flushing before writing the body prevents a Content-Length.
+			w.(http.Flusher).Flush()
+			size = 20
+		}
+
+		w.Write([]byte(strings.Repeat("a", size)))
+	}))
+	defer ts.Close()
+
+	client, err := New(config.HTTPClient{MaxResponseSize: 10})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name    string
+		path    string
+		wantErr bool
+	}{
+		{"within limit", "/", false},
+		{"content length over limit", "/large", true},
+		{"body over limit", "/stream", true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			resp, err := client.Get(ts.URL + tt.path)
+			if err == nil {
+				defer resp.Body.Close()
+				_, err = ioutil.ReadAll(resp.Body)
+			}
+
+			if (err != nil) != tt.wantErr {
+				t.Errorf("client.Get() error = %v, wantErr %v", err, tt.wantErr)
+			}
+
+			if err != nil && errors.Cause(err) != ErrResponseTooLarge && !strings.Contains(err.Error(), ErrResponseTooLarge.Error()) {
+				t.Errorf("client.Get() error = %v, want %v", err, ErrResponseTooLarge)
+			}
+		})
+	}
+}
+
+func TestClient_caBundle(t *testing.T) {
+	ts := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	defer ts.Close()
+
+	f, err := ioutil.TempFile("", "readeef-ca")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.Remove(f.Name())
+
+	pem.Encode(f, &pem.Block{Type: "CERTIFICATE", Bytes: ts.Certificate().Raw})
+	f.Close()
+
+	if client, err := New(config.HTTPClient{}); err == nil {
+		if _, err := client.Get(ts.URL); err == nil {
+			t.Errorf("client.Get() expected an error without the ca bundle")
+		}
+	} else {
+		t.Fatal(err)
+	}
+
+	client, err := New(config.HTTPClient{CABundle: f.Name()})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	resp, err := client.Get(ts.URL)
+	if err != nil {
+		t.Fatalf("client.Get() error = %v", err)
+	}
+	resp.Body.Close()
+}
+
+func TestClient_proxy(t *testing.T) {
+	var proxied string
+	proxy := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		proxied = r.URL.String()
+	}))
+	defer proxy.Close()
+
+	client, err := New(config.HTTPClient{Proxy: proxy.URL})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	resp, err := client.Get("http://example.com/feed")
+	if err != nil {
+		t.Fatalf("client.Get() error = %v", err)
+	}
+	resp.Body.Close()
+
+	if proxied != "http://example.com/feed" {
+		t.Errorf("proxied request = %s, want http://example.com/feed", proxied)
+	}
+}
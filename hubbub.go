@@ -1,9 +1,12 @@
 package readeef
 
 import (
+	"crypto/rand"
+	"encoding/hex"
 	"fmt"
 	"net/http"
 	"net/url"
+	"strings"
 	"time"
 
 	"github.com/pkg/errors"
@@ -15,15 +18,12 @@ import (
 )
 
 type Hubbub struct {
-	service       repo.Service
-	config        config.Config
-	endpoint      string
-	subscribe     chan content.Subscription
-	unsubscribe   chan content.Subscription
-	client        *http.Client
-	log           log.Log
-	subscriptions []content.Subscription
-	feedManager   *FeedManager
+	service     repo.Service
+	config      config.Config
+	endpoint    string
+	client      *http.Client
+	log         log.Log
+	feedManager *FeedManager
 }
 
 type SubscriptionError struct {
@@ -36,11 +36,23 @@ var (
 	ErrNoFeedHubLink = errors.New("Feed does not contain a hub link")
 	ErrSubscribed    = errors.New("Feed already subscribed")
 	ErrNotSubscribed = errors.New("Feed is not subscribed")
+	ErrInsecureHub   = errors.New("Feed hub does not use https")
+)
+
+const (
+	// leaseCheckInterval is how often the subscription leases are checked
+	// for renewal.
+	leaseCheckInterval = time.Minute
+
+	// renewRetryInterval is the time after which a renewal is requested
+	// again, if the hub has not verified the previous one.
+	renewRetryInterval = 15 * time.Minute
 )
 
 func NewHubbub(
 	service repo.Service,
 	c config.Config,
+	client *http.Client,
 	l log.Log,
 	endpoint string,
 	feedManager *FeedManager,
@@ -49,8 +61,7 @@ func NewHubbub(
 	return &Hubbub{
 		service: service,
 		config:  c, log: l, endpoint: endpoint,
-		subscribe: make(chan content.Subscription), unsubscribe: make(chan content.Subscription),
-		client:      NewTimeoutClient(c.Timeout.Converted.Connect, c.Timeout.Converted.ReadWrite),
+		client:      client,
 		feedManager: feedManager,
 	}
 }
@@ -70,6 +81,13 @@ func (h *Hubbub) Subscribe(f content.Feed) error {
 		if !u.IsAbs() {
 			return ErrNoFeedHubLink
 		}
+
+		// The secret may only be sent over https, and the feed is
+		// polled instead.
+		if !secureHub(f.HubLink) {
+			h.log.Infoln("Not subscribing to insecure hub " + f.HubLink)
+			return ErrInsecureHub
+		}
 	}
 
 	repo := h.service.SubscriptionRepo()
@@ -78,15 +96,31 @@ func (h *Hubbub) Subscribe(f content.Feed) error {
 		return errors.WithMessage(err, "getting feed subscription during subscribe")
 	}
 
-	if s.FeedID == f.ID {
+	// The topic is the self link of the feed, if it advertises one.
+	current := s.Topic
+	if current == "" {
+		current = f.Link
+	}
+
+	topic := f.SelfLink
+	if topic == "" {
+		topic = current
+	}
+
+	if s.FeedID == f.ID && s.Link == f.HubLink && current == topic {
 		h.log.Infoln("Already subscribed to " + f.HubLink)
 		return ErrSubscribed
 	}
 
 	s.Link = f.HubLink
 	s.FeedID = f.ID
+	s.Topic = topic
 	s.SubscriptionFailure = true
 
+	if s.Secret, err = newSecret(); err != nil {
+		return errors.WithMessage(err, "generating subscription secret")
+	}
+
 	if err = repo.Update(s); err != nil {
 		return errors.WithMessage(err, "updating subscription during subscribe")
 	}
@@ -125,6 +159,15 @@ func (h *Hubbub) Unsubscribe(f content.Feed) error {
 		return ErrNotSubscribed
 	}
 
+	// Stop renewing the subscription, even before the hub verifies the
+	// unsubscription, which is only accepted while it is pending. This is
+	// done right away, since the feed may be deleted next.
+	s.SubscriptionFailure = true
+	s.Pending = "unsubscribe"
+	if err = h.service.SubscriptionRepo().Update(s); err != nil {
+		return errors.WithMessage(err, "updating subscription during unsubscribe")
+	}
+
 	go func() {
 		h.subscription(s, f, false)
 	}()
@@ -139,6 +182,8 @@ func (h *Hubbub) InitSubscriptions() error {
 
 	h.log.Infof("Initializing %d hubbub subscriptions", len(subscriptions))
 
+	// Every subscription is renewed, which also gives the ones created
+	// before content signing was supported a secret.
 	feedRepo := h.service.FeedRepo()
 	go func() {
 		for _, s := range subscriptions {
@@ -152,43 +197,62 @@ func (h *Hubbub) InitSubscriptions() error {
 		}
 	}()
 
-	go func() {
-		after := time.After(h.config.FeedManager.Converted.UpdateInterval)
-		subscriptions := []content.Subscription{}
-
-		for {
-			select {
-			case s := <-h.subscribe:
-				subscriptions = append(subscriptions, s)
-			case s := <-h.unsubscribe:
-				filtered := []content.Subscription{}
-				for i := range subscriptions {
-					if subscriptions[i] != s {
-						filtered = append(filtered, subscriptions[i])
-					}
-				}
-
-				subscriptions = filtered
-			case <-after:
-				for _, s := range subscriptions {
-					if s.VerificationTime.Add(time.Duration(s.LeaseDuration)).Before(time.Now().Add(-30 * time.Minute)) {
-						f, err := feedRepo.Get(s.FeedID, content.User{})
-						if err != nil {
-							h.log.Printf("Error getting subscription feed: %+v\n", err)
-							continue
-						}
-
-						h.log.Infof("Renewing subscription to %s\n", s)
-						h.subscription(s, f, true)
-					}
-				}
-			}
-		}
-	}()
+	go h.renewLeases()
 
 	return nil
 }
 
+// renewLeases periodically renews the subscriptions whose lease is about to
+// run out, and lets the feed manager know which feeds are pushed by their
+// hubs, so that the rest are polled instead.
+func (h *Hubbub) renewLeases() {
+	renewed := map[content.FeedID]time.Time{}
+
+	ticker := time.NewTicker(leaseCheckInterval)
+	defer ticker.Stop()
+
+	for now := range ticker.C {
+		h.checkLeases(renewed, now)
+	}
+}
+
+func (h *Hubbub) checkLeases(renewed map[content.FeedID]time.Time, now time.Time) {
+	subscriptions, err := h.service.SubscriptionRepo().All()
+	if err != nil {
+		h.log.Printf("Error getting subscriptions: %+v\n", err)
+		return
+	}
+
+	feedRepo := h.service.FeedRepo()
+	for _, s := range subscriptions {
+		status := s.Status(now)
+
+		if h.feedManager != nil {
+			h.feedManager.SetFeedPushed(s.FeedID, s.Pushed(now))
+		}
+
+		renewTime := s.RenewTime()
+		if status == content.SubscriptionInactive || renewTime.IsZero() || now.Before(renewTime) {
+			delete(renewed, s.FeedID)
+			continue
+		}
+
+		if last, ok := renewed[s.FeedID]; ok && now.Sub(last) < renewRetryInterval {
+			continue
+		}
+
+		f, err := feedRepo.Get(s.FeedID, content.User{})
+		if err != nil {
+			h.log.Printf("Error getting subscription feed: %+v\n", err)
+			continue
+		}
+
+		h.log.Infof("Renewing subscription to %s, its lease expires at %s\n", s, s.LeaseExpiry())
+		renewed[s.FeedID] = now
+		h.subscription(s, f, true)
+	}
+}
+
 func (h *Hubbub) subscription(s content.Subscription, f content.Feed, subscribe bool) {
 	var err error
 
@@ -203,7 +267,41 @@ func (h *Hubbub) subscription(s content.Subscription, f content.Feed, subscribe
 		h.log.Infoln("Unsubscribing to hubbub for " + f.String() + " with url " + u)
 		body.Set("hub.mode", "unsubscribe")
 	}
-	body.Set("hub.topic", f.Link)
+
+	topic := s.Topic
+	if topic == "" {
+		topic = f.Link
+	}
+	body.Set("hub.topic", topic)
+
+	if subscribe {
+		if !secureHub(s.Link) {
+			h.log.Infof("Not renewing subscription %s, its hub does not use https\n", s)
+			return
+		}
+
+		if s.Secret == "" {
+			// Subscriptions created before content signing was supported
+			// get a secret when they are renewed. They are pending until
+			// the hub verifies the renewal, so that the feed is polled
+			// while the hub keeps sending unsigned content.
+			s.SubscriptionFailure = true
+			if s.Secret, err = newSecret(); err != nil {
+				h.log.Printf("Error generating secret for subscription %s: %+v\n", s, err)
+				return
+			}
+		}
+
+		body.Set("hub.secret", s.Secret)
+
+		// The hub's verification of the request is only accepted while
+		// the request is pending.
+		s.Pending = "subscribe"
+		if err = h.service.SubscriptionRepo().Update(s); err != nil {
+			h.log.Printf("Error updating subscription %s: %+v\n", s, err)
+			return
+		}
+	}
 
 	buf := pool.Buffer.Get()
 	defer pool.Buffer.Put(buf)
@@ -217,26 +315,50 @@ func (h *Hubbub) subscription(s content.Subscription, f content.Feed, subscribe
 
 	if err != nil {
 		err = SubscriptionError{error: err, Subscription: s}
-	} else if resp.StatusCode != 202 {
-		err = SubscriptionError{error: errors.New("Expected response status 202, got " + resp.Status), Subscription: s}
-	}
+	} else {
+		resp.Body.Close()
 
-	if err == nil {
-		if subscribe {
-			h.subscribe <- s
-		} else {
-			h.unsubscribe <- s
+		if resp.StatusCode != 202 {
+			err = SubscriptionError{error: errors.New("Expected response status 202, got " + resp.Status), Subscription: s}
 		}
-	} else {
+	}
+
+	if err != nil {
 		f.SubscribeError = fmt.Sprintf("%s: %s", time.Now().Format(time.UnixDate), err.Error())
 		h.log.Printf("Error subscribing to hub feed '%s': %s\n", f, err)
 
+		if subscribe {
+			s.Pending = ""
+			if err = h.service.SubscriptionRepo().Update(s); err != nil {
+				h.log.Printf("Error updating subscription %s: %+v\n", s, err)
+			}
+		}
+
 		if _, err = h.service.FeedRepo().Update(&f); err != nil {
 			h.log.Printf("Error updating feed database record for %s: %+v", f, err)
 		}
 	}
 }
 
+// secureHub returns true if the hub link uses https, over which the
+// subscription secret can be sent.
+func secureHub(link string) bool {
+	u, err := url.Parse(link)
+
+	return err == nil && strings.EqualFold(u.Scheme, "https")
+}
+
 func callbackURL(c config.Config, endpoint string, feedID content.FeedID) string {
 	return fmt.Sprintf("%s%s/%d", c.Hubbub.CallbackURL, endpoint, feedID)
 }
+
+// newSecret generates a random secret for signing the content of a
+// subscription. WebSub requires it to be less than 200 bytes long.
+func newSecret() (string, error) {
+	b := make([]byte, 32)
+	if _, err := rand.Read(b); err != nil {
+		return "", errors.Wrap(err, "reading random bytes")
+	}
+
+	return hex.EncodeToString(b), nil
+}
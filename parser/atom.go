@@ -4,32 +4,50 @@ import (
 	"bytes"
 	"encoding/xml"
 	"io"
+	"strings"
 	"time"
 )
 
 type atomFeed struct {
-	XMLName     xml.Name   `xml:"feed"`
-	Title       string     `xml:"title"`
-	Description string     `xml:"description"`
-	Link        atomLink   `xml:"link"`
-	Image       rssImage   `xml:"image"`
-	Items       []atomItem `xml:"entry"`
+	XMLName     xml.Name     `xml:"feed"`
+	Title       string       `xml:"title"`
+	Description string       `xml:"description"`
+	Link        atomLink     `xml:"link"`
+	Image       rssImage     `xml:"image"`
+	Authors     []atomPerson `xml:"author"`
+	Items       []atomItem   `xml:"entry"`
 }
 
 type atomItem struct {
-	XMLName     xml.Name   `xml:"entry"`
-	Id          string     `xml:"id"`
-	Title       string     `xml:"title"`
-	Description rssContent `xml:"summary"`
-	Content     rssContent `xml:"content"`
-	Link        atomLink   `xml:"link"`
-	Date        string     `xml:"updated"`
-	PubDate     string     `xml:"published"`
+	XMLName     xml.Name       `xml:"entry"`
+	Id          string         `xml:"id"`
+	Title       string         `xml:"title"`
+	Description rssContent     `xml:"summary"`
+	Content     rssContent     `xml:"content"`
+	Links       []atomLink     `xml:"link"`
+	Date        string         `xml:"updated"`
+	PubDate     string         `xml:"published"`
+	Authors     []atomPerson   `xml:"author"`
+	Categories  []atomCategory `xml:"category"`
+
+	mediaItem
+}
+
+type atomPerson struct {
+	Name  string `xml:"name"`
+	Email string `xml:"email"`
+}
+
+type atomCategory struct {
+	Term  string `xml:"term,attr"`
+	Label string `xml:"label,attr"`
 }
 
 type atomLink struct {
-	Rel  string `xml:"rel,attr,omitempty"`
-	Href string `xml:"href,attr"`
+	Rel    string `xml:"rel,attr,omitempty"`
+	Href   string `xml:"href,attr"`
+	Type   string `xml:"type,attr,omitempty"`
+	Length string `xml:"length,attr,omitempty"`
 }
 
 func ParseAtom(b []byte) (Feed, error) {
@@ -52,10 +70,28 @@ func ParseAtom(b []byte) (Feed, error) {
 			rss.Image.Width, rss.Image.Height},
 	}
 
+	feedAuthor := atomAuthor(rss.Authors)
+
 	var lastValidDate time.Time
 	for _, i := range rss.Items {
-		article := Article{Title: i.Title, Link: i.Link.Href, Guid: i.Id}
-		article.Description = getLargerContent(i.Content, i.Description)
+		article := Article{Title: i.Title, Link: i.link(), Guid: i.Id}
+		article.Description, article.Summary = splitContent(i.Content, i.Description)
+
+		// Entries without an author inherit the one of the feed.
+		if article.Author = atomAuthor(i.Authors); article.Author == "" {
+			article.Author = feedAuthor
+		}
+
+		categories := make([]string, 0, len(i.Categories))
+		for _, c := range i.Categories {
+			if c.Label != "" {
+				categories = append(categories, c.Label)
+			} else {
+				categories = append(categories, c.Term)
+			}
+		}
+		article.Categories = uniqueCategories(categories)
+		article.Enclosures = i.enclosures(i.Links...)
 
 		var err error
 		if i.PubDate != "" {
@@ -70,8 +106,10 @@ func ParseAtom(b []byte) (Feed, error) {
 			lastValidDate = article.Date.Add(time.Second)
 		} else if lastValidDate.IsZero() {
 			article.Date = unknownTime
+			article.DateInferred = true
 		} else {
 			article.Date = lastValidDate
+			article.DateInferred = true
 		}
 
 		f.Articles = append(f.Articles, article)
@@ -80,3 +118,33 @@ func ParseAtom(b []byte) (Feed, error) {
 
 	return f, nil
 }
+
+// link returns the alternate link of the entry, or the first one if none of
+// the links have the alternate relation.
+func (i atomItem) link() string {
+	for _, l := range i.Links {
+		if l.Rel == "" || l.Rel == "alternate" {
+			return l.Href
+		}
+	}
+
+	if len(i.Links) > 0 {
+		return i.Links[0].Href
+	}
+
+	return ""
+}
+
+func atomAuthor(authors []atomPerson) string {
+	for _, a := range authors {
+		if name := strings.TrimSpace(a.Name); name != "" {
+			return name
+		}
+
+		if email := strings.TrimSpace(a.Email); email != "" {
+			return email
+		}
+	}
+
+	return ""
+}
@@ -42,6 +42,7 @@ var (
 				Link:        "http://example.org/2003/12/13/atom03",
 				Guid:        "urn:uuid:1225c695-cfb8-4ebb-aaaa-80da344efa6a",
 				Description: "Some text.",
+				Author:      "John Doe",
 				Date:        time.Date(2003, time.December, 13, 18, 30, 02, 0, time.UTC),
 			},
 		},
@@ -52,11 +53,13 @@ var (
 		SiteLink: "http://example.org/",
 		Articles: []Article{
 			{
-				Title:       "Atom-Powered Robots Run Amok",
-				Link:        "http://example.org/2003/12/13/atom03",
-				Guid:        "urn:uuid:1225c695-cfb8-4ebb-aaaa-80da344efa6a",
-				Description: "Some text.",
-				Date:        time.Unix(0, 0),
+				Title:        "Atom-Powered Robots Run Amok",
+				Link:         "http://example.org/2003/12/13/atom03",
+				Guid:         "urn:uuid:1225c695-cfb8-4ebb-aaaa-80da344efa6a",
+				Description:  "Some text.",
+				Author:       "John Doe",
+				Date:         time.Unix(0, 0),
+				DateInferred: true,
 			},
 		},
 	}
@@ -70,14 +73,17 @@ var (
 				Link:        "http://example.org/2003/12/13/atom03",
 				Guid:        "urn:uuid:1225c695-cfb8-4ebb-aaaa-80da344efa6a",
 				Description: "Some text.",
+				Author:      "John Doe",
 				Date:        time.Date(2003, time.December, 13, 18, 30, 02, 0, time.UTC),
 			},
 			{
-				Title:       "Atom-Powered Robots Run Amok 2",
-				Link:        "http://example.org/2003/12/13/atom03 2",
-				Guid:        "urn:uuid:1225c695-cfb8-4ebb-aaaa-80da344efa6a 2",
-				Description: "Some text. 2",
-				Date:        time.Date(2003, time.December, 13, 18, 30, 03, 0, time.UTC),
+				Title:        "Atom-Powered Robots Run Amok 2",
+				Link:         "http://example.org/2003/12/13/atom03 2",
+				Guid:         "urn:uuid:1225c695-cfb8-4ebb-aaaa-80da344efa6a 2",
+				Description:  "Some text. 2",
+				Author:       "John Doe",
+				Date:         time.Date(2003, time.December, 13, 18, 30, 03, 0, time.UTC),
+				DateInferred: true,
 			},
 		},
 	}
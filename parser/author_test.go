@@ -0,0 +1,142 @@
+package parser
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestAuthorsAndCategories(t *testing.T) {
+	type meta struct {
+		author     string
+		categories []string
+	}
+	tests := []struct {
+		name  string
+		b     []byte
+		parse func([]byte) (Feed, error)
+		want  []meta
+	}{
+		{"rss2", []byte(authorRss2XML), ParseRss2, []meta{
+			{"John Doe", []string{"Go", "Feeds"}},
+			{"Jane Doe", []string{"News"}},
+			{"editor@example.com", nil},
+		}},
+		{"rss1", []byte(authorRss1XML), ParseRss1, []meta{
+			{"John Doe", []string{"Go"}},
+		}},
+		{"atom", []byte(authorAtomXML), ParseAtom, []meta{
+			{"Jane Doe", []string{"Programming", "go"}},
+			{"John Doe", nil},
+		}},
+		{"json", []byte(authorJSON), ParseJSON, []meta{
+			{"Jane Doe", []string{"go", "feeds"}},
+			{"John Doe", nil},
+		}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			f, err := tt.parse(tt.b)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if len(f.Articles) != len(tt.want) {
+				t.Fatalf("articles = %d, want %d", len(f.Articles), len(tt.want))
+			}
+
+			for i, a := range f.Articles {
+				if a.Author != tt.want[i].author {
+					t.Errorf("article %d author = %q, want %q", i, a.Author, tt.want[i].author)
+				}
+
+				if !reflect.DeepEqual(a.Categories, tt.want[i].categories) {
+					t.Errorf("article %d categories = %v, want %v", i, a.Categories, tt.want[i].categories)
+				}
+			}
+		})
+	}
+}
+
+const (
+	authorRss2XML = `
+<?xml version="1.0"?>
+<rss version="2.0" xmlns:dc="http://purl.org/dc/elements/1.1/">
+	<channel>
+		<title>Authors</title>
+		<link>http://example.com/</link>
+		<item>
+			<title>Item 1</title>
+			<guid>1</guid>
+			<author>john@example.com (John Doe)</author>
+			<category>Go</category>
+			<category domain="http://example.com/tags">Feeds</category>
+			<category>go</category>
+		</item>
+		<item>
+			<title>Item 2</title>
+			<guid>2</guid>
+			<author>jane@example.com</author>
+			<dc:creator>Jane Doe</dc:creator>
+			<dc:subject>News</dc:subject>
+		</item>
+		<item>
+			<title>Item 3</title>
+			<guid>3</guid>
+			<author>editor@example.com</author>
+		</item>
+	</channel>
+</rss>
+`
+
+	authorRss1XML = `
+<?xml version="1.0"?>
+<rdf:RDF
+	xmlns:rdf="http://www.w3.org/1999/02/22-rdf-syntax-ns#"
+	xmlns:dc="http://purl.org/dc/elements/1.1/"
+	xmlns="http://purl.org/rss/1.0/">
+	<channel rdf:about="http://example.com/">
+		<title>Authors</title>
+		<link>http://example.com/</link>
+	</channel>
+	<item rdf:about="http://example.com/1">
+		<title>Item 1</title>
+		<link>http://example.com/1</link>
+		<dc:creator>John Doe</dc:creator>
+		<dc:subject>Go</dc:subject>
+	</item>
+</rdf:RDF>
+`
+
+	authorAtomXML = `
+<feed xmlns="http://www.w3.org/2005/Atom">
+	<title>Authors</title>
+	<link href="http://example.org/"></link>
+	<author><name>John Doe</name></author>
+	<entry>
+		<title>Entry 1</title>
+		<id>1</id>
+		<link href="http://example.org/1"></link>
+		<author><name>Jane Doe</name></author>
+		<category term="prog" label="Programming"/>
+		<category term="go"/>
+	</entry>
+	<entry>
+		<title>Entry 2</title>
+		<id>2</id>
+		<link href="http://example.org/2"></link>
+	</entry>
+</feed>
+`
+
+	authorJSON = `
+{
+	"version": "https://jsonfeed.org/version/1",
+	"title": "Authors",
+	"author": {"name": "John Doe"},
+	"items": [
+		{"id": "1", "url": "http://example.org/1", "content_text": "1", "author": {"name": "Jane Doe"}, "tags": ["go", "feeds", "Go"]},
+		{"id": "2", "url": "http://example.org/2", "content_text": "2"}
+	]
+}
+`
+)
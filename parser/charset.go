@@ -0,0 +1,75 @@
+package parser
+
+import (
+	"bytes"
+	"mime"
+	"regexp"
+	"strings"
+	"unicode/utf8"
+
+	"golang.org/x/net/html/charset"
+)
+
+var (
+	utf16BEBOM = []byte{0xFE, 0xFF}
+	utf16LEBOM = []byte{0xFF, 0xFE}
+
+	prologEncoding = regexp.MustCompile(`^(\s*<\?xml[^>]*?\sencoding\s*=\s*["'])([^"']*)(["'])`)
+)
+
+// toUTF8 converts the source to utf-8, using the charset of its byte order
+// mark, content type or xml declaration, in that order. A utf-8 content type
+// is ignored for content that is not valid utf-8. The encoding of the xml
+// declaration is changed to match the converted content. The name of the
+// original charset is returned if the content was converted.
+func toUTF8(source []byte, contentType string) ([]byte, string) {
+	var label string
+
+	switch {
+	case bytes.HasPrefix(source, utf8BOM):
+		source = source[len(utf8BOM):]
+		label = "utf-8"
+	case bytes.HasPrefix(source, utf16BEBOM):
+		label = "utf-16be"
+	case bytes.HasPrefix(source, utf16LEBOM):
+		label = "utf-16le"
+	}
+
+	if label == "" && contentType != "" {
+		if _, params, err := mime.ParseMediaType(contentType); err == nil {
+			label = params["charset"]
+		}
+	}
+
+	if isUTF8Label(label) && !utf8.Valid(source) {
+		label = ""
+	}
+
+	if label == "" {
+		if m := prologEncoding.FindSubmatch(source); m != nil {
+			label = string(m[2])
+		}
+	}
+
+	var converted string
+	if label != "" && !isUTF8Label(label) {
+		if enc, name := charset.Lookup(label); enc != nil {
+			if b, err := enc.NewDecoder().Bytes(source); err == nil {
+				source = bytes.TrimPrefix(b, utf8BOM)
+				converted = name
+			}
+		}
+	}
+
+	if m := prologEncoding.FindSubmatch(source); m != nil && !strings.EqualFold(string(m[2]), "utf-8") {
+		source = prologEncoding.ReplaceAll(source, []byte("${1}utf-8${3}"))
+	}
+
+	return source, converted
+}
+
+func isUTF8Label(label string) bool {
+	label = strings.ToLower(strings.TrimSpace(label))
+
+	return label == "utf-8" || label == "utf8"
+}
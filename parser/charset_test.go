@@ -0,0 +1,43 @@
+package parser
+
+import (
+	"testing"
+
+	"golang.org/x/text/encoding/charmap"
+	"golang.org/x/text/encoding/unicode"
+)
+
+func Test_toUTF8(t *testing.T) {
+	latin1, _ := charmap.ISO8859_1.NewEncoder().String(`<?xml version="1.0" encoding="ISO-8859-1"?><p>café</p>`)
+	utf16, _ := unicode.UTF16(unicode.LittleEndian, unicode.UseBOM).NewEncoder().String(`<?xml version="1.0" encoding="UTF-16"?><p>café</p>`)
+
+	tests := []struct {
+		name          string
+		source        string
+		contentType   string
+		want          string
+		wantConverted string
+	}{
+		{"utf-8", `<?xml version="1.0" encoding="utf-8"?><p>café</p>`, "", `<?xml version="1.0" encoding="utf-8"?><p>café</p>`, ""},
+		{"no declaration", `<p>café</p>`, "text/xml", `<p>café</p>`, ""},
+		{"utf-8 bom", "\xEF\xBB\xBF<p>café</p>", "", `<p>café</p>`, ""},
+		{"utf8 declaration", `<?xml version="1.0" encoding="UTF8"?><p>café</p>`, "", `<?xml version="1.0" encoding="utf-8"?><p>café</p>`, ""},
+		{"declared", latin1, "", `<?xml version="1.0" encoding="utf-8"?><p>café</p>`, "windows-1252"},
+		{"content type", "<p>caf\xE9</p>", "text/xml; charset=iso-8859-1", `<p>café</p>`, "windows-1252"},
+		{"invalid utf-8 content type", latin1, "text/xml; charset=utf-8", `<?xml version="1.0" encoding="utf-8"?><p>café</p>`, "windows-1252"},
+		{"utf-16 bom", utf16, "", `<?xml version="1.0" encoding="utf-8"?><p>café</p>`, "utf-16le"},
+		{"unknown charset", `<?xml version="1.0" encoding="unknown"?><p>café</p>`, "", `<?xml version="1.0" encoding="utf-8"?><p>café</p>`, ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, converted := toUTF8([]byte(tt.source), tt.contentType)
+			if string(got) != tt.want {
+				t.Errorf("toUTF8() = %q, want %q", got, tt.want)
+			}
+
+			if converted != tt.wantConverted {
+				t.Errorf("toUTF8() converted = %q, want %q", converted, tt.wantConverted)
+			}
+		})
+	}
+}
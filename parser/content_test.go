@@ -0,0 +1,122 @@
+package parser
+
+import "testing"
+
+func TestContentAndSummary(t *testing.T) {
+	type body struct {
+		description string
+		summary     string
+	}
+	tests := []struct {
+		name  string
+		b     []byte
+		parse func([]byte) (Feed, error)
+		want  []body
+	}{
+		{"rss2", []byte(contentRss2XML), ParseRss2, []body{
+			{"<p>The full post.</p>", "A teaser."},
+			{"Only a description.", ""},
+			{"Only content.", ""},
+		}},
+		{"atom", []byte(contentAtomXML), ParseAtom, []body{
+			{"The full entry.", "A teaser."},
+			{"Same text.", ""},
+		}},
+		{"json", []byte(contentJSON), ParseJSON, []body{
+			{"<p>The full item.</p>", "A teaser."},
+			{"A teaser.", ""},
+		}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			f, err := tt.parse(tt.b)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if len(f.Articles) != len(tt.want) {
+				t.Fatalf("articles = %d, want %d", len(f.Articles), len(tt.want))
+			}
+
+			for i, a := range f.Articles {
+				if a.Description != tt.want[i].description {
+					t.Errorf("article %d description = %q, want %q", i, a.Description, tt.want[i].description)
+				}
+
+				if a.Summary != tt.want[i].summary {
+					t.Errorf("article %d summary = %q, want %q", i, a.Summary, tt.want[i].summary)
+				}
+			}
+		})
+	}
+}
+
+const (
+	contentRss2XML = `
+<?xml version="1.0"?>
+<rss version="2.0" xmlns:content="http://purl.org/rss/1.0/modules/content/">
+	<channel>
+		<title>Liftoff News</title>
+		<link>http://liftoff.msfc.nasa.gov/</link>
+		<item>
+			<title>First</title>
+			<link>http://liftoff.msfc.nasa.gov/1</link>
+			<description>A teaser.</description>
+			<content:encoded><![CDATA[<p>The full post.</p>]]></content:encoded>
+		</item>
+		<item>
+			<title>Second</title>
+			<link>http://liftoff.msfc.nasa.gov/2</link>
+			<description>Only a description.</description>
+		</item>
+		<item>
+			<title>Third</title>
+			<link>http://liftoff.msfc.nasa.gov/3</link>
+			<content:encoded>Only content.</content:encoded>
+		</item>
+	</channel>
+</rss>
+`
+
+	contentAtomXML = `
+<?xml version="1.0" encoding="utf-8"?>
+<feed xmlns="http://www.w3.org/2005/Atom">
+	<title>Example Feed</title>
+	<link href="http://example.org/"/>
+	<entry>
+		<title>First</title>
+		<link href="http://example.org/1"/>
+		<id>urn:uuid:1</id>
+		<summary>A teaser.</summary>
+		<content>The full entry.</content>
+	</entry>
+	<entry>
+		<title>Second</title>
+		<link href="http://example.org/2"/>
+		<id>urn:uuid:2</id>
+		<summary>Same text.</summary>
+		<content>Same text.</content>
+	</entry>
+</feed>
+`
+
+	contentJSON = `
+{
+	"version": "https://jsonfeed.org/version/1",
+	"title": "Example Feed",
+	"items": [
+		{
+			"id": "1",
+			"url": "http://example.org/1",
+			"content_html": "<p>The full item.</p>",
+			"summary": "A teaser."
+		},
+		{
+			"id": "2",
+			"url": "http://example.org/2",
+			"summary": "A teaser."
+		}
+	]
+}
+`
+)
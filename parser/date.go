@@ -0,0 +1,237 @@
+package parser
+
+import (
+	"net/http"
+	"regexp"
+	"strings"
+	"time"
+)
+
+var (
+	// dateLayouts are tried first, as they match most feed dates as is.
+	dateLayouts = []string{
+		time.ANSIC,
+		time.UnixDate,
+		time.RubyDate,
+		time.RFC822,
+		time.RFC822Z,
+		time.RFC850,
+		time.RFC1123,
+		time.RFC1123Z,
+		RFC1123NoSecond,
+		time.RFC3339,
+		time.RFC3339Nano,
+		http.TimeFormat,
+	}
+
+	// normalizedDateLayouts are tried after the date is normalized, with
+	// its month names translated to English and its weekdays removed.
+	// Dates without a time zone are parsed as UTC.
+	normalizedDateLayouts = []string{
+		"2 Jan 2006 15:04:05 -0700",
+		"2 Jan 2006 15:04:05 -07:00",
+		"2 Jan 2006 15:04:05 MST",
+		"2 Jan 2006 15:04:05",
+		"2 Jan 2006 15:04 -0700",
+		"2 Jan 2006 15:04 MST",
+		"2 Jan 2006 15:04",
+		"2 Jan 2006 3:04 PM",
+		"2 Jan 2006",
+		"Jan 2 2006 15:04:05 -0700",
+		"Jan 2 2006 15:04:05 MST",
+		"Jan 2 2006 15:04:05",
+		"Jan 2 2006 15:04",
+		"Jan 2 2006 3:04 PM",
+		"Jan 2 2006",
+		"2006-01-02T15:04:05Z0700",
+		"2006-01-02T15:04Z07:00",
+		"2006-01-02T15:04:05",
+		"2006-01-02T15:04",
+		"2006-01-02 15:04:05 -0700",
+		"2006-01-02 15:04:05 -07:00",
+		"2006-01-02 15:04:05Z07:00",
+		"2006-01-02 15:04:05 MST",
+		"2006-01-02 15:04:05",
+		"2006-01-02 15:04",
+		"2006-01-02",
+		"2006/01/02 15:04:05",
+		"2006/01/02 15:04",
+		"2006/01/02",
+		"2.1.2006 15:04:05",
+		"2.1.2006 15:04",
+		"2.1.2006",
+	}
+
+	// zoneOffsets maps the common time zone abbreviations to their offsets,
+	// since time.Parse assumes a zero offset for the ones that are not
+	// used by the local time zone.
+	zoneOffsets = map[string]string{
+		"EST": "-0500", "EDT": "-0400", "CST": "-0600", "CDT": "-0500",
+		"MST": "-0700", "MDT": "-0600", "PST": "-0800", "PDT": "-0700",
+		"AKST": "-0900", "AKDT": "-0800", "HST": "-1000",
+		"AST": "-0400", "ADT": "-0300", "NST": "-0330", "NDT": "-0230",
+		"WET": "+0000", "WEST": "+0100", "BST": "+0100",
+		"CET": "+0100", "CEST": "+0200", "MEZ": "+0100", "MESZ": "+0200",
+		"EET": "+0200", "EEST": "+0300", "MSK": "+0300", "IST": "+0530",
+		"HKT": "+0800", "SGT": "+0800", "AWST": "+0800", "JST": "+0900", "KST": "+0900",
+		"ACST": "+0930", "ACDT": "+1030", "AEST": "+1000", "AEDT": "+1100",
+		"NZST": "+1200", "NZDT": "+1300",
+	}
+
+	// monthNames maps full and abbreviated month names in a number of
+	// languages to their English abbreviation.
+	monthNames = map[string]string{}
+
+	// dateNoise are the weekday names and filler words that are removed
+	// from a date before it is normalized.
+	dateNoise = map[string]bool{}
+
+	zoneComment  = regexp.MustCompile(`\s*\([^)]*\)$`)
+	dateWord     = regexp.MustCompile(`\pL+\.?`)
+	dateOrdinal  = regexp.MustCompile(`(\d)(st|nd|rd|th|er)\b`)
+	dayDot       = regexp.MustCompile(`(\d)\.(\s|$)`)
+	dateSpace    = regexp.MustCompile(`\s+`)
+	dateTrimmed  = " ,-"
+	dateComma    = strings.NewReplacer(",", " ")
+	monthsByLang = [][12][]string{
+		// English
+		{{"january"}, {"february"}, {"march"}, {"april"}, {"may"}, {"june"},
+			{"july"}, {"august"}, {"september", "sept"}, {"october"}, {"november"}, {"december"}},
+		// German
+		{{"januar", "jänner", "jän"}, {"februar"}, {"märz", "mär", "mrz"}, {"april"}, {"mai"}, {"juni"},
+			{"juli"}, {"august"}, {"september"}, {"oktober", "okt"}, {"november"}, {"dezember", "dez"}},
+		// French
+		{{"janvier", "janv"}, {"février", "févr", "fév"}, {"mars"}, {"avril", "avr"}, {"mai"}, {"juin"},
+			{"juillet", "juil"}, {"août"}, {"septembre"}, {"octobre"}, {"novembre"}, {"décembre", "déc"}},
+		// Spanish
+		{{"enero", "ene"}, {"febrero"}, {"marzo"}, {"abril", "abr"}, {"mayo"}, {"junio"},
+			{"julio"}, {"agosto", "ago"}, {"septiembre", "setiembre"}, {"octubre"}, {"noviembre"}, {"diciembre", "dic"}},
+		// Italian
+		{{"gennaio", "gen"}, {"febbraio"}, {"marzo"}, {"aprile"}, {"maggio", "mag"}, {"giugno", "giu"},
+			{"luglio", "lug"}, {"agosto"}, {"settembre", "set"}, {"ottobre", "ott"}, {"novembre"}, {"dicembre"}},
+		// Portuguese
+		{{"janeiro"}, {"fevereiro", "fev"}, {"março"}, {"abril"}, {"maio"}, {"junho"},
+			{"julho"}, {"agosto"}, {"setembro"}, {"outubro", "out"}, {"novembro"}, {"dezembro"}},
+		// Dutch
+		{{"januari"}, {"februari"}, {"maart", "mrt"}, {"april"}, {"mei"}, {"juni"},
+			{"juli"}, {"augustus"}, {"september"}, {"oktober"}, {"november"}, {"december"}},
+		// Russian, both nominative and genitive
+		{{"январь", "января", "янв"}, {"февраль", "февраля", "фев"}, {"март", "марта", "мар"},
+			{"апрель", "апреля", "апр"}, {"май", "мая"}, {"июнь", "июня", "июн"},
+			{"июль", "июля", "июл"}, {"август", "августа", "авг"}, {"сентябрь", "сентября", "сен"},
+			{"октябрь", "октября", "окт"}, {"ноябрь", "ноября", "ноя"}, {"декабрь", "декабря", "дек"}},
+	}
+	noiseWords = []string{
+		// English
+		"monday", "tuesday", "wednesday", "thursday", "friday", "saturday", "sunday",
+		"mon", "tue", "tues", "wed", "thu", "thur", "thurs", "fri", "sat", "sun", "at", "of",
+		// German
+		"montag", "dienstag", "mittwoch", "donnerstag", "freitag", "samstag", "sonnabend", "sonntag",
+		"mo", "di", "mi", "do", "fr", "sa", "so", "um", "uhr",
+		// French
+		"lundi", "mardi", "mercredi", "jeudi", "vendredi", "samedi", "dimanche",
+		"lun", "mer", "jeu", "ven", "sam", "dim", "le", "à",
+		// Spanish
+		"lunes", "martes", "miércoles", "jueves", "viernes", "sábado", "domingo",
+		"mié", "jue", "vie", "sáb", "dom", "de", "del", "el", "a", "las",
+		// Italian
+		"lunedì", "martedì", "mercoledì", "giovedì", "venerdì", "sabato", "domenica",
+		"gio", "sab", "alle", "ore",
+		// Portuguese
+		"segunda", "terça", "quarta", "quinta", "sexta", "feira", "às",
+		// Dutch
+		"maandag", "dinsdag", "woensdag", "donderdag", "vrijdag", "zaterdag", "zondag",
+		"ma", "wo", "vr", "za", "zo", "om",
+		// Russian
+		"понедельник", "вторник", "среда", "четверг", "пятница", "суббота", "воскресенье",
+		"пн", "вт", "ср", "чт", "пт", "сб", "вс", "г", "года", "в",
+	}
+)
+
+func init() {
+	english := []string{"Jan", "Feb", "Mar", "Apr", "May", "Jun", "Jul", "Aug", "Sep", "Oct", "Nov", "Dec"}
+
+	for _, months := range monthsByLang {
+		for i, names := range months {
+			for _, name := range names {
+				monthNames[name] = english[i]
+
+				// The first three letters are a common abbreviation as well.
+				if short := []rune(name); len(short) > 3 {
+					if _, ok := monthNames[string(short[:3])]; !ok {
+						monthNames[string(short[:3])] = english[i]
+					}
+				}
+			}
+		}
+	}
+
+	for _, word := range noiseWords {
+		dateNoise[word] = true
+	}
+}
+
+// parseDate parses the date using a number of common layouts. Dates that do
+// not match any of them are normalized and parsed again, allowing month
+// names in languages other than English.
+func parseDate(date string) (time.Time, error) {
+	date = normalizeZone(strings.TrimSpace(date))
+
+	var err error
+	var t time.Time
+	for _, f := range dateLayouts {
+		t, err = time.Parse(f, date)
+		if err == nil {
+			return t, nil
+		}
+	}
+
+	normalized := normalizeDate(date)
+	for _, f := range normalizedDateLayouts {
+		if t, nerr := time.Parse(f, normalized); nerr == nil {
+			return t, nil
+		}
+	}
+
+	return t, err
+}
+
+// normalizeZone replaces a trailing time zone abbreviation with its offset.
+func normalizeZone(date string) string {
+	date = zoneComment.ReplaceAllString(date, "")
+
+	i := strings.LastIndexByte(date, ' ')
+	if i == -1 {
+		return date
+	}
+
+	if offset, ok := zoneOffsets[date[i+1:]]; ok {
+		return date[:i+1] + offset
+	}
+
+	return date
+}
+
+// normalizeDate translates the month names of the date to English, and
+// removes its weekday names, filler words, ordinal suffixes and commas.
+func normalizeDate(date string) string {
+	date = dateOrdinal.ReplaceAllString(date, "$1")
+
+	date = dateWord.ReplaceAllStringFunc(date, func(word string) string {
+		lower := strings.ToLower(strings.TrimSuffix(word, "."))
+
+		if month, ok := monthNames[lower]; ok {
+			return month
+		}
+
+		if dateNoise[lower] {
+			return ""
+		}
+
+		return word
+	})
+
+	date = dayDot.ReplaceAllString(dateComma.Replace(date), "$1 ")
+
+	return strings.Trim(dateSpace.ReplaceAllString(strings.TrimSpace(date), " "), dateTrimmed)
+}
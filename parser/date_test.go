@@ -0,0 +1,87 @@
+package parser
+
+import (
+	"testing"
+	"time"
+)
+
+func Test_parseDate(t *testing.T) {
+	date := time.Date(2017, time.October, 3, 14, 5, 0, 0, time.UTC)
+
+	tests := []struct {
+		name    string
+		date    string
+		want    time.Time
+		wantErr bool
+	}{
+		{"rfc1123", "Tue, 03 Oct 2017 14:05:00 GMT", date, false},
+		{"rfc3339", "2017-10-03T14:05:00Z", date, false},
+		{"zone abbreviation", "Tue, 03 Oct 2017 10:05:00 EDT", date, false},
+		{"zone comment", "Tue, 03 Oct 2017 16:05:00 +0200 (CEST)", date, false},
+		{"no zone", "2017-10-03 14:05:00", date, false},
+		{"iso without colon", "2017-10-03T16:05:00+0200", date, false},
+		{"iso without seconds", "2017-10-03T14:05", date, false},
+		{"date only", "2017-10-03", date.Truncate(24 * time.Hour), false},
+		{"full month", "October 3rd, 2017 2:05 PM", date, false},
+		{"day first", "3 October 2017 14:05 -0000", date, false},
+		{"dotted", "03.10.2017 14:05", date, false},
+		{"slashed", "2017/10/03 14:05", date, false},
+		{"german", "Di, 3. Okt 2017 16:05:00 MESZ", date, false},
+		{"german time", "Dienstag, 3. Oktober 2017 um 14:05 Uhr", date, false},
+		{"french", "mardi 3 octobre 2017 à 14:05", date, false},
+		{"french abbreviation", "3 févr. 2017", time.Date(2017, time.February, 3, 0, 0, 0, 0, time.UTC), false},
+		{"spanish", "martes, 3 de octubre de 2017 14:05", date, false},
+		{"italian", "3 ottobre 2017 alle 14:05", date, false},
+		{"portuguese", "terça-feira, 3 de outubro de 2017 às 14:05", date, false},
+		{"dutch", "dinsdag 3 oktober 2017 om 14:05", date, false},
+		{"russian", "3 октября 2017 г. в 14:05", date, false},
+		{"invalid", "yesterday", time.Time{}, true},
+		{"empty", "", time.Time{}, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := parseDate(tt.date)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("parseDate() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+
+			if !got.Equal(tt.want) {
+				t.Errorf("parseDate() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestFeed_InferDates(t *testing.T) {
+	date := time.Date(2017, time.May, 17, 8, 2, 12, 0, time.UTC)
+	modified := time.Date(2017, time.May, 18, 0, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name     string
+		articles []Article
+		want     []time.Time
+	}{
+		{"dated", []Article{{Date: date}}, []time.Time{date}},
+		{"undated", []Article{
+			{Date: unknownTime, DateInferred: true},
+			{Date: unknownTime, DateInferred: true},
+		}, []time.Time{modified, modified.Add(-time.Second)}},
+		{"following dated", []Article{
+			{Date: date},
+			{Date: date.Add(time.Second), DateInferred: true},
+		}, []time.Time{date, date.Add(time.Second)}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			f := Feed{Articles: tt.articles}
+			f.InferDates(modified)
+
+			for i := range f.Articles {
+				if !f.Articles[i].Date.Equal(tt.want[i]) {
+					t.Errorf("Feed.InferDates() article %d date = %v, want %v", i, f.Articles[i].Date, tt.want[i])
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,135 @@
+package parser
+
+import (
+	"strconv"
+	"strings"
+	"time"
+)
+
+// The numeric attributes are kept as strings, since a single malformed value
+// would otherwise fail the decoding of the whole feed.
+type rssEnclosure struct {
+	Url    string `xml:"url,attr"`
+	Type   string `xml:"type,attr"`
+	Length string `xml:"length,attr"`
+}
+
+type mediaContent struct {
+	Url      string `xml:"url,attr"`
+	Type     string `xml:"type,attr"`
+	Medium   string `xml:"medium,attr"`
+	FileSize string `xml:"fileSize,attr"`
+	Duration string `xml:"duration,attr"`
+}
+
+type mediaGroup struct {
+	Content []mediaContent `xml:"http://search.yahoo.com/mrss/ content"`
+}
+
+// mediaItem holds the enclosure-related elements shared by all item types.
+type mediaItem struct {
+	Enclosures     []rssEnclosure `xml:"enclosure"`
+	MediaContent   []mediaContent `xml:"http://search.yahoo.com/mrss/ content"`
+	MediaGroup     []mediaGroup   `xml:"http://search.yahoo.com/mrss/ group"`
+	ItunesDuration string         `xml:"http://www.itunes.com/dtds/podcast-1.0.dtd duration"`
+}
+
+func (m mediaItem) enclosures(links ...atomLink) []Enclosure {
+	var enclosures []Enclosure
+	seen := map[string]int{}
+
+	add := func(e Enclosure) {
+		e.Url = strings.TrimSpace(e.Url)
+		if e.Url == "" {
+			return
+		}
+
+		if i, ok := seen[e.Url]; ok {
+			if enclosures[i].Type == "" {
+				enclosures[i].Type = e.Type
+			}
+			if enclosures[i].Length == 0 {
+				enclosures[i].Length = e.Length
+			}
+			if enclosures[i].Duration == 0 {
+				enclosures[i].Duration = e.Duration
+			}
+
+			return
+		}
+
+		seen[e.Url] = len(enclosures)
+		enclosures = append(enclosures, e)
+	}
+
+	for _, e := range m.Enclosures {
+		add(Enclosure{Url: e.Url, Type: e.Type, Length: parseLength(e.Length)})
+	}
+
+	for _, l := range links {
+		if l.Rel == "enclosure" {
+			add(Enclosure{Url: l.Href, Type: l.Type, Length: parseLength(l.Length)})
+		}
+	}
+
+	media := m.MediaContent
+	for _, g := range m.MediaGroup {
+		media = append(media, g.Content...)
+	}
+
+	for _, c := range media {
+		t := c.Type
+		if t == "" && c.Medium != "" {
+			t = c.Medium + "/*"
+		}
+
+		add(Enclosure{
+			Url:      c.Url,
+			Type:     t,
+			Length:   parseLength(c.FileSize),
+			Duration: parseDuration(c.Duration),
+		})
+	}
+
+	// The itunes duration applies to the episode, which is the first enclosure.
+	if len(enclosures) > 0 && enclosures[0].Duration == 0 {
+		enclosures[0].Duration = parseDuration(m.ItunesDuration)
+	}
+
+	return enclosures
+}
+
+func parseLength(length string) int64 {
+	l, err := strconv.ParseInt(strings.TrimSpace(length), 10, 64)
+	if err != nil || l < 0 {
+		return 0
+	}
+
+	return l
+}
+
+// parseDuration parses durations in the forms of 'SS', 'MM:SS' and
+// 'HH:MM:SS'. Invalid durations are treated as unknown.
+func parseDuration(duration string) time.Duration {
+	duration = strings.TrimSpace(duration)
+	if duration == "" {
+		return 0
+	}
+
+	parts := strings.Split(duration, ":")
+	if len(parts) > 3 {
+		return 0
+	}
+
+	var seconds float64
+	for _, p := range parts {
+		v, err := strconv.ParseFloat(p, 64)
+		if err != nil || v < 0 {
+			return 0
+		}
+
+		seconds = seconds*60 + v
+	}
+
+	return time.Duration(seconds * float64(time.Second))
+}
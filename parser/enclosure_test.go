@@ -0,0 +1,160 @@
+package parser
+
+import (
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestEnclosures(t *testing.T) {
+	tests := []struct {
+		name  string
+		b     []byte
+		parse func([]byte) (Feed, error)
+		want  [][]Enclosure
+	}{
+		{"rss2 podcast", []byte(podcastRss2XML), ParseRss2, [][]Enclosure{
+			{{Url: "http://example.com/ep1.mp3", Type: "audio/mpeg", Length: 24986239, Duration: 41*time.Minute + 32*time.Second}},
+			{{Url: "http://example.com/ep2.mp3", Type: "audio/mpeg", Duration: 5*time.Minute + 2*time.Second}},
+		}},
+		{"rss2 media", []byte(mediaRss2XML), ParseRss2, [][]Enclosure{
+			{
+				{Url: "http://example.com/video.mp4", Type: "video/mp4", Length: 1024, Duration: 90 * time.Second},
+				{Url: "http://example.com/image.jpg", Type: "image/*"},
+			},
+		}},
+		{"atom enclosure link", []byte(enclosureAtomXML), ParseAtom, [][]Enclosure{
+			{{Url: "http://example.org/audio.ogg", Type: "audio/ogg", Length: 1337}},
+		}},
+		{"json attachment", []byte(attachmentJSON), ParseJSON, [][]Enclosure{
+			{{Url: "http://example.org/audio.m4a", Type: "audio/x-m4a", Length: 89970236, Duration: 6629 * time.Second}},
+		}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			f, err := tt.parse(tt.b)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if len(f.Articles) != len(tt.want) {
+				t.Fatalf("articles = %d, want %d", len(f.Articles), len(tt.want))
+			}
+
+			for i := range f.Articles {
+				if !reflect.DeepEqual(f.Articles[i].Enclosures, tt.want[i]) {
+					t.Errorf("article %d enclosures = %v, want %v", i, f.Articles[i].Enclosures, tt.want[i])
+				}
+			}
+		})
+	}
+}
+
+func TestEnclosuresAtomLink(t *testing.T) {
+	f, err := ParseAtom([]byte(enclosureAtomXML))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if f.Articles[0].Link != "http://example.org/2003/12/13/atom03" {
+		t.Errorf("article link = %s, want the alternate link", f.Articles[0].Link)
+	}
+}
+
+func Test_parseDuration(t *testing.T) {
+	tests := []struct {
+		duration string
+		want     time.Duration
+	}{
+		{"", 0},
+		{"90", 90 * time.Second},
+		{"1:30", 90 * time.Second},
+		{"01:01:30", time.Hour + 90*time.Second},
+		{"12.5", 12500 * time.Millisecond},
+		{"1:2:3:4", 0},
+		{"abc", 0},
+		{"-5", 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.duration, func(t *testing.T) {
+			if got := parseDuration(tt.duration); got != tt.want {
+				t.Errorf("parseDuration() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+const (
+	podcastRss2XML = `
+<?xml version="1.0" encoding="UTF-8"?>
+<rss xmlns:itunes="http://www.itunes.com/dtds/podcast-1.0.dtd" version="2.0">
+	<channel>
+		<title>Podcast</title>
+		<link>http://example.com/</link>
+		<item>
+			<title>Episode 1</title>
+			<guid>ep1</guid>
+			<enclosure url="http://example.com/ep1.mp3" length="24986239" type="audio/mpeg" />
+			<itunes:duration>41:32</itunes:duration>
+		</item>
+		<item>
+			<title>Episode 2</title>
+			<guid>ep2</guid>
+			<enclosure url="http://example.com/ep2.mp3" length="unknown" type="audio/mpeg" />
+			<itunes:duration>302</itunes:duration>
+		</item>
+	</channel>
+</rss>
+`
+
+	mediaRss2XML = `
+<?xml version="1.0" encoding="UTF-8"?>
+<rss xmlns:media="http://search.yahoo.com/mrss/" version="2.0">
+	<channel>
+		<title>Videos</title>
+		<link>http://example.com/</link>
+		<item>
+			<title>Video 1</title>
+			<guid>v1</guid>
+			<enclosure url="http://example.com/video.mp4" type="video/mp4" />
+			<media:group>
+				<media:content url="http://example.com/video.mp4" fileSize="1024" duration="90" />
+			</media:group>
+			<media:content url="http://example.com/image.jpg" medium="image" />
+		</item>
+	</channel>
+</rss>
+`
+
+	enclosureAtomXML = `
+<feed xmlns="http://www.w3.org/2005/Atom">
+	<title>Example Feed</title>
+	<link href="http://example.org/"></link>
+	<entry>
+		<title>Atom-Powered Robots Run Amok</title>
+		<id>urn:uuid:1225c695-cfb8-4ebb-aaaa-80da344efa6a</id>
+		<link rel="enclosure" type="audio/ogg" length="1337" href="http://example.org/audio.ogg"/>
+		<link rel="alternate" href="http://example.org/2003/12/13/atom03"></link>
+		<updated>2003-12-13T18:30:02Z</updated>
+		<summary>Some text.</summary>
+	</entry>
+</feed>
+`
+
+	attachmentJSON = `
+{
+	"version": "https://jsonfeed.org/version/1.1",
+	"title": "Example Feed",
+	"items": [
+		{
+			"id": "1",
+			"url": "http://example.org/1",
+			"content_text": "Some text.",
+			"attachments": [
+				{"url": "http://example.org/audio.m4a", "mime_type": "audio/x-m4a", "size_in_bytes": 89970236, "duration_in_seconds": 6629}
+			]
+		}
+	]
+}
+`
+)
@@ -1,25 +1,89 @@
 package parser
 
-import "time"
+import (
+	"strings"
+	"time"
+)
 
 type Feed struct {
 	Title       string
 	Description string
 	SiteLink    string
 	HubLink     string
+	SelfLink    string
 	Image       Image
 	Articles    []Article
 	TTL         time.Duration
 	SkipHours   map[int]bool
 	SkipDays    map[string]bool
+
+	// Charset is the character set the content was converted from, if it
+	// was not utf-8.
+	Charset string
+	// Repairs are the fixes applied to the malformed content.
+	Repairs []Repair
+}
+
+// Diagnostic describes the charset conversion and the repairs that were
+// needed to parse the content of the feed. It is empty if the content was
+// parsed as is.
+func (f Feed) Diagnostic() string {
+	var parts []string
+
+	if f.Charset != "" {
+		parts = append(parts, "converted from "+f.Charset)
+	}
+
+	if len(f.Repairs) > 0 {
+		repairs := make([]string, len(f.Repairs))
+		for i := range f.Repairs {
+			repairs[i] = string(f.Repairs[i])
+		}
+
+		parts = append(parts, "repaired "+strings.Join(repairs, ", "))
+	}
+
+	return strings.Join(parts, "; ")
+}
+
+// InferDates sets the date of the leading articles without a valid date to
+// t, such as the last modification time of the feed, or the time it was
+// fetched. Each following article is dated a second earlier, to preserve
+// their order.
+func (f *Feed) InferDates(t time.Time) {
+	for i := range f.Articles {
+		if !f.Articles[i].DateInferred || !f.Articles[i].Date.Equal(unknownTime) {
+			continue
+		}
+
+		f.Articles[i].Date = t.Add(-time.Duration(i) * time.Second)
+	}
 }
 
 type Article struct {
 	Title       string
 	Description string
+	Summary     string
 	Link        string
 	Guid        string
 	Date        time.Time
+	Author      string
+	Categories  []string
+	Enclosures  []Enclosure
+
+	// DateInferred is true if the article did not have a valid date, and
+	// its date was derived from the surrounding articles or the time the
+	// feed was fetched.
+	DateInferred bool
+}
+
+// Enclosure is a media object attached to an article, such as a podcast
+// episode or a video.
+type Enclosure struct {
+	Url      string
+	Type     string
+	Length   int64
+	Duration time.Duration
 }
 
 type Image struct {
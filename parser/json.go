@@ -0,0 +1,211 @@
+package parser
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"strings"
+	"time"
+)
+
+const (
+	jsonFeedVersionPrefix = "https://jsonfeed.org/version/"
+)
+
+var (
+	utf8BOM = []byte("\xef\xbb\xbf")
+)
+
+type jsonFeed struct {
+	Version     string       `json:"version"`
+	Title       string       `json:"title"`
+	HomePageURL string       `json:"home_page_url"`
+	FeedURL     string       `json:"feed_url"`
+	Description string       `json:"description"`
+	Icon        string       `json:"icon"`
+	Author      jsonAuthor   `json:"author"`
+	Authors     []jsonAuthor `json:"authors"`
+	Favicon     string       `json:"favicon"`
+	Hubs        []jsonHub    `json:"hubs"`
+	Items       []jsonItem   `json:"items"`
+}
+
+type jsonHub struct {
+	Type string `json:"type"`
+	URL  string `json:"url"`
+}
+
+type jsonItem struct {
+	Id            jsonId       `json:"id"`
+	URL           string       `json:"url"`
+	ExternalURL   string       `json:"external_url"`
+	Title         string       `json:"title"`
+	ContentHTML   string       `json:"content_html"`
+	ContentText   string       `json:"content_text"`
+	Summary       string       `json:"summary"`
+	DatePublished string       `json:"date_published"`
+	DateModified  string       `json:"date_modified"`
+	Author        jsonAuthor   `json:"author"`
+	Authors       []jsonAuthor `json:"authors"`
+	Tags          []string     `json:"tags"`
+
+	Attachments []jsonAttachment `json:"attachments"`
+}
+
+type jsonAuthor struct {
+	Name string `json:"name"`
+	URL  string `json:"url"`
+}
+
+type jsonAttachment struct {
+	URL               string  `json:"url"`
+	MIMEType          string  `json:"mime_type"`
+	SizeInBytes       int64   `json:"size_in_bytes"`
+	DurationInSeconds float64 `json:"duration_in_seconds"`
+}
+
+// jsonId holds an item id. The spec requires a string, but version 1 feeds
+// in the wild frequently use plain numbers.
+type jsonId string
+
+func (id *jsonId) UnmarshalJSON(b []byte) error {
+	var s string
+	if err := json.Unmarshal(b, &s); err == nil {
+		*id = jsonId(s)
+		return nil
+	}
+
+	var n json.Number
+	if err := json.Unmarshal(b, &n); err != nil {
+		return err
+	}
+
+	*id = jsonId(n.String())
+
+	return nil
+}
+
+// ParseJSON decodes a JSON Feed, version 1 or 1.1. Since the decoders are
+// tried in order by ParseFeed, it should precede the xml ones, which may fail
+// with a non-recoverable error when given a json document.
+func ParseJSON(b []byte) (Feed, error) {
+	var f Feed
+	var jf jsonFeed
+
+	b = bytes.TrimPrefix(b, utf8BOM)
+	if !isJSONObject(b) {
+		return f, formatError{"json"}
+	}
+
+	if err := json.Unmarshal(b, &jf); err != nil {
+		return f, err
+	}
+
+	if !strings.HasPrefix(jf.Version, jsonFeedVersionPrefix) {
+		return f, formatError{"json"}
+	}
+
+	f = Feed{
+		Title:       jf.Title,
+		Description: jf.Description,
+		SiteLink:    jf.HomePageURL,
+		Image:       Image{Url: jf.Icon},
+	}
+
+	for _, hub := range jf.Hubs {
+		if hub.URL != "" && (hub.Type == "" || hub.Type == "WebSub" || hub.Type == "websub") {
+			f.HubLink = hub.URL
+			break
+		}
+	}
+
+	// Version 1.1 deprecates the singular author in favour of a list.
+	feedAuthor := jsonAuthorName(append(jf.Authors, jf.Author))
+
+	var lastValidDate time.Time
+	for _, i := range jf.Items {
+		article := Article{Title: i.Title, Link: i.URL, Guid: string(i.Id)}
+
+		if article.Author = jsonAuthorName(append(i.Authors, i.Author)); article.Author == "" {
+			article.Author = feedAuthor
+		}
+		article.Categories = uniqueCategories(i.Tags)
+
+		if article.Link == "" {
+			article.Link = i.ExternalURL
+		}
+
+		switch {
+		case i.ContentHTML != "":
+			article.Description = i.ContentHTML
+		case i.ContentText != "":
+			article.Description = i.ContentText
+		default:
+			article.Description = i.Summary
+		}
+
+		if article.Description != i.Summary {
+			article.Summary = i.Summary
+		}
+
+		for _, a := range i.Attachments {
+			if a.URL == "" {
+				continue
+			}
+
+			article.Enclosures = append(article.Enclosures, Enclosure{
+				Url:      a.URL,
+				Type:     a.MIMEType,
+				Length:   a.SizeInBytes,
+				Duration: time.Duration(a.DurationInSeconds * float64(time.Second)),
+			})
+		}
+
+		var err error
+		if i.DatePublished != "" {
+			article.Date, err = parseDate(i.DatePublished)
+		} else if i.DateModified != "" {
+			article.Date, err = parseDate(i.DateModified)
+		} else {
+			err = io.EOF
+		}
+
+		if err == nil {
+			lastValidDate = article.Date.Add(time.Second)
+		} else if lastValidDate.IsZero() {
+			article.Date = unknownTime
+			article.DateInferred = true
+		} else {
+			article.Date = lastValidDate
+			article.DateInferred = true
+		}
+
+		f.Articles = append(f.Articles, article)
+	}
+
+	return f, nil
+}
+
+func jsonAuthorName(authors []jsonAuthor) string {
+	for _, a := range authors {
+		if name := strings.TrimSpace(a.Name); name != "" {
+			return name
+		}
+	}
+
+	return ""
+}
+
+func isJSONObject(b []byte) bool {
+	b = bytes.TrimSpace(b)
+
+	return len(b) > 0 && b[0] == '{'
+}
+
+type formatError struct {
+	format string
+}
+
+func (e formatError) Error() string {
+	return "source is not a valid " + e.format + " feed"
+}
@@ -0,0 +1,188 @@
+package parser
+
+import (
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestParseJSON(t *testing.T) {
+	tests := []struct {
+		name    string
+		b       []byte
+		want    Feed
+		wantErr bool
+	}{
+		{"single", []byte(singleJSON), singleJSONFeed, false},
+		{"single v1.1", []byte(singleJSON11), singleJSON11Feed, false},
+		{"single no date", []byte(singleNoDateJSON), singleNoDateJSONFeed, false},
+		{"multi last no date", []byte(multiLastNoDateJSON), multiLastNoDateJSONFeed, false},
+		{"xml", []byte(singleAtomXML), Feed{}, true},
+		{"unknown version", []byte(`{"version": "foo", "items": []}`), Feed{}, true},
+		{"invalid", []byte(`{"version": "https://jsonfeed.org/version/1"`), Feed{}, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ParseJSON(tt.b)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("ParseJSON() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("ParseJSON() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+var (
+	singleJSONFeed = Feed{
+		Title:       "Example Feed",
+		Description: "An example",
+		SiteLink:    "http://example.org/",
+		HubLink:     "http://hub.example.org/",
+		Image:       Image{Url: "http://example.org/icon.png"},
+		Articles: []Article{
+			{
+				Title:       "JSON-Powered Robots Run Amok",
+				Link:        "http://example.org/2017/05/17/json",
+				Guid:        "2",
+				Description: "<p>Some text.</p>",
+				Date:        time.Date(2017, time.May, 17, 8, 2, 12, 0, time.FixedZone("", -7*60*60)),
+			},
+		},
+	}
+
+	singleJSON11Feed = Feed{
+		Title:    "Example Feed",
+		SiteLink: "http://example.org/",
+		Articles: []Article{
+			{
+				Title:       "JSON-Powered Robots Run Amok",
+				Link:        "http://example.com/external",
+				Guid:        "urn:uuid:1225c695-cfb8-4ebb-aaaa-80da344efa6a",
+				Description: "Some text.",
+				Author:      "John Doe",
+				Date:        time.Date(2017, time.May, 17, 8, 2, 12, 0, time.UTC),
+			},
+		},
+	}
+
+	singleNoDateJSONFeed = Feed{
+		Title:    "Example Feed",
+		SiteLink: "http://example.org/",
+		Articles: []Article{
+			{
+				Title:        "JSON-Powered Robots Run Amok",
+				Link:         "http://example.org/2017/05/17/json",
+				Guid:         "1",
+				Description:  "A summary.",
+				Date:         time.Unix(0, 0),
+				DateInferred: true,
+			},
+		},
+	}
+
+	multiLastNoDateJSONFeed = Feed{
+		Title:    "Example Feed",
+		SiteLink: "http://example.org/",
+		Articles: []Article{
+			{
+				Title:       "JSON-Powered Robots Run Amok",
+				Link:        "http://example.org/2017/05/17/json",
+				Guid:        "1",
+				Description: "Some text.",
+				Date:        time.Date(2017, time.May, 17, 8, 2, 12, 0, time.UTC),
+			},
+			{
+				Title:        "JSON-Powered Robots Run Amok 2",
+				Link:         "http://example.org/2017/05/17/json 2",
+				Guid:         "2",
+				Description:  "Some text. 2",
+				Date:         time.Date(2017, time.May, 17, 8, 2, 13, 0, time.UTC),
+				DateInferred: true,
+			},
+		},
+	}
+)
+
+const (
+	singleJSON = `
+{
+	"version": "https://jsonfeed.org/version/1",
+	"title": "Example Feed",
+	"description": "An example",
+	"home_page_url": "http://example.org/",
+	"feed_url": "http://example.org/feed.json",
+	"icon": "http://example.org/icon.png",
+	"hubs": [{"type": "WebSub", "url": "http://hub.example.org/"}],
+	"items": [
+		{
+			"id": 2,
+			"title": "JSON-Powered Robots Run Amok",
+			"url": "http://example.org/2017/05/17/json",
+			"content_html": "<p>Some text.</p>",
+			"content_text": "Some text.",
+			"date_published": "2017-05-17T08:02:12-07:00"
+		}
+	]
+}
+`
+
+	singleJSON11 = "\xef\xbb\xbf" + `
+{
+	"version": "https://jsonfeed.org/version/1.1",
+	"title": "Example Feed",
+	"home_page_url": "http://example.org/",
+	"authors": [{"name": "John Doe"}],
+	"items": [
+		{
+			"id": "urn:uuid:1225c695-cfb8-4ebb-aaaa-80da344efa6a",
+			"title": "JSON-Powered Robots Run Amok",
+			"external_url": "http://example.com/external",
+			"content_text": "Some text.",
+			"date_modified": "2017-05-17T08:02:12Z"
+		}
+	]
+}
+`
+
+	singleNoDateJSON = `
+{
+	"version": "https://jsonfeed.org/version/1",
+	"title": "Example Feed",
+	"home_page_url": "http://example.org/",
+	"items": [
+		{
+			"id": "1",
+			"title": "JSON-Powered Robots Run Amok",
+			"url": "http://example.org/2017/05/17/json",
+			"summary": "A summary."
+		}
+	]
+}
+`
+
+	multiLastNoDateJSON = `
+{
+	"version": "https://jsonfeed.org/version/1",
+	"title": "Example Feed",
+	"home_page_url": "http://example.org/",
+	"items": [
+		{
+			"id": "1",
+			"title": "JSON-Powered Robots Run Amok",
+			"url": "http://example.org/2017/05/17/json",
+			"content_text": "Some text.",
+			"date_published": "2017-05-17T08:02:12Z"
+		},
+		{
+			"id": "2",
+			"title": "JSON-Powered Robots Run Amok 2",
+			"url": "http://example.org/2017/05/17/json 2",
+			"content_text": "Some text. 2"
+		}
+	]
+}
+`
+)
@@ -7,6 +7,9 @@ import (
 
 type Opml struct {
 	Feeds []OpmlFeed
+	// Folders lists the nested folder outlines, with the innermost ones
+	// first, so that the nesting can be recreated bottom-up.
+	Folders []OpmlFolder
 }
 
 type OpmlFeed struct {
@@ -15,6 +18,12 @@ type OpmlFeed struct {
 	Tags  []string
 }
 
+// OpmlFolder is a folder outline, nested within the Parent one.
+type OpmlFolder struct {
+	Tag    string
+	Parent string
+}
+
 type OpmlXml struct {
 	XMLName xml.Name `xml:"opml"`
 	Version string   `xml:"version,attr"`
@@ -49,12 +58,15 @@ func ParseOpml(content []byte) (Opml, error) {
 		return opml, err
 	}
 
-	processOutline(&opml, o.Body.Outline, "")
+	processOutline(&opml, o.Body.Outline, nil)
+	opml.Feeds = mergeOpmlFeeds(opml.Feeds)
 
 	return opml, nil
 }
 
-func processOutline(opml *Opml, outlines []OpmlOutline, text string) {
+// processOutline collects the feeds, and the folders nested within the
+// folder with the given tags.
+func processOutline(opml *Opml, outlines []OpmlOutline, folder []string) {
 	for _, outline := range outlines {
 		if len(outline.Outline) == 0 {
 			feed := OpmlFeed{Title: outline.Text}
@@ -64,26 +76,76 @@ func processOutline(opml *Opml, outlines []OpmlOutline, text string) {
 				feed.URL = outline.URL
 			}
 
-			tagSet := map[string]struct{}{}
-			if text == "" {
-				if outline.Category != "" {
-					for _, tag := range strings.Split(outline.Category, ",") {
-						tagSet[strings.TrimSpace(tag)] = struct{}{}
+			feed.Tags = appendTags(append([]string{}, folder...), outline.Category)
+			opml.Feeds = append(opml.Feeds, feed)
+		} else {
+			tags := appendTags(nil, outline.Text)
+			processOutline(opml, outline.Outline, tags)
+
+			if len(folder) > 0 {
+				for _, tag := range tags {
+					if tag != folder[0] && !hasOpmlFolder(opml.Folders, tag) {
+						opml.Folders = append(opml.Folders, OpmlFolder{Tag: tag, Parent: folder[0]})
 					}
 				}
-			} else {
-				for _, tag := range strings.Split(text, ",") {
-					tagSet[strings.TrimSpace(tag)] = struct{}{}
-				}
 			}
+		}
+	}
+}
+
+// appendTags appends the comma-separated tags of the list, which are not
+// already present.
+func appendTags(tags []string, list string) []string {
+	if list == "" {
+		return tags
+	}
 
-			feed.Tags = make([]string, 0, len(tagSet))
-			for tag := range tagSet {
-				feed.Tags = append(feed.Tags, tag)
+	for _, tag := range strings.Split(list, ",") {
+		tag = strings.TrimSpace(tag)
+		if tag == "" {
+			continue
+		}
+
+		found := false
+		for _, t := range tags {
+			if t == tag {
+				found = true
+				break
 			}
-			opml.Feeds = append(opml.Feeds, feed)
-		} else {
-			processOutline(opml, outline.Outline, outline.Text)
+		}
+
+		if !found {
+			tags = append(tags, tag)
+		}
+	}
+
+	return tags
+}
+
+func hasOpmlFolder(folders []OpmlFolder, tag string) bool {
+	for _, f := range folders {
+		if f.Tag == tag {
+			return true
 		}
 	}
+
+	return false
+}
+
+// mergeOpmlFeeds combines the tags of feeds listed in multiple folders.
+func mergeOpmlFeeds(feeds []OpmlFeed) []OpmlFeed {
+	merged := feeds[:0]
+	index := map[string]int{}
+
+	for _, feed := range feeds {
+		if i, ok := index[feed.URL]; ok {
+			merged[i].Tags = appendTags(merged[i].Tags, strings.Join(feed.Tags, ","))
+			continue
+		}
+
+		index[feed.URL] = len(merged)
+		merged = append(merged, feed)
+	}
+
+	return merged
 }
@@ -15,6 +15,7 @@ func TestParseOpml(t *testing.T) {
 		{"single", []byte(singleOmplXML), singleOpml, false},
 		{"single url", []byte(singleUrlOmplXML), singleTagsOpml, false},
 		{"deep", []byte(deepOpmlXML), deepOpml, false},
+		{"nested", []byte(nestedOpmlXML), nestedOpml, false},
 		{"error", []byte("<foobar/>"), Opml{}, true},
 	}
 	for _, tt := range tests {
@@ -64,6 +65,24 @@ var (
 			},
 		},
 	}
+	nestedOpml = Opml{
+		Feeds: []OpmlFeed{
+			{
+				Title: "Item 1 text",
+				URL:   "http://www.item1.com/rss",
+				Tags:  []string{"Gadgets", "cool", "News"},
+			},
+			{
+				Title: "Item 2 text",
+				URL:   "http://www.item2.com/rss",
+				Tags:  []string{"Tech"},
+			},
+		},
+		Folders: []OpmlFolder{
+			{Tag: "Gadgets", Parent: "Tech"},
+			{Tag: "Tech", Parent: "News"},
+		},
+	}
 )
 
 const (
@@ -109,5 +128,27 @@ const (
 		</outline>
     </body>
 </opml>
+`
+
+	nestedOpmlXML = `
+<?xml version="1.0" encoding="UTF-8"?>
+<opml version="1.1">
+    <head>
+        <title>
+			OPML title
+		</title>
+    </head>
+    <body>
+		<outline text="News">
+			<outline text="Tech">
+				<outline text="Gadgets">
+					<outline type="rss" text="Item 1 text" title="Item 1 title" xmlUrl="http://www.item1.com/rss" category="cool"></outline>
+				</outline>
+				<outline type="rss" text="Item 2 text" title="Item 2 title" xmlUrl="http://www.item2.com/rss"></outline>
+			</outline>
+			<outline type="rss" text="Item 1 text" title="Item 1 title" xmlUrl="http://www.item1.com/rss"></outline>
+		</outline>
+    </body>
+</opml>
 `
 )
@@ -2,8 +2,6 @@ package parser
 
 import (
 	"encoding/xml"
-	"net/http"
-	"strings"
 	"time"
 )
 
@@ -22,7 +20,9 @@ func ParseFeed(source []byte, funcs ...func([]byte) (Feed, error)) (Feed, error)
 	for _, f := range funcs {
 		feed, err = f(source)
 		if err != nil {
-			if _, ok := err.(xml.UnmarshalError); !ok {
+			switch err.(type) {
+			case xml.UnmarshalError, formatError:
+			default:
 				return feed, err
 			}
 		} else {
@@ -33,31 +33,30 @@ func ParseFeed(source []byte, funcs ...func([]byte) (Feed, error)) (Feed, error)
 	return feed, err
 }
 
-func parseDate(date string) (time.Time, error) {
-	formats := []string{
-		time.ANSIC,
-		time.UnixDate,
-		time.RubyDate,
-		time.RFC822,
-		time.RFC822Z,
-		time.RFC850,
-		time.RFC1123,
-		time.RFC1123Z,
-		RFC1123NoSecond,
-		time.RFC3339,
-		time.RFC3339Nano,
-		http.TimeFormat,
-	}
+// ParseContent parses the downloaded source like ParseFeed, after converting
+// it to utf-8 using the charset of the content type or the xml declaration.
+// Malformed content that fails to parse is repaired and parsed again. The
+// conversion and the applied repairs are recorded in the feed.
+func ParseContent(source []byte, contentType string, funcs ...func([]byte) (Feed, error)) (Feed, error) {
+	b, charset := toUTF8(source, contentType)
+
+	feed, err := ParseFeed(b, funcs...)
+	if err != nil {
+		repaired, repairs := repairXML(b)
+		if len(repairs) == 0 {
+			return feed, err
+		}
 
-	date = strings.TrimSpace(date)
-	var err error
-	var t time.Time
-	for _, f := range formats {
-		t, err = time.Parse(f, date)
-		if err == nil {
-			return t, nil
+		repairedFeed, repairedErr := ParseFeed(repaired, funcs...)
+		if repairedErr != nil {
+			return feed, err
 		}
+
+		feed = repairedFeed
+		feed.Repairs = repairs
 	}
 
-	return t, err
+	feed.Charset = charset
+
+	return feed, nil
 }
@@ -1,6 +1,11 @@
 package parser
 
-import "testing"
+import (
+	"strings"
+	"testing"
+
+	"golang.org/x/text/encoding/charmap"
+)
 
 func TestParseFeed(t *testing.T) {
 	_, err := ParseFeed([]byte(singleAtomXML), ParseRss2, ParseAtom, ParseRss1)
@@ -21,6 +26,18 @@ func TestParseFeed(t *testing.T) {
 		t.Fatal(err)
 	}
 
+	_, err = ParseFeed([]byte(singleJSON), ParseJSON, ParseRss2, ParseAtom, ParseRss1)
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	_, err = ParseFeed([]byte(singleRss2XML), ParseJSON, ParseRss2, ParseAtom, ParseRss1)
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
 	_, err = ParseFeed([]byte(singleRss1XML), ParseRss2, ParseAtom)
 
 	if err == nil {
@@ -32,4 +49,52 @@ func TestParseFeed(t *testing.T) {
 	if err == nil {
 		t.Fatalf("Expected an error\n")
 	}
+
+	_, err = ParseFeed([]byte(singleJSON), ParseRss2, ParseAtom, ParseRss1)
+
+	if err == nil {
+		t.Fatalf("Expected an error\n")
+	}
+}
+
+func TestParseContent(t *testing.T) {
+	cp1251Source := `<?xml version="1.0" encoding="windows-1251"?>
+<rss version="2.0"><channel><title>Новости</title><item><title>Статья</title></item></channel></rss>`
+	cp1251, _ := charmap.Windows1251.NewEncoder().String(cp1251Source)
+	malformedCp1251, _ := charmap.Windows1251.NewEncoder().String(strings.Replace(cp1251Source, "Новости", "Новости & Co", 1))
+
+	tests := []struct {
+		name           string
+		source         string
+		contentType    string
+		wantTitle      string
+		wantDiagnostic string
+		wantErr        bool
+	}{
+		{"valid", singleRss2XML, "application/rss+xml", "Liftoff News", "", false},
+		{"declared charset", cp1251, "text/xml", "Новости", "converted from windows-1251", false},
+		{"content type charset", strings.Replace(cp1251, "windows-1251", "utf-8", 1), "text/xml; charset=windows-1251", "Новости", "converted from windows-1251", false},
+		{"malformed", "<rss version=\"2.0\"><channel><title>Tom & Jerry&nbsp;\x01</title></channel></rss>", "", "Tom & Jerry ",
+			"repaired invalid characters, html entities, unescaped ampersands", false},
+		{"converted and malformed", malformedCp1251, "", "Новости & Co",
+			"converted from windows-1251; repaired unescaped ampersands", false},
+		{"broken", "<rss version=\"2.0\"><channel><title>Broken</channel></rss>", "", "", "", true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ParseContent([]byte(tt.source), tt.contentType, ParseJSON, ParseRss2, ParseAtom, ParseRss1)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("ParseContent() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+
+			if got.Title != tt.wantTitle {
+				t.Errorf("ParseContent() title = %q, want %q", got.Title, tt.wantTitle)
+			}
+
+			if got.Diagnostic() != tt.wantDiagnostic {
+				t.Errorf("ParseContent() diagnostic = %q, want %q", got.Diagnostic(), tt.wantDiagnostic)
+			}
+		})
+	}
 }
@@ -0,0 +1,189 @@
+package parser
+
+import (
+	"bytes"
+	"encoding/xml"
+	"strconv"
+	"unicode/utf8"
+)
+
+// Repair is a fix applied to malformed content so that it could be parsed.
+type Repair string
+
+const (
+	// RepairInvalidChars removes characters that are not allowed in xml,
+	// such as control characters, and replaces invalid utf-8 sequences.
+	RepairInvalidChars Repair = "invalid characters"
+	// RepairEntities replaces the html entities that are not defined in xml
+	// with character references.
+	RepairEntities Repair = "html entities"
+	// RepairAmpersands escapes the ampersands that do not start a
+	// reference.
+	RepairAmpersands Repair = "unescaped ampersands"
+)
+
+var (
+	cdataStart   = []byte("<![CDATA[")
+	cdataEnd     = []byte("]]>")
+	commentStart = []byte("<!--")
+	commentEnd   = []byte("-->")
+
+	xmlEntities = map[string]bool{"amp": true, "lt": true, "gt": true, "quot": true, "apos": true}
+)
+
+// maxReferenceLength is the longest reference, including the ampersand and
+// the semicolon, that is looked up. It is enough for the html entity names
+// and any character reference.
+const maxReferenceLength = 34
+
+// repairXML fixes common malformations of xml content, returning the
+// repaired content along with the applied repairs. Comments and CDATA
+// sections are only checked for invalid characters.
+func repairXML(b []byte) ([]byte, []Repair) {
+	var repairs []Repair
+
+	b, ok := repairChars(b)
+	if !ok {
+		repairs = append(repairs, RepairInvalidChars)
+	}
+
+	b, entities, ampersands := repairReferences(b)
+	if entities {
+		repairs = append(repairs, RepairEntities)
+	}
+
+	if ampersands {
+		repairs = append(repairs, RepairAmpersands)
+	}
+
+	return b, repairs
+}
+
+// repairChars drops the characters that are not allowed in xml, and
+// replaces the invalid utf-8 sequences. It returns false if any changes were
+// made.
+func repairChars(b []byte) ([]byte, bool) {
+	var buf bytes.Buffer
+	ok := true
+
+	for i := 0; i < len(b); {
+		r, size := utf8.DecodeRune(b[i:])
+
+		switch {
+		case r == utf8.RuneError && size == 1:
+			if ok {
+				buf.Write(b[:i])
+				ok = false
+			}
+			buf.WriteRune(utf8.RuneError)
+		case !isXMLChar(r):
+			if ok {
+				buf.Write(b[:i])
+				ok = false
+			}
+		case !ok:
+			buf.Write(b[i : i+size])
+		}
+
+		i += size
+	}
+
+	if ok {
+		return b, true
+	}
+
+	return buf.Bytes(), false
+}
+
+// repairReferences replaces html entities with character references, and
+// escapes ampersands that do not start a valid reference.
+func repairReferences(b []byte) ([]byte, bool, bool) {
+	var buf bytes.Buffer
+	var entities, ampersands bool
+
+	buf.Grow(len(b))
+
+	for i := 0; i < len(b); {
+		switch {
+		case bytes.HasPrefix(b[i:], cdataStart):
+			i = copySection(&buf, b, i, cdataEnd)
+		case bytes.HasPrefix(b[i:], commentStart):
+			i = copySection(&buf, b, i, commentEnd)
+		case b[i] == '&':
+			lookahead := b[i:]
+			if len(lookahead) > maxReferenceLength {
+				lookahead = lookahead[:maxReferenceLength]
+			}
+
+			end := bytes.IndexByte(lookahead, ';')
+			if end == -1 {
+				buf.WriteString("&amp;")
+				ampersands = true
+				i++
+				break
+			}
+
+			name := string(b[i+1 : i+end])
+			if isCharRef(name) || xmlEntities[name] {
+				buf.Write(b[i : i+end+1])
+				i += end + 1
+			} else if entity, ok := xml.HTMLEntity[name]; ok {
+				for _, r := range entity {
+					buf.WriteString("&#" + strconv.Itoa(int(r)) + ";")
+				}
+				entities = true
+				i += end + 1
+			} else {
+				buf.WriteString("&amp;")
+				ampersands = true
+				i++
+			}
+		default:
+			buf.WriteByte(b[i])
+			i++
+		}
+	}
+
+	if !entities && !ampersands {
+		return b, false, false
+	}
+
+	return buf.Bytes(), entities, ampersands
+}
+
+// copySection copies the section starting at i up to and including its end
+// marker, or the rest of the content if the section is not terminated.
+func copySection(buf *bytes.Buffer, b []byte, i int, end []byte) int {
+	n := bytes.Index(b[i:], end)
+	if n == -1 {
+		buf.Write(b[i:])
+		return len(b)
+	}
+
+	n += i + len(end)
+	buf.Write(b[i:n])
+
+	return n
+}
+
+func isCharRef(name string) bool {
+	if len(name) < 2 || name[0] != '#' {
+		return false
+	}
+
+	var err error
+	if name[1] == 'x' {
+		_, err = strconv.ParseUint(name[2:], 16, 32)
+	} else {
+		_, err = strconv.ParseUint(name[1:], 10, 32)
+	}
+
+	return err == nil
+}
+
+func isXMLChar(r rune) bool {
+	return r == 0x09 || r == 0x0A || r == 0x0D ||
+		r >= 0x20 && r <= 0xD7FF ||
+		r >= 0xE000 && r <= 0xFFFD ||
+		r >= 0x10000 && r <= 0x10FFFF
+}
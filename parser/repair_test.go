@@ -0,0 +1,36 @@
+package parser
+
+import (
+	"reflect"
+	"testing"
+)
+
+func Test_repairXML(t *testing.T) {
+	tests := []struct {
+		name        string
+		source      string
+		want        string
+		wantRepairs []Repair
+	}{
+		{"valid", `<p a="1">Tom &amp; Jerry &#38; &#x26; &lt;b&gt;</p>`, `<p a="1">Tom &amp; Jerry &#38; &#x26; &lt;b&gt;</p>`, nil},
+		{"ampersands", `<a href="/?a=1&b=2">Tom & Jerry &</a>`, `<a href="/?a=1&amp;b=2">Tom &amp; Jerry &amp;</a>`, []Repair{RepairAmpersands}},
+		{"entities", `<p>&nbsp;&eacute;&amp;</p>`, `<p>&#160;&#233;&amp;</p>`, []Repair{RepairEntities}},
+		{"invalid characters", "<p>a\x00b\x1Bc\td\xFFe</p>", "<p>abc\td�e</p>", []Repair{RepairInvalidChars}},
+		{"cdata and comments", `<p><![CDATA[Tom & Jerry&nbsp;]]><!-- & --></p>`, `<p><![CDATA[Tom & Jerry&nbsp;]]><!-- & --></p>`, nil},
+		{"unterminated cdata", `<p><![CDATA[Tom & Jerry`, `<p><![CDATA[Tom & Jerry`, nil},
+		{"distant semicolon", `<p>Tom & Jerry, a long enough story; the end</p>`, `<p>Tom &amp; Jerry, a long enough story; the end</p>`, []Repair{RepairAmpersands}},
+		{"all", "<p>\x01&copy; Tom & Jerry</p>", `<p>&#169; Tom &amp; Jerry</p>`, []Repair{RepairInvalidChars, RepairEntities, RepairAmpersands}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, repairs := repairXML([]byte(tt.source))
+			if string(got) != tt.want {
+				t.Errorf("repairXML() = %q, want %q", got, tt.want)
+			}
+
+			if !reflect.DeepEqual(repairs, tt.wantRepairs) {
+				t.Errorf("repairXML() repairs = %v, want %v", repairs, tt.wantRepairs)
+			}
+		})
+	}
+}
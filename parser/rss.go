@@ -24,9 +24,15 @@ type RssItem struct {
 	Content     rssContent `xml:"http://purl.org/rss/1.0/modules/content/ encoded"`
 	PubDate     string     `xml:"pubDate"`
 	Date        string     `xml:"date"`
+	Author      string     `xml:"author"`
+	Creator     []string   `xml:"http://purl.org/dc/elements/1.1/ creator"`
+	Categories  []string   `xml:"category"`
+	Subjects    []string   `xml:"http://purl.org/dc/elements/1.1/ subject"`
 	TTL         int        `xml:"ttl"`
 	SkipHours   []int      `xml:"skipHours>hour"`
 	SkipDays    []string   `xml:"skipDays>day"`
+
+	mediaItem
 }
 
 type rssContent struct {
@@ -43,12 +49,64 @@ func (c rssContent) Content() string {
 	}
 }
 
-func getLargerContent(first, second rssContent) string {
-	c1, c2 := first.Content(), second.Content()
+// splitContent returns the full body of an item, preferring the content
+// element over the summary, together with the summary if it differs from
+// the chosen body.
+func splitContent(content, summary rssContent) (string, string) {
+	c, s := content.Content(), summary.Content()
 
-	if len(c1) < len(c2) {
-		return c2
-	} else {
-		return c1
+	if strings.TrimSpace(c) == "" {
+		return s, ""
+	}
+
+	if strings.TrimSpace(s) == "" || s == c {
+		return c, ""
+	}
+
+	return c, s
+}
+
+func (i RssItem) author() string {
+	for _, c := range i.Creator {
+		if c = strings.TrimSpace(c); c != "" {
+			return c
+		}
+	}
+
+	return rssAuthor(i.Author)
+}
+
+func (i RssItem) categories() []string {
+	return uniqueCategories(append(i.Categories, i.Subjects...))
+}
+
+// rssAuthor extracts the name from the 'email (name)' form of the rss2
+// author element, falling back to the whole value.
+func rssAuthor(author string) string {
+	author = strings.TrimSpace(author)
+
+	if start, end := strings.IndexByte(author, '('), strings.LastIndexByte(author, ')'); start > 0 && end > start+1 {
+		if name := strings.TrimSpace(author[start+1 : end]); name != "" {
+			return name
+		}
 	}
+
+	return author
+}
+
+func uniqueCategories(categories []string) []string {
+	var unique []string
+	seen := map[string]bool{}
+
+	for _, c := range categories {
+		c = strings.TrimSpace(c)
+		if c == "" || seen[strings.ToLower(c)] {
+			continue
+		}
+
+		seen[strings.ToLower(c)] = true
+		unique = append(unique, c)
+	}
+
+	return unique
 }
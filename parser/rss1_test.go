@@ -82,7 +82,8 @@ var (
 				Description: `
 	Descr 1
 	`,
-				Date: time.Unix(0, 0),
+				Date:         time.Unix(0, 0),
+				DateInferred: true,
 			},
 		},
 	}
@@ -118,7 +119,8 @@ var (
 				Description: `
 	Descr 2
 	`,
-				Date: time.Date(2003, time.June, 3, 9, 39, 22, 0, gmt),
+				Date:         time.Date(2003, time.June, 3, 9, 39, 22, 0, gmt),
+				DateInferred: true,
 			},
 		},
 	}
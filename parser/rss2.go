@@ -62,7 +62,10 @@ func ParseRss2(b []byte) (Feed, error) {
 	var lastValidDate time.Time
 	for _, i := range rss.Channel.Items {
 		article := Article{Title: i.Title, Link: i.Link, Guid: i.Id}
-		article.Description = getLargerContent(i.Content, i.Description)
+		article.Description, article.Summary = splitContent(i.Content, i.Description)
+		article.Author = i.author()
+		article.Categories = i.categories()
+		article.Enclosures = i.enclosures()
 
 		var err error
 		if i.PubDate != "" {
@@ -77,8 +80,10 @@ func ParseRss2(b []byte) (Feed, error) {
 			lastValidDate = article.Date.Add(time.Second)
 		} else if lastValidDate.IsZero() {
 			article.Date = unknownTime
+			article.DateInferred = true
 		} else {
 			article.Date = lastValidDate
+			article.DateInferred = true
 		}
 
 		f.Articles = append(f.Articles, article)
@@ -62,11 +62,12 @@ var (
 		SkipDays:    map[string]bool{"Monday": true, "Saturday": true},
 		Articles: []Article{
 			{
-				Title:       "Star City",
-				Link:        "http://liftoff.msfc.nasa.gov/news/2003/news-starcity.asp",
-				Guid:        "http://liftoff.msfc.nasa.gov/2003/06/03.html#item573",
-				Description: `How do Americans get ready to work with Russians aboard the International Space Station? They take a crash course in culture, language and protocol at Russia's <a href="http://howe.iki.rssi.ru/GCTC/gctc_e.htm">Star City</a>.`,
-				Date:        time.Unix(0, 0),
+				Title:        "Star City",
+				Link:         "http://liftoff.msfc.nasa.gov/news/2003/news-starcity.asp",
+				Guid:         "http://liftoff.msfc.nasa.gov/2003/06/03.html#item573",
+				Description:  `How do Americans get ready to work with Russians aboard the International Space Station? They take a crash course in culture, language and protocol at Russia's <a href="http://howe.iki.rssi.ru/GCTC/gctc_e.htm">Star City</a>.`,
+				Date:         time.Unix(0, 0),
+				DateInferred: true,
 			},
 		},
 	}
@@ -87,11 +88,12 @@ var (
 				Date:        time.Date(2003, time.June, 3, 9, 39, 21, 0, gmt),
 			},
 			{
-				Title:       "",
-				Link:        "",
-				Guid:        "http://liftoff.msfc.nasa.gov/2003/05/30.html#item572",
-				Description: `Descr 2`,
-				Date:        time.Date(2003, time.June, 3, 9, 39, 22, 0, gmt),
+				Title:        "",
+				Link:         "",
+				Guid:         "http://liftoff.msfc.nasa.gov/2003/05/30.html#item572",
+				Description:  `Descr 2`,
+				Date:         time.Date(2003, time.June, 3, 9, 39, 22, 0, gmt),
+				DateInferred: true,
 			},
 		},
 	}
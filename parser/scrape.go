@@ -0,0 +1,200 @@
+package parser
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/PuerkitoBio/goquery"
+	"github.com/andybalholm/cascadia"
+)
+
+// ScrapeRules are the CSS selectors used to extract the articles of an HTML
+// page, for sites that do not publish a feed. Apart from the item, the
+// selectors are matched within each item.
+type ScrapeRules struct {
+	// Item selects the element containing each article.
+	Item string `json:"item"`
+	// Title selects the article title, which defaults to the text of the
+	// article link.
+	Title string `json:"title,omitempty"`
+	// Link selects the article link, which defaults to the first link in
+	// the item. The href of the selected element or its first link is used.
+	Link string `json:"link,omitempty"`
+	// Date selects the article date, preferring its datetime attribute,
+	// such as the one of the time element, over its text.
+	Date string `json:"date,omitempty"`
+	// Body selects the article content.
+	Body string `json:"body,omitempty"`
+}
+
+type scrapeMatchers struct {
+	item, title, link, date, body cascadia.Selector
+}
+
+var (
+	ErrNoScrapedArticles = errors.New("No articles matched the scrape rules")
+
+	anchorMatcher = cascadia.MustCompile("a[href]")
+	errNoDate     = errors.New("no date")
+)
+
+// Empty returns true if the rules do not select any items.
+func (r ScrapeRules) Empty() bool {
+	return r.Item == ""
+}
+
+// Validate checks whether the selectors of the rules can be compiled.
+func (r ScrapeRules) Validate() error {
+	_, err := r.compile()
+
+	return err
+}
+
+func (r ScrapeRules) compile() (scrapeMatchers, error) {
+	var m scrapeMatchers
+
+	if r.Item == "" {
+		return m, errors.New("no item selector")
+	}
+
+	for _, s := range []struct {
+		name     string
+		selector string
+		matcher  *cascadia.Selector
+	}{
+		{"item", r.Item, &m.item},
+		{"title", r.Title, &m.title},
+		{"link", r.Link, &m.link},
+		{"date", r.Date, &m.date},
+		{"body", r.Body, &m.body},
+	} {
+		if s.selector == "" {
+			continue
+		}
+
+		matcher, err := cascadia.Compile(s.selector)
+		if err != nil {
+			return m, fmt.Errorf("invalid %s selector '%s': %v", s.name, s.selector, err)
+		}
+
+		*s.matcher = matcher
+	}
+
+	return m, nil
+}
+
+// ScrapeHTML converts the HTML page downloaded from the link into a feed,
+// using the rules to find its articles. Relative article links are resolved
+// against the page link.
+func ScrapeHTML(b []byte, link string, rules ScrapeRules) (Feed, error) {
+	var f Feed
+
+	m, err := rules.compile()
+	if err != nil {
+		return f, err
+	}
+
+	base, err := url.Parse(link)
+	if err != nil {
+		return f, err
+	}
+
+	doc, err := goquery.NewDocumentFromReader(bytes.NewReader(b))
+	if err != nil {
+		return f, err
+	}
+
+	f.Title = collapseSpace(doc.Find("head title").First().Text())
+	f.Description, _ = doc.Find(`head meta[name="description"]`).Attr("content")
+	f.SiteLink = link
+
+	var lastValidDate time.Time
+	doc.FindMatcher(m.item).Each(func(i int, item *goquery.Selection) {
+		linkMatcher := m.link
+		if linkMatcher == nil {
+			linkMatcher = anchorMatcher
+		}
+
+		linkSel := firstMatch(item, linkMatcher)
+		if !linkSel.Is("[href]") {
+			linkSel = linkSel.FindMatcher(anchorMatcher).First()
+		}
+
+		href, ok := linkSel.Attr("href")
+		if !ok {
+			return
+		}
+
+		u, err := base.Parse(strings.TrimSpace(href))
+		if err != nil {
+			return
+		}
+		u.Fragment = ""
+
+		article := Article{Link: u.String(), Guid: u.String()}
+
+		if m.title != nil {
+			article.Title = collapseSpace(item.FindMatcher(m.title).First().Text())
+		} else {
+			article.Title = collapseSpace(linkSel.Text())
+		}
+
+		if article.Title == "" {
+			article.Title = article.Link
+		}
+
+		if m.body != nil {
+			article.Description, _ = item.FindMatcher(m.body).First().Html()
+		}
+
+		err = errNoDate
+		if m.date != nil {
+			dateSel := item.FindMatcher(m.date).First()
+
+			date, ok := dateSel.Attr("datetime")
+			if !ok {
+				date = dateSel.Text()
+			}
+
+			if date != "" {
+				article.Date, err = parseDate(date)
+			}
+		}
+
+		if err == nil {
+			lastValidDate = article.Date.Add(time.Second)
+		} else if lastValidDate.IsZero() {
+			article.Date = unknownTime
+			article.DateInferred = true
+		} else {
+			article.Date = lastValidDate
+			article.DateInferred = true
+		}
+
+		f.Articles = append(f.Articles, article)
+	})
+
+	if len(f.Articles) == 0 {
+		return f, ErrNoScrapedArticles
+	}
+
+	return f, nil
+}
+
+// firstMatch returns the selection itself if it is matched by the matcher,
+// or its first matching descendant otherwise.
+func firstMatch(s *goquery.Selection, matcher cascadia.Selector) *goquery.Selection {
+	if s.IsMatcher(matcher) {
+		return s
+	}
+
+	return s.FindMatcher(matcher).First()
+}
+
+func collapseSpace(s string) string {
+	return strings.Join(strings.Fields(s), " ")
+}
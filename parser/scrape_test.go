@@ -0,0 +1,139 @@
+package parser
+
+import (
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestScrapeHTML(t *testing.T) {
+	tests := []struct {
+		name    string
+		b       []byte
+		rules   ScrapeRules
+		want    Feed
+		wantErr bool
+	}{
+		{"full rules", []byte(scrapeHTML), ScrapeRules{
+			Item: "article.post", Title: "h2", Link: "h2 a", Date: "time", Body: ".content",
+		}, scrapeFullFeed, false},
+		{"default link and title", []byte(scrapeHTML), ScrapeRules{Item: "article.post"}, scrapeDefaultFeed, false},
+		{"item link", []byte(scrapeHTML), ScrapeRules{Item: "ul.archive a"}, scrapeItemLinkFeed, false},
+		{"no articles", []byte(scrapeHTML), ScrapeRules{Item: "div.missing"}, Feed{}, true},
+		{"no item", []byte(scrapeHTML), ScrapeRules{}, Feed{}, true},
+		{"invalid selector", []byte(scrapeHTML), ScrapeRules{Item: "article[", Title: "h2"}, Feed{}, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ScrapeHTML(tt.b, "http://example.org/blog/", tt.rules)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("ScrapeHTML() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+
+			if tt.wantErr {
+				return
+			}
+
+			// Parsed zone offsets aren't comparable with DeepEqual.
+			for i := range got.Articles {
+				got.Articles[i].Date = got.Articles[i].Date.UTC()
+			}
+
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("ScrapeHTML() = %#v, want %#v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestScrapeRules_Validate(t *testing.T) {
+	tests := []struct {
+		name    string
+		rules   ScrapeRules
+		wantErr bool
+	}{
+		{"valid", ScrapeRules{Item: "article", Title: "h2", Link: "a.more", Date: "time", Body: "div > p"}, false},
+		{"item only", ScrapeRules{Item: "article"}, false},
+		{"no item", ScrapeRules{Title: "h2"}, true},
+		{"invalid date", ScrapeRules{Item: "article", Date: "time["}, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := tt.rules.Validate(); (err != nil) != tt.wantErr {
+				t.Errorf("ScrapeRules.Validate() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+const scrapeHTML = `<!DOCTYPE html>
+<html>
+<head>
+	<title>
+		Example Blog
+	</title>
+	<meta name="description" content="An example blog">
+</head>
+<body>
+	<article class="post">
+		<h2><a href="/blog/first#top">First   post</a></h2>
+		<time datetime="2017-05-17T08:02:12Z">May 17</time>
+		<div class="content"><p>The first post.</p></div>
+	</article>
+	<article class="post">
+		<h2><a href="second">Second post</a></h2>
+		<time>Wed, 17 May 2017 09:02:12 +0000</time>
+		<div class="content"><p>The second post.</p></div>
+	</article>
+	<article class="post">
+		<h2><a href="http://example.com/third">Third post</a></h2>
+		<div class="content"><p>The third post.</p></div>
+	</article>
+	<article class="post">
+		<h2>No link</h2>
+	</article>
+	<ul class="archive">
+		<li><a href="/blog/old">Old post</a></li>
+		<li><a href="/blog/older"></a></li>
+	</ul>
+</body>
+</html>
+`
+
+var (
+	scrapeFirstDate  = time.Date(2017, 5, 17, 8, 2, 12, 0, time.UTC)
+	scrapeSecondDate = time.Date(2017, 5, 17, 9, 2, 12, 0, time.UTC)
+
+	scrapeFullFeed = Feed{
+		Title:       "Example Blog",
+		Description: "An example blog",
+		SiteLink:    "http://example.org/blog/",
+		Articles: []Article{
+			{Title: "First post", Link: "http://example.org/blog/first", Guid: "http://example.org/blog/first", Date: scrapeFirstDate, Description: "<p>The first post.</p>"},
+			{Title: "Second post", Link: "http://example.org/blog/second", Guid: "http://example.org/blog/second", Date: scrapeSecondDate, Description: "<p>The second post.</p>"},
+			{Title: "Third post", Link: "http://example.com/third", Guid: "http://example.com/third", Date: scrapeSecondDate.Add(time.Second), DateInferred: true, Description: "<p>The third post.</p>"},
+		},
+	}
+
+	scrapeDefaultFeed = Feed{
+		Title:       "Example Blog",
+		Description: "An example blog",
+		SiteLink:    "http://example.org/blog/",
+		Articles: []Article{
+			{Title: "First post", Link: "http://example.org/blog/first", Guid: "http://example.org/blog/first", Date: unknownTime.UTC(), DateInferred: true},
+			{Title: "Second post", Link: "http://example.org/blog/second", Guid: "http://example.org/blog/second", Date: unknownTime.UTC(), DateInferred: true},
+			{Title: "Third post", Link: "http://example.com/third", Guid: "http://example.com/third", Date: unknownTime.UTC(), DateInferred: true},
+		},
+	}
+
+	scrapeItemLinkFeed = Feed{
+		Title:       "Example Blog",
+		Description: "An example blog",
+		SiteLink:    "http://example.org/blog/",
+		Articles: []Article{
+			{Title: "Old post", Link: "http://example.org/blog/old", Guid: "http://example.org/blog/old", Date: unknownTime.UTC(), DateInferred: true},
+			{Title: "http://example.org/blog/older", Link: "http://example.org/blog/older", Guid: "http://example.org/blog/older", Date: unknownTime.UTC(), DateInferred: true},
+		},
+	}
+)
@@ -9,7 +9,9 @@ import (
 	"net/url"
 )
 
-type Facebook struct{}
+type Facebook struct {
+	client *http.Client
+}
 
 type facebookResult struct {
 	Shares   int64 `json:"share_count"`
@@ -22,7 +24,7 @@ func (f Facebook) Score(link string) (int64, error) {
 
 	link = url.QueryEscape(link)
 
-	r, err := http.Get("https://api.facebook.com/method/links.getStats?urls=" + link + "&format=json")
+	r, err := f.client.Get("https://api.facebook.com/method/links.getStats?urls=" + link + "&format=json")
 
 	if err != nil {
 		return score, err
@@ -10,7 +10,9 @@ import (
 	"github.com/urandom/readeef/pool"
 )
 
-type GoogleP struct{}
+type GoogleP struct {
+	client *http.Client
+}
 
 type googlepResult struct {
 	Result struct {
@@ -53,7 +55,7 @@ func (f GoogleP) Score(link string) (int64, error) {
 		return score, err
 	}
 
-	r, err := http.Post("https://clients6.google.com/rpc", "application/json", buf)
+	r, err := f.client.Post("https://clients6.google.com/rpc", "application/json", buf)
 
 	if err != nil {
 		return score, err
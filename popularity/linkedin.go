@@ -9,7 +9,9 @@ import (
 	"net/url"
 )
 
-type Linkedin struct{}
+type Linkedin struct {
+	client *http.Client
+}
 
 type linkedinResult struct {
 	Count int64 `json:"count"`
@@ -20,7 +22,7 @@ func (l Linkedin) Score(link string) (int64, error) {
 
 	link = url.QueryEscape(link)
 
-	r, err := http.Get("http://www.linkedin.com/countserv/count/share?url=" + link + "&format=json")
+	r, err := l.client.Get("http://www.linkedin.com/countserv/count/share?url=" + link + "&format=json")
 
 	if err != nil {
 		return score, err
@@ -3,6 +3,7 @@ package popularity
 import (
 	"context"
 	"fmt"
+	"net/http"
 	"runtime"
 	"sync"
 	"time"
@@ -34,24 +35,26 @@ type scoreResponse struct {
 	err   error
 }
 
-func New(config config.Popularity, log log.Log) Popularity {
+// New creates the configured score providers, which query their services
+// with the given client.
+func New(config config.Popularity, client *http.Client, log log.Log) Popularity {
 	p := Popularity{delay: config.Converted.Delay, log: log}
 
 	scoreProviders := []scoreProvider{}
 	for _, p := range config.Providers {
 		switch p {
 		case "Facebook":
-			scoreProviders = append(scoreProviders, Facebook{})
+			scoreProviders = append(scoreProviders, Facebook{client: client})
 		case "GoogleP":
-			scoreProviders = append(scoreProviders, GoogleP{})
+			scoreProviders = append(scoreProviders, GoogleP{client: client})
 		case "Linkedin":
-			scoreProviders = append(scoreProviders, Linkedin{})
+			scoreProviders = append(scoreProviders, Linkedin{client: client})
 		case "Reddint":
-			scoreProviders = append(scoreProviders, Reddit{})
+			scoreProviders = append(scoreProviders, Reddit{client: client})
 		case "StumbleUpon":
-			scoreProviders = append(scoreProviders, StumbleUpon{})
+			scoreProviders = append(scoreProviders, StumbleUpon{client: client})
 		case "Twitter":
-			scoreProviders = append(scoreProviders, NewTwitter(config))
+			scoreProviders = append(scoreProviders, NewTwitter(config, client))
 		}
 	}
 
@@ -9,7 +9,9 @@ import (
 	"net/url"
 )
 
-type Reddit struct{}
+type Reddit struct {
+	client *http.Client
+}
 
 type redditResult struct {
 	Data struct {
@@ -27,7 +29,7 @@ func (r Reddit) Score(link string) (int64, error) {
 
 	link = url.QueryEscape(link)
 
-	resp, err := http.Get("http://buttons.reddit.com/button_info.json?url=" + link)
+	resp, err := r.client.Get("http://buttons.reddit.com/button_info.json?url=" + link)
 
 	if err != nil {
 		return score, err
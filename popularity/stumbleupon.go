@@ -9,7 +9,9 @@ import (
 	"net/url"
 )
 
-type StumbleUpon struct{}
+type StumbleUpon struct {
+	client *http.Client
+}
 
 type StumbleUponResult struct {
 	Result struct {
@@ -22,7 +24,7 @@ func (t StumbleUpon) Score(link string) (int64, error) {
 
 	link = url.QueryEscape(link)
 
-	r, err := http.Get("http://www.stumbleupon.com/services/1.01/badge.getinfo?url=" + link)
+	r, err := t.client.Get("http://www.stumbleupon.com/services/1.01/badge.getinfo?url=" + link)
 
 	if err != nil {
 		return score, err
@@ -2,6 +2,7 @@ package popularity
 
 import (
 	"fmt"
+	"net/http"
 	"net/url"
 
 	"github.com/ChimeraCoder/anaconda"
@@ -12,11 +13,14 @@ type Twitter struct {
 	api *anaconda.TwitterApi
 }
 
-func NewTwitter(config config.Popularity) Twitter {
+func NewTwitter(config config.Popularity, client *http.Client) Twitter {
 	anaconda.SetConsumerKey(config.Twitter.ConsumerKey)
 	anaconda.SetConsumerSecret(config.Twitter.ConsumerSecret)
 
-	return Twitter{api: anaconda.NewTwitterApi(config.Twitter.AccessToken, config.Twitter.AccessTokenSecret)}
+	api := anaconda.NewTwitterApi(config.Twitter.AccessToken, config.Twitter.AccessTokenSecret)
+	api.HttpClient = client
+
+	return Twitter{api: api}
 }
 
 func (t Twitter) Score(link string) (int64, error) {
@@ -0,0 +1,638 @@
+package readeef
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/urandom/readeef/config"
+	"github.com/urandom/readeef/content"
+	"github.com/urandom/readeef/content/repo"
+	"github.com/urandom/readeef/content/repo/eventable"
+	"github.com/urandom/readeef/log"
+)
+
+// Publisher serves Atom feeds generated from the favorites, tags and filters
+// of the users, and acts as their WebSub hub, pushing the new articles of
+// the feeds to the downstream subscribers.
+type Publisher struct {
+	service  repo.Service
+	config   config.Config
+	endpoint string
+	client   *http.Client
+	log      log.Log
+}
+
+// PublishedFeedKind is the source of the articles of a published feed.
+type PublishedFeedKind string
+
+// PublishedFeed identifies a feed generated from the articles of a user.
+type PublishedFeed struct {
+	User content.Login     `json:"user"`
+	Kind PublishedFeedKind `json:"kind"`
+	// ID is the id of the tag, or the index of the filter in the user
+	// profile.
+	ID int64 `json:"id,omitempty"`
+}
+
+const (
+	PublishedFavorites PublishedFeedKind = "favorite"
+	PublishedTag       PublishedFeedKind = "tag"
+	PublishedFilter    PublishedFeedKind = "filter"
+)
+
+var (
+	ErrInvalidTopic    = errors.New("Invalid published feed topic")
+	ErrInvalidCallback = errors.New("Invalid subscriber callback")
+	ErrInvalidHubMode  = errors.New("Invalid hub mode")
+	ErrInvalidSecret   = errors.New("Invalid subscriber secret")
+)
+
+const (
+	// publishedArticles is the number of articles in a published feed.
+	publishedArticles = 50
+
+	// defaultSubscriberLease is granted to subscribers that do not request
+	// a specific lease, while maxSubscriberLease is the longest one.
+	defaultSubscriberLease = 10 * 24 * time.Hour
+	maxSubscriberLease     = 30 * 24 * time.Hour
+
+	// maxSecretLength is the limit imposed by WebSub.
+	maxSecretLength = 200
+)
+
+func NewPublisher(
+	service repo.Service,
+	c config.Config,
+	client *http.Client,
+	l log.Log,
+	endpoint string,
+) *Publisher {
+	return &Publisher{
+		service: service,
+		config:  c, log: l, endpoint: endpoint,
+		client: client,
+	}
+}
+
+// HubURL returns the url subscribers send their requests to.
+func (p *Publisher) HubURL() string {
+	return p.baseURL() + "/hub"
+}
+
+// TopicURL returns the url of the published feed. It contains a key derived
+// from the auth secret, so that only the user can hand it out.
+func (p *Publisher) TopicURL(pf PublishedFeed) string {
+	return p.baseURL() + pf.path() + "?key=" + p.key(pf)
+}
+
+// ValidKey checks the key of the published feed url.
+func (p *Publisher) ValidKey(pf PublishedFeed, key string) bool {
+	return hmac.Equal([]byte(key), []byte(p.key(pf)))
+}
+
+// ParseTopic returns the published feed of the topic url.
+func (p *Publisher) ParseTopic(topic string) (PublishedFeed, error) {
+	u, err := url.Parse(topic)
+	if err != nil {
+		return PublishedFeed{}, errors.Wrapf(ErrInvalidTopic, "parsing topic %s", topic)
+	}
+
+	key := u.Query().Get("key")
+	u.RawQuery, u.Fragment = "", ""
+
+	path := strings.TrimPrefix(u.String(), p.baseURL())
+	if path == u.String() {
+		return PublishedFeed{}, errors.Wrapf(ErrInvalidTopic, "topic %s is not published by readeef", topic)
+	}
+
+	parts := strings.Split(strings.Trim(path, "/"), "/")
+	if len(parts) < 2 {
+		return PublishedFeed{}, errors.Wrapf(ErrInvalidTopic, "topic %s has no kind", topic)
+	}
+
+	login, err := url.PathUnescape(parts[0])
+	if err != nil {
+		return PublishedFeed{}, errors.Wrapf(ErrInvalidTopic, "topic %s has an invalid user", topic)
+	}
+
+	pf := PublishedFeed{User: content.Login(login), Kind: PublishedFeedKind(parts[1])}
+
+	switch {
+	case pf.Kind == PublishedFavorites && len(parts) == 2:
+	case (pf.Kind == PublishedTag || pf.Kind == PublishedFilter) && len(parts) == 3:
+		if pf.ID, err = strconv.ParseInt(parts[2], 10, 64); err != nil {
+			return PublishedFeed{}, errors.Wrapf(ErrInvalidTopic, "topic %s has an invalid id", topic)
+		}
+	default:
+		return PublishedFeed{}, errors.Wrapf(ErrInvalidTopic, "topic %s has an unknown kind", topic)
+	}
+
+	if !p.ValidKey(pf, key) {
+		return PublishedFeed{}, errors.Wrapf(ErrInvalidTopic, "topic %s has an invalid key", topic)
+	}
+
+	return pf, nil
+}
+
+// UserFeeds returns the feeds that are published for the user, along with
+// their titles.
+func (p *Publisher) UserFeeds(user content.User) ([]PublishedFeed, []string, error) {
+	tags, err := p.service.TagRepo().ForUser(user)
+	if err != nil {
+		return nil, nil, errors.WithMessage(err, "getting user tags")
+	}
+
+	feeds := []PublishedFeed{{User: user.Login, Kind: PublishedFavorites}}
+	titles := []string{favoritesTitle(user)}
+
+	for _, t := range tags {
+		feeds = append(feeds, PublishedFeed{User: user.Login, Kind: PublishedTag, ID: int64(t.ID)})
+		titles = append(titles, string(t.Value))
+	}
+
+	for _, f := range content.GetUserFilters(user) {
+		feeds = append(feeds, PublishedFeed{User: user.Login, Kind: PublishedFilter, ID: f.ID()})
+		titles = append(titles, filterTitle(f))
+	}
+
+	return feeds, titles, nil
+}
+
+// Feed returns the most recent articles of the published feed as an Atom
+// document.
+func (p *Publisher) Feed(pf PublishedFeed) ([]byte, error) {
+	user, err := p.service.UserRepo().Get(pf.User)
+	if err != nil {
+		return nil, errors.WithMessage(err, "getting published feed user")
+	}
+
+	title, articles, err := p.articles(pf, user)
+	if err != nil {
+		return nil, err
+	}
+
+	return p.atom(pf, title, articles)
+}
+
+// HubRequest handles a WebSub (un)subscription request for a published feed.
+// As required by WebSub, the intent of the subscriber is verified
+// asynchronously, and only then is the subscriber stored or removed.
+func (p *Publisher) HubRequest(mode, topic, callback, secret string, lease time.Duration) error {
+	if mode != "subscribe" && mode != "unsubscribe" {
+		return errors.Wrapf(ErrInvalidHubMode, "mode '%s'", mode)
+	}
+
+	pf, err := p.ParseTopic(topic)
+	if err != nil {
+		return err
+	}
+
+	if u, err := url.Parse(callback); err != nil || !u.IsAbs() || (u.Scheme != "http" && u.Scheme != "https") {
+		return errors.Wrapf(ErrInvalidCallback, "callback %s", callback)
+	}
+
+	if len(secret) >= maxSecretLength {
+		return errors.Wrapf(ErrInvalidSecret, "secret is %d bytes long", len(secret))
+	}
+
+	if lease <= 0 {
+		lease = defaultSubscriberLease
+	} else if lease > maxSubscriberLease {
+		lease = maxSubscriberLease
+	}
+
+	// Subscribers are stored under the canonical topic url, which is used
+	// when pushing content.
+	s := content.Subscriber{Topic: p.TopicURL(pf), Callback: callback, Secret: secret, LeaseDuration: int64(lease)}
+
+	go p.verifyIntent(mode, topic, s)
+
+	return nil
+}
+
+// Start pushes the new articles of the published feeds to their
+// subscribers, as the events of the stream arrive.
+func (p *Publisher) Start(ctx context.Context, events eventable.Stream) {
+	p.log.Infoln("Starting the feed publisher")
+
+	for {
+		select {
+		case event := <-events:
+			p.handleEvent(event)
+		case <-ctx.Done():
+			return
+		}
+	}
+}
+
+func (p *Publisher) handleEvent(event eventable.Event) {
+	switch data := event.Data.(type) {
+	case eventable.FeedUpdateData:
+		if len(data.NewArticles) == 0 {
+			return
+		}
+
+		ids := make([]content.ArticleID, len(data.NewArticles))
+		for i := range data.NewArticles {
+			ids[i] = data.NewArticles[i].ID
+		}
+
+		users, err := p.service.FeedRepo().Users(data.Feed)
+		if err != nil {
+			p.log.Printf("Error getting users of feed %s: %+v\n", data.Feed, err)
+			return
+		}
+
+		for _, user := range users {
+			tags, err := p.service.TagRepo().ForFeed(data.Feed, user)
+			if err != nil {
+				p.log.Printf("Error getting tags of feed %s: %+v\n", data.Feed, err)
+				continue
+			}
+
+			for _, t := range tags {
+				go p.publish(PublishedFeed{User: user.Login, Kind: PublishedTag, ID: int64(t.ID)}, user, ids)
+			}
+
+			for _, f := range content.GetUserFilters(user) {
+				go p.publish(PublishedFeed{User: user.Login, Kind: PublishedFilter, ID: f.ID()}, user, ids)
+			}
+		}
+	case eventable.ArticleStateData:
+		if data.State != eventable.FavorState || !data.Value {
+			return
+		}
+
+		user, err := p.service.UserRepo().Get(data.User)
+		if err != nil {
+			p.log.Printf("Error getting user %s: %+v\n", data.User, err)
+			return
+		}
+
+		// Without ids, the most recent favorites are pushed.
+		ids, _ := data.Options["ids"].([]content.ArticleID)
+
+		go p.publish(PublishedFeed{User: user.Login, Kind: PublishedFavorites}, user, ids)
+	}
+}
+
+// publish pushes the articles of the published feed that are among the ids
+// to its subscribers.
+func (p *Publisher) publish(pf PublishedFeed, user content.User, ids []content.ArticleID) {
+	topic := p.TopicURL(pf)
+
+	subscribers, err := p.service.SubscriptionRepo().Subscribers(topic)
+	if err != nil {
+		p.log.Printf("Error getting subscribers of %s: %+v\n", topic, err)
+		return
+	}
+
+	if len(subscribers) == 0 {
+		return
+	}
+
+	var opts []content.QueryOpt
+	if len(ids) > 0 {
+		opts = append(opts, content.IDs(ids))
+	}
+
+	title, articles, err := p.articles(pf, user, opts...)
+	if err != nil {
+		p.log.Printf("Error getting articles of %s: %+v\n", topic, err)
+		return
+	}
+
+	if len(articles) == 0 {
+		return
+	}
+
+	body, err := p.atom(pf, title, articles)
+	if err != nil {
+		p.log.Printf("Error generating feed %s: %+v\n", topic, err)
+		return
+	}
+
+	now := time.Now()
+	for _, s := range subscribers {
+		if s.Expired(now) {
+			p.log.Infof("Removing expired subscriber %s\n", s)
+			p.deleteSubscriber(s)
+			continue
+		}
+
+		p.push(s, body)
+	}
+}
+
+func (p *Publisher) push(s content.Subscriber, body []byte) {
+	req, err := http.NewRequest("POST", s.Callback, bytes.NewReader(body))
+	if err != nil {
+		p.log.Printf("Error creating push request for %s: %+v\n", s, err)
+		return
+	}
+
+	req.Header.Set("Content-Type", "application/atom+xml")
+	req.Header.Set("Link", fmt.Sprintf(`<%s>; rel="hub", <%s>; rel="self"`, p.HubURL(), s.Topic))
+	if signature := s.Signature(body); signature != "" {
+		req.Header.Set("X-Hub-Signature", signature)
+	}
+
+	p.log.Infof("Pushing %d bytes to subscriber %s\n", len(body), s)
+
+	resp, err := p.client.Do(req)
+	if err != nil {
+		p.log.Printf("Error pushing content to %s: %+v\n", s, err)
+		return
+	}
+	defer resp.Body.Close()
+
+	switch {
+	case resp.StatusCode == http.StatusGone:
+		// The subscriber doesn't want any more content.
+		p.log.Infof("Removing gone subscriber %s\n", s)
+		p.deleteSubscriber(s)
+	case resp.StatusCode < 200 || resp.StatusCode > 299:
+		p.log.Printf("Error pushing content to %s: unexpected response status %s\n", s, resp.Status)
+	}
+}
+
+// verifyIntent confirms the (un)subscription request for the topic with the
+// subscriber, by asking it to echo a challenge.
+func (p *Publisher) verifyIntent(mode, topic string, s content.Subscriber) {
+	challenge, err := newSecret()
+	if err != nil {
+		p.log.Printf("Error generating challenge for %s: %+v\n", s, err)
+		return
+	}
+
+	u, err := url.Parse(s.Callback)
+	if err != nil {
+		p.log.Printf("Error parsing callback of %s: %+v\n", s, err)
+		return
+	}
+
+	query := u.Query()
+	query.Set("hub.mode", mode)
+	query.Set("hub.topic", topic)
+	query.Set("hub.challenge", challenge)
+	if mode == "subscribe" {
+		query.Set("hub.lease_seconds", strconv.FormatInt(int64(time.Duration(s.LeaseDuration)/time.Second), 10))
+	}
+	u.RawQuery = query.Encode()
+
+	resp, err := p.client.Get(u.String())
+	if err != nil {
+		p.log.Printf("Error verifying the intent of %s: %+v\n", s, err)
+		return
+	}
+	defer resp.Body.Close()
+
+	b, err := ioutil.ReadAll(io.LimitReader(resp.Body, int64(len(challenge)+1)))
+	if err != nil {
+		p.log.Printf("Error reading the intent verification of %s: %+v\n", s, err)
+		return
+	}
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 || string(b) != challenge {
+		p.log.Infof("Subscriber %s did not confirm the %s request\n", s, mode)
+		return
+	}
+
+	if mode == "unsubscribe" {
+		p.log.Infof("Unsubscribed %s\n", s)
+		p.deleteSubscriber(s)
+		return
+	}
+
+	p.log.Infof("Subscribed %s\n", s)
+
+	s.VerificationTime = time.Now()
+	if err := p.service.SubscriptionRepo().UpdateSubscriber(s); err != nil {
+		p.log.Printf("Error updating subscriber %s: %+v\n", s, err)
+	}
+}
+
+func (p *Publisher) deleteSubscriber(s content.Subscriber) {
+	if err := p.service.SubscriptionRepo().DeleteSubscriber(s); err != nil {
+		p.log.Printf("Error deleting subscriber %s: %+v\n", s, err)
+	}
+}
+
+// articles returns the title and most recent articles of the published
+// feed. As in the user's views, the filters of the user hide the articles
+// they match, while the feed of a filter holds the articles of all the
+// user's feeds, as seen through it.
+func (p *Publisher) articles(
+	pf PublishedFeed,
+	user content.User,
+	opts ...content.QueryOpt,
+) (string, []content.Article, error) {
+	var title string
+
+	switch pf.Kind {
+	case PublishedFavorites:
+		title = favoritesTitle(user)
+		opts = append(opts, content.FavoriteOnly, content.Filters(content.GetUserFilters(user)))
+	case PublishedTag:
+		tagRepo := p.service.TagRepo()
+
+		tag, err := tagRepo.Get(content.TagID(pf.ID), user)
+		if err != nil {
+			return "", nil, errors.WithMessage(err, "getting published tag")
+		}
+
+		ids, err := tagRepo.FeedIDs(tag, user)
+		if err != nil {
+			return "", nil, errors.WithMessage(err, "getting published tag feed ids")
+		}
+
+		if len(ids) == 0 {
+			return string(tag.Value), nil, nil
+		}
+
+		title = string(tag.Value)
+		opts = append(opts, content.FeedIDs(ids), content.Filters(content.GetUserFilters(user)))
+	case PublishedFilter:
+		filter, ok := userFilter(user, pf.ID)
+		if !ok {
+			return "", nil, errors.Wrapf(content.ErrNoContent, "user %s has no filter %d", user, pf.ID)
+		}
+
+		title = filterTitle(filter)
+		opts = append(opts, content.Filters([]content.Filter{filter}))
+	default:
+		return "", nil, errors.Wrapf(ErrInvalidTopic, "unknown kind '%s'", pf.Kind)
+	}
+
+	opts = append(opts,
+		content.Sorting(content.SortByDate, content.DescendingOrder),
+		content.Paging(publishedArticles, 0),
+	)
+
+	articles, err := p.service.ArticleRepo().ForUser(user, opts...)
+	if err != nil {
+		return "", nil, errors.WithMessage(err, "getting published articles")
+	}
+
+	return title, articles, nil
+}
+
+type atomFeed struct {
+	XMLName xml.Name    `xml:"http://www.w3.org/2005/Atom feed"`
+	ID      string      `xml:"id"`
+	Title   string      `xml:"title"`
+	Updated string      `xml:"updated"`
+	Links   []atomLink  `xml:"link"`
+	Entries []atomEntry `xml:"entry"`
+}
+
+type atomLink struct {
+	Rel    string `xml:"rel,attr,omitempty"`
+	Href   string `xml:"href,attr"`
+	Type   string `xml:"type,attr,omitempty"`
+	Length int64  `xml:"length,attr,omitempty"`
+}
+
+type atomEntry struct {
+	ID         string         `xml:"id"`
+	Title      string         `xml:"title"`
+	Updated    string         `xml:"updated"`
+	Links      []atomLink     `xml:"link"`
+	Author     *atomAuthor    `xml:"author,omitempty"`
+	Categories []atomCategory `xml:"category"`
+	Summary    *atomText      `xml:"summary,omitempty"`
+	Content    atomText       `xml:"content"`
+}
+
+type atomAuthor struct {
+	Name string `xml:"name"`
+}
+
+type atomCategory struct {
+	Term string `xml:"term,attr"`
+}
+
+type atomText struct {
+	Type string `xml:"type,attr"`
+	Body string `xml:",chardata"`
+}
+
+func (p *Publisher) atom(pf PublishedFeed, title string, articles []content.Article) ([]byte, error) {
+	self := p.TopicURL(pf)
+
+	feed := atomFeed{
+		ID:    self,
+		Title: title,
+		Links: []atomLink{{Rel: "self", Href: self}, {Rel: "hub", Href: p.HubURL()}},
+	}
+
+	updated := time.Time{}
+	for _, a := range articles {
+		if a.Date.After(updated) {
+			updated = a.Date
+		}
+
+		entry := atomEntry{
+			ID:      a.Link,
+			Title:   a.Title,
+			Updated: a.Date.UTC().Format(time.RFC3339),
+			Links:   []atomLink{{Rel: "alternate", Href: a.Link}},
+			Content: atomText{Type: "html", Body: a.Description},
+		}
+
+		if a.Guid.Valid && a.Guid.String != "" {
+			entry.ID = a.Guid.String
+		}
+
+		if a.Author != "" {
+			entry.Author = &atomAuthor{Name: a.Author}
+		}
+
+		if a.Summary != "" {
+			entry.Summary = &atomText{Type: "html", Body: a.Summary}
+		}
+
+		for _, c := range a.Categories {
+			entry.Categories = append(entry.Categories, atomCategory{Term: c})
+		}
+
+		for _, e := range a.Enclosures {
+			entry.Links = append(entry.Links, atomLink{Rel: "enclosure", Href: e.URL, Type: e.MIMEType, Length: e.Length})
+		}
+
+		feed.Entries = append(feed.Entries, entry)
+	}
+
+	if updated.IsZero() {
+		updated = time.Now()
+	}
+	feed.Updated = updated.UTC().Format(time.RFC3339)
+
+	b, err := xml.Marshal(feed)
+	if err != nil {
+		return nil, errors.Wrapf(err, "marshaling feed %s", self)
+	}
+
+	return append([]byte(xml.Header), b...), nil
+}
+
+func (p *Publisher) baseURL() string {
+	return p.config.Hubbub.CallbackURL + p.endpoint
+}
+
+func (p *Publisher) key(pf PublishedFeed) string {
+	mac := hmac.New(sha256.New, []byte(p.config.Auth.Secret))
+	mac.Write([]byte(pf.path()))
+
+	return hex.EncodeToString(mac.Sum(nil))[:32]
+}
+
+func (pf PublishedFeed) path() string {
+	login := url.PathEscape(string(pf.User))
+
+	if pf.Kind == PublishedFavorites {
+		return fmt.Sprintf("/%s/%s", login, pf.Kind)
+	}
+
+	return fmt.Sprintf("/%s/%s/%d", login, pf.Kind, pf.ID)
+}
+
+func favoritesTitle(user content.User) string {
+	return fmt.Sprintf("Favorites of %s", user.Login)
+}
+
+// userFilter returns the filter of the user with the given id.
+func userFilter(user content.User, id int64) (content.Filter, bool) {
+	for _, f := range content.GetUserFilters(user) {
+		if f.ID() == id {
+			return f, true
+		}
+	}
+
+	return content.Filter{}, false
+}
+
+func filterTitle(f content.Filter) string {
+	terms := []string{}
+	if f.TitleTerm != "" {
+		terms = append(terms, f.TitleTerm)
+	}
+	if f.URLTerm != "" {
+		terms = append(terms, f.URLTerm)
+	}
+
+	return "Filter: " + strings.Join(terms, ", ")
+}
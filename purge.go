@@ -0,0 +1,106 @@
+package readeef
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/urandom/readeef/config"
+	"github.com/urandom/readeef/content"
+	"github.com/urandom/readeef/content/repo"
+	"github.com/urandom/readeef/content/search"
+	"github.com/urandom/readeef/log"
+)
+
+// Purger removes the articles that are past the retention policy of their
+// feed, along with their search index entries.
+type Purger struct {
+	service  repo.Service
+	provider search.Provider
+	config   config.Retention
+	log      log.Log
+}
+
+// NewPurger creates a purger for the given retention policy. The provider
+// may be nil, if no search index is used.
+func NewPurger(service repo.Service, provider search.Provider, config config.Retention, log log.Log) Purger {
+	return Purger{service: service, provider: provider, config: config, log: log}
+}
+
+// Start purges the stale articles once a day, until the context is done.
+// It does nothing if the retention policy keeps all articles.
+func (p Purger) Start(ctx context.Context) {
+	if !p.config.Enabled() {
+		return
+	}
+
+	ticker := time.NewTicker(24 * time.Hour)
+	defer ticker.Stop()
+
+	for {
+		if _, err := p.Purge(); err != nil {
+			p.log.Printf("Error purging stale articles: %+v", err)
+		}
+
+		select {
+		case <-ticker.C:
+		case <-ctx.Done():
+			return
+		}
+	}
+}
+
+// Purge removes the stale articles of every feed, and returns their number.
+// A failure to purge one feed doesn't prevent purging the rest, and the first
+// error is returned.
+func (p Purger) Purge() (int, error) {
+	feeds, err := p.service.FeedRepo().All()
+	if err != nil {
+		return 0, errors.WithMessage(err, "getting all feeds")
+	}
+
+	var firstErr error
+	count := 0
+	now := time.Now()
+
+	for _, f := range feeds {
+		articles, err := p.purgeFeed(f, now)
+		count += len(articles)
+
+		if err != nil && firstErr == nil {
+			firstErr = err
+		}
+	}
+
+	if count > 0 {
+		p.log.Infof("Purged %d stale articles", count)
+	}
+
+	return count, firstErr
+}
+
+func (p Purger) purgeFeed(f content.Feed, now time.Time) ([]content.Article, error) {
+	maxAge, keep := p.config.ForFeed(f.Link)
+	if maxAge == 0 && keep == 0 {
+		return nil, nil
+	}
+
+	var before time.Time
+	if maxAge > 0 {
+		before = now.Add(-maxAge)
+	}
+
+	articles, err := p.service.ArticleRepo().Purge(f, before, keep)
+	if err != nil {
+		return nil, errors.WithMessage(err, fmt.Sprintf("purging articles of feed %s", f))
+	}
+
+	if p.provider != nil && len(articles) > 0 {
+		if err := p.provider.BatchIndex(articles, search.BatchDelete); err != nil {
+			return articles, errors.WithMessage(err, fmt.Sprintf("removing purged articles of feed %s from the search index", f))
+		}
+	}
+
+	return articles, nil
+}
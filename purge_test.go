@@ -0,0 +1,117 @@
+package readeef
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/urandom/readeef/config"
+	"github.com/urandom/readeef/content"
+	"github.com/urandom/readeef/content/repo/mock_repo"
+	"github.com/urandom/readeef/log"
+)
+
+type beforeMatcher struct {
+	age time.Duration
+}
+
+func (m beforeMatcher) Matches(x interface{}) bool {
+	before, ok := x.(time.Time)
+	if !ok {
+		return false
+	}
+
+	if m.age == 0 {
+		return before.IsZero()
+	}
+
+	diff := time.Now().Add(-m.age).Sub(before)
+	return diff >= 0 && diff < time.Minute
+}
+
+func (m beforeMatcher) String() string {
+	return fmt.Sprintf("is %s before now", m.age)
+}
+
+func TestPurger_Purge(t *testing.T) {
+	feeds := []content.Feed{
+		{ID: 1, Link: "http://sugr.org/1"},
+		{ID: 2, Link: "http://sugr.org/2"},
+		{ID: 3, Link: "http://sugr.org/3"},
+	}
+
+	type policy struct {
+		age  time.Duration
+		keep int
+	}
+
+	tests := []struct {
+		name      string
+		retention config.Retention
+		feedsErr  error
+		policies  map[content.FeedID]policy
+		purged    map[content.FeedID]int
+		purgeErr  map[content.FeedID]error
+		want      int
+		wantErr   bool
+	}{
+		{"global", config.Retention{MaxAge: "24h", MaxArticles: 10}, nil,
+			map[content.FeedID]policy{1: {24 * time.Hour, 10}, 2: {24 * time.Hour, 10}, 3: {24 * time.Hour, 10}},
+			map[content.FeedID]int{1: 2, 3: 1}, nil, 3, false},
+		{"overrides", config.Retention{MaxAge: "24h", Feeds: []config.FeedRetention{
+			{Link: "http://sugr.org/2", MaxArticles: 5},
+			{Link: "http://sugr.org/3"},
+		}}, nil,
+			map[content.FeedID]policy{1: {24 * time.Hour, 0}, 2: {0, 5}},
+			map[content.FeedID]int{2: 4}, nil, 4, false},
+		{"invalid age", config.Retention{MaxAge: "-1h", MaxArticles: 3}, nil,
+			map[content.FeedID]policy{1: {0, 3}, 2: {0, 3}, 3: {0, 3}},
+			nil, nil, 0, false},
+		{"feeds error", config.Retention{MaxAge: "24h"}, errors.New("feeds"), nil, nil, nil, 0, true},
+		{"purge error", config.Retention{MaxArticles: 1}, nil,
+			map[content.FeedID]policy{1: {0, 1}, 2: {0, 1}, 3: {0, 1}},
+			map[content.FeedID]int{1: 1, 3: 2}, map[content.FeedID]error{2: errors.New("purge")}, 3, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			service := mock_repo.NewMockService(ctrl)
+			feedRepo := mock_repo.NewMockFeed(ctrl)
+			articleRepo := mock_repo.NewMockArticle(ctrl)
+
+			service.EXPECT().FeedRepo().Return(feedRepo)
+			feedRepo.EXPECT().All().Return(feeds, tt.feedsErr)
+
+			for _, f := range feeds {
+				p, ok := tt.policies[f.ID]
+				if !ok {
+					continue
+				}
+
+				articles := make([]content.Article, tt.purged[f.ID])
+				service.EXPECT().ArticleRepo().Return(articleRepo)
+				articleRepo.EXPECT().Purge(f, beforeMatcher{p.age}, p.keep).Return(articles, tt.purgeErr[f.ID])
+			}
+
+			tt.retention.Convert()
+
+			cfg := config.Log{}
+			cfg.Converted.Writer = os.Stderr
+
+			got, err := NewPurger(service, nil, tt.retention, log.WithStd(cfg)).Purge()
+			if (err != nil) != tt.wantErr {
+				t.Errorf("Purger.Purge() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+
+			if got != tt.want {
+				t.Errorf("Purger.Purge() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
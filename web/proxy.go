@@ -11,7 +11,9 @@ import (
 	"github.com/pkg/errors"
 )
 
-func ProxyHandler(sessionManager *scs.Manager) http.HandlerFunc {
+// ProxyHandler fetches the requested url with the given client on behalf of
+// visitors of the ui.
+func ProxyHandler(sessionManager *scs.Manager, client *http.Client) http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
 		session := sessionManager.Load(r)
 		if ok, err := session.GetBool(visitorKey); !ok || err != nil {
@@ -48,7 +50,7 @@ func ProxyHandler(sessionManager *scs.Manager) http.HandlerFunc {
 
 			var resp *http.Response
 
-			resp, err = http.DefaultClient.Do(req.WithContext(ctx))
+			resp, err = client.Do(req.WithContext(ctx))
 			if err != nil {
 				err = errors.Wrapf(err, "Error getting proxy response from %s", u)
 				break
@@ -29,13 +29,13 @@ const (
 
 type e struct{}
 
-func Mux(fs http.FileSystem, sessionManager *scs.Manager, config config.Config, log log.Log) (http.Handler, error) {
+func Mux(fs http.FileSystem, sessionManager *scs.Manager, config config.Config, client *http.Client, log log.Log) (http.Handler, error) {
 	mux := http.NewServeMux()
 
 	if hasProxy(config) {
 		mux.Handle(
 			"/proxy",
-			http.TimeoutHandler(http.HandlerFunc(ProxyHandler(sessionManager)), 10*time.Second, ""),
+			http.TimeoutHandler(http.HandlerFunc(ProxyHandler(sessionManager, client)), 10*time.Second, ""),
 		)
 	}
 